@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/eykd/linemark-go/internal/domain"
+	"github.com/eykd/linemark-go/internal/fs"
 	"github.com/eykd/linemark-go/internal/outline"
 )
 
@@ -15,16 +17,41 @@ import (
 type outlineServicer interface {
 	Add(ctx context.Context, title, parentMP string, opts ...outline.AddOption) (*outline.AddResult, error)
 	Load(ctx context.Context) (*outline.LoadResult, error)
-	Check(ctx context.Context) (*outline.CheckResult, error)
-	Repair(ctx context.Context) (*outline.RepairResult, error)
+	Check(ctx context.Context, opts ...outline.CheckOption) (*outline.CheckResult, error)
+	WriteBaseline(ctx context.Context, opts ...outline.CheckOption) (*outline.CheckResult, error)
+	Repair(ctx context.Context, opts ...outline.RepairOption) (*outline.RepairResult, error)
 	Delete(ctx context.Context, sel domain.Selector, mode domain.DeleteMode, apply bool) (*outline.DeleteResult, error)
-	Move(ctx context.Context, source, target domain.Selector, before, after string, apply bool) (*outline.MoveResult, error)
+	Archive(ctx context.Context, sel domain.Selector, mode domain.DeleteMode, apply bool) (*outline.DeleteResult, error)
+	SelectNodes(ctx context.Context, expr domain.SelectorExpr) ([]domain.Node, error)
+	DeleteNodes(ctx context.Context, expr domain.SelectorExpr, mode domain.DeleteMode, apply bool) (*outline.DeleteResult, error)
+	ArchiveNodes(ctx context.Context, expr domain.SelectorExpr, mode domain.DeleteMode, apply bool) (*outline.DeleteResult, error)
+	Move(ctx context.Context, source, target domain.Selector, before, after string, apply bool, opts ...outline.MoveOption) (*outline.MoveResult, error)
+	Copy(ctx context.Context, source, target domain.Selector, before, after string, apply bool, opts ...outline.CopyOption) (*outline.CopyResult, error)
+	Indent(ctx context.Context, sel domain.Selector, apply bool, opts ...outline.MoveOption) (*outline.MoveResult, error)
+	Outdent(ctx context.Context, sel domain.Selector, apply bool, opts ...outline.MoveOption) (*outline.MoveResult, error)
+	Split(ctx context.Context, sel domain.Selector, at outline.SplitPoint, title string, apply bool) (*outline.SplitResult, error)
+	Join(ctx context.Context, first, second domain.Selector, apply bool) (*outline.JoinResult, error)
 	Rename(ctx context.Context, selector, newTitle string, apply bool) (*outline.RenameResult, error)
 	Compact(ctx context.Context, selector string, apply bool) (*outline.CompactResult, error)
+	Reorder(ctx context.Context, parentMP string, sids []string, apply bool) (*outline.ReorderResult, error)
+	EditTree(ctx context.Context) ([]*outline.EditNode, error)
+	ApplyEdits(ctx context.Context, loaded []string, roots []*outline.EditNode, apply bool) (*outline.EditPlan, error)
+	Merge(ctx context.Context, base, ours, theirs outline.Snapshot, apply bool) (*outline.MergeResult, error)
+	ResolveMerge(ctx context.Context, apply bool) (*outline.MergeResult, error)
+	Diff(ctx context.Context, from, to string) (*outline.DiffResult, error)
+	CreateSnapshot(ctx context.Context, message string, apply bool) (*outline.SnapshotInfo, error)
+	ListSnapshots(ctx context.Context) ([]outline.SnapshotInfo, error)
+	RestoreSnapshot(ctx context.Context, id, sid string, apply bool) (*outline.RestoreResult, error)
+	DiffSnapshots(ctx context.Context, from, to string) (*outline.DiffResult, error)
+	ListTrash(ctx context.Context) ([]outline.TrashEntry, error)
+	RestoreTrash(ctx context.Context, id string, apply bool) (*outline.TrashRestoreResult, error)
+	EmptyTrash(ctx context.Context, apply bool) ([]outline.TrashEntry, error)
 	ResolveSelector(ctx context.Context, sel domain.Selector) (domain.Node, error)
 	ListTypes(ctx context.Context, selector string) (*outline.ListResult, error)
-	AddType(ctx context.Context, docType, selector string) (*outline.ModifyResult, error)
+	AddType(ctx context.Context, docType, selector string, opts ...outline.AddTypeOption) (*outline.ModifyResult, error)
 	RemoveType(ctx context.Context, docType, selector string) (*outline.ModifyResult, error)
+	Config() domain.Config
+	SetConfig(ctx context.Context, key, value string, apply bool) (*outline.ConfigResult, error)
 }
 
 // parentMP returns the parent MP of the given MP, or "" for root-level.
@@ -90,6 +117,12 @@ func (a *addAdapter) Add(ctx context.Context, title string, apply bool, p Placem
 		opts = append(opts, outline.AddAfter(node.MP.String()))
 	}
 
+	if p.Template != "" {
+		opts = append(opts, outline.AddTemplate(p.Template))
+	}
+	if p.NoRebalance {
+		opts = append(opts, outline.AddRebalance(false))
+	}
 	if !apply {
 		opts = append(opts, outline.AddApply(false))
 	}
@@ -104,6 +137,7 @@ func (a *addAdapter) Add(ctx context.Context, title string, apply bool, p Placem
 			SID:   svcResult.SID,
 			Title: title,
 		},
+		Rebalanced: convertRenames(svcResult.Rebalanced),
 	}
 	if apply {
 		result.FilesCreated = []string{svcResult.Filename}
@@ -148,17 +182,44 @@ type checkAdapter struct {
 	svc outlineServicer
 }
 
-func (a *checkAdapter) Check(ctx context.Context) (*CheckResult, error) {
-	svcResult, err := a.svc.Check(ctx)
+func (a *checkAdapter) Check(ctx context.Context, filter CheckFilter) (*CheckResult, error) {
+	svcResult, err := a.svc.Check(ctx, checkOptions(filter)...)
+	if err != nil {
+		return nil, err
+	}
+	return convertCheckResult(svcResult), nil
+}
+
+func (a *checkAdapter) WriteBaseline(ctx context.Context, filter CheckFilter) (*CheckResult, error) {
+	svcResult, err := a.svc.WriteBaseline(ctx, checkOptions(filter)...)
 	if err != nil {
 		return nil, err
 	}
+	return convertCheckResult(svcResult), nil
+}
 
-	findings := make([]CheckFinding, len(svcResult.Findings))
-	for i, f := range svcResult.Findings {
+// checkOptions converts a CheckFilter into service check options.
+func checkOptions(filter CheckFilter) []outline.CheckOption {
+	var opts []outline.CheckOption
+	if len(filter.Rules) > 0 {
+		opts = append(opts, outline.CheckRules(filter.Rules...))
+	}
+	if len(filter.SkipRules) > 0 {
+		opts = append(opts, outline.CheckSkipRules(filter.SkipRules...))
+	}
+	if filter.IgnoreBaseline {
+		opts = append(opts, outline.CheckIgnoreBaseline())
+	}
+	return opts
+}
+
+// convertCheckResult converts a service check result to a cmd.CheckResult.
+func convertCheckResult(r *outline.CheckResult) *CheckResult {
+	findings := make([]CheckFinding, len(r.Findings))
+	for i, f := range r.Findings {
 		findings[i] = convertFinding(f)
 	}
-	return &CheckResult{Findings: findings}, nil
+	return &CheckResult{Findings: findings, Suppressed: r.Suppressed}
 }
 
 // --- repairAdapter ---
@@ -167,8 +228,15 @@ type repairAdapter struct {
 	svc outlineServicer
 }
 
-func (a *repairAdapter) Repair(ctx context.Context) (*RepairResult, error) {
-	svcResult, err := a.svc.Repair(ctx)
+func (a *repairAdapter) Repair(ctx context.Context, opts RepairOptions) (*RepairResult, error) {
+	var svcOpts []outline.RepairOption
+	if opts.FixDuplicates {
+		svcOpts = append(svcOpts, outline.RepairFixDuplicates(), outline.RepairKeep(opts.Keep...))
+	}
+	if opts.ReparentOrphans {
+		svcOpts = append(svcOpts, outline.RepairReparentOrphans())
+	}
+	svcResult, err := a.svc.Repair(ctx, svcOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -200,12 +268,35 @@ type listAdapter struct {
 	svc outlineServicer
 }
 
-func (a *listAdapter) List(ctx context.Context) (*ListResult, error) {
+func (a *listAdapter) List(ctx context.Context, selector string) (*ListResult, error) {
 	svcResult, err := a.svc.Load(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return &ListResult{Outline: svcResult.Outline}, nil
+	if selector == "" {
+		return &ListResult{Outline: svcResult.Outline}, nil
+	}
+
+	expr, err := domain.ParseSelectorExpr(selector)
+	if err != nil {
+		return nil, err
+	}
+	selected, err := a.svc.SelectNodes(ctx, expr)
+	if err != nil {
+		return nil, err
+	}
+	// Keep the loaded nodes so titles read the same with or without a selector.
+	keep := make(map[string]bool, len(selected))
+	for _, n := range selected {
+		keep[n.MP.String()] = true
+	}
+	var nodes []domain.Node
+	for _, n := range svcResult.Outline.Nodes {
+		if keep[n.MP.String()] {
+			nodes = append(nodes, n)
+		}
+	}
+	return &ListResult{Outline: domain.Outline{Nodes: nodes}}, nil
 }
 
 // --- deleteAdapter ---
@@ -215,21 +306,46 @@ type deleteAdapter struct {
 }
 
 func (a *deleteAdapter) Delete(ctx context.Context, selector string, mode domain.DeleteMode, apply bool) (*DeleteResult, error) {
-	sel, err := domain.ParseSelector(selector)
+	return a.remove(ctx, selector, mode, apply, a.svc.Delete, a.svc.DeleteNodes)
+}
+
+func (a *deleteAdapter) Archive(ctx context.Context, selector string, mode domain.DeleteMode, apply bool) (*DeleteResult, error) {
+	return a.remove(ctx, selector, mode, apply, a.svc.Archive, a.svc.ArchiveNodes)
+}
+
+// remove parses a selector expression and removes the node it names with
+// one, or the set of nodes it selects with many.
+func (a *deleteAdapter) remove(
+	ctx context.Context, selector string, mode domain.DeleteMode, apply bool,
+	one func(context.Context, domain.Selector, domain.DeleteMode, bool) (*outline.DeleteResult, error),
+	many func(context.Context, domain.SelectorExpr, domain.DeleteMode, bool) (*outline.DeleteResult, error),
+) (*DeleteResult, error) {
+	expr, err := domain.ParseSelectorExpr(selector)
 	if err != nil {
 		return nil, err
 	}
 
-	svcResult, err := a.svc.Delete(ctx, sel, mode, apply)
+	var svcResult *outline.DeleteResult
+	if sel, single := expr.Single(); single {
+		svcResult, err = one(ctx, sel, mode, apply)
+	} else {
+		svcResult, err = many(ctx, expr, mode, apply)
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	return convertDeleteResult(svcResult), nil
+}
+
+func convertDeleteResult(r *outline.DeleteResult) *DeleteResult {
 	return &DeleteResult{
-		FilesDeleted:  svcResult.FilesDeleted,
-		FilesRenamed:  svcResult.FilesRenamed,
-		SIDsPreserved: svcResult.SIDsPreserved,
-	}, nil
+		FilesDeleted:  r.FilesDeleted,
+		FilesRenamed:  r.FilesRenamed,
+		SIDsPreserved: r.SIDsPreserved,
+		TrashID:       r.TrashID,
+		TrashIDs:      r.TrashIDs,
+	}
 }
 
 // --- moveAdapter ---
@@ -238,7 +354,38 @@ type moveAdapter struct {
 	svc outlineServicer
 }
 
-func (a *moveAdapter) Move(ctx context.Context, selector, to, before, after string, apply bool) (*MoveResult, error) {
+func (a *moveAdapter) Move(ctx context.Context, selector, to, before, after string, rebalance, apply bool) (*MoveResult, error) {
+	srcSel, err := domain.ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	tgtSel, err := domain.ParseSelector(to)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []outline.MoveOption
+	if !rebalance {
+		opts = append(opts, outline.MoveRebalance(false))
+	}
+	svcResult, err := a.svc.Move(ctx, srcSel, tgtSel, before, after, apply, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MoveResult{
+		Renames:    convertRenames(svcResult.Renames),
+		Rebalanced: convertRenames(svcResult.Rebalanced),
+	}, nil
+}
+
+// --- copyAdapter ---
+
+type copyAdapter struct {
+	svc outlineServicer
+}
+
+func (a *copyAdapter) Copy(ctx context.Context, selector, to, before, after string, recursive, rebalance, apply bool) (*CopyResult, error) {
 	srcSel, err := domain.ParseSelector(selector)
 	if err != nil {
 		return nil, err
@@ -248,12 +395,116 @@ func (a *moveAdapter) Move(ctx context.Context, selector, to, before, after stri
 		return nil, err
 	}
 
-	svcResult, err := a.svc.Move(ctx, srcSel, tgtSel, before, after, apply)
+	opts := []outline.CopyOption{outline.CopyRecursive(recursive)}
+	if !rebalance {
+		opts = append(opts, outline.CopyRebalance(false))
+	}
+	svcResult, err := a.svc.Copy(ctx, srcSel, tgtSel, before, after, apply, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	return &MoveResult{Renames: convertRenames(svcResult.Renames)}, nil
+	return &CopyResult{
+		SIDs:       sortedRenames(svcResult.SIDs),
+		Files:      sortedRenames(svcResult.Files),
+		Rebalanced: sortedRenames(svcResult.Rebalanced),
+	}, nil
+}
+
+// --- indentAdapter ---
+
+type indentAdapter struct {
+	svc outlineServicer
+}
+
+func (a *indentAdapter) Indent(ctx context.Context, selector string, rebalance, apply bool) (*MoveResult, error) {
+	return a.shift(ctx, a.svc.Indent, selector, rebalance, apply)
+}
+
+func (a *indentAdapter) Outdent(ctx context.Context, selector string, rebalance, apply bool) (*MoveResult, error) {
+	return a.shift(ctx, a.svc.Outdent, selector, rebalance, apply)
+}
+
+// shift parses the selector and calls the given indent or outdent method.
+func (a *indentAdapter) shift(ctx context.Context, method func(context.Context, domain.Selector, bool, ...outline.MoveOption) (*outline.MoveResult, error), selector string, rebalance, apply bool) (*MoveResult, error) {
+	sel, err := domain.ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []outline.MoveOption
+	if !rebalance {
+		opts = append(opts, outline.MoveRebalance(false))
+	}
+	svcResult, err := method(ctx, sel, apply, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MoveResult{
+		Renames:    convertRenames(svcResult.Renames),
+		Rebalanced: convertRenames(svcResult.Rebalanced),
+	}, nil
+}
+
+// --- splitAdapter ---
+
+type splitAdapter struct {
+	svc outlineServicer
+}
+
+func (a *splitAdapter) Split(ctx context.Context, selector string, line int, heading, title string, apply bool) (*SplitResult, error) {
+	sel, err := domain.ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	svcResult, err := a.svc.Split(ctx, sel, outline.SplitPoint{Line: line, Heading: heading}, title, apply)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SplitResult{
+		MP:         svcResult.MP,
+		SID:        svcResult.SID,
+		Title:      svcResult.Title,
+		Draft:      svcResult.Draft,
+		Created:    svcResult.Created,
+		Lines:      svcResult.Lines,
+		FirstLine:  svcResult.FirstLine,
+		Rebalanced: sortedRenames(svcResult.Rebalanced),
+	}, nil
+}
+
+// --- joinAdapter ---
+
+type joinAdapter struct {
+	svc outlineServicer
+}
+
+func (a *joinAdapter) Join(ctx context.Context, first, second string, apply bool) (*JoinResult, error) {
+	firstSel, err := domain.ParseSelector(first)
+	if err != nil {
+		return nil, err
+	}
+	secondSel, err := domain.ParseSelector(second)
+	if err != nil {
+		return nil, err
+	}
+
+	svcResult, err := a.svc.Join(ctx, firstSel, secondSel, apply)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JoinResult{
+		MP:         svcResult.MP,
+		SID:        svcResult.SID,
+		RemovedMP:  svcResult.RemovedMP,
+		RemovedSID: svcResult.RemovedSID,
+		Written:    svcResult.Written,
+		Deleted:    svcResult.Deleted,
+	}, nil
 }
 
 // --- renameAdapter ---
@@ -295,13 +546,291 @@ func (a *compactAdapter) Compact(ctx context.Context, selector string, apply boo
 		Renames:       convertRenames(svcResult.Renames),
 		FilesAffected: len(svcResult.Renames),
 	}
-	if result.FilesAffected > 50 {
+	if result.FilesAffected > a.svc.Config().CompactWarningThreshold {
 		w := fmt.Sprintf("compact affects %d files — review with --dry-run before applying", result.FilesAffected)
 		result.Warning = &w
 	}
 	return result, nil
 }
 
+// --- reorderAdapter ---
+
+type reorderAdapter struct {
+	svc outlineServicer
+}
+
+// resolveParent returns the MP of the parent selector, or "" for the root.
+func (a *reorderAdapter) resolveParent(ctx context.Context, parent string) (string, error) {
+	if parent == "" {
+		return "", nil
+	}
+	sel, err := domain.ParseSelector(parent)
+	if err != nil {
+		return "", err
+	}
+	node, err := a.svc.ResolveSelector(ctx, sel)
+	if err != nil {
+		return "", err
+	}
+	return node.MP.String(), nil
+}
+
+func (a *reorderAdapter) Children(ctx context.Context, parent string) ([]ReorderChild, error) {
+	mp, err := a.resolveParent(ctx, parent)
+	if err != nil {
+		return nil, err
+	}
+	loaded, err := a.svc.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var children []ReorderChild
+	for _, n := range loaded.Outline.Nodes {
+		if parentMP(n.MP.String()) == mp {
+			children = append(children, ReorderChild{SID: n.SID, MP: n.MP.String(), Title: n.Title})
+		}
+	}
+	return children, nil
+}
+
+func (a *reorderAdapter) Reorder(ctx context.Context, parent string, sids []string, apply bool) (*ReorderResult, error) {
+	mp, err := a.resolveParent(ctx, parent)
+	if err != nil {
+		return nil, err
+	}
+	svcResult, err := a.svc.Reorder(ctx, mp, sids, apply)
+	if err != nil {
+		return nil, err
+	}
+	return &ReorderResult{Renames: convertRenames(svcResult.Renames)}, nil
+}
+
+// --- tuiAdapter ---
+
+type tuiAdapter struct {
+	svc outlineServicer
+}
+
+func (a *tuiAdapter) EditTree(ctx context.Context) ([]*EditNode, error) {
+	roots, err := a.svc.EditTree(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return convertEditNodes(roots), nil
+}
+
+func (a *tuiAdapter) ApplyEdits(ctx context.Context, loaded []string, roots []*EditNode, apply bool) (*EditPlan, error) {
+	svcPlan, err := a.svc.ApplyEdits(ctx, loaded, toOutlineEditNodes(roots), apply)
+	if err != nil {
+		return nil, err
+	}
+	plan := &EditPlan{
+		Renames: convertRenames(svcPlan.Renames),
+		Deleted: svcPlan.Deleted,
+		Written: svcPlan.Written,
+	}
+	for _, added := range svcPlan.Added {
+		plan.Added = append(plan.Added, EditAddition{SID: added.SID, MP: added.MP, Title: added.Title})
+	}
+	return plan, nil
+}
+
+// convertEditNodes converts outline.EditNodes to cmd.EditNodes.
+func convertEditNodes(nodes []*outline.EditNode) []*EditNode {
+	var result []*EditNode
+	for _, n := range nodes {
+		result = append(result, &EditNode{SID: n.SID, Title: n.Title, Types: n.Types, Children: convertEditNodes(n.Children)})
+	}
+	return result
+}
+
+// toOutlineEditNodes converts cmd.EditNodes to outline.EditNodes.
+func toOutlineEditNodes(nodes []*EditNode) []*outline.EditNode {
+	var result []*outline.EditNode
+	for _, n := range nodes {
+		result = append(result, &outline.EditNode{SID: n.SID, Title: n.Title, Types: n.Types, Children: toOutlineEditNodes(n.Children)})
+	}
+	return result
+}
+
+// --- mergeAdapter ---
+
+type mergeAdapter struct {
+	svc outlineServicer
+}
+
+func (a *mergeAdapter) Merge(ctx context.Context, base, ours, theirs string, apply bool) (*MergeResult, error) {
+	svcResult, err := a.svc.Merge(ctx,
+		&fs.OSSnapshot{Root: base}, &fs.OSSnapshot{Root: ours}, &fs.OSSnapshot{Root: theirs}, apply)
+	if err != nil {
+		return nil, err
+	}
+	return convertMergeResult(svcResult), nil
+}
+
+func (a *mergeAdapter) ResolveMerge(ctx context.Context, apply bool) (*MergeResult, error) {
+	svcResult, err := a.svc.ResolveMerge(ctx, apply)
+	if err != nil {
+		return nil, err
+	}
+	return convertMergeResult(svcResult), nil
+}
+
+// convertMergeResult converts an outline.MergeResult to a cmd.MergeResult.
+func convertMergeResult(r *outline.MergeResult) *MergeResult {
+	result := &MergeResult{
+		Written:   append([]string{}, r.Written...),
+		Deleted:   append([]string{}, r.Deleted...),
+		Conflicts: []MergeConflict{},
+	}
+	for _, c := range r.Conflicts {
+		result.Conflicts = append(result.Conflicts, MergeConflict{
+			Kind:    string(c.Kind),
+			SID:     c.SID,
+			DocType: c.DocType,
+			Path:    c.Path,
+			Message: c.Message,
+		})
+	}
+	return result
+}
+
+// --- diffAdapter ---
+
+type diffAdapter struct {
+	svc outlineServicer
+}
+
+func (a *diffAdapter) Diff(ctx context.Context, from, to string) (*DiffResult, error) {
+	svcResult, err := a.svc.Diff(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return convertDiffResult(svcResult), nil
+}
+
+// convertDiffResult converts an outline.DiffResult to a cmd.DiffResult.
+func convertDiffResult(r *outline.DiffResult) *DiffResult {
+	result := &DiffResult{Nodes: []NodeChange{}}
+	for _, n := range r.Nodes {
+		result.Nodes = append(result.Nodes, NodeChange{
+			SID:            n.SID,
+			Status:         string(n.Status),
+			OldMP:          n.OldMP,
+			NewMP:          n.NewMP,
+			OldParent:      n.OldParent,
+			NewParent:      n.NewParent,
+			OldTitle:       n.OldTitle,
+			NewTitle:       n.NewTitle,
+			Moved:          n.Moved,
+			Retitled:       n.Retitled,
+			DocsAdded:      append([]string{}, n.DocsAdded...),
+			DocsRemoved:    append([]string{}, n.DocsRemoved...),
+			ContentChanged: append([]string{}, n.ContentChanged...),
+		})
+	}
+	return result
+}
+
+// --- snapshotAdapter ---
+
+type snapshotAdapter struct {
+	svc outlineServicer
+}
+
+func (a *snapshotAdapter) CreateSnapshot(ctx context.Context, message string, apply bool) (*SnapshotInfo, error) {
+	info, err := a.svc.CreateSnapshot(ctx, message, apply)
+	if err != nil {
+		return nil, err
+	}
+	converted := convertSnapshotInfo(*info)
+	return &converted, nil
+}
+
+func (a *snapshotAdapter) ListSnapshots(ctx context.Context) ([]SnapshotInfo, error) {
+	infos, err := a.svc.ListSnapshots(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := []SnapshotInfo{}
+	for _, info := range infos {
+		result = append(result, convertSnapshotInfo(info))
+	}
+	return result, nil
+}
+
+func (a *snapshotAdapter) RestoreSnapshot(ctx context.Context, id, sid string, apply bool) (*RestoreResult, error) {
+	svcResult, err := a.svc.RestoreSnapshot(ctx, id, sid, apply)
+	if err != nil {
+		return nil, err
+	}
+	return &RestoreResult{
+		Written: append([]string{}, svcResult.Written...),
+		Deleted: append([]string{}, svcResult.Deleted...),
+	}, nil
+}
+
+func (a *snapshotAdapter) DiffSnapshots(ctx context.Context, from, to string) (*DiffResult, error) {
+	svcResult, err := a.svc.DiffSnapshots(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return convertDiffResult(svcResult), nil
+}
+
+// convertSnapshotInfo converts an outline.SnapshotInfo to a cmd.SnapshotInfo.
+func convertSnapshotInfo(info outline.SnapshotInfo) SnapshotInfo {
+	return SnapshotInfo{ID: info.ID, Created: info.Created, Message: info.Message, Files: info.Files}
+}
+
+// --- trashAdapter ---
+
+type trashAdapter struct {
+	svc outlineServicer
+}
+
+func (a *trashAdapter) ListTrash(ctx context.Context) ([]TrashEntry, error) {
+	entries, err := a.svc.ListTrash(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return convertTrashEntries(entries), nil
+}
+
+func (a *trashAdapter) RestoreTrash(ctx context.Context, id string, apply bool) (*TrashRestoreResult, error) {
+	svcResult, err := a.svc.RestoreTrash(ctx, id, apply)
+	if err != nil {
+		return nil, err
+	}
+	return &TrashRestoreResult{ID: svcResult.ID, SID: svcResult.SID, MP: svcResult.MP, Renames: svcResult.Renames}, nil
+}
+
+func (a *trashAdapter) EmptyTrash(ctx context.Context, apply bool) ([]TrashEntry, error) {
+	entries, err := a.svc.EmptyTrash(ctx, apply)
+	if err != nil {
+		return nil, err
+	}
+	return convertTrashEntries(entries), nil
+}
+
+// convertTrashEntries converts outline.TrashEntry values to cmd.TrashEntry
+// values, returning a non-nil slice.
+func convertTrashEntries(entries []outline.TrashEntry) []TrashEntry {
+	result := []TrashEntry{}
+	for _, e := range entries {
+		result = append(result, TrashEntry{
+			ID:        e.ID,
+			Deleted:   e.Deleted,
+			SID:       e.SID,
+			MP:        e.MP,
+			ParentSID: e.ParentSID,
+			Slug:      e.Slug,
+			Files:     append([]string{}, e.Files...),
+		})
+	}
+	return result
+}
+
 // --- typesAdapter ---
 
 type typesAdapter struct {
@@ -319,11 +848,20 @@ func (a *typesAdapter) ListTypes(ctx context.Context, selector string) (*TypesLi
 	}, nil
 }
 
-func (a *typesAdapter) AddType(ctx context.Context, docType, selector string, apply bool) (*TypesModifyResult, error) {
+func (a *typesAdapter) AddType(ctx context.Context, docType, selector, template string, apply bool) (*TypesModifyResult, error) {
+	if template != "" {
+		if err := domain.ValidateTemplateName(template); err != nil {
+			return nil, err
+		}
+	}
 	if !apply {
 		return a.resolveTypesDryRun(ctx, docType, selector)
 	}
-	svcResult, err := a.svc.AddType(ctx, docType, selector)
+	var opts []outline.AddTypeOption
+	if template != "" {
+		opts = append(opts, outline.AddTypeTemplate(template))
+	}
+	svcResult, err := a.svc.AddType(ctx, docType, selector, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -347,6 +885,72 @@ func (a *typesAdapter) RemoveType(ctx context.Context, docType, selector string,
 	}, nil
 }
 
+func (a *typesAdapter) AddTypes(ctx context.Context, docType, selector, template string, apply bool) (*TypesBatchResult, error) {
+	if template != "" {
+		if err := domain.ValidateTemplateName(template); err != nil {
+			return nil, err
+		}
+	}
+	var opts []outline.AddTypeOption
+	if template != "" {
+		opts = append(opts, outline.AddTypeTemplate(template))
+	}
+	return a.modifyTypes(ctx, docType, selector, true, apply, func(mp string) (*outline.ModifyResult, error) {
+		return a.svc.AddType(ctx, docType, mp, opts...)
+	})
+}
+
+func (a *typesAdapter) RemoveTypes(ctx context.Context, docType, selector string, apply bool) (*TypesBatchResult, error) {
+	return a.modifyTypes(ctx, docType, selector, false, apply, func(mp string) (*outline.ModifyResult, error) {
+		return a.svc.RemoveType(ctx, docType, mp)
+	})
+}
+
+// modifyTypes applies modify to each node a selector expression selects,
+// skipping the nodes that already have the type (add) or lack it (remove).
+func (a *typesAdapter) modifyTypes(ctx context.Context, docType, selector string, add, apply bool, modify func(mp string) (*outline.ModifyResult, error)) (*TypesBatchResult, error) {
+	if err := domain.ValidateDocType(docType); err != nil {
+		return nil, err
+	}
+	expr, err := domain.ParseSelectorExpr(selector)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := a.svc.SelectNodes(ctx, expr)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TypesBatchResult{Changed: []TypesModifyResult{}, Skipped: []NodeInfo{}, Planned: !apply}
+	for _, n := range nodes {
+		info := NodeInfo{MP: n.MP.String(), SID: n.SID}
+		if nodeHasType(n, docType) == add {
+			result.Skipped = append(result.Skipped, info)
+			continue
+		}
+		filename := domain.GenerateFilename(info.MP, n.SID, docType, "")
+		if apply {
+			svcResult, err := modify(info.MP)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", info.MP, err)
+			}
+			filename = svcResult.Filename
+		}
+		result.Changed = append(result.Changed, TypesModifyResult{Node: info, Filename: filename, Planned: !apply})
+	}
+	return result, nil
+}
+
+// nodeHasType reports whether a node has a document of the given type.
+func nodeHasType(n domain.Node, docType string) bool {
+	for _, d := range n.Documents {
+		if d.Type == docType {
+			return true
+		}
+	}
+	return false
+}
+
 // resolveTypesDryRun validates inputs and resolves the target node to produce
 // the would-be filename without performing any I/O.
 func (a *typesAdapter) resolveTypesDryRun(ctx context.Context, docType, selector string) (*TypesModifyResult, error) {
@@ -368,6 +972,43 @@ func (a *typesAdapter) resolveTypesDryRun(ctx context.Context, docType, selector
 	}, nil
 }
 
+// --- configAdapter ---
+
+type configAdapter struct {
+	svc outlineServicer
+}
+
+func (a *configAdapter) ListConfig(_ context.Context) ([]ConfigEntry, error) {
+	cfg := a.svc.Config()
+	keys := cfg.Keys()
+	entries := make([]ConfigEntry, len(keys))
+	for i, key := range keys {
+		value, _ := cfg.Get(key)
+		entries[i] = ConfigEntry{Key: key, Value: value}
+	}
+	return entries, nil
+}
+
+func (a *configAdapter) GetConfig(_ context.Context, key string) (*ConfigEntry, error) {
+	value, err := a.svc.Config().Get(key)
+	if err != nil {
+		return nil, err
+	}
+	return &ConfigEntry{Key: key, Value: value}, nil
+}
+
+func (a *configAdapter) SetConfig(ctx context.Context, key, value string, apply bool) (*ConfigSetResult, error) {
+	svcResult, err := a.svc.SetConfig(ctx, key, value, apply)
+	if err != nil {
+		return nil, err
+	}
+	return &ConfigSetResult{
+		Key:      svcResult.Key,
+		OldValue: svcResult.OldValue,
+		NewValue: svcResult.NewValue,
+	}, nil
+}
+
 // convertRenames converts a rename map to a slice of RenameEntry.
 func convertRenames(m map[string]string) []RenameEntry {
 	entries := make([]RenameEntry, 0, len(m))
@@ -377,12 +1018,24 @@ func convertRenames(m map[string]string) []RenameEntry {
 	return entries
 }
 
+// sortedRenames converts a rename map to entries ordered by old name.
+func sortedRenames(m map[string]string) []RenameEntry {
+	entries := convertRenames(m)
+	slices.SortFunc(entries, func(a, b RenameEntry) int { return strings.Compare(a.Old, b.Old) })
+	return entries
+}
+
 // convertFinding converts a domain.Finding to a cmd.CheckFinding.
 func convertFinding(f domain.Finding) CheckFinding {
 	return CheckFinding{
-		Type:     FindingType(f.Type),
-		Severity: Severity(f.Severity),
-		Message:  f.Message,
-		Path:     f.Path,
+		Type:      FindingType(f.Type),
+		Severity:  Severity(f.Severity),
+		Message:   f.Message,
+		Path:      f.Path,
+		Rule:      f.Rule,
+		Line:      f.Line,
+		Column:    f.Column,
+		EndLine:   f.EndLine,
+		EndColumn: f.EndColumn,
 	}
 }
@@ -76,7 +76,11 @@ func (s *dryRunStubSlugifier) Slug(title string) string {
 // dryRunStubFMHandler is a minimal FrontmatterHandler stub.
 type dryRunStubFMHandler struct{}
 
-func (h *dryRunStubFMHandler) GetTitle(input string) (string, error)           { return "", nil }
+func (h *dryRunStubFMHandler) GetTitle(input string) (string, error) { return "", nil }
+func (h *dryRunStubFMHandler) GetFields(input string) (map[string]string, error) {
+	return nil, nil
+}
+func (h *dryRunStubFMHandler) GetList(input, key string) ([]string, error)     { return nil, nil }
 func (h *dryRunStubFMHandler) SetTitle(input, newTitle string) (string, error) { return input, nil }
 func (h *dryRunStubFMHandler) EncodeYAMLValue(s string) string                 { return s }
 func (h *dryRunStubFMHandler) Serialize(fm, body string) string                { return fm }
@@ -99,7 +103,7 @@ func TestTypesAdapter_AddType_DryRunDoesNotWriteFile(t *testing.T) {
 	svc := outline.NewOutlineService(reader, writer, locker, nil)
 	adapter := &typesAdapter{svc: svc}
 
-	result, err := adapter.AddType(context.Background(), "characters", "100", false)
+	result, err := adapter.AddType(context.Background(), "characters", "100", "", false)
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -125,7 +129,7 @@ func TestTypesAdapter_AddType_ApplyWritesFile(t *testing.T) {
 	svc := outline.NewOutlineService(reader, writer, locker, nil)
 	adapter := &typesAdapter{svc: svc}
 
-	_, err := adapter.AddType(context.Background(), "characters", "100", true)
+	_, err := adapter.AddType(context.Background(), "characters", "100", "", true)
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
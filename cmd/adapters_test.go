@@ -4,9 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/eykd/linemark-go/internal/domain"
+	"github.com/eykd/linemark-go/internal/fs"
 	"github.com/eykd/linemark-go/internal/outline"
 )
 
@@ -18,17 +21,43 @@ type stubOutlineService struct {
 	loadResult       *outline.LoadResult
 	loadErr          error
 	checkResult      *outline.CheckResult
+	checkOpts        []outline.CheckOption
+	baselineResult   *outline.CheckResult
+	baselineErr      error
+	baselineOpts     []outline.CheckOption
 	checkErr         error
 	repairResult     *outline.RepairResult
+	repairOpts       []outline.RepairOption
 	repairErr        error
 	deleteResult     *outline.DeleteResult
 	deleteErr        error
+	selectedNodes    []domain.Node
+	selectErr        error
 	moveResult       *outline.MoveResult
 	moveErr          error
+	copyResult       *outline.CopyResult
+	splitResult      *outline.SplitResult
+	joinResult       *outline.JoinResult
 	renameResult     *outline.RenameResult
 	renameErr        error
 	compactResult    *outline.CompactResult
 	compactErr       error
+	reorderResult    *outline.ReorderResult
+	reorderErr       error
+	editTree         []*outline.EditNode
+	editPlan         *outline.EditPlan
+	editErr          error
+	mergeResult      *outline.MergeResult
+	mergeErr         error
+	diffResult       *outline.DiffResult
+	diffErr          error
+	snapshotInfo     *outline.SnapshotInfo
+	snapshotInfos    []outline.SnapshotInfo
+	restoreResult    *outline.RestoreResult
+	snapshotErr      error
+	trashEntries     []outline.TrashEntry
+	trashRestore     *outline.TrashRestoreResult
+	trashErr         error
 	listTypesResult  *outline.ListResult
 	listTypesErr     error
 	addTypeResult    *outline.ModifyResult
@@ -40,21 +69,46 @@ type stubOutlineService struct {
 	addTitle     string
 	addParentMP  string
 	addOpts      []outline.AddOption
+	addTypeOpts  []outline.AddTypeOption
 	deleteMode   domain.DeleteMode
 	deleteSel    domain.Selector
 	deleteApply  bool
+	deleteExpr   domain.SelectorExpr
+	archived     bool
+	typeTargets  []string
 	moveSrc      domain.Selector
 	moveTgt      domain.Selector
 	moveBefore   string
 	moveAfter    string
 	moveApply    bool
+	moveOpts     []outline.MoveOption
+	shiftOp      string
+	copyOpts     []outline.CopyOption
+	splitAt      outline.SplitPoint
+	splitTitle   string
 	renameSel    string
 	renameTitle  string
 	renameApply  bool
 	compactSel   string
 	compactApply bool
+	reorderArgs  []any
+	editLoaded   []string
+	editRoots    []*outline.EditNode
+	editApply    bool
+	mergeSides   []outline.Snapshot
+	mergeApply   bool
+	diffRefs     [2]string
+	snapshotArgs []any
+	trashArgs    []any
 	resolvedNode domain.Node
 	resolveErr   error
+
+	config       *domain.Config
+	setConfigRes *outline.ConfigResult
+	setConfigErr error
+	setKey       string
+	setValue     string
+	setApply     bool
 }
 
 func (s *stubOutlineService) Add(ctx context.Context, title, parentMP string, opts ...outline.AddOption) (*outline.AddResult, error) {
@@ -68,11 +122,18 @@ func (s *stubOutlineService) Load(ctx context.Context) (*outline.LoadResult, err
 	return s.loadResult, s.loadErr
 }
 
-func (s *stubOutlineService) Check(ctx context.Context) (*outline.CheckResult, error) {
+func (s *stubOutlineService) Check(ctx context.Context, opts ...outline.CheckOption) (*outline.CheckResult, error) {
+	s.checkOpts = opts
 	return s.checkResult, s.checkErr
 }
 
-func (s *stubOutlineService) Repair(ctx context.Context) (*outline.RepairResult, error) {
+func (s *stubOutlineService) WriteBaseline(ctx context.Context, opts ...outline.CheckOption) (*outline.CheckResult, error) {
+	s.baselineOpts = opts
+	return s.baselineResult, s.baselineErr
+}
+
+func (s *stubOutlineService) Repair(ctx context.Context, opts ...outline.RepairOption) (*outline.RepairResult, error) {
+	s.repairOpts = opts
 	return s.repairResult, s.repairErr
 }
 
@@ -83,7 +144,30 @@ func (s *stubOutlineService) Delete(ctx context.Context, sel domain.Selector, mo
 	return s.deleteResult, s.deleteErr
 }
 
-func (s *stubOutlineService) Move(ctx context.Context, source, target domain.Selector, before, after string, apply bool) (*outline.MoveResult, error) {
+func (s *stubOutlineService) Archive(ctx context.Context, sel domain.Selector, mode domain.DeleteMode, apply bool) (*outline.DeleteResult, error) {
+	s.archived = true
+	return s.Delete(ctx, sel, mode, apply)
+}
+
+func (s *stubOutlineService) SelectNodes(ctx context.Context, expr domain.SelectorExpr) ([]domain.Node, error) {
+	s.deleteExpr = expr
+	return s.selectedNodes, s.selectErr
+}
+
+func (s *stubOutlineService) DeleteNodes(ctx context.Context, expr domain.SelectorExpr, mode domain.DeleteMode, apply bool) (*outline.DeleteResult, error) {
+	s.deleteExpr = expr
+	s.deleteMode = mode
+	s.deleteApply = apply
+	return s.deleteResult, s.deleteErr
+}
+
+func (s *stubOutlineService) ArchiveNodes(ctx context.Context, expr domain.SelectorExpr, mode domain.DeleteMode, apply bool) (*outline.DeleteResult, error) {
+	s.archived = true
+	return s.DeleteNodes(ctx, expr, mode, apply)
+}
+
+func (s *stubOutlineService) Move(ctx context.Context, source, target domain.Selector, before, after string, apply bool, opts ...outline.MoveOption) (*outline.MoveResult, error) {
+	s.moveOpts = opts
 	s.moveSrc = source
 	s.moveTgt = target
 	s.moveBefore = before
@@ -92,6 +176,35 @@ func (s *stubOutlineService) Move(ctx context.Context, source, target domain.Sel
 	return s.moveResult, s.moveErr
 }
 
+func (s *stubOutlineService) Copy(ctx context.Context, source, target domain.Selector, before, after string, apply bool, opts ...outline.CopyOption) (*outline.CopyResult, error) {
+	s.moveSrc, s.moveTgt, s.moveBefore, s.moveAfter, s.moveApply = source, target, before, after, apply
+	s.copyOpts = opts
+	return s.copyResult, s.moveErr
+}
+
+func (s *stubOutlineService) Indent(ctx context.Context, sel domain.Selector, apply bool, opts ...outline.MoveOption) (*outline.MoveResult, error) {
+	s.shiftOp = "indent"
+	s.moveSrc, s.moveApply, s.moveOpts = sel, apply, opts
+	return s.moveResult, s.moveErr
+}
+
+func (s *stubOutlineService) Outdent(ctx context.Context, sel domain.Selector, apply bool, opts ...outline.MoveOption) (*outline.MoveResult, error) {
+	s.shiftOp = "outdent"
+	s.moveSrc, s.moveApply, s.moveOpts = sel, apply, opts
+	return s.moveResult, s.moveErr
+}
+
+func (s *stubOutlineService) Split(ctx context.Context, sel domain.Selector, at outline.SplitPoint, title string, apply bool) (*outline.SplitResult, error) {
+	s.moveSrc, s.moveApply = sel, apply
+	s.splitAt, s.splitTitle = at, title
+	return s.splitResult, s.moveErr
+}
+
+func (s *stubOutlineService) Join(ctx context.Context, first, second domain.Selector, apply bool) (*outline.JoinResult, error) {
+	s.moveSrc, s.moveTgt, s.moveApply = first, second, apply
+	return s.joinResult, s.moveErr
+}
+
 func (s *stubOutlineService) Rename(ctx context.Context, selector, newTitle string, apply bool) (*outline.RenameResult, error) {
 	s.renameSel = selector
 	s.renameTitle = newTitle
@@ -105,6 +218,69 @@ func (s *stubOutlineService) Compact(ctx context.Context, selector string, apply
 	return s.compactResult, s.compactErr
 }
 
+func (s *stubOutlineService) Reorder(ctx context.Context, parentMP string, sids []string, apply bool) (*outline.ReorderResult, error) {
+	s.reorderArgs = []any{parentMP, sids, apply}
+	return s.reorderResult, s.reorderErr
+}
+
+func (s *stubOutlineService) EditTree(ctx context.Context) ([]*outline.EditNode, error) {
+	return s.editTree, s.editErr
+}
+
+func (s *stubOutlineService) ApplyEdits(ctx context.Context, loaded []string, roots []*outline.EditNode, apply bool) (*outline.EditPlan, error) {
+	s.editLoaded, s.editRoots, s.editApply = loaded, roots, apply
+	return s.editPlan, s.editErr
+}
+
+func (s *stubOutlineService) Merge(ctx context.Context, base, ours, theirs outline.Snapshot, apply bool) (*outline.MergeResult, error) {
+	s.mergeSides = []outline.Snapshot{base, ours, theirs}
+	s.mergeApply = apply
+	return s.mergeResult, s.mergeErr
+}
+
+func (s *stubOutlineService) ResolveMerge(ctx context.Context, apply bool) (*outline.MergeResult, error) {
+	s.mergeApply = apply
+	return s.mergeResult, s.mergeErr
+}
+
+func (s *stubOutlineService) Diff(ctx context.Context, from, to string) (*outline.DiffResult, error) {
+	s.diffRefs = [2]string{from, to}
+	return s.diffResult, s.diffErr
+}
+
+func (s *stubOutlineService) CreateSnapshot(ctx context.Context, message string, apply bool) (*outline.SnapshotInfo, error) {
+	s.snapshotArgs = []any{message, apply}
+	return s.snapshotInfo, s.snapshotErr
+}
+
+func (s *stubOutlineService) ListSnapshots(ctx context.Context) ([]outline.SnapshotInfo, error) {
+	return s.snapshotInfos, s.snapshotErr
+}
+
+func (s *stubOutlineService) RestoreSnapshot(ctx context.Context, id, sid string, apply bool) (*outline.RestoreResult, error) {
+	s.snapshotArgs = []any{id, sid, apply}
+	return s.restoreResult, s.snapshotErr
+}
+
+func (s *stubOutlineService) DiffSnapshots(ctx context.Context, from, to string) (*outline.DiffResult, error) {
+	s.snapshotArgs = []any{from, to}
+	return s.diffResult, s.snapshotErr
+}
+
+func (s *stubOutlineService) ListTrash(ctx context.Context) ([]outline.TrashEntry, error) {
+	return s.trashEntries, s.trashErr
+}
+
+func (s *stubOutlineService) RestoreTrash(ctx context.Context, id string, apply bool) (*outline.TrashRestoreResult, error) {
+	s.trashArgs = []any{id, apply}
+	return s.trashRestore, s.trashErr
+}
+
+func (s *stubOutlineService) EmptyTrash(ctx context.Context, apply bool) ([]outline.TrashEntry, error) {
+	s.trashArgs = []any{apply}
+	return s.trashEntries, s.trashErr
+}
+
 func (s *stubOutlineService) ResolveSelector(ctx context.Context, sel domain.Selector) (domain.Node, error) {
 	return s.resolvedNode, s.resolveErr
 }
@@ -113,14 +289,31 @@ func (s *stubOutlineService) ListTypes(ctx context.Context, selector string) (*o
 	return s.listTypesResult, s.listTypesErr
 }
 
-func (s *stubOutlineService) AddType(ctx context.Context, docType, selector string) (*outline.ModifyResult, error) {
+func (s *stubOutlineService) AddType(ctx context.Context, docType, selector string, opts ...outline.AddTypeOption) (*outline.ModifyResult, error) {
+	s.addTypeOpts = opts
+	s.typeTargets = append(s.typeTargets, selector)
 	return s.addTypeResult, s.addTypeErr
 }
 
 func (s *stubOutlineService) RemoveType(ctx context.Context, docType, selector string) (*outline.ModifyResult, error) {
+	s.typeTargets = append(s.typeTargets, selector)
 	return s.removeTypeResult, s.removeTypeErr
 }
 
+func (s *stubOutlineService) Config() domain.Config {
+	if s.config == nil {
+		return domain.DefaultConfig()
+	}
+	return *s.config
+}
+
+func (s *stubOutlineService) SetConfig(ctx context.Context, key, value string, apply bool) (*outline.ConfigResult, error) {
+	s.setKey = key
+	s.setValue = value
+	s.setApply = apply
+	return s.setConfigRes, s.setConfigErr
+}
+
 // --- addAdapter tests ---
 
 func TestAddAdapter_DefaultPlacement(t *testing.T) {
@@ -193,6 +386,47 @@ func TestAddAdapter_BeforePlacement(t *testing.T) {
 	}
 }
 
+func TestAddAdapter_PassesTemplate(t *testing.T) {
+	stub := &stubOutlineService{
+		addResult: &outline.AddResult{SID: "NEWNODE12345", MP: "100", Filename: "100_NEWNODE12345_draft_new.md"},
+	}
+	adapter := &addAdapter{svc: stub}
+
+	_, err := adapter.Add(context.Background(), "New", true, Placement{Template: "interlude"})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stub.addOpts) != 1 {
+		t.Errorf("addOpts = %d, want 1 (template)", len(stub.addOpts))
+	}
+}
+
+func TestAddAdapter_PassesNoRebalance(t *testing.T) {
+	stub := &stubOutlineService{
+		addResult: &outline.AddResult{
+			SID:        "NEWNODE12345",
+			MP:         "400",
+			Filename:   "400_NEWNODE12345_draft_new.md",
+			Rebalanced: map[string]string{"101.md": "700.md"},
+		},
+	}
+	adapter := &addAdapter{svc: stub}
+
+	result, err := adapter.Add(context.Background(), "New", true, Placement{NoRebalance: true})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stub.addOpts) != 1 {
+		t.Errorf("addOpts = %d, want 1 (no rebalance)", len(stub.addOpts))
+	}
+	want := []RenameEntry{{Old: "101.md", New: "700.md"}}
+	if !reflect.DeepEqual(result.Rebalanced, want) {
+		t.Errorf("Rebalanced = %v, want %v", result.Rebalanced, want)
+	}
+}
+
 func TestAddAdapter_ServiceError(t *testing.T) {
 	stub := &stubOutlineService{
 		addErr: errors.New("disk full"),
@@ -219,7 +453,7 @@ func TestCheckAdapter_ConvertsFindings(t *testing.T) {
 	}
 	adapter := &checkAdapter{svc: stub}
 
-	result, err := adapter.Check(context.Background())
+	result, err := adapter.Check(context.Background(), CheckFilter{})
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -235,6 +469,44 @@ func TestCheckAdapter_ConvertsFindings(t *testing.T) {
 	}
 }
 
+func TestCheckAdapter_PassesFilterAndLocation(t *testing.T) {
+	stub := &stubOutlineService{
+		checkResult: &outline.CheckResult{
+			Findings: []domain.Finding{
+				{Type: domain.FindingLint, Severity: domain.SeverityWarning, Message: "todo", Path: "a.md", Rule: "todo", Line: 3, Column: 5},
+			},
+		},
+	}
+	adapter := &checkAdapter{svc: stub}
+
+	tests := []struct {
+		name     string
+		filter   CheckFilter
+		wantOpts int
+	}{
+		{"no filter", CheckFilter{}, 0},
+		{"rules", CheckFilter{Rules: []string{"todo"}}, 1},
+		{"rules and skip rules", CheckFilter{Rules: []string{"todo"}, SkipRules: []string{"slug_drift"}}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := adapter.Check(context.Background(), tt.filter)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(stub.checkOpts) != tt.wantOpts {
+				t.Errorf("options = %d, want %d", len(stub.checkOpts), tt.wantOpts)
+			}
+			want := CheckFinding{Type: FindingLint, Severity: SeverityWarning, Message: "todo", Path: "a.md", Rule: "todo", Line: 3, Column: 5}
+			if result.Findings[0] != want {
+				t.Errorf("finding = %+v, want %+v", result.Findings[0], want)
+			}
+		})
+	}
+}
+
 // --- repairAdapter tests ---
 
 func TestRepairAdapter_ConvertsRepairs(t *testing.T) {
@@ -250,7 +522,7 @@ func TestRepairAdapter_ConvertsRepairs(t *testing.T) {
 	}
 	adapter := &repairAdapter{svc: stub}
 
-	result, err := adapter.Repair(context.Background())
+	result, err := adapter.Repair(context.Background(), RepairOptions{})
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -266,6 +538,33 @@ func TestRepairAdapter_ConvertsRepairs(t *testing.T) {
 	}
 }
 
+func TestRepairAdapter_PassesRepairOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     RepairOptions
+		wantOpts int
+	}{
+		{"default repairs only", RepairOptions{}, 0},
+		{"fix duplicates with keep", RepairOptions{FixDuplicates: true, Keep: []string{"200"}}, 2},
+		{"reparent orphans", RepairOptions{ReparentOrphans: true}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubOutlineService{repairResult: &outline.RepairResult{}}
+			adapter := &repairAdapter{svc: stub}
+
+			if _, err := adapter.Repair(context.Background(), tt.opts); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(stub.repairOpts) != tt.wantOpts {
+				t.Errorf("service options = %d, want %d", len(stub.repairOpts), tt.wantOpts)
+			}
+		})
+	}
+}
+
 // --- listAdapter tests ---
 
 func TestListAdapter_ConvertsOutline(t *testing.T) {
@@ -280,7 +579,7 @@ func TestListAdapter_ConvertsOutline(t *testing.T) {
 	}
 	adapter := &listAdapter{svc: stub}
 
-	result, err := adapter.List(context.Background())
+	result, err := adapter.List(context.Background(), "")
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -290,6 +589,49 @@ func TestListAdapter_ConvertsOutline(t *testing.T) {
 	}
 }
 
+func TestListAdapter_FiltersBySelector(t *testing.T) {
+	stub := &stubOutlineService{
+		loadResult: &outline.LoadResult{
+			Outline: domain.Outline{
+				Nodes: []domain.Node{
+					{MP: mustParseMP("100"), SID: "ABC", Title: "first"},
+					{MP: mustParseMP("100-100"), SID: "DEF", Title: "child"},
+					{MP: mustParseMP("200"), SID: "GHI", Title: "second"},
+				},
+			},
+		},
+		selectedNodes: []domain.Node{
+			{MP: mustParseMP("100-100"), SID: "DEF", Title: "The Child"},
+			{MP: mustParseMP("200"), SID: "GHI", Title: "second"},
+		},
+	}
+	adapter := &listAdapter{svc: stub}
+
+	result, err := adapter.List(context.Background(), "title:*")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stub.deleteExpr.String() != "title:*" {
+		t.Errorf("expr = %q, want %q", stub.deleteExpr, "title:*")
+	}
+	if len(result.Outline.Nodes) != 2 || result.Outline.Nodes[0].Title != "child" {
+		t.Errorf("nodes = %+v, want the loaded 100-100 and 200", result.Outline.Nodes)
+	}
+}
+
+func TestListAdapter_SelectorErrors(t *testing.T) {
+	stub := &stubOutlineService{loadResult: &outline.LoadResult{}, selectErr: domain.ErrNodeNotFound}
+	adapter := &listAdapter{svc: stub}
+
+	if _, err := adapter.List(context.Background(), "bad!selector"); !errors.Is(err, domain.ErrInvalidSelector) {
+		t.Errorf("List() error = %v, want ErrInvalidSelector", err)
+	}
+	if _, err := adapter.List(context.Background(), "100/*"); !errors.Is(err, domain.ErrNodeNotFound) {
+		t.Errorf("List() error = %v, want ErrNodeNotFound", err)
+	}
+}
+
 // --- deleteAdapter tests ---
 
 func TestDeleteAdapter_ConvertsSelectorAndMode(t *testing.T) {
@@ -317,6 +659,50 @@ func TestDeleteAdapter_ConvertsSelectorAndMode(t *testing.T) {
 	}
 }
 
+func TestDeleteAdapter_Archive(t *testing.T) {
+	stub := &stubOutlineService{
+		deleteResult: &outline.DeleteResult{FilesDeleted: []string{"100_ABC_draft_hello.md"}, TrashID: "20260301T120000Z"},
+	}
+	adapter := &deleteAdapter{svc: stub}
+
+	result, err := adapter.Archive(context.Background(), "100", domain.DeleteModeDefault, true)
+
+	if err != nil || result.TrashID != "20260301T120000Z" || !stub.archived {
+		t.Errorf("Archive() = %+v, %v; archived %v", result, err, stub.archived)
+	}
+	if _, err := adapter.Archive(context.Background(), "bad!selector", domain.DeleteModeDefault, true); err == nil {
+		t.Error("expected error for invalid selector")
+	}
+	stub.deleteErr = errors.New("trash failed")
+	if _, err := adapter.Archive(context.Background(), "100", domain.DeleteModeDefault, true); !errors.Is(err, stub.deleteErr) {
+		t.Errorf("Archive() error = %v", err)
+	}
+}
+
+func TestDeleteAdapter_Expression(t *testing.T) {
+	stub := &stubOutlineService{
+		deleteResult: &outline.DeleteResult{TrashID: "T1", TrashIDs: []string{"T1", "T2"}},
+	}
+	adapter := &deleteAdapter{svc: stub}
+
+	result, err := adapter.Archive(context.Background(), "100/**", domain.DeleteModeRecursive, false)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stub.deleteExpr.String() != "100/**" || stub.deleteMode != domain.DeleteModeRecursive || stub.deleteApply || !stub.archived {
+		t.Errorf("service called with %q, mode %v, apply %v, archived %v", stub.deleteExpr, stub.deleteMode, stub.deleteApply, stub.archived)
+	}
+	if len(result.TrashIDs) != 2 {
+		t.Errorf("TrashIDs = %v, want 2 entries", result.TrashIDs)
+	}
+
+	stub.deleteErr = domain.ErrNodeNotFound
+	if _, err := adapter.Delete(context.Background(), "100..200", domain.DeleteModeDefault, true); !errors.Is(err, domain.ErrNodeNotFound) {
+		t.Errorf("Delete() error = %v, want ErrNodeNotFound", err)
+	}
+}
+
 // --- moveAdapter tests ---
 
 func TestMoveAdapter_PassesThroughArgs(t *testing.T) {
@@ -327,7 +713,7 @@ func TestMoveAdapter_PassesThroughArgs(t *testing.T) {
 	}
 	adapter := &moveAdapter{svc: stub}
 
-	result, err := adapter.Move(context.Background(), "100", "200", "200-100", "", true)
+	result, err := adapter.Move(context.Background(), "100", "200", "200-100", "", true, true)
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -338,6 +724,32 @@ func TestMoveAdapter_PassesThroughArgs(t *testing.T) {
 	if len(result.Renames) != 1 {
 		t.Errorf("renames = %d, want 1", len(result.Renames))
 	}
+	if len(stub.moveOpts) != 0 {
+		t.Errorf("moveOpts = %d, want none when rebalancing", len(stub.moveOpts))
+	}
+}
+
+func TestMoveAdapter_ConvertsRebalanced(t *testing.T) {
+	stub := &stubOutlineService{
+		moveResult: &outline.MoveResult{
+			Renames:    map[string]string{"old.md": "new.md"},
+			Rebalanced: map[string]string{"101.md": "700.md"},
+		},
+	}
+	adapter := &moveAdapter{svc: stub}
+
+	result, err := adapter.Move(context.Background(), "100", "200", "", "", false, true)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stub.moveOpts) != 1 {
+		t.Errorf("moveOpts = %d, want 1 (no rebalance)", len(stub.moveOpts))
+	}
+	want := []RenameEntry{{Old: "101.md", New: "700.md"}}
+	if !reflect.DeepEqual(result.Rebalanced, want) {
+		t.Errorf("Rebalanced = %v, want %v", result.Rebalanced, want)
+	}
 }
 
 // --- renameAdapter tests ---
@@ -457,6 +869,308 @@ func TestCompactAdapter_Warning(t *testing.T) {
 	}
 }
 
+func TestCompactAdapter_WarningUsesConfiguredThreshold(t *testing.T) {
+	cfg := domain.DefaultConfig()
+	cfg.CompactWarningThreshold = 1
+	stub := &stubOutlineService{
+		config: &cfg,
+		compactResult: &outline.CompactResult{
+			Renames: map[string]string{"a.md": "b.md", "c.md": "d.md"},
+		},
+	}
+	adapter := &compactAdapter{svc: stub}
+
+	result, err := adapter.Compact(context.Background(), "", false)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Warning == nil {
+		t.Error("expected warning above configured threshold")
+	}
+}
+
+// --- mergeAdapter tests ---
+
+func TestMergeAdapter_Merge(t *testing.T) {
+	stub := &stubOutlineService{
+		mergeResult: &outline.MergeResult{
+			Written: []string{"100_SID001AABB_draft_a.md"},
+			Conflicts: []outline.MergeConflict{{
+				Kind: outline.MergeConflictMove, SID: "SID001AABB", Path: "100_SID001AABB_draft_a.md", Message: "moved",
+			}},
+		},
+	}
+	adapter := &mergeAdapter{svc: stub}
+
+	result, err := adapter.Merge(context.Background(), "base", "ours", "theirs", true)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, want := range []string{"base", "ours", "theirs"} {
+		if snap, ok := stub.mergeSides[i].(*fs.OSSnapshot); !ok || snap.Root != want {
+			t.Errorf("side %d = %#v, want snapshot of %q", i, stub.mergeSides[i], want)
+		}
+	}
+	if !stub.mergeApply {
+		t.Error("apply was not passed through")
+	}
+	want := MergeConflict{Kind: "move", SID: "SID001AABB", Path: "100_SID001AABB_draft_a.md", Message: "moved"}
+	if len(result.Written) != 1 || result.Deleted == nil || len(result.Conflicts) != 1 || result.Conflicts[0] != want {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestMergeAdapter_ResolveMerge(t *testing.T) {
+	stub := &stubOutlineService{mergeResult: &outline.MergeResult{Deleted: []string{"gone.md"}}}
+	adapter := &mergeAdapter{svc: stub}
+
+	result, err := adapter.ResolveMerge(context.Background(), false)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Deleted) != 1 || result.Written == nil || result.Conflicts == nil {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestMergeAdapter_PropagatesErrors(t *testing.T) {
+	svcErr := errors.New("merge failed")
+	adapter := &mergeAdapter{svc: &stubOutlineService{mergeErr: svcErr}}
+
+	if _, err := adapter.Merge(context.Background(), "a", "b", "c", true); !errors.Is(err, svcErr) {
+		t.Errorf("Merge() error = %v, want %v", err, svcErr)
+	}
+	if _, err := adapter.ResolveMerge(context.Background(), true); !errors.Is(err, svcErr) {
+		t.Errorf("ResolveMerge() error = %v, want %v", err, svcErr)
+	}
+}
+
+// --- diffAdapter tests ---
+
+func TestDiffAdapter_ConvertsResult(t *testing.T) {
+	stub := &stubOutlineService{diffResult: &outline.DiffResult{Nodes: []outline.NodeDiff{{
+		SID: "SID001AABB", Status: outline.DiffChanged, OldMP: "200", NewMP: "100-100",
+		NewParent: "SID002AABB", OldTitle: "Old", NewTitle: "New", Moved: true, Retitled: true,
+		DocsAdded: []string{"research"},
+	}}}}
+	adapter := &diffAdapter{svc: stub}
+
+	result, err := adapter.Diff(context.Background(), "HEAD", "")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stub.diffRefs != [2]string{"HEAD", ""} {
+		t.Errorf("refs = %v, want HEAD and the working tree", stub.diffRefs)
+	}
+	n := result.Nodes[0]
+	if n.Status != "changed" || n.OldMP != "200" || n.NewParent != "SID002AABB" || !n.Moved || !n.Retitled ||
+		len(n.DocsAdded) != 1 || n.DocsRemoved == nil || n.ContentChanged == nil {
+		t.Errorf("node = %+v", n)
+	}
+}
+
+func TestDiffAdapter_PropagatesError(t *testing.T) {
+	svcErr := errors.New("no such revision")
+	adapter := &diffAdapter{svc: &stubOutlineService{diffErr: svcErr}}
+
+	if _, err := adapter.Diff(context.Background(), "nope", ""); !errors.Is(err, svcErr) {
+		t.Errorf("error = %v, want %v", err, svcErr)
+	}
+}
+
+// --- snapshotAdapter tests ---
+
+func TestSnapshotAdapter_ConvertsResults(t *testing.T) {
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	info := outline.SnapshotInfo{ID: "20260301T120000Z", Created: created, Message: "m", Files: 3}
+	stub := &stubOutlineService{
+		snapshotInfo:  &info,
+		restoreResult: &outline.RestoreResult{Written: []string{"a.md"}},
+		diffResult:    &outline.DiffResult{Nodes: []outline.NodeDiff{{SID: "SID001AABB", Status: outline.DiffAdded}}},
+	}
+	adapter := &snapshotAdapter{svc: stub}
+	ctx := context.Background()
+
+	got, err := adapter.CreateSnapshot(ctx, "m", true)
+	if err != nil || *got != (SnapshotInfo{ID: info.ID, Created: created, Message: "m", Files: 3}) {
+		t.Errorf("CreateSnapshot() = %+v, %v", got, err)
+	}
+
+	list, err := adapter.ListSnapshots(ctx)
+	if err != nil || list == nil || len(list) != 0 {
+		t.Errorf("ListSnapshots() = %#v, %v; want an empty, non-nil list", list, err)
+	}
+
+	restored, err := adapter.RestoreSnapshot(ctx, info.ID, "SID001AABB", false)
+	if err != nil || len(restored.Written) != 1 || restored.Deleted == nil {
+		t.Errorf("RestoreSnapshot() = %+v, %v", restored, err)
+	}
+	if fmt.Sprint(stub.snapshotArgs) != "[20260301T120000Z SID001AABB false]" {
+		t.Errorf("restore args = %v", stub.snapshotArgs)
+	}
+
+	diff, err := adapter.DiffSnapshots(ctx, info.ID, "")
+	if err != nil || len(diff.Nodes) != 1 || diff.Nodes[0].Status != "added" {
+		t.Errorf("DiffSnapshots() = %+v, %v", diff, err)
+	}
+}
+
+func TestSnapshotAdapter_ListConvertsEachSnapshot(t *testing.T) {
+	stub := &stubOutlineService{snapshotInfos: []outline.SnapshotInfo{{ID: "a"}, {ID: "b"}}}
+
+	list, err := (&snapshotAdapter{svc: stub}).ListSnapshots(context.Background())
+
+	if err != nil || len(list) != 2 || list[1].ID != "b" {
+		t.Errorf("ListSnapshots() = %+v, %v", list, err)
+	}
+}
+
+func TestSnapshotAdapter_PropagatesErrors(t *testing.T) {
+	svcErr := errors.New("no such snapshot")
+	adapter := &snapshotAdapter{svc: &stubOutlineService{snapshotErr: svcErr}}
+	ctx := context.Background()
+
+	if _, err := adapter.CreateSnapshot(ctx, "", true); !errors.Is(err, svcErr) {
+		t.Errorf("CreateSnapshot() error = %v", err)
+	}
+	if _, err := adapter.ListSnapshots(ctx); !errors.Is(err, svcErr) {
+		t.Errorf("ListSnapshots() error = %v", err)
+	}
+	if _, err := adapter.RestoreSnapshot(ctx, "x", "", true); !errors.Is(err, svcErr) {
+		t.Errorf("RestoreSnapshot() error = %v", err)
+	}
+	if _, err := adapter.DiffSnapshots(ctx, "x", ""); !errors.Is(err, svcErr) {
+		t.Errorf("DiffSnapshots() error = %v", err)
+	}
+}
+
+// --- trashAdapter tests ---
+
+func TestTrashAdapter_ConvertsResults(t *testing.T) {
+	deleted := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	entry := outline.TrashEntry{
+		ID: "20260301T120000Z", Deleted: deleted, SID: "SID002CCDD", MP: "100-200",
+		ParentSID: "SID001AABB", Slug: "scene", Files: []string{"100-200_SID002CCDD_draft_scene.md"},
+	}
+	stub := &stubOutlineService{
+		trashEntries: []outline.TrashEntry{entry},
+		trashRestore: &outline.TrashRestoreResult{ID: entry.ID, SID: entry.SID, MP: "100-300", Renames: map[string]string{"a": "b"}},
+	}
+	adapter := &trashAdapter{svc: stub}
+	ctx := context.Background()
+
+	list, err := adapter.ListTrash(ctx)
+	want := TrashEntry{ID: entry.ID, Deleted: deleted, SID: entry.SID, MP: entry.MP, ParentSID: entry.ParentSID, Slug: "scene"}
+	if err != nil || len(list) != 1 || !reflect.DeepEqual(list[0].Files, entry.Files) {
+		t.Fatalf("ListTrash() = %+v, %v", list, err)
+	}
+	if list[0].Files = nil; !reflect.DeepEqual(list[0], want) {
+		t.Errorf("ListTrash()[0] = %+v, want %+v", list[0], want)
+	}
+
+	restored, err := adapter.RestoreTrash(ctx, entry.ID, false)
+	if err != nil || restored.MP != "100-300" || restored.Renames["a"] != "b" || fmt.Sprint(stub.trashArgs) != "[20260301T120000Z false]" {
+		t.Errorf("RestoreTrash() = %+v, %v; args %v", restored, err, stub.trashArgs)
+	}
+
+	stub.trashEntries = nil
+	emptied, err := adapter.EmptyTrash(ctx, true)
+	if err != nil || emptied == nil || len(emptied) != 0 || fmt.Sprint(stub.trashArgs) != "[true]" {
+		t.Errorf("EmptyTrash() = %#v, %v; want an empty, non-nil list", emptied, err)
+	}
+}
+
+func TestTrashAdapter_PropagatesErrors(t *testing.T) {
+	svcErr := errors.New("no such trash entry")
+	adapter := &trashAdapter{svc: &stubOutlineService{trashErr: svcErr}}
+	ctx := context.Background()
+
+	if _, err := adapter.ListTrash(ctx); !errors.Is(err, svcErr) {
+		t.Errorf("ListTrash() error = %v", err)
+	}
+	if _, err := adapter.RestoreTrash(ctx, "x", true); !errors.Is(err, svcErr) {
+		t.Errorf("RestoreTrash() error = %v", err)
+	}
+	if _, err := adapter.EmptyTrash(ctx, true); !errors.Is(err, svcErr) {
+		t.Errorf("EmptyTrash() error = %v", err)
+	}
+}
+
+// --- configAdapter tests ---
+
+func TestConfigAdapter_ListConfig(t *testing.T) {
+	adapter := &configAdapter{svc: &stubOutlineService{}}
+
+	entries, err := adapter.ListConfig(context.Background())
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != len(domain.ConfigKeys()) {
+		t.Fatalf("entries = %d, want %d", len(entries), len(domain.ConfigKeys()))
+	}
+	if entries[0].Key != "doc_types.required" || entries[0].Value != "draft,notes" {
+		t.Errorf("entries[0] = %+v, want doc_types.required=draft,notes", entries[0])
+	}
+}
+
+func TestConfigAdapter_GetConfig(t *testing.T) {
+	adapter := &configAdapter{svc: &stubOutlineService{}}
+
+	entry, err := adapter.GetConfig(context.Background(), "sid.length")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entry.Value != "12" {
+		t.Errorf("value = %q, want 12", entry.Value)
+	}
+}
+
+func TestConfigAdapter_GetConfig_UnknownKey(t *testing.T) {
+	adapter := &configAdapter{svc: &stubOutlineService{}}
+
+	_, err := adapter.GetConfig(context.Background(), "bogus")
+
+	if !errors.Is(err, domain.ErrUnknownConfigKey) {
+		t.Errorf("error = %v, want ErrUnknownConfigKey", err)
+	}
+}
+
+func TestConfigAdapter_SetConfig(t *testing.T) {
+	stub := &stubOutlineService{
+		setConfigRes: &outline.ConfigResult{Key: "sid.length", OldValue: "12", NewValue: "10"},
+	}
+	adapter := &configAdapter{svc: stub}
+
+	result, err := adapter.SetConfig(context.Background(), "sid.length", "10", true)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stub.setKey != "sid.length" || stub.setValue != "10" || !stub.setApply {
+		t.Errorf("SetConfig called with %q=%q apply=%v", stub.setKey, stub.setValue, stub.setApply)
+	}
+	if result.OldValue != "12" || result.NewValue != "10" {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestConfigAdapter_SetConfig_Error(t *testing.T) {
+	stub := &stubOutlineService{setConfigErr: domain.ErrInvalidConfig}
+	adapter := &configAdapter{svc: stub}
+
+	_, err := adapter.SetConfig(context.Background(), "sid.length", "3", true)
+
+	if !errors.Is(err, domain.ErrInvalidConfig) {
+		t.Errorf("error = %v, want ErrInvalidConfig", err)
+	}
+}
+
 // --- typesAdapter tests ---
 
 func TestTypesAdapter_ListTypes(t *testing.T) {
@@ -492,7 +1206,7 @@ func TestTypesAdapter_AddType(t *testing.T) {
 	}
 	adapter := &typesAdapter{svc: stub}
 
-	result, err := adapter.AddType(context.Background(), "notes", "100", true)
+	result, err := adapter.AddType(context.Background(), "notes", "100", "", true)
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -502,6 +1216,32 @@ func TestTypesAdapter_AddType(t *testing.T) {
 	}
 }
 
+func TestTypesAdapter_AddTypeWithTemplate(t *testing.T) {
+	stub := &stubOutlineService{
+		addTypeResult: &outline.ModifyResult{Filename: "100_ABC_beats.md", NodeMP: "100", NodeSID: "ABC"},
+	}
+	adapter := &typesAdapter{svc: stub}
+
+	_, err := adapter.AddType(context.Background(), "beats", "100", "beats-short", true)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stub.addTypeOpts) != 1 {
+		t.Errorf("addTypeOpts = %d, want 1 (template)", len(stub.addTypeOpts))
+	}
+}
+
+func TestTypesAdapter_AddTypeInvalidTemplate(t *testing.T) {
+	adapter := &typesAdapter{svc: &stubOutlineService{}}
+
+	_, err := adapter.AddType(context.Background(), "beats", "100", "../escape", false)
+
+	if !errors.Is(err, domain.ErrInvalidTemplateName) {
+		t.Errorf("error = %v, want ErrInvalidTemplateName", err)
+	}
+}
+
 func TestTypesAdapter_RemoveType(t *testing.T) {
 	stub := &stubOutlineService{
 		removeTypeResult: &outline.ModifyResult{
@@ -524,6 +1264,98 @@ func TestTypesAdapter_RemoveType(t *testing.T) {
 
 // --- parentMP tests ---
 
+func typesBatchStub() *stubOutlineService {
+	return &stubOutlineService{
+		selectedNodes: []domain.Node{
+			{MP: mustParseMP("100"), SID: "AAAAAAAAAAAA", Documents: []domain.Document{{Type: "draft"}, {Type: "notes"}}},
+			{MP: mustParseMP("100-100"), SID: "BBBBBBBBBBBB", Documents: []domain.Document{{Type: "draft"}}},
+		},
+		addTypeResult:    &outline.ModifyResult{Filename: "100-100_BBBBBBBBBBBB_notes.md"},
+		removeTypeResult: &outline.ModifyResult{Filename: "100_AAAAAAAAAAAA_notes.md"},
+	}
+}
+
+func TestTypesAdapter_AddTypes(t *testing.T) {
+	stub := typesBatchStub()
+	adapter := &typesAdapter{svc: stub}
+
+	result, err := adapter.AddTypes(context.Background(), "notes", "100/**", "scene", true)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Changed) != 1 || result.Changed[0].Filename != "100-100_BBBBBBBBBBBB_notes.md" || result.Planned {
+		t.Errorf("Changed = %+v, planned %v", result.Changed, result.Planned)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0].MP != "100" {
+		t.Errorf("Skipped = %+v, want 100", result.Skipped)
+	}
+	if len(stub.typeTargets) != 1 || stub.typeTargets[0] != "100-100" || len(stub.addTypeOpts) != 1 {
+		t.Errorf("AddType called for %v with %d options", stub.typeTargets, len(stub.addTypeOpts))
+	}
+}
+
+func TestTypesAdapter_RemoveTypes_DryRun(t *testing.T) {
+	stub := typesBatchStub()
+	adapter := &typesAdapter{svc: stub}
+
+	result, err := adapter.RemoveTypes(context.Background(), "notes", "100/**", false)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Changed) != 1 || result.Changed[0].Filename != "100_AAAAAAAAAAAA_notes.md" || !result.Planned || !result.Changed[0].Planned {
+		t.Errorf("result = %+v", result)
+	}
+	if len(stub.typeTargets) != 0 {
+		t.Errorf("RemoveType called for %v during a dry run", stub.typeTargets)
+	}
+}
+
+func TestTypesAdapter_BatchErrors(t *testing.T) {
+	ctx := context.Background()
+	modifyErr := errors.New("write failed")
+
+	tests := []struct {
+		name string
+		call func(*typesAdapter) error
+		want error
+	}{
+		{"invalid template", func(a *typesAdapter) error {
+			_, err := a.AddTypes(ctx, "notes", "100/*", "../x", true)
+			return err
+		}, domain.ErrInvalidTemplateName},
+		{"invalid type", func(a *typesAdapter) error {
+			_, err := a.RemoveTypes(ctx, "bad type", "100/*", true)
+			return err
+		}, domain.ErrInvalidDocType},
+		{"invalid selector", func(a *typesAdapter) error {
+			_, err := a.RemoveTypes(ctx, "notes", "bad!/*", true)
+			return err
+		}, domain.ErrInvalidSelector},
+		{"select fails", func(a *typesAdapter) error {
+			a.svc.(*stubOutlineService).selectErr = domain.ErrNodeNotFound
+			_, err := a.AddTypes(ctx, "notes", "100/*", "", true)
+			return err
+		}, domain.ErrNodeNotFound},
+		{"modify fails", func(a *typesAdapter) error {
+			a.svc.(*stubOutlineService).removeTypeErr = modifyErr
+			_, err := a.RemoveTypes(ctx, "notes", "100/*", true)
+			return err
+		}, modifyErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call(&typesAdapter{svc: typesBatchStub()})
+
+			if !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
 func TestParentMP_RootLevel(t *testing.T) {
 	if got := parentMP("100"); got != "" {
 		t.Errorf("parentMP(%q) = %q, want empty", "100", got)
@@ -664,7 +1496,7 @@ func TestCheckAdapter_ServiceError(t *testing.T) {
 	stub := &stubOutlineService{checkErr: errors.New("check failed")}
 	adapter := &checkAdapter{svc: stub}
 
-	_, err := adapter.Check(context.Background())
+	_, err := adapter.Check(context.Background(), CheckFilter{})
 
 	if err == nil {
 		t.Fatal("expected error")
@@ -675,7 +1507,7 @@ func TestRepairAdapter_ServiceError(t *testing.T) {
 	stub := &stubOutlineService{repairErr: errors.New("repair failed")}
 	adapter := &repairAdapter{svc: stub}
 
-	_, err := adapter.Repair(context.Background())
+	_, err := adapter.Repair(context.Background(), RepairOptions{})
 
 	if err == nil {
 		t.Fatal("expected error")
@@ -686,7 +1518,7 @@ func TestListAdapter_ServiceError(t *testing.T) {
 	stub := &stubOutlineService{loadErr: errors.New("load failed")}
 	adapter := &listAdapter{svc: stub}
 
-	_, err := adapter.List(context.Background())
+	_, err := adapter.List(context.Background(), "")
 
 	if err == nil {
 		t.Fatal("expected error")
@@ -717,7 +1549,7 @@ func TestDeleteAdapter_ServiceError(t *testing.T) {
 func TestMoveAdapter_InvalidSourceSelector(t *testing.T) {
 	adapter := &moveAdapter{svc: &stubOutlineService{}}
 
-	_, err := adapter.Move(context.Background(), "bad!selector", "200", "", "", true)
+	_, err := adapter.Move(context.Background(), "bad!selector", "200", "", "", true, true)
 
 	if err == nil {
 		t.Fatal("expected error for invalid source selector")
@@ -727,7 +1559,7 @@ func TestMoveAdapter_InvalidSourceSelector(t *testing.T) {
 func TestMoveAdapter_InvalidTargetSelector(t *testing.T) {
 	adapter := &moveAdapter{svc: &stubOutlineService{}}
 
-	_, err := adapter.Move(context.Background(), "100", "bad!selector", "", "", true)
+	_, err := adapter.Move(context.Background(), "100", "bad!selector", "", "", true, true)
 
 	if err == nil {
 		t.Fatal("expected error for invalid target selector")
@@ -738,7 +1570,7 @@ func TestMoveAdapter_ServiceError(t *testing.T) {
 	stub := &stubOutlineService{moveErr: errors.New("move failed")}
 	adapter := &moveAdapter{svc: stub}
 
-	_, err := adapter.Move(context.Background(), "100", "200", "", "", true)
+	_, err := adapter.Move(context.Background(), "100", "200", "", "", true, true)
 
 	if err == nil {
 		t.Fatal("expected error")
@@ -782,7 +1614,7 @@ func TestTypesAdapter_AddTypeError(t *testing.T) {
 	stub := &stubOutlineService{addTypeErr: errors.New("add failed")}
 	adapter := &typesAdapter{svc: stub}
 
-	_, err := adapter.AddType(context.Background(), "notes", "100", true)
+	_, err := adapter.AddType(context.Background(), "notes", "100", "", true)
 
 	if err == nil {
 		t.Fatal("expected error")
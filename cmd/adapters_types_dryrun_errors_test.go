@@ -15,7 +15,7 @@ import (
 func TestTypesAdapter_AddType_DryRun_InvalidDocType(t *testing.T) {
 	adapter := &typesAdapter{svc: &stubOutlineService{}}
 
-	_, err := adapter.AddType(context.Background(), "INVALID", "100", false)
+	_, err := adapter.AddType(context.Background(), "INVALID", "100", "", false)
 
 	if err == nil {
 		t.Fatal("expected error for invalid doc type, got nil")
@@ -25,7 +25,7 @@ func TestTypesAdapter_AddType_DryRun_InvalidDocType(t *testing.T) {
 func TestTypesAdapter_AddType_DryRun_InvalidSelector(t *testing.T) {
 	adapter := &typesAdapter{svc: &stubOutlineService{}}
 
-	_, err := adapter.AddType(context.Background(), "characters", "", false)
+	_, err := adapter.AddType(context.Background(), "characters", "", "", false)
 
 	if err == nil {
 		t.Fatal("expected error for empty selector, got nil")
@@ -36,7 +36,7 @@ func TestTypesAdapter_AddType_DryRun_ResolveError(t *testing.T) {
 	stub := &stubOutlineService{resolveErr: errors.New("node not found")}
 	adapter := &typesAdapter{svc: stub}
 
-	_, err := adapter.AddType(context.Background(), "characters", "100", false)
+	_, err := adapter.AddType(context.Background(), "characters", "100", "", false)
 
 	if err == nil {
 		t.Fatal("expected error from ResolveSelector, got nil")
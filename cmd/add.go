@@ -18,18 +18,23 @@ type AddNodeInfo struct {
 
 // AddResult holds the outcome of an add operation.
 type AddResult struct {
-	Node         AddNodeInfo `json:"node"`
-	FilesCreated []string    `json:"files_created"`
-	FilesPlanned []string    `json:"files_planned"`
-	Planned      bool        `json:"planned"`
+	Node         AddNodeInfo   `json:"node"`
+	FilesCreated []string      `json:"files_created"`
+	FilesPlanned []string      `json:"files_planned"`
+	Rebalanced   []RenameEntry `json:"rebalanced"`
+	Planned      bool          `json:"planned"`
 }
 
-// Placement holds options for positioning a new node relative to existing nodes.
+// Placement holds options for positioning a new node relative to existing nodes,
+// and the template used to fill its draft. NoRebalance makes a full gap an
+// error instead of renumbering neighbouring siblings.
 type Placement struct {
-	ChildOf   string
-	SiblingOf string
-	Before    string
-	After     string
+	ChildOf     string
+	SiblingOf   string
+	Before      string
+	After       string
+	Template    string
+	NoRebalance bool
 }
 
 // AddRunner defines the interface for running the add operation.
@@ -44,6 +49,8 @@ func NewAddCmd(runner AddRunner) *cobra.Command {
 	var siblingOf string
 	var before string
 	var after string
+	var template string
+	var noRebalance bool
 
 	cmd := &cobra.Command{
 		Use:          "add <title>",
@@ -82,10 +89,12 @@ func NewAddCmd(runner AddRunner) *cobra.Command {
 
 			isDryRun := GetDryRun()
 			placement := Placement{
-				ChildOf:   childOf,
-				SiblingOf: siblingOf,
-				Before:    before,
-				After:     after,
+				ChildOf:     childOf,
+				SiblingOf:   siblingOf,
+				Before:      before,
+				After:       after,
+				Template:    template,
+				NoRebalance: noRebalance,
 			}
 			result, err := runner.Add(cmd.Context(), args[0], !isDryRun, placement)
 			if err != nil {
@@ -99,10 +108,16 @@ func NewAddCmd(runner AddRunner) *cobra.Command {
 			if jsonOutput || GetJSON() {
 				writeJSON(cmd.OutOrStdout(), result)
 			} else if isDryRun {
+				for _, r := range result.Rebalanced {
+					fmt.Fprintf(cmd.OutOrStdout(), "Would renumber %s -> %s\n", r.Old, r.New)
+				}
 				for _, f := range result.FilesPlanned {
 					fmt.Fprintf(cmd.OutOrStdout(), "Would create %s\n", f)
 				}
 			} else {
+				for _, r := range result.Rebalanced {
+					fmt.Fprintf(cmd.OutOrStdout(), "Renumbered %s -> %s\n", r.Old, r.New)
+				}
 				for _, f := range result.FilesCreated {
 					fmt.Fprintf(cmd.OutOrStdout(), "Created %s\n", f)
 				}
@@ -116,6 +131,8 @@ func NewAddCmd(runner AddRunner) *cobra.Command {
 	cmd.Flags().StringVar(&siblingOf, "sibling-of", "", "Add immediately after the specified node")
 	cmd.Flags().StringVar(&before, "before", "", "Insert before the specified sibling node")
 	cmd.Flags().StringVar(&after, "after", "", "Insert after the specified sibling node")
+	cmd.Flags().StringVar(&template, "template", "", "Fill the draft from .linemark/templates/<name>.md")
+	cmd.Flags().BoolVar(&noRebalance, "no-rebalance", false, "Fail instead of renumbering neighbouring siblings when no gap exists")
 
 	return cmd
 }
@@ -742,3 +742,56 @@ func TestAddCmd_PlacementFlagRejectsInvalidSelector(t *testing.T) {
 		})
 	}
 }
+
+func TestAddCmd_TemplateFlag(t *testing.T) {
+	runner := &mockAddRunner{result: chapterOneResult()}
+	cmd, _ := newTestAddCmd(runner, "--template", "chapter/draft", "Chapter One")
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if runner.placement.Template != "chapter/draft" {
+		t.Errorf("template = %q, want %q", runner.placement.Template, "chapter/draft")
+	}
+}
+
+func TestAddCmd_NoRebalanceFlag(t *testing.T) {
+	runner := &mockAddRunner{result: chapterOneResult()}
+	cmd, _ := newTestAddCmd(runner, "--no-rebalance", "Chapter One")
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !runner.placement.NoRebalance {
+		t.Error("placement.NoRebalance should be set by --no-rebalance")
+	}
+}
+
+func TestAddCmd_HumanReadableRebalanced(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"applied", []string{"add", "Chapter One"}, "Renumbered 101_B8kQ2mNp4Rs1_draft_two.md -> 700_B8kQ2mNp4Rs1_draft_two.md\n"},
+		{"dry run", []string{"add", "--dry-run", "Chapter One"}, "Would renumber 101_B8kQ2mNp4Rs1_draft_two.md -> 700_B8kQ2mNp4Rs1_draft_two.md\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(func() { dryRun, jsonFlag = false, false })
+			result := chapterOneResult()
+			result.Rebalanced = []RenameEntry{{Old: "101_B8kQ2mNp4Rs1_draft_two.md", New: "700_B8kQ2mNp4Rs1_draft_two.md"}}
+			root, buf := newTestRootAddCmd(&mockAddRunner{result: result}, tt.args...)
+
+			if err := root.Execute(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.Contains(buf.String(), tt.want) {
+				t.Errorf("output = %q, want it to contain %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/eykd/linemark-go/internal/domain"
 	"github.com/spf13/cobra"
 )
 
@@ -28,6 +29,20 @@ const (
 	FindingOrphanedReservation FindingType = "orphaned_reservation"
 	// FindingUnreservedSID indicates a SID is in use but not reserved.
 	FindingUnreservedSID FindingType = "unreserved_sid"
+	// FindingInvalidConfig indicates the project config file is malformed or invalid.
+	FindingInvalidConfig FindingType = "invalid_config"
+	// FindingMissingField indicates a required frontmatter field is absent or empty.
+	FindingMissingField FindingType = "missing_field"
+	// FindingInvalidField indicates a frontmatter field has a value outside its allowed list.
+	FindingInvalidField FindingType = "invalid_field"
+	// FindingLint indicates a prose lint rule flagged draft text.
+	FindingLint FindingType = "lint"
+	// FindingOrphanedNode indicates a node whose parent MP has no files.
+	FindingOrphanedNode FindingType = "orphaned_node"
+	// FindingDuplicateMP indicates two nodes share one MP, typically after a merge.
+	FindingDuplicateMP FindingType = "duplicate_mp"
+	// FindingSegmentWidth indicates an MP segment narrower or wider than numbering.width.
+	FindingSegmentWidth FindingType = "segment_width"
 )
 
 // Severity represents the severity level of a check finding.
@@ -42,20 +57,49 @@ const (
 
 // CheckFinding represents a single finding from the check command.
 type CheckFinding struct {
-	Type     FindingType `json:"type"`
-	Severity Severity    `json:"severity"`
-	Message  string      `json:"message"`
-	Path     string      `json:"path"`
+	Type      FindingType `json:"type"`
+	Severity  Severity    `json:"severity"`
+	Message   string      `json:"message"`
+	Path      string      `json:"path"`
+	Rule      string      `json:"rule,omitempty"`
+	Line      int         `json:"line,omitempty"`
+	Column    int         `json:"column,omitempty"`
+	EndLine   int         `json:"end_line,omitempty"`
+	EndColumn int         `json:"end_column,omitempty"`
 }
 
-// CheckResult holds all findings from a check run.
+// ruleID returns the finding's lint rule, or its type for structural findings.
+func (f CheckFinding) ruleID() string {
+	if f.Rule != "" {
+		return f.Rule
+	}
+	return string(f.Type)
+}
+
+// CheckResult holds all findings from a check run. Suppressed counts
+// findings hidden by the baseline or lmk_ignore frontmatter.
 type CheckResult struct {
-	Findings []CheckFinding `json:"findings"`
+	Findings   []CheckFinding `json:"findings"`
+	Suppressed int            `json:"-"`
+}
+
+// CheckFilter selects which findings a check reports. Rules and SkipRules
+// hold lint rule IDs or finding types; an empty Rules means all rules.
+// IgnoreBaseline reports findings listed in the baseline.
+type CheckFilter struct {
+	Rules          []string
+	SkipRules      []string
+	IgnoreBaseline bool
 }
 
 // CheckRunner defines the interface for running project checks.
 type CheckRunner interface {
-	Check(ctx context.Context) (*CheckResult, error)
+	Check(ctx context.Context, filter CheckFilter) (*CheckResult, error)
+}
+
+// BaselineWriter defines the interface for recording current findings as the baseline.
+type BaselineWriter interface {
+	WriteBaseline(ctx context.Context, filter CheckFilter) (*CheckResult, error)
 }
 
 // FindingsDetectedError is returned when check detects findings.
@@ -88,9 +132,16 @@ type RepairResult struct {
 	Unrepaired []CheckFinding `json:"unrepaired"`
 }
 
+// RepairOptions selects the opt-in repairs doctor --apply performs.
+type RepairOptions struct {
+	FixDuplicates   bool
+	Keep            []string
+	ReparentOrphans bool
+}
+
 // RepairRunner defines the interface for running project repairs.
 type RepairRunner interface {
-	Repair(ctx context.Context) (*RepairResult, error)
+	Repair(ctx context.Context, opts RepairOptions) (*RepairResult, error)
 }
 
 // UnrepairedError is returned when repair leaves unresolved findings.
@@ -112,8 +163,9 @@ func (e *UnrepairedError) ExitCode() int {
 type checkJSONResponse struct {
 	Findings []CheckFinding `json:"findings"`
 	Summary  struct {
-		Errors   int `json:"errors"`
-		Warnings int `json:"warnings"`
+		Errors     int `json:"errors"`
+		Warnings   int `json:"warnings"`
+		Suppressed int `json:"suppressed"`
 	} `json:"summary"`
 }
 
@@ -130,40 +182,51 @@ func countBySeverity(findings []CheckFinding) (errCount, warnCount int) {
 }
 
 // formatCheckJSON writes findings as JSON to w.
-func formatCheckJSON(w io.Writer, findings []CheckFinding, errCount, warnCount int) {
+func formatCheckJSON(w io.Writer, findings []CheckFinding, errCount, warnCount, suppressed int) {
 	if findings == nil {
 		findings = []CheckFinding{}
 	}
 	out := checkJSONResponse{Findings: findings}
 	out.Summary.Errors = errCount
 	out.Summary.Warnings = warnCount
+	out.Summary.Suppressed = suppressed
 	writeJSON(w, out)
 }
 
 // formatCheckHuman writes findings as human-readable text to w.
-func formatCheckHuman(w io.Writer, findings []CheckFinding, errCount, warnCount int) {
+func formatCheckHuman(w io.Writer, findings []CheckFinding, errCount, warnCount, suppressed int) {
 	for _, f := range findings {
-		fmt.Fprintf(w, "%s [%s] %s: %s\n", f.Path, f.Severity, f.Type, f.Message)
+		location := f.Path
+		if f.Line > 0 {
+			location = fmt.Sprintf("%s:%d:%d", f.Path, f.Line, f.Column)
+		}
+		fmt.Fprintf(w, "%s [%s] %s: %s\n", location, f.Severity, f.ruleID(), f.Message)
 	}
 	if errCount > 0 || warnCount > 0 {
 		fmt.Fprintf(w, "\n%d error(s), %d warning(s)\n", errCount, warnCount)
 	}
+	if suppressed > 0 {
+		fmt.Fprintf(w, "%d known finding(s) suppressed\n", suppressed)
+	}
 }
 
-// runCheckAndReport runs the checker and formats findings as JSON or human-readable text.
+// runCheckAndReport runs the checker and formats findings as text, JSON or SARIF.
 // It returns a FindingsDetectedError if any findings are present.
-func runCheckAndReport(ctx context.Context, w io.Writer, runner CheckRunner, jsonOutput bool) error {
-	result, err := runner.Check(ctx)
+func runCheckAndReport(ctx context.Context, w io.Writer, runner CheckRunner, filter CheckFilter, format outputFormat) error {
+	result, err := runner.Check(ctx, filter)
 	if err != nil {
 		return err
 	}
 
 	errCount, warnCount := countBySeverity(result.Findings)
 
-	if jsonOutput {
-		formatCheckJSON(w, result.Findings, errCount, warnCount)
-	} else {
-		formatCheckHuman(w, result.Findings, errCount, warnCount)
+	switch format {
+	case formatJSON:
+		formatCheckJSON(w, result.Findings, errCount, warnCount, result.Suppressed)
+	case formatSARIF:
+		formatFindingsSARIF(w, result.Findings)
+	default:
+		formatCheckHuman(w, result.Findings, errCount, warnCount, result.Suppressed)
 	}
 
 	if len(result.Findings) > 0 {
@@ -172,9 +235,38 @@ func runCheckAndReport(ctx context.Context, w io.Writer, runner CheckRunner, jso
 	return nil
 }
 
-// NewCheckCmd creates the check command with the given runner.
-func NewCheckCmd(runner CheckRunner) *cobra.Command {
+// baselineJSONResponse is the JSON output structure for check --write-baseline.
+type baselineJSONResponse struct {
+	Baseline string `json:"baseline"`
+	Findings int    `json:"findings"`
+}
+
+// runWriteBaseline records the current findings as the baseline and reports how many.
+func runWriteBaseline(ctx context.Context, w io.Writer, writer BaselineWriter, filter CheckFilter, jsonOutput bool) error {
+	result, err := writer.WriteBaseline(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if jsonOutput {
+		writeJSON(w, baselineJSONResponse{Baseline: domain.BaselinePath, Findings: len(result.Findings)})
+	} else {
+		fmt.Fprintf(w, "Wrote %d finding(s) to %s\n", len(result.Findings), domain.BaselinePath)
+	}
+	return nil
+}
+
+// NewCheckCmd creates the check command with the given runner. The optional
+// writer enables --write-baseline.
+func NewCheckCmd(runner CheckRunner, writers ...BaselineWriter) *cobra.Command {
 	var jsonOutput bool
+	var format string
+	var filter CheckFilter
+	var writeBaseline bool
+
+	var writer BaselineWriter
+	if len(writers) > 0 {
+		writer = writers[0]
+	}
 
 	cmd := &cobra.Command{
 		Use:          "check",
@@ -184,11 +276,29 @@ func NewCheckCmd(runner CheckRunner) *cobra.Command {
 			if runner == nil {
 				return ErrNotInProject
 			}
-			return runCheckAndReport(cmd.Context(), cmd.OutOrStdout(), runner, jsonOutput || GetJSON())
+			f, err := resolveFormat(format, jsonOutput || GetJSON())
+			if err != nil {
+				return err
+			}
+			if writeBaseline {
+				if len(filter.Rules) > 0 || len(filter.SkipRules) > 0 {
+					return fmt.Errorf("--write-baseline records every finding and cannot be combined with --rule or --skip-rule")
+				}
+				if writer == nil {
+					return ErrNotInProject
+				}
+				return runWriteBaseline(cmd.Context(), cmd.OutOrStdout(), writer, filter, f == formatJSON)
+			}
+			return runCheckAndReport(cmd.Context(), cmd.OutOrStdout(), runner, filter, f)
 		},
 	}
 
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output results as JSON")
+	cmd.Flags().StringVar(&format, "format", "", "Output format: text, json or sarif")
+	cmd.Flags().StringSliceVar(&filter.Rules, "rule", nil, "Only report these rules (lint rule IDs or finding types)")
+	cmd.Flags().StringSliceVar(&filter.SkipRules, "skip-rule", nil, "Do not report these rules")
+	cmd.Flags().BoolVar(&writeBaseline, "write-baseline", false, "Record current findings as known; later runs report only new ones")
+	cmd.Flags().BoolVar(&filter.IgnoreBaseline, "no-baseline", false, "Report findings listed in the baseline too")
 
 	return cmd
 }
@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/eykd/linemark-go/internal/domain"
+	"github.com/eykd/linemark-go/internal/outline"
+)
+
+// mockBaselineWriter is a test double for BaselineWriter.
+type mockBaselineWriter struct {
+	result *CheckResult
+	err    error
+	filter CheckFilter
+	called bool
+}
+
+func (m *mockBaselineWriter) WriteBaseline(ctx context.Context, filter CheckFilter) (*CheckResult, error) {
+	m.called = true
+	m.filter = filter
+	return m.result, m.err
+}
+
+func TestCheckCmd_WriteBaseline(t *testing.T) {
+	findings := []CheckFinding{{Type: FindingSlugDrift, Severity: SeverityWarning, Message: "drift", Path: "a.md"}}
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"human", []string{"--write-baseline"}, "Wrote 1 finding(s) to .linemark/baseline\n"},
+		{"json", []string{"--write-baseline", "--json"}, `{"baseline":".linemark/baseline","findings":1}` + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &mockCheckRunner{result: &CheckResult{}}
+			writer := &mockBaselineWriter{result: &CheckResult{Findings: findings}}
+			cmd := NewCheckCmd(runner, writer)
+			buf := new(bytes.Buffer)
+			cmd.SetOut(buf)
+			cmd.SetArgs(tt.args)
+
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if buf.String() != tt.want {
+				t.Errorf("output = %q, want %q", buf.String(), tt.want)
+			}
+			if !writer.called {
+				t.Error("baseline not written")
+			}
+		})
+	}
+}
+
+func TestCheckCmd_WriteBaseline_Errors(t *testing.T) {
+	writeErr := errors.New("disk full")
+
+	tests := []struct {
+		name    string
+		writers []BaselineWriter
+		wantErr error
+	}{
+		{"no writer", nil, ErrNotInProject},
+		{"writer error", []BaselineWriter{&mockBaselineWriter{err: writeErr}}, writeErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewCheckCmd(&mockCheckRunner{result: &CheckResult{}}, tt.writers...)
+			cmd.SetOut(new(bytes.Buffer))
+			cmd.SetArgs([]string{"--write-baseline"})
+
+			if err := cmd.Execute(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckCmd_WriteBaseline_RejectsRuleFilters(t *testing.T) {
+	for _, args := range [][]string{
+		{"--write-baseline", "--rule", "slug_drift"},
+		{"--write-baseline", "--skip-rule", "todo"},
+	} {
+		writer := &mockBaselineWriter{result: &CheckResult{}}
+		cmd := NewCheckCmd(&mockCheckRunner{result: &CheckResult{}}, writer)
+		cmd.SetOut(new(bytes.Buffer))
+		cmd.SetErr(new(bytes.Buffer))
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+
+		if err == nil || !strings.Contains(err.Error(), "cannot be combined") {
+			t.Errorf("%v: error = %v, want the filters rejected", args, err)
+		}
+		if writer.called {
+			t.Errorf("%v: baseline written despite the filter", args)
+		}
+	}
+}
+
+func TestCheckCmd_NoBaselineFlag(t *testing.T) {
+	runner := &mockCheckRunner{result: &CheckResult{}}
+	cmd := NewCheckCmd(runner)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetArgs([]string{"--no-baseline"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !runner.filter.IgnoreBaseline {
+		t.Error("--no-baseline should set IgnoreBaseline")
+	}
+}
+
+func TestCheckCmd_ReportsSuppressedCount(t *testing.T) {
+	result := &CheckResult{
+		Findings:   []CheckFinding{{Type: FindingSlugDrift, Severity: SeverityWarning, Message: "drift", Path: "a.md"}},
+		Suppressed: 4,
+	}
+
+	t.Run("human", func(t *testing.T) {
+		cmd := NewCheckCmd(&mockCheckRunner{result: result})
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+		cmd.SetErr(new(bytes.Buffer))
+
+		_ = cmd.Execute()
+
+		if !strings.Contains(buf.String(), "4 known finding(s) suppressed\n") {
+			t.Errorf("output = %q, want suppressed count", buf.String())
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		cmd := NewCheckCmd(&mockCheckRunner{result: result})
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+		cmd.SetErr(new(bytes.Buffer))
+		cmd.SetArgs([]string{"--json"})
+
+		_ = cmd.Execute()
+
+		var out checkJSONResponse
+		if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if out.Summary.Suppressed != 4 {
+			t.Errorf("summary.suppressed = %d, want 4", out.Summary.Suppressed)
+		}
+	})
+}
+
+func TestCheckAdapter_WriteBaseline(t *testing.T) {
+	stub := &stubOutlineService{baselineResult: &outline.CheckResult{
+		Findings:   []domain.Finding{{Type: domain.FindingSlugDrift, Severity: domain.SeverityWarning, Path: "a.md"}},
+		Suppressed: 2,
+	}}
+	adapter := &checkAdapter{svc: stub}
+
+	result, err := adapter.WriteBaseline(context.Background(), CheckFilter{SkipRules: []string{"todo"}, IgnoreBaseline: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Findings) != 1 || result.Suppressed != 2 {
+		t.Errorf("result = %+v, want one finding and 2 suppressed", result)
+	}
+	if len(stub.baselineOpts) != 2 {
+		t.Errorf("options = %d, want 2", len(stub.baselineOpts))
+	}
+}
+
+func TestCheckAdapter_WriteBaseline_Error(t *testing.T) {
+	writeErr := errors.New("disk full")
+	adapter := &checkAdapter{svc: &stubOutlineService{baselineErr: writeErr}}
+
+	if _, err := adapter.WriteBaseline(context.Background(), CheckFilter{}); !errors.Is(err, writeErr) {
+		t.Errorf("error = %v, want %v", err, writeErr)
+	}
+}
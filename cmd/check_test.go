@@ -14,9 +14,11 @@ import (
 type mockCheckRunner struct {
 	result *CheckResult
 	err    error
+	filter CheckFilter
 }
 
-func (m *mockCheckRunner) Check(ctx context.Context) (*CheckResult, error) {
+func (m *mockCheckRunner) Check(ctx context.Context, filter CheckFilter) (*CheckResult, error) {
+	m.filter = filter
 	return m.result, m.err
 }
 
@@ -375,6 +377,46 @@ func TestCheckCmd_HumanReadableOutput_Summary(t *testing.T) {
 	}
 }
 
+func TestCheckCmd_HumanReadableOutput_LintLocation(t *testing.T) {
+	runner := &mockCheckRunner{
+		result: &CheckResult{
+			Findings: []CheckFinding{
+				{Type: FindingLint, Severity: SeverityWarning, Message: "TODO marker left in draft", Path: "a.md", Rule: "todo", Line: 4, Column: 7},
+			},
+		},
+	}
+	cmd := NewCheckCmd(runner)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(new(bytes.Buffer))
+
+	_ = cmd.Execute()
+
+	want := "a.md:4:7 [warning] todo: TODO marker left in draft\n"
+	if !strings.HasPrefix(buf.String(), want) {
+		t.Errorf("human output = %q, want prefix %q", buf.String(), want)
+	}
+}
+
+func TestCheckCmd_RuleFlags(t *testing.T) {
+	runner := &mockCheckRunner{result: &CheckResult{}}
+	cmd := NewCheckCmd(runner)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"--rule", "todo,slug_drift", "--skip-rule", "passive-voice"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := runner.filter.Rules; len(got) != 2 || got[0] != "todo" || got[1] != "slug_drift" {
+		t.Errorf("Rules = %q, want [todo slug_drift]", got)
+	}
+	if got := runner.filter.SkipRules; len(got) != 1 || got[0] != "passive-voice" {
+		t.Errorf("SkipRules = %q, want [passive-voice]", got)
+	}
+}
+
 func TestFindingsDetectedError_ExitCode(t *testing.T) {
 	tests := []struct {
 		name     string
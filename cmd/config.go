@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// ConfigEntry holds a single configuration key and its effective value.
+type ConfigEntry struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// ConfigSetResult holds the outcome of a config set operation.
+type ConfigSetResult struct {
+	Key      string `json:"key"`
+	OldValue string `json:"old_value"`
+	NewValue string `json:"new_value"`
+	Planned  bool   `json:"planned"`
+}
+
+// ConfigService defines the interface for reading and changing project configuration.
+type ConfigService interface {
+	ListConfig(ctx context.Context) ([]ConfigEntry, error)
+	GetConfig(ctx context.Context, key string) (*ConfigEntry, error)
+	SetConfig(ctx context.Context, key, value string, apply bool) (*ConfigSetResult, error)
+}
+
+// NewConfigCmd creates the config command with the given service.
+func NewConfigCmd(svc ConfigService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "config",
+		Short:        "Read and change project configuration",
+		SilenceUsage: true,
+	}
+
+	cmd.AddCommand(newConfigListCmd(svc))
+	cmd.AddCommand(newConfigGetCmd(svc))
+	cmd.AddCommand(newConfigSetCmd(svc))
+
+	return cmd
+}
+
+func newConfigListCmd(svc ConfigService) *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:          "list",
+		Short:        "List all configuration values",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if svc == nil {
+				return ErrNotInProject
+			}
+			entries, err := svc.ListConfig(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput || GetJSON() {
+				writeJSON(cmd.OutOrStdout(), struct {
+					Entries []ConfigEntry `json:"entries"`
+				}{Entries: entries})
+			} else {
+				for _, e := range entries {
+					fmt.Fprintf(cmd.OutOrStdout(), "%s=%s\n", e.Key, e.Value)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output results as JSON")
+
+	return cmd
+}
+
+func newConfigGetCmd(svc ConfigService) *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:          "get <key>",
+		Short:        "Print a configuration value",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if svc == nil {
+				return ErrNotInProject
+			}
+			entry, err := svc.GetConfig(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput || GetJSON() {
+				writeJSON(cmd.OutOrStdout(), entry)
+			} else {
+				fmt.Fprintln(cmd.OutOrStdout(), entry.Value)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output results as JSON")
+
+	return cmd
+}
+
+func newConfigSetCmd(svc ConfigService) *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Change a configuration value",
+		Long: `Change a configuration value. List values are comma-separated, so a list
+item containing a comma, such as a banned phrase, must be written in
+.linemark/config.yaml directly.`,
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if svc == nil {
+				return ErrNotInProject
+			}
+			isDryRun := GetDryRun()
+			result, err := svc.SetConfig(cmd.Context(), args[0], args[1], !isDryRun)
+			if err != nil {
+				return err
+			}
+
+			if isDryRun {
+				result.Planned = true
+			}
+
+			if jsonOutput || GetJSON() {
+				writeJSON(cmd.OutOrStdout(), result)
+			} else if isDryRun {
+				fmt.Fprintf(cmd.OutOrStdout(), "Would set %s: %s -> %s\n", result.Key, result.OldValue, result.NewValue)
+			} else {
+				fmt.Fprintf(cmd.OutOrStdout(), "Set %s: %s -> %s\n", result.Key, result.OldValue, result.NewValue)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output results as JSON")
+
+	return cmd
+}
@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// mockConfigService is a test double for ConfigService.
+type mockConfigService struct {
+	entries   []ConfigEntry
+	listErr   error
+	getResult *ConfigEntry
+	getErr    error
+	setResult *ConfigSetResult
+	setErr    error
+	setApply  bool
+}
+
+func (m *mockConfigService) ListConfig(ctx context.Context) ([]ConfigEntry, error) {
+	return m.entries, m.listErr
+}
+
+func (m *mockConfigService) GetConfig(ctx context.Context, key string) (*ConfigEntry, error) {
+	return m.getResult, m.getErr
+}
+
+func (m *mockConfigService) SetConfig(ctx context.Context, key, value string, apply bool) (*ConfigSetResult, error) {
+	m.setApply = apply
+	return m.setResult, m.setErr
+}
+
+func runConfigCmd(t *testing.T, svc ConfigService, args ...string) (string, error) {
+	t.Helper()
+	cmd := NewConfigCmd(svc)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func TestConfigCmd_RegisteredWithRoot(t *testing.T) {
+	found := false
+	for _, sub := range rootCmd.Commands() {
+		if sub.Name() == "config" {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Error("config command not registered with root")
+	}
+}
+
+func TestConfigCmd_List(t *testing.T) {
+	svc := &mockConfigService{entries: []ConfigEntry{
+		{Key: "doc_types.required", Value: "draft,notes"},
+		{Key: "sid.length", Value: "12"},
+	}}
+
+	out, err := runConfigCmd(t, svc, "list")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "doc_types.required=draft,notes\nsid.length=12\n"
+	if out != want {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+}
+
+func TestConfigCmd_List_JSON(t *testing.T) {
+	svc := &mockConfigService{entries: []ConfigEntry{{Key: "sid.length", Value: "12"}}}
+
+	out, err := runConfigCmd(t, svc, "list", "--json")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got struct {
+		Entries []ConfigEntry `json:"entries"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if len(got.Entries) != 1 || got.Entries[0].Key != "sid.length" {
+		t.Errorf("entries = %+v", got.Entries)
+	}
+}
+
+func TestConfigCmd_Get(t *testing.T) {
+	svc := &mockConfigService{getResult: &ConfigEntry{Key: "sid.length", Value: "12"}}
+
+	out, err := runConfigCmd(t, svc, "get", "sid.length")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "12\n" {
+		t.Errorf("output = %q, want %q", out, "12\n")
+	}
+}
+
+func TestConfigCmd_Get_JSON(t *testing.T) {
+	svc := &mockConfigService{getResult: &ConfigEntry{Key: "sid.length", Value: "12"}}
+
+	out, err := runConfigCmd(t, svc, "get", "sid.length", "--json")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got ConfigEntry
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if got.Value != "12" {
+		t.Errorf("value = %q, want 12", got.Value)
+	}
+}
+
+func TestConfigCmd_Set(t *testing.T) {
+	svc := &mockConfigService{setResult: &ConfigSetResult{Key: "sid.length", OldValue: "12", NewValue: "10"}}
+
+	out, err := runConfigCmd(t, svc, "set", "sid.length", "10")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !svc.setApply {
+		t.Error("set should apply without --dry-run")
+	}
+	if out != "Set sid.length: 12 -> 10\n" {
+		t.Errorf("output = %q", out)
+	}
+}
+
+func TestConfigCmd_Set_DryRun(t *testing.T) {
+	svc := &mockConfigService{setResult: &ConfigSetResult{Key: "sid.length", OldValue: "12", NewValue: "10"}}
+	root := NewRootCmd()
+	root.AddCommand(NewConfigCmd(svc))
+	buf := new(bytes.Buffer)
+	root.SetOut(buf)
+	root.SetErr(new(bytes.Buffer))
+	root.SetArgs([]string{"config", "set", "sid.length", "10", "--dry-run"})
+	t.Cleanup(func() { dryRun = false })
+
+	if err := root.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if svc.setApply {
+		t.Error("dry run should not apply")
+	}
+	if !strings.HasPrefix(buf.String(), "Would set") {
+		t.Errorf("output = %q, want Would set prefix", buf.String())
+	}
+}
+
+func TestConfigCmd_Set_JSON(t *testing.T) {
+	svc := &mockConfigService{setResult: &ConfigSetResult{Key: "sid.length", OldValue: "12", NewValue: "10"}}
+
+	out, err := runConfigCmd(t, svc, "set", "sid.length", "10", "--json")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got ConfigSetResult
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if got.NewValue != "10" {
+		t.Errorf("new_value = %q, want 10", got.NewValue)
+	}
+}
+
+func TestConfigCmd_Errors(t *testing.T) {
+	errBoom := fmt.Errorf("boom")
+	tests := []struct {
+		name string
+		svc  *mockConfigService
+		args []string
+	}{
+		{"list error", &mockConfigService{listErr: errBoom}, []string{"list"}},
+		{"get error", &mockConfigService{getErr: errBoom}, []string{"get", "x"}},
+		{"set error", &mockConfigService{setErr: errBoom}, []string{"set", "x", "y"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runConfigCmd(t, tt.svc, tt.args...)
+			if err != errBoom {
+				t.Errorf("error = %v, want %v", err, errBoom)
+			}
+		})
+	}
+}
+
+func TestConfigCmd_NilService(t *testing.T) {
+	for _, args := range [][]string{{"list"}, {"get", "x"}, {"set", "x", "y"}} {
+		_, err := runConfigCmd(t, nil, args...)
+		if err != ErrNotInProject {
+			t.Errorf("%v: error = %v, want ErrNotInProject", args, err)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/eykd/linemark-go/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+// CopyResult holds the outcome of a copy operation. SIDs maps each copied
+// node's SID to its copy's, and Files each copied file to the file created.
+type CopyResult struct {
+	SIDs       []RenameEntry `json:"sids"`
+	Files      []RenameEntry `json:"files"`
+	Rebalanced []RenameEntry `json:"rebalanced"`
+	Planned    bool          `json:"planned"`
+}
+
+// CopyRunner defines the interface for running the copy operation.
+type CopyRunner interface {
+	Copy(ctx context.Context, selector, to, before, after string, recursive, rebalance, apply bool) (*CopyResult, error)
+}
+
+// NewCopyCmd creates the copy command with the given runner.
+func NewCopyCmd(runner CopyRunner) *cobra.Command {
+	var to string
+	var jsonOutput bool
+	var before string
+	var after string
+	var recursive bool
+	var noRebalance bool
+
+	cmd := &cobra.Command{
+		Use:          "copy <selector>",
+		Short:        "Copy a node or subtree with new SIDs",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if runner == nil {
+				return ErrNotInProject
+			}
+			if before != "" && after != "" {
+				return fmt.Errorf("--before and --after are mutually exclusive")
+			}
+
+			selector := args[0]
+			if _, err := domain.ParseSelector(selector); err != nil {
+				return fmt.Errorf("invalid selector %q: %w", selector, err)
+			}
+
+			if to == "" {
+				return fmt.Errorf("required flag \"to\" not set")
+			}
+			if _, err := domain.ParseSelector(to); err != nil {
+				return fmt.Errorf("invalid target selector %q: %w", to, err)
+			}
+
+			isDryRun := GetDryRun()
+			result, err := runner.Copy(cmd.Context(), selector, to, before, after, recursive, !noRebalance, !isDryRun)
+			if err != nil {
+				return err
+			}
+			result.Planned = isDryRun
+
+			if jsonOutput || GetJSON() {
+				writeJSON(cmd.OutOrStdout(), result)
+				return nil
+			}
+			w := cmd.OutOrStdout()
+			verb := "Copied"
+			if isDryRun {
+				verb = "Would copy"
+			}
+			for _, s := range result.SIDs {
+				fmt.Fprintf(w, "%s %s as %s\n", verb, s.Old, s.New)
+			}
+			for _, r := range append(result.Files, result.Rebalanced...) {
+				fmt.Fprintf(w, "  %s -> %s\n", r.Old, r.New)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&to, "to", "", "Target parent for the copy")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output results as JSON")
+	cmd.Flags().StringVar(&before, "before", "", "Place before this sibling")
+	cmd.Flags().StringVar(&after, "after", "", "Place after this sibling")
+	cmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "Copy the node's descendants too")
+	cmd.Flags().BoolVar(&noRebalance, "no-rebalance", false, "Fail instead of renumbering neighbouring siblings when no gap exists")
+
+	return cmd
+}
@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/eykd/linemark-go/internal/domain"
+	"github.com/eykd/linemark-go/internal/outline"
+)
+
+// mockCopyRunner is a test double for CopyRunner.
+type mockCopyRunner struct {
+	result    *CopyResult
+	err       error
+	called    bool
+	selector  string
+	to        string
+	before    string
+	after     string
+	recursive bool
+	rebalance bool
+	apply     bool
+}
+
+func (m *mockCopyRunner) Copy(ctx context.Context, selector, to, before, after string, recursive, rebalance, apply bool) (*CopyResult, error) {
+	m.called = true
+	m.selector, m.to, m.before, m.after = selector, to, before, after
+	m.recursive, m.rebalance, m.apply = recursive, rebalance, apply
+	return m.result, m.err
+}
+
+func copyFixture() *CopyResult {
+	return &CopyResult{
+		SIDs: []RenameEntry{{Old: "SID002CCDD", New: "SIDN01AABB"}, {Old: "SID003EEFF", New: "SIDN02AABB"}},
+		Files: []RenameEntry{
+			{Old: "100-200-100_SID003EEFF_draft_scene.md", New: "200-100-100_SIDN02AABB_draft_scene.md"},
+			{Old: "100-200_SID002CCDD_draft_chapter.md", New: "200-100_SIDN01AABB_draft_chapter.md"},
+		},
+		Rebalanced: []RenameEntry{{Old: "200-101_SID005IIJJ_draft_x.md", New: "200-200_SID005IIJJ_draft_x.md"}},
+	}
+}
+
+// runCopyCmd runs the copy command under a root command.
+func runCopyCmd(t *testing.T, runner CopyRunner, args ...string) (string, error) {
+	t.Helper()
+	root := NewRootCmd()
+	root.AddCommand(NewCopyCmd(runner))
+	buf := new(bytes.Buffer)
+	root.SetOut(buf)
+	root.SetErr(new(bytes.Buffer))
+	root.SetArgs(append([]string{"copy"}, args...))
+	t.Cleanup(func() { dryRun, jsonFlag = false, false })
+	err := root.Execute()
+	return buf.String(), err
+}
+
+func TestCopyCmd_ForwardsArguments(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want mockCopyRunner
+	}{
+		{"node only", []string{"100-200", "--to", "200"},
+			mockCopyRunner{selector: "100-200", to: "200", rebalance: true, apply: true}},
+		{"recursive", []string{"sid:SID002CCDD", "--to", "200", "-r"},
+			mockCopyRunner{selector: "sid:SID002CCDD", to: "200", recursive: true, rebalance: true, apply: true}},
+		{"before without rebalancing", []string{"100", "--to", "200", "--before", "200-100", "--no-rebalance"},
+			mockCopyRunner{selector: "100", to: "200", before: "200-100", apply: true}},
+		{"after on a dry run", []string{"100", "--to", "200", "--after", "200-100", "--dry-run"},
+			mockCopyRunner{selector: "100", to: "200", after: "200-100", rebalance: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &mockCopyRunner{result: copyFixture()}
+
+			_, err := runCopyCmd(t, runner, tt.args...)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			runner.result, runner.called = nil, false
+			if *runner != tt.want {
+				t.Errorf("runner got %+v, want %+v", *runner, tt.want)
+			}
+		})
+	}
+}
+
+func TestCopyCmd_HumanReadableOutput(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		verb string
+	}{
+		{"applied", nil, "Copied"},
+		{"dry run", []string{"--dry-run"}, "Would copy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := runCopyCmd(t, &mockCopyRunner{result: copyFixture()}, append([]string{"100-200", "--to", "200"}, tt.args...)...)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			want := tt.verb + " SID002CCDD as SIDN01AABB\n" +
+				tt.verb + " SID003EEFF as SIDN02AABB\n" +
+				"  100-200-100_SID003EEFF_draft_scene.md -> 200-100-100_SIDN02AABB_draft_scene.md\n" +
+				"  100-200_SID002CCDD_draft_chapter.md -> 200-100_SIDN01AABB_draft_chapter.md\n" +
+				"  200-101_SID005IIJJ_draft_x.md -> 200-200_SID005IIJJ_draft_x.md\n"
+			if out != want {
+				t.Errorf("output = %q, want %q", out, want)
+			}
+		})
+	}
+}
+
+func TestCopyCmd_JSONOutput(t *testing.T) {
+	out, err := runCopyCmd(t, &mockCopyRunner{result: copyFixture()}, "100-200", "--to", "200", "--json", "--dry-run")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var result CopyResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\nraw: %s", err, out)
+	}
+	want := copyFixture()
+	want.Planned = true
+	if !reflect.DeepEqual(&result, want) {
+		t.Errorf("result = %+v, want %+v", result, want)
+	}
+	for _, key := range []string{`"sids"`, `"files"`, `"rebalanced"`, `"planned"`} {
+		if !strings.Contains(out, key) {
+			t.Errorf("JSON output missing %s: %s", key, out)
+		}
+	}
+}
+
+func TestCopyCmd_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		err        error
+		want       string
+		wantCalled bool
+	}{
+		{"invalid selector", []string{"not a selector!", "--to", "200"}, nil, "invalid selector", false},
+		{"missing --to", []string{"100"}, nil, `required flag "to" not set`, false},
+		{"invalid target", []string{"100", "--to", "not a selector!"}, nil, "invalid target selector", false},
+		{"before and after", []string{"100", "--to", "200", "--before", "1", "--after", "2"}, nil, "mutually exclusive", false},
+		{"runner error", []string{"100", "--to", "100", "-r"}, outline.ErrCycleDetected, "cycle detected", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &mockCopyRunner{err: tt.err}
+
+			_, err := runCopyCmd(t, runner, tt.args...)
+
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.want)
+			}
+			if runner.called != tt.wantCalled {
+				t.Errorf("runner called = %v, want %v", runner.called, tt.wantCalled)
+			}
+		})
+	}
+}
+
+// --- copyAdapter tests ---
+
+func TestCopyAdapter_Copy(t *testing.T) {
+	stub := &stubOutlineService{copyResult: &outline.CopyResult{
+		SIDs:       map[string]string{"SID003EEFF": "SIDN02AABB", "SID002CCDD": "SIDN01AABB"},
+		Files:      map[string]string{"b.md": "d.md", "a.md": "c.md"},
+		Rebalanced: map[string]string{"e.md": "f.md"},
+	}}
+	adapter := &copyAdapter{svc: stub}
+
+	result, err := adapter.Copy(context.Background(), "100-200", "sid:SID004GGHH", "", "200-100", true, false, false)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := &CopyResult{
+		SIDs:       []RenameEntry{{Old: "SID002CCDD", New: "SIDN01AABB"}, {Old: "SID003EEFF", New: "SIDN02AABB"}},
+		Files:      []RenameEntry{{Old: "a.md", New: "c.md"}, {Old: "b.md", New: "d.md"}},
+		Rebalanced: []RenameEntry{{Old: "e.md", New: "f.md"}},
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("result = %+v, want %+v", result, want)
+	}
+	if stub.moveSrc.Value() != "100-200" || stub.moveTgt.Kind() != domain.SelectorSID || stub.moveAfter != "200-100" || stub.moveApply {
+		t.Errorf("service got source %v, target %v, after %q, apply %v", stub.moveSrc, stub.moveTgt, stub.moveAfter, stub.moveApply)
+	}
+	if len(stub.copyOpts) != 2 {
+		t.Errorf("opts = %d, want recursive and no-rebalance", len(stub.copyOpts))
+	}
+}
+
+func TestCopyAdapter_Errors(t *testing.T) {
+	svcErr := errors.New("locked")
+	adapter := &copyAdapter{svc: &stubOutlineService{moveErr: svcErr}}
+
+	tests := []struct {
+		name     string
+		selector string
+		to       string
+	}{
+		{"invalid source", "not a selector!", "200"},
+		{"invalid target", "100", "not a selector!"},
+		{"service error", "100", "200"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := adapter.Copy(context.Background(), tt.selector, tt.to, "", "", false, true, true); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -13,12 +13,15 @@ type DeleteResult struct {
 	FilesDeleted  []string          `json:"files_deleted"`
 	FilesRenamed  map[string]string `json:"files_renamed,omitempty"`
 	SIDsPreserved []string          `json:"sids_preserved"`
+	TrashID       string            `json:"trash_id,omitempty"`
+	TrashIDs      []string          `json:"trash_ids,omitempty"`
 	Planned       bool              `json:"planned"`
 }
 
 // DeleteRunner defines the interface for running the delete operation.
 type DeleteRunner interface {
 	Delete(ctx context.Context, selector string, mode domain.DeleteMode, apply bool) (*DeleteResult, error)
+	Archive(ctx context.Context, selector string, mode domain.DeleteMode, apply bool) (*DeleteResult, error)
 }
 
 // Confirmer defines the interface for confirming destructive operations.
@@ -38,10 +41,16 @@ func NewDeleteCmd(runner DeleteRunner, confirmers ...Confirmer) *cobra.Command {
 	var recursive bool
 	var promote bool
 	var force bool
+	var archive bool
 
 	cmd := &cobra.Command{
-		Use:          "delete <selector>",
-		Short:        "Delete a node from the outline",
+		Use:   "delete <selector>",
+		Short: "Delete a node from the outline",
+		Long: `Delete a node from the outline. The selector may also be an expression
+selecting several nodes: a subtree ("001-200/**"), a node's children
+("001-200/*"), a sibling range ("001-200..001-500"), a title glob
+("title:Chapter*") or a document type ("type:notes"). A selected node whose
+descendants are all selected is deleted with its subtree.`,
 		Args:         cobra.ExactArgs(1),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -53,7 +62,7 @@ func NewDeleteCmd(runner DeleteRunner, confirmers ...Confirmer) *cobra.Command {
 			}
 
 			selector := args[0]
-			if _, err := domain.ParseSelector(selector); err != nil {
+			if _, err := domain.ParseSelectorExpr(selector); err != nil {
 				return fmt.Errorf("invalid selector %q: %w", selector, err)
 			}
 
@@ -74,8 +83,12 @@ func NewDeleteCmd(runner DeleteRunner, confirmers ...Confirmer) *cobra.Command {
 				mode = domain.DeleteModePromote
 			}
 
+			remove := runner.Delete
+			if archive {
+				remove = runner.Archive
+			}
 			isDryRun := GetDryRun()
-			result, err := runner.Delete(cmd.Context(), selector, mode, !isDryRun)
+			result, err := remove(cmd.Context(), selector, mode, !isDryRun)
 			if err != nil {
 				return err
 			}
@@ -93,6 +106,13 @@ func NewDeleteCmd(runner DeleteRunner, confirmers ...Confirmer) *cobra.Command {
 				for _, newName := range result.FilesRenamed {
 					fmt.Fprintln(cmd.OutOrStdout(), newName)
 				}
+				trashIDs := result.TrashIDs
+				if len(trashIDs) == 0 && result.TrashID != "" {
+					trashIDs = []string{result.TrashID}
+				}
+				for _, id := range trashIDs {
+					fmt.Fprintf(cmd.OutOrStdout(), "Moved to trash as %s\n", id)
+				}
 			}
 			return nil
 		},
@@ -102,6 +122,7 @@ func NewDeleteCmd(runner DeleteRunner, confirmers ...Confirmer) *cobra.Command {
 	cmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "Delete node and entire subtree")
 	cmd.Flags().BoolVarP(&promote, "promote", "p", false, "Delete node and promote children")
 	cmd.Flags().BoolVar(&force, "force", false, "Skip confirmation prompt")
+	cmd.Flags().BoolVar(&archive, "archive", false, "Move files to the trash instead of deleting them")
 
 	return cmd
 }
@@ -21,6 +21,7 @@ type mockDeleteRunner struct {
 	selector string
 	mode     domain.DeleteMode
 	apply    bool
+	archived bool
 }
 
 func (m *mockDeleteRunner) Delete(ctx context.Context, selector string, mode domain.DeleteMode, apply bool) (*DeleteResult, error) {
@@ -31,6 +32,11 @@ func (m *mockDeleteRunner) Delete(ctx context.Context, selector string, mode dom
 	return m.result, m.err
 }
 
+func (m *mockDeleteRunner) Archive(ctx context.Context, selector string, mode domain.DeleteMode, apply bool) (*DeleteResult, error) {
+	m.archived = true
+	return m.Delete(ctx, selector, mode, apply)
+}
+
 func newTestDeleteCmd(runner *mockDeleteRunner, args ...string) (*cobra.Command, *bytes.Buffer) {
 	cmd := NewDeleteCmd(runner)
 	if len(args) > 0 {
@@ -53,6 +59,8 @@ func TestDeleteCmd_ValidSelector(t *testing.T) {
 		{"explicit SID prefix", "sid:A3F7c9Qx7Lm2"},
 		{"single segment MP", "100"},
 		{"explicit single segment MP", "mp:100"},
+		{"title", "title:The Beginning"},
+		{"slug", "slug:chapter-one"},
 	}
 
 	for _, tt := range tests {
@@ -452,3 +460,47 @@ func TestDeleteResult_PlannedField_JSON(t *testing.T) {
 		t.Errorf("planned = %v, want true", parsed["planned"])
 	}
 }
+
+func TestDeleteCmd_Archive(t *testing.T) {
+	runner := &mockDeleteRunner{result: &DeleteResult{
+		FilesDeleted: []string{"100_SID001AABB_draft_hello.md"},
+		TrashID:      "20260301T120000Z",
+	}}
+	cmd, buf := newTestDeleteCmd(runner, "100", "--archive")
+
+	err := cmd.Execute()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !runner.archived {
+		t.Error("expected --archive to move the node to the trash")
+	}
+	want := "100_SID001AABB_draft_hello.md\nMoved to trash as 20260301T120000Z\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestDeleteCmd_Expression(t *testing.T) {
+	runner := &mockDeleteRunner{result: &DeleteResult{
+		FilesDeleted: []string{"100-100_SID002CCDD_draft_one.md", "100-200_SID003EEFF_draft_two.md"},
+		TrashID:      "20260301T120000Z",
+		TrashIDs:     []string{"20260301T120000Z", "20260301T120000Z-2"},
+	}}
+	cmd, buf := newTestDeleteCmd(runner, "100/*", "--archive", "-r")
+
+	err := cmd.Execute()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if runner.selector != "100/*" || runner.mode != domain.DeleteModeRecursive {
+		t.Errorf("runner called with %q, mode %v", runner.selector, runner.mode)
+	}
+	want := "100-100_SID002CCDD_draft_one.md\n100-200_SID003EEFF_draft_two.md\n" +
+		"Moved to trash as 20260301T120000Z\nMoved to trash as 20260301T120000Z-2\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
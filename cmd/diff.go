@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// NodeChange describes how one node differs between two outline states.
+type NodeChange struct {
+	SID            string   `json:"sid"`
+	Status         string   `json:"status"`
+	OldMP          string   `json:"old_mp,omitempty"`
+	NewMP          string   `json:"new_mp,omitempty"`
+	OldParent      string   `json:"old_parent,omitempty"`
+	NewParent      string   `json:"new_parent,omitempty"`
+	OldTitle       string   `json:"old_title,omitempty"`
+	NewTitle       string   `json:"new_title,omitempty"`
+	Moved          bool     `json:"moved"`
+	Retitled       bool     `json:"retitled"`
+	DocsAdded      []string `json:"docs_added"`
+	DocsRemoved    []string `json:"docs_removed"`
+	ContentChanged []string `json:"content_changed"`
+}
+
+// DiffResult holds the node-level changes between two outline states.
+type DiffResult struct {
+	Nodes []NodeChange `json:"nodes"`
+}
+
+// DiffRunner compares two outline states.
+type DiffRunner interface {
+	Diff(ctx context.Context, from, to string) (*DiffResult, error)
+}
+
+// NewDiffCmd creates the diff command with the given runner.
+func NewDiffCmd(runner DiffRunner) *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "diff [<from> [<to>]]",
+		Short: "Show node-level changes between two outline states",
+		Long: `Compare two states of the outline node by node, matching nodes by SID, and
+report nodes added, deleted, moved, retitled, with doc types added or
+removed, or with changed content. Renumbering alone is not a move.
+
+Each state is a git revision or a directory. With no arguments, HEAD is
+compared with the working tree; with one, that state is.`,
+		SilenceUsage: true,
+		Args:         cobra.MaximumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if runner == nil {
+				return ErrNotInProject
+			}
+			from, to := "HEAD", ""
+			if len(args) > 0 {
+				from = args[0]
+			}
+			if len(args) > 1 {
+				to = args[1]
+			}
+
+			result, err := runner.Diff(cmd.Context(), from, to)
+			if err != nil {
+				return err
+			}
+			if jsonOutput || GetJSON() {
+				writeJSON(cmd.OutOrStdout(), result)
+				return nil
+			}
+			writeDiffHuman(cmd.OutOrStdout(), result)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output results as JSON")
+
+	return cmd
+}
+
+// writeDiffHuman writes one line per changed node, indented by depth.
+func writeDiffHuman(w io.Writer, result *DiffResult) {
+	if len(result.Nodes) == 0 {
+		fmt.Fprintln(w, "No changes")
+		return
+	}
+	for _, n := range result.Nodes {
+		mp, title, marker := n.NewMP, n.NewTitle, "~"
+		switch n.Status {
+		case "added":
+			marker = "+"
+		case "deleted":
+			mp, title, marker = n.OldMP, n.OldTitle, "-"
+		}
+		indent := strings.Repeat("  ", strings.Count(mp, "-"))
+		fmt.Fprintf(w, "%s%s %s %s%s\n", indent, marker, mp, title, describeChange(n))
+	}
+}
+
+// describeChange summarises a changed node's changes in brackets.
+func describeChange(n NodeChange) string {
+	var parts []string
+	if n.Moved {
+		parts = append(parts, "moved from "+n.OldMP)
+	}
+	if n.Retitled {
+		parts = append(parts, fmt.Sprintf("retitled from %q", n.OldTitle))
+	}
+	for _, docType := range n.DocsAdded {
+		parts = append(parts, "+"+docType)
+	}
+	for _, docType := range n.DocsRemoved {
+		parts = append(parts, "-"+docType)
+	}
+	if len(n.ContentChanged) > 0 {
+		parts = append(parts, "changed: "+strings.Join(n.ContentChanged, ", "))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(parts, "; ") + "]"
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// mockDiffRunner is a test double for DiffRunner.
+type mockDiffRunner struct {
+	result *DiffResult
+	err    error
+	from   string
+	to     string
+}
+
+func (m *mockDiffRunner) Diff(ctx context.Context, from, to string) (*DiffResult, error) {
+	m.from, m.to = from, to
+	return m.result, m.err
+}
+
+// runDiffCmd runs the diff command under a root command, so the global
+// flags apply, and returns the output.
+func runDiffCmd(t *testing.T, runner DiffRunner, args ...string) (string, error) {
+	t.Helper()
+	root := NewRootCmd()
+	root.AddCommand(NewDiffCmd(runner))
+	buf := new(bytes.Buffer)
+	root.SetOut(buf)
+	root.SetErr(new(bytes.Buffer))
+	root.SetArgs(append([]string{"diff"}, args...))
+	t.Cleanup(func() { jsonFlag = false })
+	err := root.Execute()
+	return buf.String(), err
+}
+
+func TestDiffCmd_Refs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantFrom string
+		wantTo   string
+	}{
+		{"HEAD against working tree by default", nil, "HEAD", ""},
+		{"one state against working tree", []string{"main"}, "main", ""},
+		{"two states", []string{"main", "../draft"}, "main", "../draft"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &mockDiffRunner{result: &DiffResult{}}
+
+			if _, err := runDiffCmd(t, runner, tt.args...); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if runner.from != tt.wantFrom || runner.to != tt.wantTo {
+				t.Errorf("diffed %q..%q, want %q..%q", runner.from, runner.to, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+}
+
+func TestDiffCmd_HumanOutput(t *testing.T) {
+	runner := &mockDiffRunner{result: &DiffResult{Nodes: []NodeChange{
+		{SID: "A", Status: "changed", OldMP: "200", NewMP: "100", NewTitle: "Part", Moved: true,
+			Retitled: true, OldTitle: "Old Part", DocsAdded: []string{"research"}, DocsRemoved: []string{"notes"}},
+		{SID: "B", Status: "added", NewMP: "100-100", NewTitle: "Scene"},
+		{SID: "C", Status: "changed", OldMP: "100-200", NewMP: "100-200", NewTitle: "Other", ContentChanged: []string{"draft", "notes"}},
+		{SID: "D", Status: "deleted", OldMP: "300", OldTitle: "Gone"},
+	}}}
+
+	out, err := runDiffCmd(t, runner)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `~ 100 Part [moved from 200; retitled from "Old Part"; +research; -notes]
+  + 100-100 Scene
+  ~ 100-200 Other [changed: draft, notes]
+- 300 Gone
+`
+	if out != want {
+		t.Errorf("output:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestDiffCmd_NoChanges(t *testing.T) {
+	out, err := runDiffCmd(t, &mockDiffRunner{result: &DiffResult{}})
+
+	if err != nil || out != "No changes\n" {
+		t.Errorf("output = %q, error = %v", out, err)
+	}
+}
+
+func TestDiffCmd_JSON(t *testing.T) {
+	run := map[string]func(t *testing.T, runner DiffRunner) (string, error){
+		"local flag": func(t *testing.T, runner DiffRunner) (string, error) {
+			cmd := NewDiffCmd(runner)
+			buf := new(bytes.Buffer)
+			cmd.SetOut(buf)
+			cmd.SetArgs([]string{"--json"})
+			err := cmd.Execute()
+			return buf.String(), err
+		},
+		"global flag": func(t *testing.T, runner DiffRunner) (string, error) {
+			t.Cleanup(func() { jsonFlag = false })
+			root := NewRootCmd()
+			root.AddCommand(NewDiffCmd(runner))
+			buf := new(bytes.Buffer)
+			root.SetOut(buf)
+			root.SetArgs([]string{"--json", "diff"})
+			err := root.Execute()
+			return buf.String(), err
+		},
+	}
+
+	for name, fn := range run {
+		t.Run(name, func(t *testing.T) {
+			runner := &mockDiffRunner{result: &DiffResult{Nodes: []NodeChange{{SID: "B", Status: "added", NewMP: "100"}}}}
+
+			out, err := fn(t, runner)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got DiffResult
+			if err := json.Unmarshal([]byte(out), &got); err != nil {
+				t.Fatalf("invalid JSON: %v\n%s", err, out)
+			}
+			if len(got.Nodes) != 1 || got.Nodes[0].Status != "added" {
+				t.Errorf("nodes = %+v", got.Nodes)
+			}
+		})
+	}
+}
+
+func TestDiffCmd_Errors(t *testing.T) {
+	diffErr := errors.New("no such revision or directory: nope")
+
+	if _, err := runDiffCmd(t, nil); !errors.Is(err, ErrNotInProject) {
+		t.Errorf("nil runner: error = %v, want ErrNotInProject", err)
+	}
+	if _, err := runDiffCmd(t, &mockDiffRunner{err: diffErr}, "nope"); !errors.Is(err, diffErr) {
+		t.Errorf("error = %v, want %v", err, diffErr)
+	}
+	if _, err := runDiffCmd(t, &mockDiffRunner{}, "a", "b", "c"); err == nil {
+		t.Error("expected an error for three arguments")
+	}
+}
@@ -12,6 +12,10 @@ import (
 func NewDoctorCmd(runner CheckRunner, repairers ...RepairRunner) *cobra.Command {
 	var applyFlag bool
 	var jsonOutput bool
+	var format string
+	var fixDuplicates bool
+	var keep []string
+	var reparentOrphans bool
 
 	var repairer RepairRunner
 	if len(repairers) > 0 {
@@ -23,21 +27,39 @@ func NewDoctorCmd(runner CheckRunner, repairers ...RepairRunner) *cobra.Command
 		Short:        "Diagnose and fix project issues",
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := resolveFormat(format, jsonOutput || GetJSON())
+			if err != nil {
+				return err
+			}
+			if fixDuplicates && !applyFlag {
+				return fmt.Errorf("--fix-duplicates requires --apply")
+			}
+			if reparentOrphans && !applyFlag {
+				return fmt.Errorf("--reparent-orphans requires --apply")
+			}
+			if len(keep) > 0 && !fixDuplicates {
+				return fmt.Errorf("--keep requires --fix-duplicates")
+			}
 			if applyFlag {
 				if repairer == nil {
 					return ErrNotInProject
 				}
-				return runRepairAndReport(cmd.Context(), cmd.OutOrStdout(), runner, repairer, jsonOutput || GetJSON())
+				opts := RepairOptions{FixDuplicates: fixDuplicates, Keep: keep, ReparentOrphans: reparentOrphans}
+				return runRepairAndReport(cmd.Context(), cmd.OutOrStdout(), runner, repairer, opts, f)
 			}
 			if runner == nil {
 				return ErrNotInProject
 			}
-			return runCheckAndReport(cmd.Context(), cmd.OutOrStdout(), runner, jsonOutput || GetJSON())
+			return runCheckAndReport(cmd.Context(), cmd.OutOrStdout(), runner, CheckFilter{}, f)
 		},
 	}
 
 	cmd.Flags().BoolVar(&applyFlag, "apply", false, "Apply automatic fixes")
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output results as JSON")
+	cmd.Flags().StringVar(&format, "format", "", "Output format: text, json or sarif")
+	cmd.Flags().BoolVar(&fixDuplicates, "fix-duplicates", false, "Give each duplicate-SID node but one a fresh SID and renumber nodes sharing an MP (with --apply)")
+	cmd.Flags().StringSliceVar(&keep, "keep", nil, "MP of the node that keeps its SID when fixing duplicates")
+	cmd.Flags().BoolVar(&reparentOrphans, "reparent-orphans", false, "Move orphaned nodes under their nearest ancestor instead of creating placeholder parents (with --apply)")
 
 	return cmd
 }
@@ -84,17 +106,21 @@ func formatRepairHuman(w io.Writer, repairs []RepairAction, unrepaired []CheckFi
 	}
 }
 
-// runRepairAndReport runs the repairer and formats results as JSON or human-readable text.
+// runRepairAndReport runs the repairer and formats results as text, JSON or
+// SARIF; SARIF output lists only the unrepaired findings.
 // After repair, it re-runs the checker to detect any remaining findings.
-func runRepairAndReport(ctx context.Context, w io.Writer, runner CheckRunner, repairer RepairRunner, jsonOutput bool) error {
-	result, err := repairer.Repair(ctx)
+func runRepairAndReport(ctx context.Context, w io.Writer, runner CheckRunner, repairer RepairRunner, opts RepairOptions, format outputFormat) error {
+	result, err := repairer.Repair(ctx, opts)
 	if err != nil {
 		return err
 	}
 
-	if jsonOutput {
+	switch format {
+	case formatJSON:
 		formatRepairJSON(w, result.Repairs, result.Unrepaired)
-	} else {
+	case formatSARIF:
+		formatFindingsSARIF(w, result.Unrepaired)
+	default:
 		formatRepairHuman(w, result.Repairs, result.Unrepaired)
 	}
 
@@ -104,5 +130,5 @@ func runRepairAndReport(ctx context.Context, w io.Writer, runner CheckRunner, re
 
 	// Post-repair validation: re-run checker with suppressed output to detect
 	// any remaining findings that were not repaired (e.g. invalid_filename).
-	return runCheckAndReport(ctx, io.Discard, runner, false)
+	return runCheckAndReport(ctx, io.Discard, runner, CheckFilter{}, formatText)
 }
@@ -292,10 +292,12 @@ type mockRepairRunner struct {
 	result *RepairResult
 	err    error
 	called bool
+	opts   RepairOptions
 }
 
-func (m *mockRepairRunner) Repair(ctx context.Context) (*RepairResult, error) {
+func (m *mockRepairRunner) Repair(ctx context.Context, opts RepairOptions) (*RepairResult, error) {
 	m.called = true
+	m.opts = opts
 	return m.result, m.err
 }
 
@@ -611,6 +613,50 @@ func TestDoctorCmd_WithoutApply_DoesNotCallRepairer(t *testing.T) {
 	}
 }
 
+func TestDoctorCmd_Apply_FixDuplicatesFlags(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantErr  string
+		wantOpts RepairOptions
+	}{
+		{"defaults", []string{"--apply"}, "", RepairOptions{}},
+		{"fix duplicates", []string{"--apply", "--fix-duplicates"}, "", RepairOptions{FixDuplicates: true}},
+		{"keep", []string{"--apply", "--fix-duplicates", "--keep", "200,300"}, "", RepairOptions{FixDuplicates: true, Keep: []string{"200", "300"}}},
+		{"fix duplicates without apply", []string{"--fix-duplicates"}, "--fix-duplicates requires --apply", RepairOptions{}},
+		{"keep without fix duplicates", []string{"--apply", "--keep", "200"}, "--keep requires --fix-duplicates", RepairOptions{}},
+		{"reparent orphans", []string{"--apply", "--reparent-orphans"}, "", RepairOptions{ReparentOrphans: true}},
+		{"reparent orphans without apply", []string{"--reparent-orphans"}, "--reparent-orphans requires --apply", RepairOptions{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repairer := &mockRepairRunner{result: &RepairResult{}}
+			cmd := NewDoctorCmd(&mockCheckRunner{result: &CheckResult{}}, repairer)
+			cmd.SetOut(new(bytes.Buffer))
+			cmd.SetErr(new(bytes.Buffer))
+			cmd.SetArgs(tt.args)
+
+			err := cmd.Execute()
+
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if repairer.opts.FixDuplicates != tt.wantOpts.FixDuplicates ||
+				repairer.opts.ReparentOrphans != tt.wantOpts.ReparentOrphans ||
+				strings.Join(repairer.opts.Keep, ",") != strings.Join(tt.wantOpts.Keep, ",") {
+				t.Errorf("opts = %+v, want %+v", repairer.opts, tt.wantOpts)
+			}
+		})
+	}
+}
+
 func TestDoctorCmd_Apply_Idempotent(t *testing.T) {
 	checker := &mockCheckRunner{result: &CheckResult{}}
 	// Second run returns no repairs (everything already fixed)
@@ -669,9 +715,9 @@ type trackingCheckRunner struct {
 	called bool
 }
 
-func (t *trackingCheckRunner) Check(ctx context.Context) (*CheckResult, error) {
+func (t *trackingCheckRunner) Check(ctx context.Context, filter CheckFilter) (*CheckResult, error) {
 	t.called = true
-	return t.mockCheckRunner.Check(ctx)
+	return t.mockCheckRunner.Check(ctx, filter)
 }
 
 // TestDoctorCmd_Apply_CheckerInvokedAfterRepair verifies that the doctor
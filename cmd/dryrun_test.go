@@ -200,7 +200,7 @@ func (s *spyTypesService) ListTypes(ctx context.Context, selector string) (*Type
 	return nil, nil
 }
 
-func (s *spyTypesService) AddType(ctx context.Context, docType, selector string, apply bool) (*TypesModifyResult, error) {
+func (s *spyTypesService) AddType(ctx context.Context, docType, selector, template string, apply bool) (*TypesModifyResult, error) {
 	s.applyPassed = apply
 	return s.addResult, s.addErr
 }
@@ -210,6 +210,16 @@ func (s *spyTypesService) RemoveType(ctx context.Context, docType, selector stri
 	return s.removeResult, s.removeErr
 }
 
+func (s *spyTypesService) AddTypes(ctx context.Context, docType, selector, template string, apply bool) (*TypesBatchResult, error) {
+	s.applyPassed = apply
+	return &TypesBatchResult{}, nil
+}
+
+func (s *spyTypesService) RemoveTypes(ctx context.Context, docType, selector string, apply bool) (*TypesBatchResult, error) {
+	s.applyPassed = apply
+	return &TypesBatchResult{}, nil
+}
+
 func TestTypesAddCmd_DryRunPreventsServiceMutation(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -11,6 +11,9 @@ import (
 // ErrNotInProject is returned when a command is run outside a linemark project.
 var ErrNotInProject = errors.New("not in a linemark project (no .linemark/ directory found)")
 
+// ErrInvalidFormat is returned when --format names an unsupported output format.
+var ErrInvalidFormat = errors.New("invalid output format")
+
 // ContextError adds operation and path context to an underlying error.
 type ContextError struct {
 	Op   string
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/eykd/linemark-go/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+// IndentRunner defines the interface for running the indent and outdent
+// operations.
+type IndentRunner interface {
+	Indent(ctx context.Context, selector string, rebalance bool, apply bool) (*MoveResult, error)
+	Outdent(ctx context.Context, selector string, rebalance bool, apply bool) (*MoveResult, error)
+}
+
+// NewIndentCmd creates the indent command with the given runner.
+func NewIndentCmd(runner IndentRunner) *cobra.Command {
+	var run shiftFunc
+	if runner != nil {
+		run = runner.Indent
+	}
+	return newShiftCmd("indent <selector>", "Make a node the last child of its previous sibling", run)
+}
+
+// NewOutdentCmd creates the outdent command with the given runner.
+func NewOutdentCmd(runner IndentRunner) *cobra.Command {
+	var run shiftFunc
+	if runner != nil {
+		run = runner.Outdent
+	}
+	return newShiftCmd("outdent <selector>", "Make a node the next sibling after its parent", run)
+}
+
+// shiftFunc is the runner method an indent or outdent command calls.
+type shiftFunc func(ctx context.Context, selector string, rebalance bool, apply bool) (*MoveResult, error)
+
+// newShiftCmd builds the indent and outdent commands, which differ only in
+// the runner method they call. A nil run means no project was found.
+func newShiftCmd(use, short string, run shiftFunc) *cobra.Command {
+	var jsonOutput bool
+	var noRebalance bool
+
+	cmd := &cobra.Command{
+		Use:          use,
+		Short:        short,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if run == nil {
+				return ErrNotInProject
+			}
+
+			selector := args[0]
+			if _, err := domain.ParseSelector(selector); err != nil {
+				return fmt.Errorf("invalid selector %q: %w", selector, err)
+			}
+
+			isDryRun := GetDryRun()
+			result, err := run(cmd.Context(), selector, !noRebalance, !isDryRun)
+			if err != nil {
+				return err
+			}
+
+			writeMoveResult(cmd, result, isDryRun, jsonOutput)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output results as JSON")
+	cmd.Flags().BoolVar(&noRebalance, "no-rebalance", false, "Fail instead of renumbering neighbouring siblings when no gap exists")
+
+	return cmd
+}
@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/eykd/linemark-go/internal/domain"
+	"github.com/eykd/linemark-go/internal/outline"
+)
+
+// mockIndentRunner is a test double for IndentRunner.
+type mockIndentRunner struct {
+	result    *MoveResult
+	err       error
+	op        string
+	selector  string
+	rebalance bool
+	apply     bool
+}
+
+func (m *mockIndentRunner) Indent(ctx context.Context, selector string, rebalance bool, apply bool) (*MoveResult, error) {
+	m.op, m.selector, m.rebalance, m.apply = "indent", selector, rebalance, apply
+	return m.result, m.err
+}
+
+func (m *mockIndentRunner) Outdent(ctx context.Context, selector string, rebalance bool, apply bool) (*MoveResult, error) {
+	m.op, m.selector, m.rebalance, m.apply = "outdent", selector, rebalance, apply
+	return m.result, m.err
+}
+
+func indentFixture() *MoveResult {
+	return &MoveResult{
+		Renames:    []RenameEntry{{Old: "200_SID004GGHH_draft_two.md", New: "100-300_SID004GGHH_draft_two.md"}},
+		Rebalanced: []RenameEntry{{Old: "100-101_SID005IIJJ_draft_x.md", New: "100-150_SID005IIJJ_draft_x.md"}},
+	}
+}
+
+// runIndentCmd runs the indent and outdent commands under a root command.
+func runIndentCmd(t *testing.T, runner IndentRunner, args ...string) (string, error) {
+	t.Helper()
+	root := NewRootCmd()
+	root.AddCommand(NewIndentCmd(runner), NewOutdentCmd(runner))
+	buf := new(bytes.Buffer)
+	root.SetOut(buf)
+	root.SetErr(new(bytes.Buffer))
+	root.SetArgs(args)
+	t.Cleanup(func() { dryRun, jsonFlag = false, false })
+	err := root.Execute()
+	return buf.String(), err
+}
+
+func TestIndentCmd_CallsRunner(t *testing.T) {
+	tests := []struct {
+		args          []string
+		wantOp        string
+		wantSelector  string
+		wantRebalance bool
+		wantApply     bool
+	}{
+		{[]string{"indent", "200"}, "indent", "200", true, true},
+		{[]string{"outdent", "sid:SID002CCDD"}, "outdent", "sid:SID002CCDD", true, true},
+		{[]string{"indent", "--no-rebalance", "200"}, "indent", "200", false, true},
+		{[]string{"outdent", "--dry-run", "100-200"}, "outdent", "100-200", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			runner := &mockIndentRunner{result: indentFixture()}
+
+			_, err := runIndentCmd(t, runner, tt.args...)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if runner.op != tt.wantOp || runner.selector != tt.wantSelector || runner.rebalance != tt.wantRebalance || runner.apply != tt.wantApply {
+				t.Errorf("runner got %s(%q, rebalance=%v, apply=%v), want %s(%q, rebalance=%v, apply=%v)",
+					runner.op, runner.selector, runner.rebalance, runner.apply,
+					tt.wantOp, tt.wantSelector, tt.wantRebalance, tt.wantApply)
+			}
+		})
+	}
+}
+
+func TestIndentCmd_HumanReadableOutput(t *testing.T) {
+	out, err := runIndentCmd(t, &mockIndentRunner{result: indentFixture()}, "indent", "200")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "  200_SID004GGHH_draft_two.md -> 100-300_SID004GGHH_draft_two.md\n  100-101_SID005IIJJ_draft_x.md -> 100-150_SID005IIJJ_draft_x.md\n"
+	if out != want {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+}
+
+func TestOutdentCmd_JSONOutput(t *testing.T) {
+	for _, args := range [][]string{
+		{"outdent", "--json", "--dry-run", "100-200"},
+		{"--json", "--dry-run", "outdent", "100-200"},
+	} {
+		t.Run(strings.Join(args, " "), func(t *testing.T) {
+			out, err := runIndentCmd(t, &mockIndentRunner{result: indentFixture()}, args...)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var result MoveResult
+			if err := json.Unmarshal([]byte(out), &result); err != nil {
+				t.Fatalf("invalid JSON: %v\nraw: %s", err, out)
+			}
+			if !result.Planned || len(result.Renames) != 1 || len(result.Rebalanced) != 1 {
+				t.Errorf("result = %+v, want the planned renames", result)
+			}
+		})
+	}
+}
+
+func TestIndentCmd_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		err  error
+		want string
+	}{
+		{"invalid selector", []string{"indent", "not a selector!"}, nil, "invalid selector"},
+		{"missing selector", []string{"outdent"}, nil, "accepts 1 arg(s)"},
+		{"runner error", []string{"outdent", "100"}, outline.ErrAlreadyTopLevel, "already at the top level"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runIndentCmd(t, &mockIndentRunner{err: tt.err}, tt.args...)
+
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// --- indentAdapter tests ---
+
+func TestIndentAdapter(t *testing.T) {
+	tests := []struct {
+		name      string
+		call      func(*indentAdapter) (*MoveResult, error)
+		wantOp    string
+		wantOpts  int
+		wantApply bool
+	}{
+		{"indent", func(a *indentAdapter) (*MoveResult, error) {
+			return a.Indent(context.Background(), "200", true, true)
+		}, "indent", 0, true},
+		{"outdent without rebalancing", func(a *indentAdapter) (*MoveResult, error) {
+			return a.Outdent(context.Background(), "200", false, false)
+		}, "outdent", 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubOutlineService{moveResult: &outline.MoveResult{
+				Renames:    map[string]string{"a.md": "b.md"},
+				Rebalanced: map[string]string{"c.md": "d.md"},
+			}}
+
+			result, err := tt.call(&indentAdapter{svc: stub})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if stub.shiftOp != tt.wantOp || stub.moveSrc.Value() != "200" || stub.moveSrc.Kind() != domain.SelectorMP {
+				t.Errorf("service got %s(%v)", stub.shiftOp, stub.moveSrc)
+			}
+			if len(stub.moveOpts) != tt.wantOpts || stub.moveApply != tt.wantApply {
+				t.Errorf("opts = %d, apply = %v; want %d, %v", len(stub.moveOpts), stub.moveApply, tt.wantOpts, tt.wantApply)
+			}
+			if len(result.Renames) != 1 || result.Renames[0] != (RenameEntry{Old: "a.md", New: "b.md"}) ||
+				len(result.Rebalanced) != 1 || result.Rebalanced[0] != (RenameEntry{Old: "c.md", New: "d.md"}) {
+				t.Errorf("result = %+v", result)
+			}
+		})
+	}
+}
+
+func TestIndentAdapter_Errors(t *testing.T) {
+	svcErr := errors.New("locked")
+	adapter := &indentAdapter{svc: &stubOutlineService{moveErr: svcErr}}
+
+	if _, err := adapter.Indent(context.Background(), "not a selector!", true, true); err == nil {
+		t.Error("expected a selector parse error")
+	}
+	if _, err := adapter.Outdent(context.Background(), "200", true, true); !errors.Is(err, svcErr) {
+		t.Errorf("error = %v, want %v", err, svcErr)
+	}
+}
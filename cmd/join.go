@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/eykd/linemark-go/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+// JoinResult holds the outcome of a join operation. The node at RemovedMP
+// was joined into the node at MP.
+type JoinResult struct {
+	MP         string   `json:"mp"`
+	SID        string   `json:"sid"`
+	RemovedMP  string   `json:"removed_mp"`
+	RemovedSID string   `json:"removed_sid"`
+	Written    []string `json:"written"`
+	Deleted    []string `json:"deleted"`
+	Planned    bool     `json:"planned"`
+}
+
+// JoinRunner defines the interface for running the join operation.
+type JoinRunner interface {
+	Join(ctx context.Context, first, second string, apply bool) (*JoinResult, error)
+}
+
+// NewJoinCmd creates the join command with the given runner.
+func NewJoinCmd(runner JoinRunner) *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "join <first> <second>",
+		Short: "Merge a node's documents into another node and delete it",
+		Long: `Append the draft, notes and other documents of the second node to the
+matching documents of the first, then delete the second node. The second
+node's frontmatter is dropped, a document type the first node lacks is
+moved to it whole, and the second node's SID stays reserved. The nodes
+must be siblings, and the second must have no children.
+
+To merge snapshots of a whole outline, use "lmk merge".`,
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if runner == nil {
+				return ErrNotInProject
+			}
+			for _, selector := range args {
+				if _, err := domain.ParseSelector(selector); err != nil {
+					return fmt.Errorf("invalid selector %q: %w", selector, err)
+				}
+			}
+
+			isDryRun := GetDryRun()
+			result, err := runner.Join(cmd.Context(), args[0], args[1], !isDryRun)
+			if err != nil {
+				return err
+			}
+			result.Planned = isDryRun
+
+			if jsonOutput || GetJSON() {
+				writeJSON(cmd.OutOrStdout(), result)
+				return nil
+			}
+			w := cmd.OutOrStdout()
+			verb := "Joined"
+			if isDryRun {
+				verb = "Would join"
+			}
+			fmt.Fprintf(w, "%s %s (%s) into %s (%s)\n", verb, result.RemovedMP, result.RemovedSID, result.MP, result.SID)
+			for _, name := range result.Written {
+				fmt.Fprintf(w, "  write  %s\n", name)
+			}
+			for _, name := range result.Deleted {
+				fmt.Fprintf(w, "  delete %s\n", name)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output results as JSON")
+
+	return cmd
+}
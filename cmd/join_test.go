@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/eykd/linemark-go/internal/outline"
+)
+
+// mockJoinRunner is a test double for JoinRunner.
+type mockJoinRunner struct {
+	result *JoinResult
+	err    error
+	called bool
+	first  string
+	second string
+	apply  bool
+}
+
+func (m *mockJoinRunner) Join(ctx context.Context, first, second string, apply bool) (*JoinResult, error) {
+	m.called = true
+	m.first, m.second, m.apply = first, second, apply
+	return m.result, m.err
+}
+
+func joinFixture() *JoinResult {
+	return &JoinResult{
+		MP:         "100",
+		SID:        "SID001AABB",
+		RemovedMP:  "200",
+		RemovedSID: "SID002CCDD",
+		Written:    []string{"100_SID001AABB_draft_one.md", "100_SID001AABB_notes.md"},
+		Deleted:    []string{"200_SID002CCDD_draft_two.md", "200_SID002CCDD_notes.md"},
+	}
+}
+
+// runJoinCmd runs the join command under a root command.
+func runJoinCmd(t *testing.T, runner JoinRunner, args ...string) (string, error) {
+	t.Helper()
+	root := NewRootCmd()
+	root.AddCommand(NewJoinCmd(runner))
+	buf := new(bytes.Buffer)
+	root.SetOut(buf)
+	root.SetErr(new(bytes.Buffer))
+	root.SetArgs(append([]string{"join"}, args...))
+	t.Cleanup(func() { dryRun, jsonFlag = false, false })
+	err := root.Execute()
+	return buf.String(), err
+}
+
+func TestJoinCmd_HumanReadableOutput(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		verb  string
+		apply bool
+	}{
+		{"applied", nil, "Joined", true},
+		{"dry run", []string{"--dry-run"}, "Would join", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &mockJoinRunner{result: joinFixture()}
+
+			out, err := runJoinCmd(t, runner, append([]string{"100", "sid:SID002CCDD"}, tt.args...)...)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if runner.first != "100" || runner.second != "sid:SID002CCDD" || runner.apply != tt.apply {
+				t.Errorf("runner got %q, %q, apply %v", runner.first, runner.second, runner.apply)
+			}
+			want := tt.verb + " 200 (SID002CCDD) into 100 (SID001AABB)\n" +
+				"  write  100_SID001AABB_draft_one.md\n" +
+				"  write  100_SID001AABB_notes.md\n" +
+				"  delete 200_SID002CCDD_draft_two.md\n" +
+				"  delete 200_SID002CCDD_notes.md\n"
+			if out != want {
+				t.Errorf("output = %q, want %q", out, want)
+			}
+		})
+	}
+}
+
+func TestJoinCmd_JSONOutput(t *testing.T) {
+	out, err := runJoinCmd(t, &mockJoinRunner{result: joinFixture()}, "100", "200", "--json", "--dry-run")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var result JoinResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\nraw: %s", err, out)
+	}
+	want := joinFixture()
+	want.Planned = true
+	if !reflect.DeepEqual(&result, want) {
+		t.Errorf("result = %+v, want %+v", result, want)
+	}
+	for _, key := range []string{`"removed_mp"`, `"removed_sid"`, `"written"`, `"deleted"`, `"planned"`} {
+		if !strings.Contains(out, key) {
+			t.Errorf("JSON output missing %s: %s", key, out)
+		}
+	}
+}
+
+func TestJoinCmd_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		err        error
+		want       string
+		wantCalled bool
+	}{
+		{"one selector", []string{"100"}, nil, "accepts 2 arg(s)", false},
+		{"invalid first", []string{"not a selector!", "200"}, nil, "invalid selector", false},
+		{"invalid second", []string{"100", "not a selector!"}, nil, "invalid selector", false},
+		{"runner error", []string{"100", "300"}, outline.ErrJoinHasChildren, "has children", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &mockJoinRunner{err: tt.err}
+
+			_, err := runJoinCmd(t, runner, tt.args...)
+
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.want)
+			}
+			if runner.called != tt.wantCalled {
+				t.Errorf("runner called = %v, want %v", runner.called, tt.wantCalled)
+			}
+		})
+	}
+}
+
+// --- joinAdapter tests ---
+
+func TestJoinAdapter_Join(t *testing.T) {
+	stub := &stubOutlineService{joinResult: &outline.JoinResult{
+		MP: "100", SID: "SID001AABB", RemovedMP: "200", RemovedSID: "SID002CCDD",
+		Written: []string{"100_SID001AABB_draft_one.md"}, Deleted: []string{"200_SID002CCDD_draft_two.md"},
+	}}
+	adapter := &joinAdapter{svc: stub}
+
+	result, err := adapter.Join(context.Background(), "100", "sid:SID002CCDD", true)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := &JoinResult{
+		MP: "100", SID: "SID001AABB", RemovedMP: "200", RemovedSID: "SID002CCDD",
+		Written: []string{"100_SID001AABB_draft_one.md"}, Deleted: []string{"200_SID002CCDD_draft_two.md"},
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("result = %+v, want %+v", result, want)
+	}
+	if stub.moveSrc.Value() != "100" || stub.moveTgt.Value() != "SID002CCDD" || !stub.moveApply {
+		t.Errorf("service got %v, %v, apply %v", stub.moveSrc, stub.moveTgt, stub.moveApply)
+	}
+}
+
+func TestJoinAdapter_Errors(t *testing.T) {
+	adapter := &joinAdapter{svc: &stubOutlineService{moveErr: errors.New("locked")}}
+
+	tests := []struct {
+		name   string
+		first  string
+		second string
+	}{
+		{"invalid first", "not a selector!", "200"},
+		{"invalid second", "100", "not a selector!"},
+		{"service error", "100", "200"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := adapter.Join(context.Background(), tt.first, tt.second, true); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
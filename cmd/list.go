@@ -14,9 +14,10 @@ type ListResult struct {
 	Outline domain.Outline
 }
 
-// ListRunner defines the interface for running the list operation.
+// ListRunner defines the interface for running the list operation. A
+// non-empty selector expression limits the outline to the nodes it selects.
 type ListRunner interface {
-	List(ctx context.Context) (*ListResult, error)
+	List(ctx context.Context, selector string) (*ListResult, error)
 }
 
 // treeNode represents a node in the hierarchical tree for display.
@@ -41,14 +42,22 @@ func NewListCmd(runner ListRunner) *cobra.Command {
 	var typeFilter string
 
 	cmd := &cobra.Command{
-		Use:          "list",
+		Use:          "list [selector]",
 		Short:        "Display the project outline as a tree",
+		Args:         cobra.MaximumNArgs(1),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if runner == nil {
 				return ErrNotInProject
 			}
-			result, err := runner.List(cmd.Context())
+			var selector string
+			if len(args) > 0 {
+				selector = args[0]
+				if _, err := domain.ParseSelectorExpr(selector); err != nil {
+					return fmt.Errorf("invalid selector %q: %w", selector, err)
+				}
+			}
+			result, err := runner.List(cmd.Context(), selector)
 			if err != nil {
 				return err
 			}
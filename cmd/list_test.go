@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -14,11 +15,13 @@ import (
 
 // mockListRunner is a test double for ListRunner.
 type mockListRunner struct {
-	result *ListResult
-	err    error
+	result   *ListResult
+	err      error
+	selector string
 }
 
-func (m *mockListRunner) List(ctx context.Context) (*ListResult, error) {
+func (m *mockListRunner) List(ctx context.Context, selector string) (*ListResult, error) {
+	m.selector = selector
 	return m.result, m.err
 }
 
@@ -779,3 +782,29 @@ func TestListCmd_MultipleRootsDepthOneExactFormat(t *testing.T) {
 		t.Errorf("tree output with --depth=1 mismatch.\nwant:\n%s\ngot:\n%s", want, got)
 	}
 }
+
+func TestListCmd_Selector(t *testing.T) {
+	runner := &mockListRunner{result: &ListResult{}}
+	cmd, _ := newTestListCmd(runner, "001/**")
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if runner.selector != "001/**" {
+		t.Errorf("selector = %q, want %q", runner.selector, "001/**")
+	}
+}
+
+func TestListCmd_InvalidSelector(t *testing.T) {
+	runner := &mockListRunner{result: &ListResult{}}
+	cmd, _ := newTestListCmd(runner, "nope!/*")
+
+	err := cmd.Execute()
+
+	if !errors.Is(err, domain.ErrInvalidSelector) {
+		t.Errorf("error = %v, want ErrInvalidSelector", err)
+	}
+	if runner.selector != "" {
+		t.Errorf("runner called with %q for an invalid selector", runner.selector)
+	}
+}
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/eykd/linemark-go/internal/outline"
+	"github.com/spf13/cobra"
+)
+
+// MergeConflict is a change the merge could not reconcile on its own.
+type MergeConflict struct {
+	Kind    string `json:"kind"`
+	SID     string `json:"sid"`
+	DocType string `json:"doc_type,omitempty"`
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// MergeResult holds the outcome of a three-way outline merge.
+type MergeResult struct {
+	Written   []string        `json:"written"`
+	Deleted   []string        `json:"deleted"`
+	Conflicts []MergeConflict `json:"conflicts"`
+	Planned   bool            `json:"planned"`
+}
+
+// MergeRunner executes three-way outline merges.
+type MergeRunner interface {
+	Merge(ctx context.Context, base, ours, theirs string, apply bool) (*MergeResult, error)
+	ResolveMerge(ctx context.Context, apply bool) (*MergeResult, error)
+}
+
+// MergeConflictsError is returned when a merge leaves conflicts to resolve.
+type MergeConflictsError struct {
+	Count int
+}
+
+// Error implements the error interface.
+func (e *MergeConflictsError) Error() string {
+	return fmt.Sprintf("merge left %d conflict(s)", e.Count)
+}
+
+// ExitCode returns the exit code for conflicts (always 2).
+func (e *MergeConflictsError) ExitCode() int {
+	return 2
+}
+
+// NewMergeCmd creates the merge command with the given runner.
+func NewMergeCmd(runner MergeRunner) *cobra.Command {
+	var resolve bool
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "merge (<base> <ours> <theirs> | --resolve)",
+		Short: "Merge three snapshots of an outline into the project",
+		Long: `Merge three directory snapshots of an outline, matching nodes by SID, and
+write the result into the project. Moves, renumberings, renames, additions
+and deletions on either side are combined; a node moved under different
+parents on each side, a node deleted on one side but changed on the other,
+and a document edited on both sides are reported as conflicts.
+
+With --resolve, merge the merge base, HEAD and MERGE_HEAD versions of the
+project directory instead, to resolve a git merge that stopped with
+conflicts in the outline. Stage and commit the result once any reported
+conflicts are resolved. This runs after git merge: the merge-driver command
+merges one file's content at a time, but git never hands a driver the
+renames that renumbering produces.`,
+		SilenceUsage: true,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if resolve {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.ExactArgs(3)(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if runner == nil {
+				return ErrNotInProject
+			}
+			var result *MergeResult
+			var err error
+			if resolve {
+				result, err = runner.ResolveMerge(cmd.Context(), !GetDryRun())
+			} else {
+				result, err = runner.Merge(cmd.Context(), args[0], args[1], args[2], !GetDryRun())
+			}
+			if err != nil {
+				return err
+			}
+			return reportMerge(cmd.OutOrStdout(), result, jsonOutput || GetJSON())
+		},
+	}
+
+	cmd.Flags().BoolVar(&resolve, "resolve", false, "Resolve the in-progress git merge of the outline")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output results as JSON")
+
+	return cmd
+}
+
+// MergeFileFunc merges the base and theirs versions of a file into the ours
+// file, reporting whether conflicts remain.
+type MergeFileFunc func(ctx context.Context, base, ours, theirs string) (conflict bool, err error)
+
+// NewMergeDriverCmd creates the merge-driver command with the given merge
+// function.
+func NewMergeDriverCmd(mergeFile MergeFileFunc) *cobra.Command {
+	return &cobra.Command{
+		Use:   "merge-driver <base> <ours> <theirs> [<path>]",
+		Short: "Merge one outline file as a git merge driver",
+		Long: `Merge one version-controlled outline file as a git merge driver, called
+with git's %O %A %B %P placeholders: the merged content replaces the ours
+file. A document changed on one side takes that side's content; one changed
+differently on both sides keeps both versions between conflict markers, and
+the command exits non-zero so git reports the conflict. Configure it with
+
+  git config merge.lmk.driver "lmk merge-driver %O %A %B %P"
+
+and "*.md merge=lmk" in .gitattributes. Git hands a driver one file at a
+time, so renumbering on either side still leaves renamed files for
+lmk merge --resolve to reconcile.`,
+		SilenceUsage: true,
+		Args:         cobra.RangeArgs(3, 4),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			conflict, err := mergeFile(cmd.Context(), args[0], args[1], args[2])
+			if err != nil {
+				return err
+			}
+			if !conflict {
+				return nil
+			}
+			path := args[1]
+			if len(args) == 4 {
+				path = args[3]
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "CONFLICT %s [content] changed on both sides; kept both between conflict markers\n", path)
+			return &MergeConflictsError{Count: 1}
+		},
+	}
+}
+
+// mergeFileImpl merges three versions of a file on disk into ours.
+func mergeFileImpl(_ context.Context, base, ours, theirs string) (bool, error) {
+	var contents [3]string
+	for i, name := range []string{base, ours, theirs} {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return false, err
+		}
+		contents[i] = string(data)
+	}
+	merged, conflict := outline.MergeDocument(contents[0], contents[1], contents[2])
+	if err := os.WriteFile(ours, []byte(merged), 0o644); err != nil {
+		return false, err
+	}
+	return conflict, nil
+}
+
+// reportMerge writes the merge result and returns a MergeConflictsError
+// when conflicts remain.
+func reportMerge(w io.Writer, result *MergeResult, jsonOutput bool) error {
+	result.Planned = GetDryRun()
+	if jsonOutput {
+		writeJSON(w, result)
+	} else {
+		writeMergeHuman(w, result)
+	}
+	if len(result.Conflicts) > 0 {
+		return &MergeConflictsError{Count: len(result.Conflicts)}
+	}
+	return nil
+}
+
+func writeMergeHuman(w io.Writer, result *MergeResult) {
+	for _, name := range result.Written {
+		fmt.Fprintf(w, "  write  %s\n", name)
+	}
+	for _, name := range result.Deleted {
+		fmt.Fprintf(w, "  delete %s\n", name)
+	}
+	for _, c := range result.Conflicts {
+		fmt.Fprintf(w, "CONFLICT %s [%s] %s\n", c.Path, c.Kind, c.Message)
+	}
+}
@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// mockMergeRunner is a test double for MergeRunner.
+type mockMergeRunner struct {
+	result      *MergeResult
+	err         error
+	sides       []string
+	applyPassed bool
+	resolved    bool
+}
+
+func (m *mockMergeRunner) Merge(ctx context.Context, base, ours, theirs string, apply bool) (*MergeResult, error) {
+	m.sides = []string{base, ours, theirs}
+	m.applyPassed = apply
+	return m.result, m.err
+}
+
+func (m *mockMergeRunner) ResolveMerge(ctx context.Context, apply bool) (*MergeResult, error) {
+	m.resolved = true
+	m.applyPassed = apply
+	return m.result, m.err
+}
+
+// runMergeCmd runs the merge commands under a root command, so the global
+// flags apply, and returns the output.
+func runMergeCmd(t *testing.T, runner MergeRunner, args ...string) (string, error) {
+	t.Helper()
+	root := NewRootCmd()
+	root.AddCommand(NewMergeCmd(runner))
+	buf := new(bytes.Buffer)
+	root.SetOut(buf)
+	root.SetErr(new(bytes.Buffer))
+	root.SetArgs(args)
+	t.Cleanup(func() { dryRun, jsonFlag = false, false })
+	err := root.Execute()
+	return buf.String(), err
+}
+
+func TestMergeCmd_RegisteredWithRoot(t *testing.T) {
+	cmd, _, err := rootCmd.Find([]string{"merge"})
+	if err != nil || cmd.Name() != "merge" {
+		t.Error("merge command not registered with root")
+	}
+}
+
+func TestMergeCmd_PassesSidesAndReports(t *testing.T) {
+	runner := &mockMergeRunner{result: &MergeResult{
+		Written: []string{"100_SID001AABB_draft_a.md"},
+		Deleted: []string{"200_SID002AABB_draft_b.md"},
+	}}
+
+	out, err := runMergeCmd(t, runner, "merge", "base", "ours", "theirs")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(runner.sides) != 3 || runner.sides[0] != "base" || runner.sides[2] != "theirs" || !runner.applyPassed {
+		t.Errorf("runner called with %v, apply %v", runner.sides, runner.applyPassed)
+	}
+	want := "  write  100_SID001AABB_draft_a.md\n  delete 200_SID002AABB_draft_b.md\n"
+	if out != want {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+}
+
+func TestMergeCmd_RequiresThreeSides(t *testing.T) {
+	if _, err := runMergeCmd(t, &mockMergeRunner{}, "merge", "base", "ours"); err == nil {
+		t.Error("expected an error for two arguments")
+	}
+}
+
+func TestMergeCmd_ResolveTakesNoSides(t *testing.T) {
+	runner := &mockMergeRunner{}
+
+	if _, err := runMergeCmd(t, runner, "merge", "--resolve", "base", "ours", "theirs"); err == nil {
+		t.Error("expected an error for sides with --resolve")
+	}
+	if runner.resolved || runner.sides != nil {
+		t.Error("runner called despite invalid arguments")
+	}
+}
+
+func TestMergeCmd_ResolveDryRunJSON(t *testing.T) {
+	runner := &mockMergeRunner{result: &MergeResult{Written: []string{}, Deleted: []string{}, Conflicts: []MergeConflict{}}}
+
+	out, err := runMergeCmd(t, runner, "--dry-run", "--json", "merge", "--resolve")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !runner.resolved || runner.applyPassed {
+		t.Errorf("resolved = %v, apply = %v; want resolved without applying", runner.resolved, runner.applyPassed)
+	}
+	var got MergeResult
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if !got.Planned {
+		t.Error("planned = false, want true")
+	}
+}
+
+func TestMergeCmd_LocalJSONFlag(t *testing.T) {
+	runner := &mockMergeRunner{result: &MergeResult{Written: []string{"a.md"}, Deleted: []string{}, Conflicts: []MergeConflict{}}}
+	cmd := NewMergeCmd(runner)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"base", "ours", "theirs", "--json"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got MergeResult
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil || len(got.Written) != 1 {
+		t.Errorf("JSON = %s (%v)", buf.String(), err)
+	}
+}
+
+func TestMergeCmd_ConflictsExitWithCode2(t *testing.T) {
+	runner := &mockMergeRunner{result: &MergeResult{Conflicts: []MergeConflict{{
+		Kind: "move", SID: "SID001AABB", Path: "100_SID001AABB_draft_a.md", Message: "moved both ways",
+	}}}}
+
+	for _, args := range [][]string{{"merge", "a", "b", "c"}, {"merge", "--resolve"}} {
+		out, err := runMergeCmd(t, runner, args...)
+
+		var conflictsErr *MergeConflictsError
+		if !errors.As(err, &conflictsErr) || ExitCodeFromError(err) != 2 {
+			t.Fatalf("%v: error = %v, want MergeConflictsError", args, err)
+		}
+		if conflictsErr.Error() != "merge left 1 conflict(s)" {
+			t.Errorf("%v: error message = %q", args, conflictsErr.Error())
+		}
+		if out != "CONFLICT 100_SID001AABB_draft_a.md [move] moved both ways\n" {
+			t.Errorf("%v: output = %q", args, out)
+		}
+	}
+}
+
+func TestMergeCmd_Errors(t *testing.T) {
+	runErr := errors.New("no merge in progress")
+
+	for _, args := range [][]string{{"merge", "a", "b", "c"}, {"merge", "--resolve"}} {
+		if _, err := runMergeCmd(t, nil, args...); !errors.Is(err, ErrNotInProject) {
+			t.Errorf("%v with nil runner: error = %v, want ErrNotInProject", args, err)
+		}
+		if _, err := runMergeCmd(t, &mockMergeRunner{err: runErr}, args...); !errors.Is(err, runErr) {
+			t.Errorf("%v: error = %v, want %v", args, err, runErr)
+		}
+	}
+}
+
+func TestMergeDriverCmd_MergesIntoOurs(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name       string
+		ours       string
+		theirs     string
+		want       string
+		wantOutput string
+		wantCode   int
+	}{
+		{name: "clean", ours: "base\n", theirs: "theirs\n", want: "theirs\n"},
+		{
+			name: "conflict", ours: "ours\n", theirs: "theirs\n",
+			want:       "<<<<<<< ours\nours\n=======\ntheirs\n>>>>>>> theirs\n",
+			wantOutput: "CONFLICT 100_SID001AABB_draft_a.md [content] changed on both sides; kept both between conflict markers\n",
+			wantCode:   2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, ours, theirs := write("base", "base\n"), write("ours", tt.ours), write("theirs", tt.theirs)
+			cmd := NewMergeDriverCmd(mergeFileImpl)
+			buf := new(bytes.Buffer)
+			cmd.SetOut(buf)
+			cmd.SetErr(new(bytes.Buffer))
+			cmd.SetArgs([]string{base, ours, theirs, "100_SID001AABB_draft_a.md"})
+
+			err := cmd.Execute()
+
+			if ExitCodeFromError(err) != tt.wantCode {
+				t.Errorf("error = %v, want exit code %d", err, tt.wantCode)
+			}
+			if got, _ := os.ReadFile(ours); string(got) != tt.want {
+				t.Errorf("ours = %q, want %q", got, tt.want)
+			}
+			if buf.String() != tt.wantOutput {
+				t.Errorf("output = %q, want %q", buf.String(), tt.wantOutput)
+			}
+		})
+	}
+}
+
+func TestMergeDriverCmd_Errors(t *testing.T) {
+	readErr := errors.New("unreadable")
+	mergeFile := func(ctx context.Context, base, ours, theirs string) (bool, error) { return false, readErr }
+
+	cmd := NewMergeDriverCmd(mergeFile)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"base", "ours", "theirs"})
+
+	if err := cmd.Execute(); !errors.Is(err, readErr) {
+		t.Errorf("error = %v, want %v", err, readErr)
+	}
+	if _, err := mergeFileImpl(context.Background(), filepath.Join(t.TempDir(), "missing"), "ours", "theirs"); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
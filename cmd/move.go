@@ -10,13 +10,14 @@ import (
 
 // MoveResult holds the outcome of a move operation.
 type MoveResult struct {
-	Renames []RenameEntry `json:"renames"`
-	Planned bool          `json:"planned"`
+	Renames    []RenameEntry `json:"renames"`
+	Rebalanced []RenameEntry `json:"rebalanced"`
+	Planned    bool          `json:"planned"`
 }
 
 // MoveRunner defines the interface for running the move operation.
 type MoveRunner interface {
-	Move(ctx context.Context, selector string, to string, before string, after string, apply bool) (*MoveResult, error)
+	Move(ctx context.Context, selector string, to string, before string, after string, rebalance bool, apply bool) (*MoveResult, error)
 }
 
 // NewMoveCmd creates the move command with the given runner.
@@ -25,6 +26,7 @@ func NewMoveCmd(runner MoveRunner) *cobra.Command {
 	var jsonOutput bool
 	var before string
 	var after string
+	var noRebalance bool
 
 	cmd := &cobra.Command{
 		Use:          "move <selector>",
@@ -52,22 +54,12 @@ func NewMoveCmd(runner MoveRunner) *cobra.Command {
 			}
 
 			isDryRun := GetDryRun()
-			result, err := runner.Move(cmd.Context(), selector, to, before, after, !isDryRun)
+			result, err := runner.Move(cmd.Context(), selector, to, before, after, !noRebalance, !isDryRun)
 			if err != nil {
 				return err
 			}
 
-			if isDryRun {
-				result.Planned = true
-			}
-
-			if jsonOutput || GetJSON() {
-				writeJSON(cmd.OutOrStdout(), result)
-			} else {
-				for _, r := range result.Renames {
-					fmt.Fprintf(cmd.OutOrStdout(), "  %s -> %s\n", r.Old, r.New)
-				}
-			}
+			writeMoveResult(cmd, result, isDryRun, jsonOutput)
 			return nil
 		},
 	}
@@ -76,6 +68,22 @@ func NewMoveCmd(runner MoveRunner) *cobra.Command {
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output results as JSON")
 	cmd.Flags().StringVar(&before, "before", "", "Place before this sibling")
 	cmd.Flags().StringVar(&after, "after", "", "Place after this sibling")
+	cmd.Flags().BoolVar(&noRebalance, "no-rebalance", false, "Fail instead of renumbering neighbouring siblings when no gap exists")
 
 	return cmd
 }
+
+// writeMoveResult prints the renames a move made, or planned under dry run.
+func writeMoveResult(cmd *cobra.Command, result *MoveResult, isDryRun, jsonOutput bool) {
+	if isDryRun {
+		result.Planned = true
+	}
+
+	if jsonOutput || GetJSON() {
+		writeJSON(cmd.OutOrStdout(), result)
+		return
+	}
+	for _, r := range append(result.Renames, result.Rebalanced...) {
+		fmt.Fprintf(cmd.OutOrStdout(), "  %s -> %s\n", r.Old, r.New)
+	}
+}
@@ -15,17 +15,19 @@ import (
 
 // mockMoveRunner is a test double for MoveRunner.
 type mockMoveRunner struct {
-	result   *MoveResult
-	err      error
-	called   bool
-	selector string
-	to       string
-	before   string
-	after    string
-	apply    bool
+	result    *MoveResult
+	err       error
+	called    bool
+	selector  string
+	to        string
+	before    string
+	after     string
+	rebalance bool
+	apply     bool
 }
 
-func (m *mockMoveRunner) Move(ctx context.Context, selector string, to string, before string, after string, apply bool) (*MoveResult, error) {
+func (m *mockMoveRunner) Move(ctx context.Context, selector string, to string, before string, after string, rebalance bool, apply bool) (*MoveResult, error) {
+	m.rebalance = rebalance
 	m.called = true
 	m.selector = selector
 	m.to = to
@@ -600,3 +602,43 @@ func TestMoveCmd_HumanReadableMultipleRenames(t *testing.T) {
 		t.Errorf("output should contain renamed descendant filename, got: %q", output)
 	}
 }
+
+func TestMoveCmd_NoRebalanceFlag(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		wantRebalance bool
+	}{
+		{"rebalances by default", []string{"001-200", "--to", "300"}, true},
+		{"--no-rebalance disables it", []string{"001-200", "--to", "300", "--no-rebalance"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &mockMoveRunner{result: moveFixture()}
+			cmd, _ := newTestMoveCmd(runner, tt.args...)
+
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if runner.rebalance != tt.wantRebalance {
+				t.Errorf("rebalance = %v, want %v", runner.rebalance, tt.wantRebalance)
+			}
+		})
+	}
+}
+
+func TestMoveCmd_HumanReadableRebalanced(t *testing.T) {
+	result := moveFixture()
+	result.Rebalanced = []RenameEntry{{Old: "300-001_B8kQ2mNp4Rs1_draft_two.md", New: "300-667_B8kQ2mNp4Rs1_draft_two.md"}}
+	runner := &mockMoveRunner{result: result}
+	cmd, buf := newTestMoveCmd(runner, "001-200", "--to", "300")
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "  300-001_B8kQ2mNp4Rs1_draft_two.md -> 300-667_B8kQ2mNp4Rs1_draft_two.md") {
+		t.Errorf("output should list the rebalanced sibling, got: %q", buf.String())
+	}
+}
@@ -18,3 +18,27 @@ func writeJSON(w io.Writer, v interface{}) {
 		fmt.Fprintf(w, "{\"error\":%q}\n", err.Error())
 	}
 }
+
+// outputFormat selects how check and doctor report findings.
+type outputFormat string
+
+const (
+	formatText  outputFormat = "text"
+	formatJSON  outputFormat = "json"
+	formatSARIF outputFormat = "sarif"
+)
+
+// resolveFormat returns the format named by --format. Without one, --json
+// selects JSON and text is the default.
+func resolveFormat(format string, jsonOutput bool) (outputFormat, error) {
+	switch f := outputFormat(format); f {
+	case "":
+		if jsonOutput {
+			return formatJSON, nil
+		}
+		return formatText, nil
+	case formatText, formatJSON, formatSARIF:
+		return f, nil
+	}
+	return "", fmt.Errorf("%w: %q (want text, json or sarif)", ErrInvalidFormat, format)
+}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/eykd/linemark-go/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+// errReorderAborted is returned when the reorder buffer is saved empty.
+var errReorderAborted = errors.New("reorder aborted: no children listed")
+
+// ReorderChild describes a child node offered for reordering.
+type ReorderChild struct {
+	SID   string
+	MP    string
+	Title string
+}
+
+// ReorderResult holds the outcome of a reorder operation.
+type ReorderResult struct {
+	Renames []RenameEntry `json:"renames"`
+	Planned bool          `json:"planned"`
+}
+
+// ReorderRunner lists and reorders the children of a node. An empty parent
+// selects the root-level nodes.
+type ReorderRunner interface {
+	Children(ctx context.Context, parent string) ([]ReorderChild, error)
+	Reorder(ctx context.Context, parent string, sids []string, apply bool) (*ReorderResult, error)
+}
+
+// Editor lets the user edit text and returns the saved result.
+type Editor func(ctx context.Context, text string) (string, error)
+
+// NewReorderCmd creates the reorder command with the given runner and editor.
+func NewReorderCmd(runner ReorderRunner, edit Editor) *cobra.Command {
+	var root bool
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "reorder <parent-selector> [<sid>...]",
+		Short: "Put a node's children in a new order",
+		Long: `Renumber the children of a node, or of the root with --root, so they follow
+the given SIDs, renaming their subtrees with them. Every child must be listed
+exactly once. Without SIDs, the children are opened in $VISUAL or $EDITOR,
+one per line, to be rearranged as in git rebase -i.`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if runner == nil {
+				return ErrNotInProject
+			}
+			var parent string
+			if !root {
+				if len(args) == 0 {
+					return fmt.Errorf("requires a parent selector, or --root")
+				}
+				parent, args = args[0], args[1:]
+				if _, err := domain.ParseSelector(parent); err != nil {
+					return fmt.Errorf("invalid selector %q: %w", parent, err)
+				}
+			}
+
+			sids := args
+			if len(sids) == 0 {
+				var err error
+				if sids, err = editReorder(cmd.Context(), runner, edit, parent); err != nil {
+					return err
+				}
+			}
+
+			isDryRun := GetDryRun()
+			result, err := runner.Reorder(cmd.Context(), parent, sids, !isDryRun)
+			if err != nil {
+				return err
+			}
+			result.Planned = isDryRun
+
+			w := cmd.OutOrStdout()
+			switch {
+			case jsonOutput || GetJSON():
+				writeJSON(w, result)
+			case len(result.Renames) == 0:
+				fmt.Fprintln(w, "Already in that order")
+			default:
+				for _, r := range result.Renames {
+					fmt.Fprintf(w, "  %s -> %s\n", r.Old, r.New)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&root, "root", false, "Reorder the root-level nodes")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output results as JSON")
+
+	return cmd
+}
+
+// editReorder lists the parent's children in the editor and returns the
+// SIDs in the order the user saved them.
+func editReorder(ctx context.Context, runner ReorderRunner, edit Editor, parent string) ([]string, error) {
+	children, err := runner.Children(ctx, parent)
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	for _, c := range children {
+		fmt.Fprintf(&b, "%s %s %s\n", c.SID, c.MP, c.Title)
+	}
+	b.WriteString("\n# Reorder the children by moving lines; the first word of each line is\n")
+	b.WriteString("# the SID. Every child must stay listed exactly once. Lines starting\n")
+	b.WriteString("# with # are ignored; removing every line aborts the reorder.\n")
+
+	text, err := edit(ctx, b.String())
+	if err != nil {
+		return nil, err
+	}
+
+	var sids []string
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 0 && !strings.HasPrefix(fields[0], "#") {
+			sids = append(sids, fields[0])
+		}
+	}
+	if len(sids) == 0 {
+		return nil, errReorderAborted
+	}
+	return sids, nil
+}
+
+// editTextImpl opens text in $VISUAL, $EDITOR or vi, in that order of
+// preference, and returns the saved file.
+func editTextImpl(ctx context.Context, text string) (string, error) {
+	f, err := os.CreateTemp("", "lmk-reorder-*.txt")
+	if err != nil {
+		return "", fmt.Errorf("creating reorder buffer: %w", err)
+	}
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(text)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("writing reorder buffer: %w", err)
+	}
+
+	// The editor may carry its own arguments, so let the shell split it.
+	editor := cmp.Or(os.Getenv("VISUAL"), os.Getenv("EDITOR"), "vi")
+	c := exec.CommandContext(ctx, "sh", "-c", editor+` "$1"`, "sh", f.Name())
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return "", fmt.Errorf("running editor %s: %w", editor, err)
+	}
+
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", fmt.Errorf("reading reorder buffer: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,276 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/eykd/linemark-go/internal/domain"
+	"github.com/eykd/linemark-go/internal/outline"
+)
+
+// mockReorderRunner is a test double for ReorderRunner.
+type mockReorderRunner struct {
+	children    []ReorderChild
+	childrenErr error
+	result      *ReorderResult
+	err         error
+
+	parent string
+	sids   []string
+	apply  bool
+}
+
+func (m *mockReorderRunner) Children(ctx context.Context, parent string) ([]ReorderChild, error) {
+	m.parent = parent
+	return m.children, m.childrenErr
+}
+
+func (m *mockReorderRunner) Reorder(ctx context.Context, parent string, sids []string, apply bool) (*ReorderResult, error) {
+	m.parent, m.sids, m.apply = parent, sids, apply
+	return m.result, m.err
+}
+
+// runReorderCmd runs the reorder command under a root command, so the
+// global flags apply, and returns the output.
+func runReorderCmd(t *testing.T, runner ReorderRunner, edit Editor, args ...string) (string, error) {
+	t.Helper()
+	root := NewRootCmd()
+	root.AddCommand(NewReorderCmd(runner, edit))
+	buf := new(bytes.Buffer)
+	root.SetOut(buf)
+	root.SetErr(new(bytes.Buffer))
+	root.SetArgs(append([]string{"reorder"}, args...))
+	t.Cleanup(func() { dryRun, jsonFlag = false, false })
+	err := root.Execute()
+	return buf.String(), err
+}
+
+// noEditor fails the test if the editor is opened.
+func noEditor(t *testing.T) Editor {
+	return func(ctx context.Context, text string) (string, error) {
+		t.Error("editor should not be opened")
+		return "", nil
+	}
+}
+
+var testReorderRenames = []RenameEntry{{Old: "100-300_SID003AABB_draft_three.md", New: "100-100_SID003AABB_draft_three.md"}}
+
+func TestReorderCmd_WithSIDs(t *testing.T) {
+	runner := &mockReorderRunner{result: &ReorderResult{Renames: testReorderRenames}}
+
+	out, err := runReorderCmd(t, runner, noEditor(t), "100", "SID003AABB", "SID001AABB")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if runner.parent != "100" || !reflect.DeepEqual(runner.sids, []string{"SID003AABB", "SID001AABB"}) || !runner.apply {
+		t.Errorf("runner got %q %v apply=%v", runner.parent, runner.sids, runner.apply)
+	}
+	if want := "  100-300_SID003AABB_draft_three.md -> 100-100_SID003AABB_draft_three.md\n"; out != want {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+}
+
+func TestReorderCmd_Root(t *testing.T) {
+	runner := &mockReorderRunner{result: &ReorderResult{}}
+
+	out, err := runReorderCmd(t, runner, noEditor(t), "--root", "SID002AABB", "SID001AABB")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if runner.parent != "" || len(runner.sids) != 2 {
+		t.Errorf("runner got %q %v, want root and both SIDs", runner.parent, runner.sids)
+	}
+	if out != "Already in that order\n" {
+		t.Errorf("output = %q", out)
+	}
+}
+
+func TestReorderCmd_DryRunJSON(t *testing.T) {
+	runner := &mockReorderRunner{result: &ReorderResult{Renames: testReorderRenames}}
+
+	out, err := runReorderCmd(t, runner, noEditor(t), "--dry-run", "--json", "100", "SID003AABB")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got ReorderResult
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("output is not JSON: %q", out)
+	}
+	if !got.Planned || runner.apply || len(got.Renames) != 1 {
+		t.Errorf("result = %+v, apply = %v; want a planned rename", got, runner.apply)
+	}
+}
+
+func TestReorderCmd_Editor(t *testing.T) {
+	runner := &mockReorderRunner{
+		children: []ReorderChild{
+			{SID: "SID001AABB", MP: "100-100", Title: "One"},
+			{SID: "SID002AABB", MP: "100-200", Title: "Two"},
+		},
+		result: &ReorderResult{},
+	}
+	var offered string
+	edit := func(ctx context.Context, text string) (string, error) {
+		offered = text
+		return "# comment\nSID002AABB 100-200 Two\n\nSID001AABB 100-100 One\n", nil
+	}
+
+	_, err := runReorderCmd(t, runner, edit, "100")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(offered, "SID001AABB 100-100 One\nSID002AABB 100-200 Two\n") {
+		t.Errorf("buffer = %q, want one child per line", offered)
+	}
+	if !reflect.DeepEqual(runner.sids, []string{"SID002AABB", "SID001AABB"}) {
+		t.Errorf("sids = %v, want edited order", runner.sids)
+	}
+}
+
+func TestReorderCmd_Errors(t *testing.T) {
+	runnerErr := errors.New("boom")
+	editErr := errors.New("editor failed")
+	emptyEdit := func(ctx context.Context, text string) (string, error) { return "# all gone\n", nil }
+	failEdit := func(ctx context.Context, text string) (string, error) { return "", editErr }
+
+	tests := []struct {
+		name    string
+		runner  ReorderRunner
+		edit    Editor
+		args    []string
+		wantErr error
+		wantMsg string
+	}{
+		{"not in project", nil, emptyEdit, []string{"100", "SID001AABB"}, ErrNotInProject, ""},
+		{"missing parent", &mockReorderRunner{}, emptyEdit, nil, nil, "requires a parent selector"},
+		{"invalid parent", &mockReorderRunner{}, emptyEdit, []string{"bad!sel", "SID001AABB"}, nil, "invalid selector"},
+		{"children error", &mockReorderRunner{childrenErr: runnerErr}, emptyEdit, []string{"100"}, runnerErr, ""},
+		{"editor error", &mockReorderRunner{}, failEdit, []string{"100"}, editErr, ""},
+		{"emptied buffer", &mockReorderRunner{}, emptyEdit, []string{"100"}, errReorderAborted, ""},
+		{"reorder error", &mockReorderRunner{err: runnerErr}, emptyEdit, []string{"100", "SID001AABB"}, runnerErr, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runReorderCmd(t, tt.runner, tt.edit, tt.args...)
+
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantMsg != "" && !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("error = %q, want it to mention %q", err, tt.wantMsg)
+			}
+		})
+	}
+}
+
+// --- reorderAdapter tests ---
+
+func TestReorderAdapter_Children(t *testing.T) {
+	stub := &stubOutlineService{
+		resolvedNode: domain.Node{MP: mustParseMP("100"), SID: "SID000AABB"},
+		loadResult: &outline.LoadResult{Outline: domain.Outline{Nodes: []domain.Node{
+			{MP: mustParseMP("100"), SID: "SID000AABB", Title: "Book"},
+			{MP: mustParseMP("100-100"), SID: "SID001AABB", Title: "One"},
+			{MP: mustParseMP("100-100-100"), SID: "SID002AABB", Title: "Scene"},
+			{MP: mustParseMP("200"), SID: "SID003AABB", Title: "Appendix"},
+		}}},
+	}
+	adapter := &reorderAdapter{svc: stub}
+
+	children, err := adapter.Children(context.Background(), "SID000AABB")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []ReorderChild{{SID: "SID001AABB", MP: "100-100", Title: "One"}}; !reflect.DeepEqual(children, want) {
+		t.Errorf("children = %+v, want %+v", children, want)
+	}
+
+	roots, err := adapter.Children(context.Background(), "")
+	if err != nil || len(roots) != 2 {
+		t.Errorf("root children = %+v, %v; want two", roots, err)
+	}
+}
+
+func TestReorderAdapter_Reorder(t *testing.T) {
+	stub := &stubOutlineService{
+		resolvedNode:  domain.Node{MP: mustParseMP("100"), SID: "SID000AABB"},
+		reorderResult: &outline.ReorderResult{Renames: map[string]string{"old.md": "new.md"}},
+	}
+	adapter := &reorderAdapter{svc: stub}
+
+	result, err := adapter.Reorder(context.Background(), "sid:SID000AABB", []string{"SID001AABB"}, true)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []any{"100", []string{"SID001AABB"}, true}; !reflect.DeepEqual(stub.reorderArgs, want) {
+		t.Errorf("Reorder args = %v, want %v", stub.reorderArgs, want)
+	}
+	if len(result.Renames) != 1 {
+		t.Errorf("renames = %v, want 1", result.Renames)
+	}
+}
+
+func TestReorderAdapter_Errors(t *testing.T) {
+	svcErr := errors.New("boom")
+	tests := []struct {
+		name string
+		stub *stubOutlineService
+		call func(a *reorderAdapter) error
+	}{
+		{"invalid selector", &stubOutlineService{}, func(a *reorderAdapter) error {
+			_, err := a.Children(context.Background(), "bad!sel")
+			return err
+		}},
+		{"unresolved parent", &stubOutlineService{resolveErr: svcErr}, func(a *reorderAdapter) error {
+			_, err := a.Reorder(context.Background(), "100", nil, true)
+			return err
+		}},
+		{"load failure", &stubOutlineService{loadErr: svcErr}, func(a *reorderAdapter) error {
+			_, err := a.Children(context.Background(), "")
+			return err
+		}},
+		{"reorder failure", &stubOutlineService{reorderErr: svcErr}, func(a *reorderAdapter) error {
+			_, err := a.Reorder(context.Background(), "", nil, true)
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(&reorderAdapter{svc: tt.stub}); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestReorderCmd_LocalJSONFlag(t *testing.T) {
+	runner := &mockReorderRunner{result: &ReorderResult{Renames: testReorderRenames}}
+	cmd := NewReorderCmd(runner, noEditor(t))
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"100", "SID003AABB", "--json"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !json.Valid(buf.Bytes()) {
+		t.Errorf("output is not JSON: %q", buf.String())
+	}
+}
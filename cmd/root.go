@@ -7,6 +7,8 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/eykd/linemark-go/internal/config"
+	"github.com/eykd/linemark-go/internal/domain"
 	"github.com/eykd/linemark-go/internal/fs"
 	"github.com/eykd/linemark-go/internal/lock"
 	"github.com/eykd/linemark-go/internal/outline"
@@ -72,54 +74,103 @@ func BuildCommandTree(svc *outline.OutlineService, bootstrapAdd AddRunner) *cobr
 
 	var aa AddRunner
 	var ca CheckRunner
+	var bwa BaselineWriter
 	var ra RepairRunner
 	var la ListRunner
 	var da DeleteRunner
 	var ma MoveRunner
+	var ia IndentRunner
+	var cya CopyRunner
+	var spa SplitRunner
+	var jna JoinRunner
 	var rna RenameRunner
 	var cpa CompactRunner
+	var roa ReorderRunner
+	var tua TUIService
+	var mga MergeRunner
+	var dfa DiffRunner
+	var sna SnapshotService
+	var tra TrashService
 	var ta TypesService
+	var cfa ConfigService
 
 	if svc != nil {
 		aa = &addAdapter{svc: svc}
 		ca = &checkAdapter{svc: svc}
+		bwa = &checkAdapter{svc: svc}
 		ra = &repairAdapter{svc: svc}
 		la = &listAdapter{svc: svc}
 		da = &deleteAdapter{svc: svc}
 		ma = &moveAdapter{svc: svc}
+		ia = &indentAdapter{svc: svc}
+		cya = &copyAdapter{svc: svc}
+		spa = &splitAdapter{svc: svc}
+		jna = &joinAdapter{svc: svc}
 		rna = &renameAdapter{svc: svc}
 		cpa = &compactAdapter{svc: svc}
+		roa = &reorderAdapter{svc: svc}
+		tua = &tuiAdapter{svc: svc}
+		mga = &mergeAdapter{svc: svc}
+		dfa = &diffAdapter{svc: svc}
+		sna = &snapshotAdapter{svc: svc}
+		tra = &trashAdapter{svc: svc}
 		ta = &typesAdapter{svc: svc}
+		cfa = &configAdapter{svc: svc}
 	}
 
 	// Commands that work without a project
 	root.AddCommand(NewInitCmd(os.Getwd))
+	root.AddCommand(NewMergeDriverCmd(mergeFileImpl))
 
 	// Commands that require a project
 	root.AddCommand(NewAddCmd(aa))
-	root.AddCommand(NewCheckCmd(ca))
+	root.AddCommand(NewCheckCmd(ca, bwa))
 	root.AddCommand(NewDoctorCmd(ca, ra))
 	root.AddCommand(NewTypesCmd(ta))
 	root.AddCommand(NewCompactCmd(cpa))
 	root.AddCommand(NewListCmd(la))
 	root.AddCommand(NewDeleteCmd(da))
 	root.AddCommand(NewMoveCmd(ma))
+	root.AddCommand(NewIndentCmd(ia))
+	root.AddCommand(NewOutdentCmd(ia))
+	root.AddCommand(NewCopyCmd(cya))
+	root.AddCommand(NewSplitCmd(spa))
+	root.AddCommand(NewJoinCmd(jna))
+	root.AddCommand(NewReorderCmd(roa, editTextImpl))
+	root.AddCommand(NewTUICmd(tua, runTerminalImpl))
 	root.AddCommand(NewRenameCmd(rna))
+	root.AddCommand(NewMergeCmd(mga))
+	root.AddCommand(NewDiffCmd(dfa))
+	root.AddCommand(NewSnapshotCmd(sna))
+	root.AddCommand(NewTrashCmd(tra))
+	root.AddCommand(NewConfigCmd(cfa))
 
 	return root
 }
 
 // wireServiceFromRootImpl creates an OutlineService for the given project root.
 func wireServiceFromRootImpl(projectRoot string) (*outline.OutlineService, error) {
+	configStore := &config.Store{Root: projectRoot}
+	cfg, cfgErr := configStore.Load(context.Background())
+	if cfgErr != nil {
+		cfg = domain.DefaultConfig()
+	}
+
 	reader := &fs.OSReader{Root: projectRoot}
 	writer := &fs.OSWriter{Root: projectRoot}
 	deleter := &fs.OSDeleter{Root: projectRoot}
 	renamer := &fs.OSRenamer{Root: projectRoot}
 	contentReader := &fs.OSContentReader{Root: projectRoot}
-	reserver := &fs.SIDReserver{Rand: rand.Reader}
+	reserver := &fs.SIDReserver{Rand: rand.Reader, Length: cfg.SIDLength}
 	locker := lock.NewFromPath(filepath.Join(projectRoot, lock.DefaultPath))
 
 	reservationStore := &fs.OSReservationStore{Root: projectRoot}
+	templateReader := &fs.OSTemplateReader{Root: projectRoot}
+	baselineStore := &fs.OSBaselineStore{Root: projectRoot}
+	mergeSource := &fs.GitMergeSource{Root: projectRoot}
+	snapshotResolver := &fs.GitSnapshotResolver{Root: projectRoot}
+	snapshotStore := &fs.OSSnapshotStore{Root: projectRoot}
+	trashStore := &fs.OSTrashStore{Root: projectRoot}
 
 	svc := outline.NewOutlineService(reader, writer, locker, reserver,
 		outline.WithDeleter(deleter),
@@ -128,6 +179,15 @@ func wireServiceFromRootImpl(projectRoot string) (*outline.OutlineService, error
 		outline.WithSlugifier(fs.SlugAdapter{}),
 		outline.WithFrontmatterHandler(fs.FMAdapter{}),
 		outline.WithReservationStore(reservationStore),
+		outline.WithTemplateReader(templateReader),
+		outline.WithBaselineStore(baselineStore),
+		outline.WithMergeSource(mergeSource),
+		outline.WithSnapshotResolver(snapshotResolver),
+		outline.WithSnapshotStore(snapshotStore),
+		outline.WithTrashStore(trashStore),
+		outline.WithConfigStore(configStore),
+		outline.WithConfig(cfg),
+		outline.WithConfigError(cfgErr),
 	)
 
 	return svc, nil
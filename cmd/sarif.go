@@ -0,0 +1,111 @@
+package cmd
+
+import "io"
+
+// SARIF 2.1.0 constants for check and doctor output.
+const (
+	sarifVersion        = "2.1.0"
+	sarifSchema         = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifToolName       = "lmk"
+	sarifInformationURI = "https://github.com/eykd/linemark-go"
+)
+
+// sarifLog is the top-level SARIF document.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// sarifRegion uses 1-based lines and columns; EndColumn is exclusive.
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+// newSARIFLog converts findings into a single-run SARIF log. Rules are
+// listed in order of first appearance.
+func newSARIFLog(findings []CheckFinding) sarifLog {
+	rules := []sarifRule{}
+	seen := map[string]bool{}
+	results := []sarifResult{}
+	for _, f := range findings {
+		id := f.ruleID()
+		if !seen[id] {
+			seen[id] = true
+			rules = append(rules, sarifRule{ID: id})
+		}
+		result := sarifResult{RuleID: id, Level: string(f.Severity), Message: sarifMessage{Text: f.Message}}
+		if f.Path != "" {
+			loc := sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: f.Path}}}
+			if f.Line > 0 {
+				loc.PhysicalLocation.Region = &sarifRegion{
+					StartLine:   f.Line,
+					StartColumn: f.Column,
+					EndLine:     f.EndLine,
+					EndColumn:   f.EndColumn,
+				}
+			}
+			result.Locations = []sarifLocation{loc}
+		}
+		results = append(results, result)
+	}
+	return sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool:    sarifTool{Driver: sarifDriver{Name: sarifToolName, InformationURI: sarifInformationURI, Rules: rules}},
+			Results: results,
+		}},
+	}
+}
+
+// formatFindingsSARIF writes findings as a SARIF log to w.
+func formatFindingsSARIF(w io.Writer, findings []CheckFinding) {
+	writeJSON(w, newSARIFLog(findings))
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestNewSARIFLog(t *testing.T) {
+	findings := []CheckFinding{
+		{Type: FindingLint, Severity: SeverityWarning, Message: "TODO marker left in draft", Path: "a.md", Rule: "todo", Line: 4, Column: 1, EndLine: 4, EndColumn: 5},
+		{Type: FindingSlugDrift, Severity: SeverityWarning, Message: "drift", Path: "b.md"},
+		{Type: FindingDuplicateSID, Severity: SeverityError, Message: "dup"},
+		{Type: FindingLint, Severity: SeverityWarning, Message: "TK marker left in draft", Path: "a.md", Rule: "todo", Line: 9, Column: 3},
+	}
+
+	log := newSARIFLog(findings)
+
+	if log.Version != "2.1.0" || log.Schema != sarifSchema {
+		t.Errorf("version/schema = %q/%q", log.Version, log.Schema)
+	}
+	run := log.Runs[0]
+	if run.Tool.Driver.Name != "lmk" {
+		t.Errorf("driver name = %q, want lmk", run.Tool.Driver.Name)
+	}
+	wantRules := []string{"todo", "slug_drift", "duplicate_sid"}
+	if len(run.Tool.Driver.Rules) != len(wantRules) {
+		t.Fatalf("rules = %+v, want %q", run.Tool.Driver.Rules, wantRules)
+	}
+	for i, id := range wantRules {
+		if run.Tool.Driver.Rules[i].ID != id {
+			t.Errorf("rule[%d] = %q, want %q", i, run.Tool.Driver.Rules[i].ID, id)
+		}
+	}
+	if len(run.Results) != 4 {
+		t.Fatalf("results = %d, want 4", len(run.Results))
+	}
+
+	first := run.Results[0]
+	if first.RuleID != "todo" || first.Level != "warning" || first.Message.Text != "TODO marker left in draft" {
+		t.Errorf("result[0] = %+v", first)
+	}
+	region := first.Locations[0].PhysicalLocation.Region
+	if first.Locations[0].PhysicalLocation.ArtifactLocation.URI != "a.md" || *region != (sarifRegion{StartLine: 4, StartColumn: 1, EndLine: 4, EndColumn: 5}) {
+		t.Errorf("result[0] location = %+v region %+v", first.Locations[0], region)
+	}
+	if run.Results[1].Locations[0].PhysicalLocation.Region != nil {
+		t.Error("finding without a line should have no region")
+	}
+	if run.Results[2].Level != "error" || run.Results[2].Locations != nil {
+		t.Errorf("pathless finding = %+v, want error level and no locations", run.Results[2])
+	}
+}
+
+func TestCheckCmd_FormatSARIF(t *testing.T) {
+	runner := &mockCheckRunner{result: &CheckResult{Findings: []CheckFinding{
+		{Type: FindingSlugDrift, Severity: SeverityWarning, Message: "drift", Path: "b.md", Line: 2, Column: 1},
+	}}}
+	cmd := NewCheckCmd(runner)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"--format", "sarif"})
+
+	err := cmd.Execute()
+
+	var findingsErr *FindingsDetectedError
+	if !errors.As(err, &findingsErr) {
+		t.Fatalf("expected FindingsDetectedError, got %v", err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid SARIF JSON: %v\n%s", err, buf.String())
+	}
+	if got := log.Runs[0].Results[0].RuleID; got != "slug_drift" {
+		t.Errorf("ruleId = %q, want slug_drift", got)
+	}
+}
+
+func TestCheckCmd_NoFindings_SARIFHasEmptyResults(t *testing.T) {
+	cmd := NewCheckCmd(&mockCheckRunner{result: &CheckResult{}})
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"--format", "sarif"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !bytes.Contains(buf.Bytes(), []byte(`"results":[]`)) {
+		t.Errorf("output = %s, want empty results array", buf.String())
+	}
+}
+
+func TestDoctorCmd_FormatSARIF(t *testing.T) {
+	checker := &mockCheckRunner{result: &CheckResult{}}
+	repairer := &mockRepairRunner{result: &RepairResult{
+		Repairs:    []RepairAction{{Type: FindingSlugDrift, Action: "rename", Old: "a.md", New: "b.md"}},
+		Unrepaired: []CheckFinding{{Type: FindingDuplicateSID, Severity: SeverityError, Message: "dup", Path: "c.md"}},
+	}}
+
+	tests := []struct {
+		name        string
+		args        []string
+		wantResults int
+	}{
+		{"report only", []string{"--format", "sarif"}, 0},
+		{"apply lists unrepaired findings", []string{"--apply", "--format", "sarif"}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewDoctorCmd(checker, repairer)
+			buf := new(bytes.Buffer)
+			cmd.SetOut(buf)
+			cmd.SetErr(new(bytes.Buffer))
+			cmd.SetArgs(tt.args)
+
+			_ = cmd.Execute()
+
+			var log sarifLog
+			if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+				t.Fatalf("invalid SARIF JSON: %v\n%s", err, buf.String())
+			}
+			if got := len(log.Runs[0].Results); got != tt.wantResults {
+				t.Errorf("results = %d, want %d", got, tt.wantResults)
+			}
+		})
+	}
+}
+
+func TestFormatFlag_Invalid(t *testing.T) {
+	for name, cmd := range map[string]interface {
+		SetArgs([]string)
+		Execute() error
+	}{
+		"check":  NewCheckCmd(&mockCheckRunner{result: &CheckResult{}}),
+		"doctor": NewDoctorCmd(&mockCheckRunner{result: &CheckResult{}}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			cmd.SetArgs([]string{"--format", "xml"})
+
+			if err := cmd.Execute(); !errors.Is(err, ErrInvalidFormat) {
+				t.Errorf("error = %v, want ErrInvalidFormat", err)
+			}
+		})
+	}
+}
+
+func TestResolveFormat(t *testing.T) {
+	tests := []struct {
+		format string
+		json   bool
+		want   outputFormat
+	}{
+		{"", false, formatText},
+		{"", true, formatJSON},
+		{"text", true, formatText},
+		{"json", false, formatJSON},
+		{"sarif", true, formatSARIF},
+	}
+
+	for _, tt := range tests {
+		got, err := resolveFormat(tt.format, tt.json)
+		if err != nil || got != tt.want {
+			t.Errorf("resolveFormat(%q, %v) = %q, %v; want %q", tt.format, tt.json, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// SnapshotInfo describes a stored snapshot.
+type SnapshotInfo struct {
+	ID      string    `json:"id"`
+	Created time.Time `json:"created"`
+	Message string    `json:"message"`
+	Files   int       `json:"files"`
+	Planned bool      `json:"planned"`
+}
+
+// RestoreResult holds the outcome of restoring a snapshot.
+type RestoreResult struct {
+	Written []string `json:"written"`
+	Deleted []string `json:"deleted"`
+	Planned bool     `json:"planned"`
+}
+
+// SnapshotService manages snapshots of the outline.
+type SnapshotService interface {
+	CreateSnapshot(ctx context.Context, message string, apply bool) (*SnapshotInfo, error)
+	ListSnapshots(ctx context.Context) ([]SnapshotInfo, error)
+	RestoreSnapshot(ctx context.Context, id, sid string, apply bool) (*RestoreResult, error)
+	DiffSnapshots(ctx context.Context, from, to string) (*DiffResult, error)
+}
+
+// NewSnapshotCmd creates the snapshot command with the given service.
+func NewSnapshotCmd(svc SnapshotService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Save and restore copies of the outline without git",
+		Long: `Save and restore copies of the outline's content files under
+.linemark/snapshots/. Files are stored compressed, and a file unchanged
+between snapshots is stored once.`,
+		SilenceUsage: true,
+	}
+
+	cmd.AddCommand(newSnapshotCreateCmd(svc))
+	cmd.AddCommand(newSnapshotListCmd(svc))
+	cmd.AddCommand(newSnapshotRestoreCmd(svc))
+	cmd.AddCommand(newSnapshotDiffCmd(svc))
+
+	return cmd
+}
+
+func newSnapshotCreateCmd(svc SnapshotService) *cobra.Command {
+	var message string
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:          "create",
+		Short:        "Save a snapshot of every content file",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if svc == nil {
+				return ErrNotInProject
+			}
+			isDryRun := GetDryRun()
+			info, err := svc.CreateSnapshot(cmd.Context(), message, !isDryRun)
+			if err != nil {
+				return err
+			}
+			info.Planned = isDryRun
+
+			w := cmd.OutOrStdout()
+			switch {
+			case jsonOutput || GetJSON():
+				writeJSON(w, info)
+			case isDryRun:
+				fmt.Fprintf(w, "Would save a snapshot of %d file(s)\n", info.Files)
+			default:
+				fmt.Fprintf(w, "Saved snapshot %s of %d file(s)\n", info.ID, info.Files)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&message, "message", "m", "", "Describe the snapshot")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output results as JSON")
+
+	return cmd
+}
+
+func newSnapshotListCmd(svc SnapshotService) *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:          "list",
+		Short:        "List snapshots, oldest first",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if svc == nil {
+				return ErrNotInProject
+			}
+			infos, err := svc.ListSnapshots(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			w := cmd.OutOrStdout()
+			if jsonOutput || GetJSON() {
+				writeJSON(w, infos)
+				return nil
+			}
+			for _, info := range infos {
+				fmt.Fprintf(w, "%s  %4d file(s)  %s\n", info.ID, info.Files, info.Message)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output results as JSON")
+
+	return cmd
+}
+
+func newSnapshotRestoreCmd(svc SnapshotService) *cobra.Command {
+	var sid string
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "restore <id>",
+		Short: "Return the outline to a snapshot",
+		Long: `Return the outline to a snapshot: its content files are written as stored
+and content files the snapshot lacks are deleted. With --sid, only that node's
+documents are restored, at its current position, or at its position in the
+snapshot when it has since been deleted.`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if svc == nil {
+				return ErrNotInProject
+			}
+			isDryRun := GetDryRun()
+			result, err := svc.RestoreSnapshot(cmd.Context(), args[0], sid, !isDryRun)
+			if err != nil {
+				return err
+			}
+			result.Planned = isDryRun
+
+			w := cmd.OutOrStdout()
+			if jsonOutput || GetJSON() {
+				writeJSON(w, result)
+				return nil
+			}
+			writeRestoreHuman(w, result)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&sid, "sid", "", "Restore only the node with this SID")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output results as JSON")
+
+	return cmd
+}
+
+func writeRestoreHuman(w io.Writer, result *RestoreResult) {
+	if len(result.Written) == 0 && len(result.Deleted) == 0 {
+		fmt.Fprintln(w, "Already matches the snapshot")
+		return
+	}
+	for _, name := range result.Written {
+		fmt.Fprintf(w, "  write  %s\n", name)
+	}
+	for _, name := range result.Deleted {
+		fmt.Fprintf(w, "  delete %s\n", name)
+	}
+}
+
+func newSnapshotDiffCmd(svc SnapshotService) *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "diff <id> [<id>]",
+		Short: "Show node-level changes since a snapshot",
+		Long: `Compare a snapshot with the working tree, or with a second snapshot, node
+by node, as lmk diff does.`,
+		Args:         cobra.RangeArgs(1, 2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if svc == nil {
+				return ErrNotInProject
+			}
+			var to string
+			if len(args) > 1 {
+				to = args[1]
+			}
+			result, err := svc.DiffSnapshots(cmd.Context(), args[0], to)
+			if err != nil {
+				return err
+			}
+			if jsonOutput || GetJSON() {
+				writeJSON(cmd.OutOrStdout(), result)
+				return nil
+			}
+			writeDiffHuman(cmd.OutOrStdout(), result)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output results as JSON")
+
+	return cmd
+}
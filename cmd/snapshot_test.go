@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+// mockSnapshotService is a test double for SnapshotService.
+type mockSnapshotService struct {
+	info    *SnapshotInfo
+	infos   []SnapshotInfo
+	restore *RestoreResult
+	diff    *DiffResult
+	err     error
+
+	message string
+	id      string
+	sid     string
+	refs    [2]string
+	apply   bool
+}
+
+func (m *mockSnapshotService) CreateSnapshot(ctx context.Context, message string, apply bool) (*SnapshotInfo, error) {
+	m.message, m.apply = message, apply
+	return m.info, m.err
+}
+
+func (m *mockSnapshotService) ListSnapshots(ctx context.Context) ([]SnapshotInfo, error) {
+	return m.infos, m.err
+}
+
+func (m *mockSnapshotService) RestoreSnapshot(ctx context.Context, id, sid string, apply bool) (*RestoreResult, error) {
+	m.id, m.sid, m.apply = id, sid, apply
+	return m.restore, m.err
+}
+
+func (m *mockSnapshotService) DiffSnapshots(ctx context.Context, from, to string) (*DiffResult, error) {
+	m.refs = [2]string{from, to}
+	return m.diff, m.err
+}
+
+// runSnapshotCmd runs the snapshot command under a root command, so the
+// global flags apply, and returns the output.
+func runSnapshotCmd(t *testing.T, svc SnapshotService, args ...string) (string, error) {
+	t.Helper()
+	root := NewRootCmd()
+	root.AddCommand(NewSnapshotCmd(svc))
+	buf := new(bytes.Buffer)
+	root.SetOut(buf)
+	root.SetErr(new(bytes.Buffer))
+	root.SetArgs(append([]string{"snapshot"}, args...))
+	t.Cleanup(func() { dryRun, jsonFlag = false, false })
+	err := root.Execute()
+	return buf.String(), err
+}
+
+func TestSnapshotCmd_Create(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		wantApply bool
+		wantOut   string
+	}{
+		{"saves", []string{"create", "-m", "before cuts"}, true, "Saved snapshot 20260301T120000Z of 3 file(s)\n"},
+		{"dry run", []string{"create", "-m", "before cuts", "--dry-run"}, false, "Would save a snapshot of 3 file(s)\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockSnapshotService{info: &SnapshotInfo{ID: "20260301T120000Z", Files: 3}}
+
+			out, err := runSnapshotCmd(t, svc, tt.args...)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if svc.message != "before cuts" || svc.apply != tt.wantApply {
+				t.Errorf("created with %q, apply %v", svc.message, svc.apply)
+			}
+			if out != tt.wantOut {
+				t.Errorf("output = %q, want %q", out, tt.wantOut)
+			}
+		})
+	}
+}
+
+func TestSnapshotCmd_Create_JSON(t *testing.T) {
+	svc := &mockSnapshotService{info: &SnapshotInfo{Files: 3}}
+
+	out, err := runSnapshotCmd(t, svc, "create", "--dry-run", "--json")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got SnapshotInfo
+	if err := json.Unmarshal([]byte(out), &got); err != nil || !got.Planned || got.Files != 3 {
+		t.Errorf("JSON = %s (%v)", out, err)
+	}
+}
+
+func TestSnapshotCmd_List(t *testing.T) {
+	svc := &mockSnapshotService{infos: []SnapshotInfo{
+		{ID: "20260301T120000Z", Created: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC), Files: 12, Message: "before cuts"},
+		{ID: "20260302T090000Z", Files: 140},
+	}}
+
+	out, err := runSnapshotCmd(t, svc, "list")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "20260301T120000Z    12 file(s)  before cuts\n20260302T090000Z   140 file(s)  \n"
+	if out != want {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+
+	out, _ = runSnapshotCmd(t, svc, "list", "--json")
+	var got []SnapshotInfo
+	if err := json.Unmarshal([]byte(out), &got); err != nil || len(got) != 2 {
+		t.Errorf("JSON = %s (%v)", out, err)
+	}
+}
+
+func TestSnapshotCmd_Restore(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		result    *RestoreResult
+		wantSID   string
+		wantApply bool
+		wantOut   string
+	}{
+		{
+			name:      "whole outline",
+			args:      []string{"restore", "20260301T120000Z"},
+			result:    &RestoreResult{Written: []string{"100_SID001AABB_draft_a.md"}, Deleted: []string{"200_SID002AABB_draft_b.md"}},
+			wantApply: true,
+			wantOut:   "  write  100_SID001AABB_draft_a.md\n  delete 200_SID002AABB_draft_b.md\n",
+		},
+		{
+			name:    "one node, dry run",
+			args:    []string{"restore", "20260301T120000Z", "--sid", "SID001AABB", "--dry-run"},
+			result:  &RestoreResult{},
+			wantSID: "SID001AABB",
+			wantOut: "Already matches the snapshot\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockSnapshotService{restore: tt.result}
+
+			out, err := runSnapshotCmd(t, svc, tt.args...)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if svc.id != "20260301T120000Z" || svc.sid != tt.wantSID || svc.apply != tt.wantApply {
+				t.Errorf("restored %q sid %q apply %v", svc.id, svc.sid, svc.apply)
+			}
+			if out != tt.wantOut {
+				t.Errorf("output = %q, want %q", out, tt.wantOut)
+			}
+		})
+	}
+}
+
+func TestSnapshotCmd_Restore_JSON(t *testing.T) {
+	svc := &mockSnapshotService{restore: &RestoreResult{Written: []string{}, Deleted: []string{}}}
+
+	out, err := runSnapshotCmd(t, svc, "restore", "x", "--json")
+
+	var got RestoreResult
+	if err != nil || json.Unmarshal([]byte(out), &got) != nil || got.Planned {
+		t.Errorf("JSON = %s (%v)", out, err)
+	}
+}
+
+func TestSnapshotCmd_Diff(t *testing.T) {
+	svc := &mockSnapshotService{diff: &DiffResult{}}
+
+	out, err := runSnapshotCmd(t, svc, "diff", "a")
+	if err != nil || svc.refs != [2]string{"a", ""} || out != "No changes\n" {
+		t.Errorf("diff a: refs %v, output %q, error %v", svc.refs, out, err)
+	}
+
+	out, err = runSnapshotCmd(t, svc, "diff", "a", "b", "--json")
+	if err != nil || svc.refs != [2]string{"a", "b"} || out != "{\"nodes\":null}\n" {
+		t.Errorf("diff a b: refs %v, output %q, error %v", svc.refs, out, err)
+	}
+}
+
+func TestSnapshotCmd_LocalJSONFlag(t *testing.T) {
+	svc := &mockSnapshotService{
+		info:    &SnapshotInfo{Files: 3},
+		infos:   []SnapshotInfo{},
+		restore: &RestoreResult{Written: []string{}, Deleted: []string{}},
+		diff:    &DiffResult{Nodes: []NodeChange{}},
+	}
+
+	for _, args := range [][]string{{"create"}, {"list"}, {"restore", "x"}, {"diff", "x"}} {
+		t.Run(args[0], func(t *testing.T) {
+			cmd := NewSnapshotCmd(svc)
+			buf := new(bytes.Buffer)
+			cmd.SetOut(buf)
+			cmd.SetArgs(append(args, "--json"))
+
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !json.Valid(buf.Bytes()) {
+				t.Errorf("output is not JSON: %q", buf.String())
+			}
+		})
+	}
+}
+
+func TestSnapshotCmd_Errors(t *testing.T) {
+	svcErr := errors.New("no such snapshot")
+	commands := [][]string{{"create"}, {"list"}, {"restore", "x"}, {"diff", "x"}}
+
+	for _, args := range commands {
+		if _, err := runSnapshotCmd(t, nil, args...); !errors.Is(err, ErrNotInProject) {
+			t.Errorf("%v with nil service: error = %v, want ErrNotInProject", args, err)
+		}
+		if _, err := runSnapshotCmd(t, &mockSnapshotService{err: svcErr}, args...); !errors.Is(err, svcErr) {
+			t.Errorf("%v: error = %v, want %v", args, err, svcErr)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/eykd/linemark-go/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+// SplitResult holds the outcome of a split operation. The new node at MP
+// holds Lines lines of Draft, starting with FirstLine.
+type SplitResult struct {
+	MP         string        `json:"mp"`
+	SID        string        `json:"sid"`
+	Title      string        `json:"title"`
+	Draft      string        `json:"draft"`
+	Created    []string      `json:"created"`
+	Lines      int           `json:"lines"`
+	FirstLine  string        `json:"first_line"`
+	Rebalanced []RenameEntry `json:"rebalanced"`
+	Planned    bool          `json:"planned"`
+}
+
+// SplitRunner defines the interface for running the split operation. The
+// draft is split before line when it is set, or else before heading.
+type SplitRunner interface {
+	Split(ctx context.Context, selector string, line int, heading, title string, apply bool) (*SplitResult, error)
+}
+
+// NewSplitCmd creates the split command with the given runner.
+func NewSplitCmd(runner SplitRunner) *cobra.Command {
+	var line int
+	var heading string
+	var title string
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "split <selector>",
+		Short: "Split the tail of a node's draft into a new sibling",
+		Long: `Split a node's draft before a line of the file or before a heading, moving
+the rest of the draft into a new sibling placed right after the node. The
+new node is titled with --title, or else with the heading split at, or the
+node's title followed by "(part 2)".`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if runner == nil {
+				return ErrNotInProject
+			}
+			if (line == 0) == (heading == "") {
+				return fmt.Errorf("exactly one of --at-line and --at-heading is required")
+			}
+
+			selector := args[0]
+			if _, err := domain.ParseSelector(selector); err != nil {
+				return fmt.Errorf("invalid selector %q: %w", selector, err)
+			}
+
+			isDryRun := GetDryRun()
+			result, err := runner.Split(cmd.Context(), selector, line, heading, title, !isDryRun)
+			if err != nil {
+				return err
+			}
+			result.Planned = isDryRun
+
+			if jsonOutput || GetJSON() {
+				writeJSON(cmd.OutOrStdout(), result)
+				return nil
+			}
+			w := cmd.OutOrStdout()
+			verb := "Split"
+			if isDryRun {
+				verb = "Would split"
+			}
+			fmt.Fprintf(w, "%s %d line(s) starting %q from %s into %s %s\n", verb, result.Lines, result.FirstLine, result.Draft, result.MP, result.Title)
+			for _, name := range result.Created {
+				fmt.Fprintf(w, "  create %s\n", name)
+			}
+			for _, r := range result.Rebalanced {
+				fmt.Fprintf(w, "  %s -> %s\n", r.Old, r.New)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&line, "at-line", 0, "Split before this line of the draft file")
+	cmd.Flags().StringVar(&heading, "at-heading", "", "Split before the first heading with this text")
+	cmd.Flags().StringVar(&title, "title", "", "Title for the new node")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output results as JSON")
+
+	return cmd
+}
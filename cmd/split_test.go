@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/eykd/linemark-go/internal/outline"
+)
+
+// mockSplitRunner is a test double for SplitRunner.
+type mockSplitRunner struct {
+	result   *SplitResult
+	err      error
+	called   bool
+	selector string
+	line     int
+	heading  string
+	title    string
+	apply    bool
+}
+
+func (m *mockSplitRunner) Split(ctx context.Context, selector string, line int, heading, title string, apply bool) (*SplitResult, error) {
+	m.called = true
+	m.selector, m.line, m.heading, m.title, m.apply = selector, line, heading, title, apply
+	return m.result, m.err
+}
+
+func splitFixture() *SplitResult {
+	return &SplitResult{
+		MP:         "110",
+		SID:        "SIDN01AABB",
+		Title:      "The Storm",
+		Draft:      "100_SID001AABB_draft_part-one.md",
+		Created:    []string{"110_SIDN01AABB_draft_the-storm.md", "110_SIDN01AABB_notes.md"},
+		Lines:      2,
+		FirstLine:  "## The Storm",
+		Rebalanced: []RenameEntry{{Old: "101_SID002CCDD_draft_x.md", New: "200_SID002CCDD_draft_x.md"}},
+	}
+}
+
+// runSplitCmd runs the split command under a root command.
+func runSplitCmd(t *testing.T, runner SplitRunner, args ...string) (string, error) {
+	t.Helper()
+	root := NewRootCmd()
+	root.AddCommand(NewSplitCmd(runner))
+	buf := new(bytes.Buffer)
+	root.SetOut(buf)
+	root.SetErr(new(bytes.Buffer))
+	root.SetArgs(append([]string{"split"}, args...))
+	t.Cleanup(func() { dryRun, jsonFlag = false, false })
+	err := root.Execute()
+	return buf.String(), err
+}
+
+func TestSplitCmd_ForwardsArguments(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want mockSplitRunner
+	}{
+		{"at a line", []string{"100", "--at-line", "7"},
+			mockSplitRunner{selector: "100", line: 7, apply: true}},
+		{"at a heading with a title", []string{"sid:SID001AABB", "--at-heading", "The Storm", "--title", "Storm"},
+			mockSplitRunner{selector: "sid:SID001AABB", heading: "The Storm", title: "Storm", apply: true}},
+		{"dry run", []string{"100", "--at-line", "7", "--dry-run"},
+			mockSplitRunner{selector: "100", line: 7}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &mockSplitRunner{result: splitFixture()}
+
+			_, err := runSplitCmd(t, runner, tt.args...)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			runner.result, runner.called = nil, false
+			if *runner != tt.want {
+				t.Errorf("runner got %+v, want %+v", *runner, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitCmd_HumanReadableOutput(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		verb string
+	}{
+		{"applied", nil, "Split"},
+		{"dry run", []string{"--dry-run"}, "Would split"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := runSplitCmd(t, &mockSplitRunner{result: splitFixture()}, append([]string{"100", "--at-heading", "The Storm"}, tt.args...)...)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			want := tt.verb + " 2 line(s) starting \"## The Storm\" from 100_SID001AABB_draft_part-one.md into 110 The Storm\n" +
+				"  create 110_SIDN01AABB_draft_the-storm.md\n" +
+				"  create 110_SIDN01AABB_notes.md\n" +
+				"  101_SID002CCDD_draft_x.md -> 200_SID002CCDD_draft_x.md\n"
+			if out != want {
+				t.Errorf("output = %q, want %q", out, want)
+			}
+		})
+	}
+}
+
+func TestSplitCmd_JSONOutput(t *testing.T) {
+	out, err := runSplitCmd(t, &mockSplitRunner{result: splitFixture()}, "100", "--at-line", "6", "--json", "--dry-run")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var result SplitResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\nraw: %s", err, out)
+	}
+	want := splitFixture()
+	want.Planned = true
+	if !reflect.DeepEqual(&result, want) {
+		t.Errorf("result = %+v, want %+v", result, want)
+	}
+	for _, key := range []string{`"mp"`, `"draft"`, `"created"`, `"first_line"`, `"rebalanced"`, `"planned"`} {
+		if !strings.Contains(out, key) {
+			t.Errorf("JSON output missing %s: %s", key, out)
+		}
+	}
+}
+
+func TestSplitCmd_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		err        error
+		want       string
+		wantCalled bool
+	}{
+		{"no split point", []string{"100"}, nil, "exactly one of --at-line and --at-heading", false},
+		{"both split points", []string{"100", "--at-line", "3", "--at-heading", "x"}, nil, "exactly one of --at-line and --at-heading", false},
+		{"invalid selector", []string{"not a selector!", "--at-line", "3"}, nil, "invalid selector", false},
+		{"runner error", []string{"100", "--at-line", "1"}, outline.ErrBadSplitPoint, "split point", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &mockSplitRunner{err: tt.err}
+
+			_, err := runSplitCmd(t, runner, tt.args...)
+
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.want)
+			}
+			if runner.called != tt.wantCalled {
+				t.Errorf("runner called = %v, want %v", runner.called, tt.wantCalled)
+			}
+		})
+	}
+}
+
+// --- splitAdapter tests ---
+
+func TestSplitAdapter_Split(t *testing.T) {
+	stub := &stubOutlineService{splitResult: &outline.SplitResult{
+		MP: "110", SID: "SIDN01AABB", Title: "The Storm", Draft: "100_SID001AABB_draft_part-one.md",
+		Created: []string{"110_SIDN01AABB_draft_the-storm.md"}, Lines: 2, FirstLine: "## The Storm",
+		Rebalanced: map[string]string{"b.md": "d.md", "a.md": "c.md"},
+	}}
+	adapter := &splitAdapter{svc: stub}
+
+	result, err := adapter.Split(context.Background(), "100", 0, "The Storm", "Storm", false)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := &SplitResult{
+		MP: "110", SID: "SIDN01AABB", Title: "The Storm", Draft: "100_SID001AABB_draft_part-one.md",
+		Created: []string{"110_SIDN01AABB_draft_the-storm.md"}, Lines: 2, FirstLine: "## The Storm",
+		Rebalanced: []RenameEntry{{Old: "a.md", New: "c.md"}, {Old: "b.md", New: "d.md"}},
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("result = %+v, want %+v", result, want)
+	}
+	if stub.moveSrc.Value() != "100" || stub.splitAt != (outline.SplitPoint{Heading: "The Storm"}) || stub.splitTitle != "Storm" || stub.moveApply {
+		t.Errorf("service got selector %v, at %+v, title %q, apply %v", stub.moveSrc, stub.splitAt, stub.splitTitle, stub.moveApply)
+	}
+}
+
+func TestSplitAdapter_Errors(t *testing.T) {
+	adapter := &splitAdapter{svc: &stubOutlineService{moveErr: errors.New("locked")}}
+
+	for _, selector := range []string{"not a selector!", "100"} {
+		if _, err := adapter.Split(context.Background(), selector, 3, "", "", true); err == nil {
+			t.Errorf("Split(%q): expected an error", selector)
+		}
+	}
+}
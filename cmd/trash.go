@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// TrashEntry describes a node archived by delete --archive.
+type TrashEntry struct {
+	ID        string    `json:"id"`
+	Deleted   time.Time `json:"deleted"`
+	SID       string    `json:"sid"`
+	MP        string    `json:"mp"`
+	ParentSID string    `json:"parent_sid,omitempty"`
+	Slug      string    `json:"slug,omitempty"`
+	Files     []string  `json:"files"`
+}
+
+// TrashRestoreResult holds the outcome of restoring a node from the trash.
+type TrashRestoreResult struct {
+	ID      string            `json:"id"`
+	SID     string            `json:"sid"`
+	MP      string            `json:"mp"`
+	Renames map[string]string `json:"renames"`
+	Planned bool              `json:"planned"`
+}
+
+// TrashEmptyResult holds the outcome of emptying the trash.
+type TrashEmptyResult struct {
+	Emptied []TrashEntry `json:"emptied"`
+	Planned bool         `json:"planned"`
+}
+
+// TrashService manages nodes archived by delete --archive.
+type TrashService interface {
+	ListTrash(ctx context.Context) ([]TrashEntry, error)
+	RestoreTrash(ctx context.Context, id string, apply bool) (*TrashRestoreResult, error)
+	EmptyTrash(ctx context.Context, apply bool) ([]TrashEntry, error)
+}
+
+// NewTrashCmd creates the trash command with the given service.
+func NewTrashCmd(svc TrashService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trash",
+		Short: "List, restore and empty archived nodes",
+		Long: `Manage nodes archived with lmk delete --archive, whose files are kept under
+.linemark/trash/ with a record of where they stood in the outline.`,
+		SilenceUsage: true,
+	}
+
+	cmd.AddCommand(newTrashListCmd(svc))
+	cmd.AddCommand(newTrashRestoreCmd(svc))
+	cmd.AddCommand(newTrashEmptyCmd(svc))
+
+	return cmd
+}
+
+func newTrashListCmd(svc TrashService) *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:          "list",
+		Short:        "List archived nodes, oldest first",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if svc == nil {
+				return ErrNotInProject
+			}
+			entries, err := svc.ListTrash(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			w := cmd.OutOrStdout()
+			if jsonOutput || GetJSON() {
+				writeJSON(w, entries)
+				return nil
+			}
+			for _, e := range entries {
+				fmt.Fprintf(w, "%s  %s  %s  %s  %d file(s)\n", e.ID, e.MP, e.SID, e.Slug, len(e.Files))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output results as JSON")
+
+	return cmd
+}
+
+func newTrashRestoreCmd(svc TrashService) *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "restore <id>",
+		Short: "Return an archived node to the outline",
+		Long: `Return an archived node, with any descendants archived with it, to the
+outline under its original parent. It keeps its original number unless
+another node now holds it, in which case it takes the next free number
+after it. The parent must still be in the outline.`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if svc == nil {
+				return ErrNotInProject
+			}
+			isDryRun := GetDryRun()
+			result, err := svc.RestoreTrash(cmd.Context(), args[0], !isDryRun)
+			if err != nil {
+				return err
+			}
+			result.Planned = isDryRun
+
+			w := cmd.OutOrStdout()
+			switch {
+			case jsonOutput || GetJSON():
+				writeJSON(w, result)
+			case isDryRun:
+				fmt.Fprintf(w, "Would restore %s at %s\n", result.SID, result.MP)
+			default:
+				fmt.Fprintf(w, "Restored %s at %s\n", result.SID, result.MP)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output results as JSON")
+
+	return cmd
+}
+
+func newTrashEmptyCmd(svc TrashService) *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:          "empty",
+		Short:        "Permanently delete every archived node",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if svc == nil {
+				return ErrNotInProject
+			}
+			isDryRun := GetDryRun()
+			entries, err := svc.EmptyTrash(cmd.Context(), !isDryRun)
+			if err != nil {
+				return err
+			}
+
+			w := cmd.OutOrStdout()
+			switch {
+			case jsonOutput || GetJSON():
+				writeJSON(w, &TrashEmptyResult{Emptied: entries, Planned: isDryRun})
+			case isDryRun:
+				fmt.Fprintf(w, "Would permanently delete %d archived node(s)\n", len(entries))
+			default:
+				fmt.Fprintf(w, "Permanently deleted %d archived node(s)\n", len(entries))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output results as JSON")
+
+	return cmd
+}
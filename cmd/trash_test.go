@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+// mockTrashService is a test double for TrashService.
+type mockTrashService struct {
+	entries []TrashEntry
+	restore *TrashRestoreResult
+	err     error
+
+	id    string
+	apply bool
+}
+
+func (m *mockTrashService) ListTrash(ctx context.Context) ([]TrashEntry, error) {
+	return m.entries, m.err
+}
+
+func (m *mockTrashService) RestoreTrash(ctx context.Context, id string, apply bool) (*TrashRestoreResult, error) {
+	m.id, m.apply = id, apply
+	return m.restore, m.err
+}
+
+func (m *mockTrashService) EmptyTrash(ctx context.Context, apply bool) ([]TrashEntry, error) {
+	m.apply = apply
+	return m.entries, m.err
+}
+
+// runTrashCmd runs the trash command under a root command, so the global
+// flags apply, and returns the output.
+func runTrashCmd(t *testing.T, svc TrashService, args ...string) (string, error) {
+	t.Helper()
+	root := NewRootCmd()
+	root.AddCommand(NewTrashCmd(svc))
+	buf := new(bytes.Buffer)
+	root.SetOut(buf)
+	root.SetErr(new(bytes.Buffer))
+	root.SetArgs(append([]string{"trash"}, args...))
+	t.Cleanup(func() { dryRun, jsonFlag = false, false })
+	err := root.Execute()
+	return buf.String(), err
+}
+
+var testTrashEntry = TrashEntry{
+	ID:      "20260301T120000Z",
+	Deleted: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	SID:     "SID002CCDD",
+	MP:      "100-200",
+	Slug:    "scene",
+	Files:   []string{"100-200_SID002CCDD_draft_scene.md", "100-200_SID002CCDD_notes.md"},
+}
+
+func TestTrashCmd_List(t *testing.T) {
+	svc := &mockTrashService{entries: []TrashEntry{testTrashEntry}}
+
+	out, err := runTrashCmd(t, svc, "list")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "20260301T120000Z  100-200  SID002CCDD  scene  2 file(s)\n"; out != want {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+}
+
+func TestTrashCmd_List_JSON(t *testing.T) {
+	svc := &mockTrashService{entries: []TrashEntry{testTrashEntry}}
+
+	out, err := runTrashCmd(t, svc, "list", "--json")
+
+	var got []TrashEntry
+	if err != nil || json.Unmarshal([]byte(out), &got) != nil || len(got) != 1 || got[0].MP != "100-200" {
+		t.Errorf("output = %q, %v", out, err)
+	}
+}
+
+func TestTrashCmd_Restore(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		wantApply bool
+		wantOut   string
+	}{
+		{"restores", []string{"restore", "20260301T120000Z"}, true, "Restored SID002CCDD at 100-300\n"},
+		{"dry run", []string{"restore", "20260301T120000Z", "--dry-run"}, false, "Would restore SID002CCDD at 100-300\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockTrashService{restore: &TrashRestoreResult{ID: "20260301T120000Z", SID: "SID002CCDD", MP: "100-300"}}
+
+			out, err := runTrashCmd(t, svc, tt.args...)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out != tt.wantOut || svc.id != "20260301T120000Z" || svc.apply != tt.wantApply {
+				t.Errorf("output = %q, id %q, apply %v", out, svc.id, svc.apply)
+			}
+		})
+	}
+}
+
+func TestTrashCmd_Restore_JSON(t *testing.T) {
+	svc := &mockTrashService{restore: &TrashRestoreResult{SID: "SID002CCDD", MP: "100-300", Renames: map[string]string{"a": "b"}}}
+
+	out, err := runTrashCmd(t, svc, "restore", "x", "--json", "--dry-run")
+
+	var got TrashRestoreResult
+	if err != nil || json.Unmarshal([]byte(out), &got) != nil || !got.Planned || got.Renames["a"] != "b" {
+		t.Errorf("output = %q, %v", out, err)
+	}
+}
+
+func TestTrashCmd_Empty(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		wantApply bool
+		wantOut   string
+	}{
+		{"empties", []string{"empty"}, true, "Permanently deleted 1 archived node(s)\n"},
+		{"dry run", []string{"empty", "--dry-run"}, false, "Would permanently delete 1 archived node(s)\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockTrashService{entries: []TrashEntry{testTrashEntry}}
+
+			out, err := runTrashCmd(t, svc, tt.args...)
+
+			if err != nil || out != tt.wantOut || svc.apply != tt.wantApply {
+				t.Errorf("output = %q, %v; apply %v", out, err, svc.apply)
+			}
+		})
+	}
+}
+
+func TestTrashCmd_Empty_JSON(t *testing.T) {
+	svc := &mockTrashService{entries: []TrashEntry{testTrashEntry}}
+
+	out, err := runTrashCmd(t, svc, "empty", "--json")
+
+	var got TrashEmptyResult
+	if err != nil || json.Unmarshal([]byte(out), &got) != nil || got.Planned || len(got.Emptied) != 1 {
+		t.Errorf("output = %q, %v", out, err)
+	}
+}
+
+func TestTrashCmd_LocalJSONFlag(t *testing.T) {
+	svc := &mockTrashService{
+		entries: []TrashEntry{testTrashEntry},
+		restore: &TrashRestoreResult{SID: "SID002CCDD", MP: "100-200"},
+	}
+
+	for _, args := range [][]string{{"list"}, {"restore", "x"}, {"empty"}} {
+		t.Run(args[0], func(t *testing.T) {
+			cmd := NewTrashCmd(svc)
+			buf := new(bytes.Buffer)
+			cmd.SetOut(buf)
+			cmd.SetArgs(append(args, "--json"))
+
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !json.Valid(buf.Bytes()) {
+				t.Errorf("output is not JSON: %q", buf.String())
+			}
+		})
+	}
+}
+
+func TestTrashCmd_Errors(t *testing.T) {
+	svcErr := errors.New("no such trash entry")
+
+	for _, args := range [][]string{{"list"}, {"restore", "x"}, {"empty"}} {
+		if _, err := runTrashCmd(t, &mockTrashService{err: svcErr}, args...); !errors.Is(err, svcErr) {
+			t.Errorf("%v: error = %v, want %v", args, err, svcErr)
+		}
+		if _, err := runTrashCmd(t, nil, args...); !errors.Is(err, ErrNotInProject) {
+			t.Errorf("%v without a project: error = %v", args, err)
+		}
+	}
+}
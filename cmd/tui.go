@@ -0,0 +1,539 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/eykd/linemark-go/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+// errNotTerminal is returned when lmk tui is run without a terminal.
+var errNotTerminal = errors.New("lmk tui must be run in a terminal")
+
+// EditNode is a node of the outline being edited in the TUI. SID is empty
+// for a node added during the session. Types lists the document types other
+// than the draft.
+type EditNode struct {
+	SID      string
+	Title    string
+	Types    []string
+	Children []*EditNode
+}
+
+// EditAddition describes a node created by applying the TUI's edits.
+type EditAddition struct {
+	SID   string
+	MP    string
+	Title string
+}
+
+// EditPlan holds the changes that make the outline match the edited tree.
+type EditPlan struct {
+	Renames []RenameEntry
+	Deleted []string
+	Written []string
+	Added   []EditAddition
+}
+
+// TUIService loads the outline as a tree to edit and applies the edited
+// tree in one locked operation. ApplyEdits is given the SIDs of the tree as
+// loaded, and fails rather than delete nodes added on disk since.
+type TUIService interface {
+	EditTree(ctx context.Context) ([]*EditNode, error)
+	ApplyEdits(ctx context.Context, loaded []string, roots []*EditNode, apply bool) (*EditPlan, error)
+}
+
+// Terminal runs an interactive session on the user's terminal, passing it
+// the keyboard input, the screen and the screen's height in lines.
+type Terminal func(ctx context.Context, session func(in io.Reader, out io.Writer, height int) error) error
+
+// NewTUICmd creates the tui command with the given service and terminal.
+func NewTUICmd(svc TUIService, term Terminal) *cobra.Command {
+	return &cobra.Command{
+		Use:   "tui",
+		Short: "Edit the outline interactively in the terminal",
+		Long: `Show the outline as a tree and edit it from the keyboard: add, rename,
+indent, outdent, reorder and delete nodes and add or remove document types.
+Edits are collected into a single plan, previewed as a rename map and written
+in one locked operation. Nothing changes on disk until the plan is written.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if svc == nil {
+				return ErrNotInProject
+			}
+			ctx := cmd.Context()
+			roots, err := svc.EditTree(ctx)
+			if err != nil {
+				return err
+			}
+			m := newTUIModel(svc, roots, GetDryRun())
+			return term(ctx, func(in io.Reader, out io.Writer, height int) error {
+				return m.run(ctx, in, out, height)
+			})
+		},
+	}
+}
+
+// tuiHelp lists the TUI's keys.
+const tuiHelp = "j/k select  a/A add sibling/child  r rename  >/< indent/outdent  " +
+	"J/K move down/up  x delete  t/T add/remove type  p preview  w write  q quit"
+
+// tuiRow is a node as listed on screen, with where it sits in the tree.
+type tuiRow struct {
+	node     *EditNode
+	depth    int
+	parent   *EditNode
+	siblings *[]*EditNode
+	index    int
+}
+
+// tuiModel is the state of a TUI session: the edited tree, the selection
+// and any prompt, question or preview on screen.
+type tuiModel struct {
+	svc    TUIService
+	dryRun bool
+	roots  []*EditNode
+	loaded []string
+	rows   []tuiRow
+	cursor int
+	top    int
+	dirty  bool
+	quit   bool
+	status string
+
+	// preview lists a planned change per line until the next key.
+	preview []string
+
+	// prompt asks for a line of text, passed to onInput on enter.
+	prompt  string
+	input   []rune
+	onInput func(ctx context.Context, text string)
+
+	// question asks for y or n, calling onYes on y.
+	question string
+	onYes    func(ctx context.Context)
+}
+
+func newTUIModel(svc TUIService, roots []*EditNode, dryRun bool) *tuiModel {
+	m := &tuiModel{svc: svc, roots: roots, loaded: editSIDs(roots), dryRun: dryRun}
+	m.refresh()
+	return m
+}
+
+// editSIDs returns the SIDs of the nodes in a tree.
+func editSIDs(nodes []*EditNode) []string {
+	var sids []string
+	for _, n := range nodes {
+		if n.SID != "" {
+			sids = append(sids, n.SID)
+		}
+		sids = append(sids, editSIDs(n.Children)...)
+	}
+	return sids
+}
+
+// run draws the screen and handles keys until the session is quit or the
+// input ends.
+func (m *tuiModel) run(ctx context.Context, in io.Reader, out io.Writer, height int) error {
+	r := bufio.NewReader(in)
+	for !m.quit {
+		io.WriteString(out, m.render(height))
+		key, err := readKey(r)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		m.handleKey(ctx, key)
+	}
+	return nil
+}
+
+// readKey reads one keypress, naming the special keys the TUI uses.
+// Escape sequences it does not know yield "".
+func readKey(r *bufio.Reader) (string, error) {
+	c, _, err := r.ReadRune()
+	if err != nil {
+		return "", err
+	}
+	switch c {
+	case '\r', '\n':
+		return "enter", nil
+	case 0x7f, 0x08:
+		return "backspace", nil
+	case '\t':
+		return "tab", nil
+	case 0x03:
+		return "ctrl-c", nil
+	case 0x1b:
+		// A lone escape arrives by itself; a sequence arrives all at once.
+		if r.Buffered() == 0 {
+			return "esc", nil
+		}
+		if next, _, _ := r.ReadRune(); next != '[' && next != 'O' {
+			r.UnreadRune()
+			return "esc", nil
+		}
+		code, _, _ := r.ReadRune()
+		return map[rune]string{'A': "up", 'B': "down", 'C': "right", 'D': "left", 'Z': "shift-tab"}[code], nil
+	}
+	return string(c), nil
+}
+
+// refresh lists the tree's nodes in order and keeps the cursor on one.
+func (m *tuiModel) refresh() {
+	m.rows = m.rows[:0]
+	var walk func(nodes *[]*EditNode, parent *EditNode, depth int)
+	walk = func(nodes *[]*EditNode, parent *EditNode, depth int) {
+		for i, n := range *nodes {
+			m.rows = append(m.rows, tuiRow{node: n, depth: depth, parent: parent, siblings: nodes, index: i})
+			walk(&n.Children, n, depth+1)
+		}
+	}
+	walk(&m.roots, nil, 0)
+	m.cursor = max(min(m.cursor, len(m.rows)-1), 0)
+}
+
+// current returns the selected row, if the tree has any.
+func (m *tuiModel) current() (tuiRow, bool) {
+	if len(m.rows) == 0 {
+		return tuiRow{}, false
+	}
+	return m.rows[m.cursor], true
+}
+
+// changed records an edit and selects node.
+func (m *tuiModel) changed(node *EditNode) {
+	m.dirty = true
+	m.refresh()
+	if i := slices.IndexFunc(m.rows, func(r tuiRow) bool { return r.node == node }); i >= 0 {
+		m.cursor = i
+	}
+}
+
+// ask prompts for a line of text, starting from initial.
+func (m *tuiModel) ask(prompt, initial string, onInput func(ctx context.Context, text string)) {
+	m.prompt, m.input, m.onInput = prompt, []rune(initial), onInput
+}
+
+// confirm asks a yes-or-no question.
+func (m *tuiModel) confirm(question string, onYes func(ctx context.Context)) {
+	m.question, m.onYes = question, onYes
+}
+
+func (m *tuiModel) handleKey(ctx context.Context, key string) {
+	switch {
+	case m.onInput != nil:
+		m.handleInput(ctx, key)
+	case m.onYes != nil:
+		onYes := m.onYes
+		m.question, m.onYes, m.preview = "", nil, nil
+		if key == "y" || key == "Y" {
+			onYes(ctx)
+		} else {
+			m.status = "Cancelled"
+		}
+	case m.preview != nil:
+		m.preview = nil
+	default:
+		m.status = ""
+		m.command(ctx, key)
+	}
+}
+
+func (m *tuiModel) handleInput(ctx context.Context, key string) {
+	switch key {
+	case "enter":
+		onInput, text := m.onInput, strings.TrimSpace(string(m.input))
+		m.prompt, m.input, m.onInput = "", nil, nil
+		onInput(ctx, text)
+	case "esc", "ctrl-c":
+		m.prompt, m.input, m.onInput = "", nil, nil
+		m.status = "Cancelled"
+	case "backspace":
+		if len(m.input) > 0 {
+			m.input = m.input[:len(m.input)-1]
+		}
+	default:
+		if r, size := utf8.DecodeRuneInString(key); size > 0 && size == len(key) && unicode.IsPrint(r) {
+			m.input = append(m.input, r)
+		}
+	}
+}
+
+func (m *tuiModel) command(ctx context.Context, key string) {
+	row, ok := m.current()
+	switch key {
+	case "up", "k":
+		m.cursor = max(m.cursor-1, 0)
+	case "down", "j":
+		m.cursor = max(min(m.cursor+1, len(m.rows)-1), 0)
+	case "a":
+		m.ask("New sibling title: ", "", m.addSibling)
+	case "A":
+		m.ask("New child title: ", "", m.addChild)
+	case "q", "ctrl-c":
+		if !m.dirty {
+			m.quit = true
+			return
+		}
+		m.confirm("Discard unwritten changes? (y/n)", func(context.Context) { m.quit = true })
+	case "p":
+		if plan := m.plan(ctx); plan != nil {
+			m.preview = plan
+		}
+	case "w":
+		m.write(ctx)
+	}
+	if !ok {
+		return
+	}
+
+	node := row.node
+	switch key {
+	case "r":
+		m.ask("Title: ", node.Title, func(_ context.Context, title string) {
+			if title == "" {
+				m.status = "Title must not be empty"
+				return
+			}
+			node.Title = title
+			m.changed(node)
+		})
+	case ">", "tab":
+		if row.index == 0 {
+			m.status = "Nothing to indent under"
+			return
+		}
+		prev := (*row.siblings)[row.index-1]
+		*row.siblings = slices.Delete(*row.siblings, row.index, row.index+1)
+		prev.Children = append(prev.Children, node)
+		m.changed(node)
+	case "<", "shift-tab":
+		if row.parent == nil {
+			m.status = "Already at the top level"
+			return
+		}
+		parentRow := m.rows[slices.IndexFunc(m.rows, func(r tuiRow) bool { return r.node == row.parent })]
+		row.parent.Children = slices.Delete(row.parent.Children, row.index, row.index+1)
+		*parentRow.siblings = slices.Insert(*parentRow.siblings, parentRow.index+1, node)
+		m.changed(node)
+	case "K", "J":
+		other := row.index - 1
+		if key == "J" {
+			other = row.index + 1
+		}
+		if other < 0 || other >= len(*row.siblings) {
+			m.status = "No sibling to swap with"
+			return
+		}
+		siblings := *row.siblings
+		siblings[row.index], siblings[other] = siblings[other], siblings[row.index]
+		m.changed(node)
+	case "x":
+		question := fmt.Sprintf("Delete %q? (y/n)", node.Title)
+		if n := countDescendants(node); n > 0 {
+			question = fmt.Sprintf("Delete %q and %d descendant(s)? (y/n)", node.Title, n)
+		}
+		m.confirm(question, func(context.Context) {
+			*row.siblings = slices.Delete(*row.siblings, row.index, row.index+1)
+			m.dirty = true
+			m.refresh()
+		})
+	case "t":
+		m.ask("Add type: ", "", func(_ context.Context, docType string) {
+			switch {
+			case domain.ValidateDocType(docType) != nil:
+				m.status = fmt.Sprintf("Invalid type %q", docType)
+			case docType == domain.DocTypeDraft || slices.Contains(node.Types, docType):
+				m.status = fmt.Sprintf("%s already has %s", node.Title, docType)
+			default:
+				node.Types = append(node.Types, docType)
+				slices.Sort(node.Types)
+				m.changed(node)
+			}
+		})
+	case "T":
+		m.ask("Remove type: ", "", func(_ context.Context, docType string) {
+			i := slices.Index(node.Types, docType)
+			if i < 0 {
+				m.status = fmt.Sprintf("%s has no %s to remove", node.Title, docType)
+				return
+			}
+			node.Types = slices.Delete(node.Types, i, i+1)
+			m.changed(node)
+		})
+	}
+}
+
+// addSibling adds a node after the selected one, or at the end of the top
+// level when the tree is empty.
+func (m *tuiModel) addSibling(_ context.Context, title string) {
+	if title == "" {
+		m.status = "Title must not be empty"
+		return
+	}
+	node := &EditNode{Title: title}
+	if row, ok := m.current(); ok {
+		*row.siblings = slices.Insert(*row.siblings, row.index+1, node)
+	} else {
+		m.roots = append(m.roots, node)
+	}
+	m.changed(node)
+}
+
+// addChild adds a node as the last child of the selected one, or at the
+// top level when the tree is empty.
+func (m *tuiModel) addChild(ctx context.Context, title string) {
+	row, ok := m.current()
+	if !ok || title == "" {
+		m.addSibling(ctx, title)
+		return
+	}
+	node := &EditNode{Title: title}
+	row.node.Children = append(row.node.Children, node)
+	m.changed(node)
+}
+
+// countDescendants returns the number of nodes below node.
+func countDescendants(node *EditNode) int {
+	n := len(node.Children)
+	for _, c := range node.Children {
+		n += countDescendants(c)
+	}
+	return n
+}
+
+// plan previews the edits, returning a line per change, or nil with the
+// status set when there is nothing to show.
+func (m *tuiModel) plan(ctx context.Context) []string {
+	plan, err := m.svc.ApplyEdits(ctx, m.loaded, m.roots, false)
+	if err != nil {
+		m.status = "Error: " + err.Error()
+		return nil
+	}
+	lines := planLines(plan)
+	if len(lines) == 0 {
+		m.status = "No changes to write"
+		return nil
+	}
+	return lines
+}
+
+// write previews the edits and, once confirmed, applies them and reloads
+// the tree from disk.
+func (m *tuiModel) write(ctx context.Context) {
+	lines := m.plan(ctx)
+	if lines == nil {
+		return
+	}
+	m.preview = lines
+	if m.dryRun {
+		m.status = "Dry run: nothing written"
+		return
+	}
+	m.confirm(fmt.Sprintf("Write %d change(s)? (y/n)", len(lines)), func(ctx context.Context) {
+		if _, err := m.svc.ApplyEdits(ctx, m.loaded, m.roots, true); err != nil {
+			m.status = "Error: " + err.Error()
+			return
+		}
+		roots, err := m.svc.EditTree(ctx)
+		if err != nil {
+			m.status = "Error: " + err.Error()
+			return
+		}
+		m.roots, m.loaded, m.dirty = roots, editSIDs(roots), false
+		m.refresh()
+		m.status = fmt.Sprintf("Wrote %d change(s)", len(lines))
+	})
+}
+
+// planLines describes each change in a plan on a line of its own.
+func planLines(plan *EditPlan) []string {
+	var lines []string
+	renames := slices.SortedFunc(slices.Values(plan.Renames), func(a, b RenameEntry) int { return strings.Compare(a.Old, b.Old) })
+	for _, r := range renames {
+		lines = append(lines, fmt.Sprintf("rename %s -> %s", r.Old, r.New))
+	}
+	for _, name := range plan.Deleted {
+		lines = append(lines, "delete "+name)
+	}
+	for _, name := range plan.Written {
+		lines = append(lines, "write  "+name)
+	}
+	for _, a := range plan.Added {
+		lines = append(lines, fmt.Sprintf("add    %s %s", a.MP, a.Title))
+	}
+	return lines
+}
+
+// render draws the whole screen for a terminal of the given height: a
+// title, the tree or a preview, a status line and a prompt or help line.
+func (m *tuiModel) render(height int) string {
+	body := max(height-3, 1)
+	title := "lmk tui"
+	if m.dirty {
+		title += " [modified]"
+	}
+	lines := []string{title}
+
+	switch {
+	case m.preview != nil:
+		shown := m.preview
+		if len(shown) > body {
+			shown = append(slices.Clone(shown[:body-1]), fmt.Sprintf("... and %d more", len(shown)-body+1))
+		}
+		lines = append(lines, shown...)
+	case len(m.rows) == 0:
+		lines = append(lines, "  (empty outline; press a to add a node)")
+	default:
+		m.top = min(m.top, m.cursor)
+		m.top = max(m.top, m.cursor-body+1)
+		for i := m.top; i < min(len(m.rows), m.top+body); i++ {
+			lines = append(lines, m.rowLine(i))
+		}
+	}
+	for len(lines) < body+1 {
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, m.status)
+	switch {
+	case m.onInput != nil:
+		lines = append(lines, m.prompt+string(m.input))
+	case m.onYes != nil:
+		lines = append(lines, m.question)
+	default:
+		lines = append(lines, tuiHelp)
+	}
+	return "\x1b[H\x1b[2J" + strings.Join(lines, "\r\n")
+}
+
+// rowLine formats a row: a cursor marker, the SID, then the title indented
+// by depth with any types besides the draft.
+func (m *tuiModel) rowLine(i int) string {
+	row := m.rows[i]
+	marker := "  "
+	if i == m.cursor {
+		marker = "> "
+	}
+	sid := row.node.SID
+	if sid == "" {
+		sid = "(new)"
+	}
+	line := fmt.Sprintf("%s%-12s %s%s", marker, sid, strings.Repeat("  ", row.depth), row.node.Title)
+	if len(row.node.Types) > 0 {
+		line += " [" + strings.Join(row.node.Types, ", ") + "]"
+	}
+	return line
+}
@@ -0,0 +1,44 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// runTerminalImpl puts the terminal on stdin in raw mode and switches to
+// the alternate screen for the session, restoring both afterwards.
+func runTerminalImpl(_ context.Context, session func(in io.Reader, out io.Writer, height int) error) error {
+	fd := int(os.Stdin.Fd())
+	saved, err := unix.IoctlGetTermios(fd, ioctlReadTermios)
+	if err != nil {
+		return errNotTerminal
+	}
+
+	raw := *saved
+	raw.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+	raw.Oflag &^= unix.OPOST
+	raw.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	raw.Cflag &^= unix.CSIZE | unix.PARENB
+	raw.Cflag |= unix.CS8
+	raw.Cc[unix.VMIN] = 1
+	raw.Cc[unix.VTIME] = 0
+	if err := unix.IoctlSetTermios(fd, ioctlWriteTermios, &raw); err != nil {
+		return fmt.Errorf("entering raw mode: %w", err)
+	}
+	defer unix.IoctlSetTermios(fd, ioctlWriteTermios, saved)
+
+	height := 24
+	if ws, err := unix.IoctlGetWinsize(fd, unix.TIOCGWINSZ); err == nil && ws.Row > 0 {
+		height = int(ws.Row)
+	}
+
+	fmt.Fprint(os.Stdout, "\x1b[?1049h")
+	defer fmt.Fprint(os.Stdout, "\x1b[?1049l")
+	return session(os.Stdin, os.Stdout, height)
+}
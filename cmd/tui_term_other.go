@@ -0,0 +1,15 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// runTerminalImpl reports that the TUI has no terminal support on this
+// platform.
+func runTerminalImpl(context.Context, func(in io.Reader, out io.Writer, height int) error) error {
+	return errors.New("lmk tui is not supported on this platform")
+}
@@ -0,0 +1,496 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/eykd/linemark-go/internal/outline"
+)
+
+// mockTUIService is a test double for TUIService.
+type mockTUIService struct {
+	tree      []*EditNode
+	treeErr   error
+	reloadErr error
+	plan      *EditPlan
+	planErr   error
+	applyErr  error
+
+	loads   int
+	applies int
+	applied string
+	loaded  []string
+}
+
+func (m *mockTUIService) EditTree(ctx context.Context) ([]*EditNode, error) {
+	m.loads++
+	if m.loads > 1 && m.reloadErr != nil {
+		return nil, m.reloadErr
+	}
+	return cloneEditNodes(m.tree), m.treeErr
+}
+
+func (m *mockTUIService) ApplyEdits(ctx context.Context, loaded []string, roots []*EditNode, apply bool) (*EditPlan, error) {
+	m.loaded = loaded
+	if !apply {
+		return m.plan, m.planErr
+	}
+	m.applies++
+	m.applied = tuiTreeString(roots, 0)
+	return m.plan, m.applyErr
+}
+
+// tuiTreeString renders an edit tree one node per line, indented by depth.
+func tuiTreeString(nodes []*EditNode, depth int) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		fmt.Fprintf(&b, "%s%s %v\n", strings.Repeat("  ", depth), n.Title, n.Types)
+		b.WriteString(tuiTreeString(n.Children, depth+1))
+	}
+	return b.String()
+}
+
+// cloneEditNodes deep-copies an edit tree, as a fresh load from disk would.
+func cloneEditNodes(nodes []*EditNode) []*EditNode {
+	var out []*EditNode
+	for _, n := range nodes {
+		c := *n
+		c.Children = cloneEditNodes(n.Children)
+		out = append(out, &c)
+	}
+	return out
+}
+
+// newTUITree returns a book with notes and two chapters, then an appendix.
+func newTUITree() []*EditNode {
+	return []*EditNode{
+		{SID: "SID000AABB", Title: "Book", Types: []string{"notes"}, Children: []*EditNode{
+			{SID: "SID001AABB", Title: "One"},
+			{SID: "SID002AABB", Title: "Two"},
+		}},
+		{SID: "SID003AABB", Title: "Appendix"},
+	}
+}
+
+var testEditPlan = &EditPlan{
+	Renames: []RenameEntry{{Old: "200_b.md", New: "300_b.md"}, {Old: "100_a.md", New: "110_a.md"}},
+	Deleted: []string{"400_gone.md"},
+	Written: []string{"500_notes.md"},
+	Added:   []EditAddition{{MP: "600", Title: "New"}},
+}
+
+// runTUICmd runs the tui command under a root command with the given keys
+// as input and returns everything drawn on the screen.
+func runTUICmd(t *testing.T, svc TUIService, height int, keys string, args ...string) (string, error) {
+	t.Helper()
+	var screen bytes.Buffer
+	term := func(ctx context.Context, session func(in io.Reader, out io.Writer, height int) error) error {
+		return session(strings.NewReader(keys), &screen, height)
+	}
+	root := NewRootCmd()
+	root.AddCommand(NewTUICmd(svc, term))
+	root.SetOut(new(bytes.Buffer))
+	root.SetErr(new(bytes.Buffer))
+	root.SetArgs(append([]string{"tui"}, args...))
+	t.Cleanup(func() { dryRun, jsonFlag = false, false })
+	err := root.Execute()
+	return screen.String(), err
+}
+
+// lastFrame returns the last screen drawn.
+func lastFrame(screen string) string {
+	frames := strings.Split(screen, "\x1b[H\x1b[2J")
+	return frames[len(frames)-1]
+}
+
+func TestTUICmd_Edits(t *testing.T) {
+	const backspace4 = "\x7f\x7f\x7f\x7f"
+	tests := []struct {
+		name string
+		keys string
+		want string
+	}{
+		{"rename", "r" + backspace4 + "Tome\r", "Tome [notes]\n  One []\n  Two []\nAppendix []\n"},
+		{"add sibling", "jaNew\r", "Book [notes]\n  One []\n  New []\n  Two []\nAppendix []\n"},
+		{"add child", "AÉté\r", "Book [notes]\n  One []\n  Two []\n  Été []\nAppendix []\n"},
+		{"ignore special keys in a prompt", "aX\x1b[Y\r", "Book [notes]\n  One []\n  Two []\nX []\nAppendix []\n"},
+		{"indent", "jj>", "Book [notes]\n  One []\n    Two []\nAppendix []\n"},
+		{"indent with tab", "\x1b[B\x1b[B\x1b[B\t", "Book [notes]\n  One []\n  Two []\n  Appendix []\n"},
+		{"outdent", "j<", "Book [notes]\n  Two []\nOne []\nAppendix []\n"},
+		{"outdent with shift-tab", "jj\x1b[Z", "Book [notes]\n  One []\nTwo []\nAppendix []\n"},
+		{"move down", "jJ", "Book [notes]\n  Two []\n  One []\nAppendix []\n"},
+		{"move up", "jj\x1bOA\x1bOBK", "Book [notes]\n  Two []\n  One []\nAppendix []\n"},
+		{"delete with descendants", "xy", "Appendix []\n"},
+		{"delete a leaf", "jjxy", "Book [notes]\n  One []\nAppendix []\n"},
+		{"add type", "tsummary\r", "Book [notes summary]\n  One []\n  Two []\nAppendix []\n"},
+		{"remove type", "Tnotes\r", "Book []\n  One []\n  Two []\nAppendix []\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockTUIService{tree: newTUITree(), plan: testEditPlan}
+
+			screen, err := runTUICmd(t, svc, 20, tt.keys+"wyq")
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if svc.applies != 1 || svc.applied != tt.want {
+				t.Errorf("applied %d time(s):\n%s\nwant\n%s", svc.applies, svc.applied, tt.want)
+			}
+			if want := []string{"SID000AABB", "SID001AABB", "SID002AABB", "SID003AABB"}; !reflect.DeepEqual(svc.loaded, want) {
+				t.Errorf("loaded SIDs = %v, want %v", svc.loaded, want)
+			}
+			if !strings.Contains(screen, "Wrote 5 change(s)") {
+				t.Errorf("screen does not report the write:\n%s", lastFrame(screen))
+			}
+		})
+	}
+}
+
+func TestTUICmd_Statuses(t *testing.T) {
+	tests := []struct {
+		name string
+		keys string
+		want string
+	}{
+		{"indent the first child", ">", "Nothing to indent under"},
+		{"outdent at the top level", "<", "Already at the top level"},
+		{"swap the first sibling up", "K", "No sibling to swap with"},
+		{"swap the last sibling down", "jjJ", "No sibling to swap with"},
+		{"add without a title", "a\r", "Title must not be empty"},
+		{"rename to nothing", "r\x7f\x7f\x7f\x7f\r", "Title must not be empty"},
+		{"add an invalid type", "tBad Type\r", `Invalid type "Bad Type"`},
+		{"add a type twice", "tnotes\r", "Book already has notes"},
+		{"add the draft", "tdraft\r", "Book already has draft"},
+		{"remove a missing type", "Tsummary\r", "Book has no summary to remove"},
+		{"cancel a prompt", "aFoo\x1b", "Cancelled"},
+		{"cancel a prompt with ctrl-c", "aFoo\x03", "Cancelled"},
+		{"cancel a delete", "xn", "Cancelled"},
+		{"ask before deleting descendants", "x", `Delete "Book" and 2 descendant(s)? (y/n)`},
+		{"ask before deleting a leaf", "jx", `Delete "One"? (y/n)`},
+		{"ask before discarding edits", "jJq", "Discard unwritten changes? (y/n)"},
+		{"keep editing after declining to quit", "jJqn", "[modified]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockTUIService{tree: newTUITree(), plan: testEditPlan}
+
+			screen, err := runTUICmd(t, svc, 20, tt.keys)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.Contains(lastFrame(screen), tt.want) {
+				t.Errorf("last frame does not show %q:\n%s", tt.want, lastFrame(screen))
+			}
+			if svc.applies != 0 {
+				t.Errorf("edits applied %d time(s), want none", svc.applies)
+			}
+		})
+	}
+}
+
+func TestTUICmd_Preview(t *testing.T) {
+	svc := &mockTUIService{tree: newTUITree(), plan: testEditPlan}
+
+	screen, err := runTUICmd(t, svc, 20, "p")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	frame := lastFrame(screen)
+	want := "rename 100_a.md -> 110_a.md\r\nrename 200_b.md -> 300_b.md\r\ndelete 400_gone.md\r\nwrite  500_notes.md\r\nadd    600 New"
+	if !strings.Contains(frame, want) {
+		t.Errorf("preview frame = %q, want it to list %q", frame, want)
+	}
+}
+
+func TestTUICmd_PreviewIsDismissedByAnyKey(t *testing.T) {
+	svc := &mockTUIService{tree: newTUITree(), plan: testEditPlan}
+
+	screen, err := runTUICmd(t, svc, 20, "pj")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	frame := lastFrame(screen)
+	if strings.Contains(frame, "rename 100_a.md") || !strings.Contains(frame, "> SID000AABB") {
+		t.Errorf("frame = %q, want the tree back with the selection unmoved", frame)
+	}
+}
+
+func TestTUICmd_PreviewTruncated(t *testing.T) {
+	svc := &mockTUIService{tree: newTUITree(), plan: testEditPlan}
+
+	screen, err := runTUICmd(t, svc, 6, "p")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if frame := lastFrame(screen); !strings.Contains(frame, "... and 3 more") {
+		t.Errorf("frame = %q, want the preview cut short", frame)
+	}
+}
+
+func TestTUICmd_WriteStatuses(t *testing.T) {
+	tests := []struct {
+		name  string
+		svc   *mockTUIService
+		keys  string
+		args  []string
+		want  string
+		wrote int
+	}{
+		{"nothing to write", &mockTUIService{plan: &EditPlan{}}, "w", nil, "No changes to write", 0},
+		{"nothing to preview", &mockTUIService{plan: &EditPlan{}}, "p", nil, "No changes to write", 0},
+		{"plan failure", &mockTUIService{planErr: errors.New("too many siblings")}, "w", nil, "Error: too many siblings", 0},
+		{"dry run", &mockTUIService{plan: testEditPlan}, "w", []string{"--dry-run"}, "Dry run: nothing written", 0},
+		{"declined", &mockTUIService{plan: testEditPlan}, "wn", nil, "Cancelled", 0},
+		{"apply failure", &mockTUIService{plan: testEditPlan, applyErr: errors.New("disk full")}, "wy", nil, "Error: disk full", 1},
+		{"reload failure", &mockTUIService{plan: testEditPlan, reloadErr: errors.New("disk gone")}, "wy", nil, "Error: disk gone", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.svc.tree = newTUITree()
+
+			screen, err := runTUICmd(t, tt.svc, 20, tt.keys, tt.args...)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.Contains(lastFrame(screen), tt.want) {
+				t.Errorf("last frame does not show %q:\n%s", tt.want, lastFrame(screen))
+			}
+			if tt.svc.applies != tt.wrote {
+				t.Errorf("applies = %d, want %d", tt.svc.applies, tt.wrote)
+			}
+		})
+	}
+}
+
+func TestTUICmd_ConfirmWriteShowsPlan(t *testing.T) {
+	svc := &mockTUIService{tree: newTUITree(), plan: testEditPlan}
+
+	screen, err := runTUICmd(t, svc, 20, "w")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	frame := lastFrame(screen)
+	if !strings.Contains(frame, "rename 100_a.md -> 110_a.md") || !strings.HasSuffix(frame, "Write 5 change(s)? (y/n)") {
+		t.Errorf("frame = %q, want the plan and a question", frame)
+	}
+}
+
+func TestTUICmd_WriteClearsModified(t *testing.T) {
+	svc := &mockTUIService{tree: newTUITree(), plan: testEditPlan}
+
+	screen, err := runTUICmd(t, svc, 20, "jJwy")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if frame := lastFrame(screen); strings.Contains(frame, "[modified]") {
+		t.Errorf("frame = %q, want the edits written", frame)
+	}
+}
+
+func TestTUICmd_Quit(t *testing.T) {
+	for _, keys := range []string{"q", "\x03", "jJqy"} {
+		t.Run(fmt.Sprintf("%q", keys), func(t *testing.T) {
+			svc := &mockTUIService{tree: newTUITree()}
+
+			// Keys after quitting would fail the test by applying edits.
+			_, err := runTUICmd(t, svc, 20, keys+"wy")
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if svc.applies != 0 {
+				t.Errorf("keys were read after quitting")
+			}
+		})
+	}
+}
+
+func TestTUICmd_EmptyOutline(t *testing.T) {
+	svc := &mockTUIService{plan: testEditPlan}
+
+	screen, err := runTUICmd(t, svc, 20, "AFirst\raSecond\rwy")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(screen, "(empty outline; press a to add a node)") {
+		t.Error("empty outline not shown")
+	}
+	if want := "First []\nSecond []\n"; svc.applied != want {
+		t.Errorf("applied:\n%s\nwant\n%s", svc.applied, want)
+	}
+}
+
+func TestTUICmd_Scrolls(t *testing.T) {
+	svc := &mockTUIService{tree: newTUITree()}
+
+	screen, err := runTUICmd(t, svc, 5, "jjj")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	frame := lastFrame(screen)
+	if strings.Contains(frame, "Book") || !strings.Contains(frame, "  SID002AABB     Two") || !strings.Contains(frame, "> SID003AABB   Appendix") {
+		t.Errorf("frame = %q, want the last two rows", frame)
+	}
+
+	screen, _ = runTUICmd(t, svc, 5, "jjjkkk")
+	if frame := lastFrame(screen); !strings.Contains(frame, "> SID000AABB   Book [notes]") {
+		t.Errorf("frame = %q, want the view scrolled back to the top", frame)
+	}
+}
+
+func TestTUICmd_ShowsNewNodes(t *testing.T) {
+	svc := &mockTUIService{tree: newTUITree()}
+
+	screen, err := runTUICmd(t, svc, 20, "ANew\r")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if frame := lastFrame(screen); !strings.Contains(frame, "> (new)          New") || !strings.Contains(frame, "lmk tui [modified]") {
+		t.Errorf("frame = %q, want the new node selected", frame)
+	}
+}
+
+func TestTUICmd_Errors(t *testing.T) {
+	t.Run("load failure", func(t *testing.T) {
+		_, err := runTUICmd(t, &mockTUIService{treeErr: errors.New("shared MP")}, 20, "q")
+		if err == nil || err.Error() != "shared MP" {
+			t.Errorf("error = %v, want the load failure", err)
+		}
+	})
+
+	t.Run("terminal failure", func(t *testing.T) {
+		root := NewRootCmd()
+		root.AddCommand(NewTUICmd(&mockTUIService{}, func(context.Context, func(io.Reader, io.Writer, int) error) error {
+			return errNotTerminal
+		}))
+		root.SetArgs([]string{"tui"})
+		if err := root.Execute(); !errors.Is(err, errNotTerminal) {
+			t.Errorf("error = %v, want errNotTerminal", err)
+		}
+	})
+
+	t.Run("input failure", func(t *testing.T) {
+		m := newTUIModel(&mockTUIService{}, nil, false)
+		err := m.run(context.Background(), iotest.ErrReader(errors.New("tty gone")), io.Discard, 20)
+		if err == nil || err.Error() != "tty gone" {
+			t.Errorf("error = %v, want the read failure", err)
+		}
+	})
+}
+
+func TestReadKey(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"a\r\n\x7f\x08\t\x03", []string{"a", "enter", "enter", "backspace", "backspace", "tab", "ctrl-c"}},
+		{"\x1b[A\x1b[B\x1b[C\x1b[D\x1b[Z\x1bOA", []string{"up", "down", "right", "left", "shift-tab", "up"}},
+		{"\x1bq", []string{"esc", "q"}},
+		{"\x1b", []string{"esc"}},
+		{"\x1b[3~", []string{"", "~"}},
+		{"é", []string{"é"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%q", tt.input), func(t *testing.T) {
+			r := bufio.NewReader(strings.NewReader(tt.input))
+			var got []string
+			for {
+				key, err := readKey(r)
+				if err != nil {
+					break
+				}
+				got = append(got, key)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("keys = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// --- tuiAdapter tests ---
+
+func TestTUIAdapter_EditTree(t *testing.T) {
+	stub := &stubOutlineService{editTree: []*outline.EditNode{
+		{SID: "SID000AABB", Title: "Book", Types: []string{"notes"}, Children: []*outline.EditNode{{SID: "SID001AABB", Title: "One"}}},
+	}}
+	adapter := &tuiAdapter{svc: stub}
+
+	roots, err := adapter.EditTree(context.Background())
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := tuiTreeString(roots, 0), "Book [notes]\n  One []\n"; got != want {
+		t.Errorf("tree = %q, want %q", got, want)
+	}
+	if roots[0].SID != "SID000AABB" || roots[0].Children[0].SID != "SID001AABB" {
+		t.Errorf("SIDs not carried over: %+v", roots[0])
+	}
+}
+
+func TestTUIAdapter_ApplyEdits(t *testing.T) {
+	stub := &stubOutlineService{editPlan: &outline.EditPlan{
+		Renames: map[string]string{"100_a.md": "200_a.md"},
+		Deleted: []string{"300_b.md"},
+		Written: []string{"400_c.md"},
+		Added:   []outline.EditAddition{{SID: "SID009AABB", MP: "500", Title: "New"}},
+	}}
+	adapter := &tuiAdapter{svc: stub}
+
+	plan, err := adapter.ApplyEdits(context.Background(), []string{"SID001AABB"}, []*EditNode{{Title: "New", Children: []*EditNode{{SID: "SID001AABB", Title: "One"}}}}, true)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := &EditPlan{
+		Renames: []RenameEntry{{Old: "100_a.md", New: "200_a.md"}},
+		Deleted: []string{"300_b.md"},
+		Written: []string{"400_c.md"},
+		Added:   []EditAddition{{SID: "SID009AABB", MP: "500", Title: "New"}},
+	}
+	if !reflect.DeepEqual(plan, want) {
+		t.Errorf("plan = %+v, want %+v", plan, want)
+	}
+	if !stub.editApply || len(stub.editRoots) != 1 || stub.editRoots[0].Children[0].SID != "SID001AABB" {
+		t.Errorf("service got roots %+v apply=%v", stub.editRoots, stub.editApply)
+	}
+	if !reflect.DeepEqual(stub.editLoaded, []string{"SID001AABB"}) {
+		t.Errorf("service got loaded SIDs %v", stub.editLoaded)
+	}
+}
+
+func TestTUIAdapter_Errors(t *testing.T) {
+	stub := &stubOutlineService{editErr: errors.New("locked")}
+	adapter := &tuiAdapter{svc: stub}
+
+	if _, err := adapter.EditTree(context.Background()); err == nil {
+		t.Error("EditTree: expected error")
+	}
+	if _, err := adapter.ApplyEdits(context.Background(), nil, nil, false); err == nil {
+		t.Error("ApplyEdits: expected error")
+	}
+}
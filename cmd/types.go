@@ -3,7 +3,9 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 
+	"github.com/eykd/linemark-go/internal/domain"
 	"github.com/spf13/cobra"
 )
 
@@ -26,11 +28,45 @@ type TypesModifyResult struct {
 	Planned  bool     `json:"planned"`
 }
 
+// TypesBatchResult holds the result of adding or removing a type on every
+// node a selector expression selects. Skipped lists the nodes that already
+// had the type, or lacked it.
+type TypesBatchResult struct {
+	Changed []TypesModifyResult `json:"changed"`
+	Skipped []NodeInfo          `json:"skipped"`
+	Planned bool                `json:"planned"`
+}
+
 // TypesService defines the interface for managing document types.
+// AddTypes and RemoveTypes take a selector expression selecting any number
+// of nodes.
 type TypesService interface {
 	ListTypes(ctx context.Context, selector string) (*TypesListResult, error)
-	AddType(ctx context.Context, docType, selector string, apply bool) (*TypesModifyResult, error)
+	AddType(ctx context.Context, docType, selector, template string, apply bool) (*TypesModifyResult, error)
 	RemoveType(ctx context.Context, docType, selector string, apply bool) (*TypesModifyResult, error)
+	AddTypes(ctx context.Context, docType, selector, template string, apply bool) (*TypesBatchResult, error)
+	RemoveTypes(ctx context.Context, docType, selector string, apply bool) (*TypesBatchResult, error)
+}
+
+// selectsMany reports whether selector is an expression selecting a set of
+// nodes rather than naming one.
+func selectsMany(selector string) bool {
+	expr, err := domain.ParseSelectorExpr(selector)
+	if err != nil {
+		return false
+	}
+	_, single := expr.Single()
+	return !single
+}
+
+// writeTypesBatch prints one line per changed file, then the skipped nodes.
+func writeTypesBatch(w io.Writer, result *TypesBatchResult, verb, skipReason string) {
+	for _, c := range result.Changed {
+		fmt.Fprintf(w, "%s %s\n", verb, c.Filename)
+	}
+	for _, n := range result.Skipped {
+		fmt.Fprintf(w, "Skipped %s (%s): %s\n", n.MP, n.SID, skipReason)
+	}
 }
 
 // NewTypesCmd creates the types command with the given service.
@@ -83,10 +119,14 @@ func newTypesListCmd(svc TypesService) *cobra.Command {
 
 func newTypesAddCmd(svc TypesService) *cobra.Command {
 	var jsonOutput bool
+	var template string
 
 	cmd := &cobra.Command{
-		Use:          "add <type> <selector>",
-		Short:        "Add a document type to a node",
+		Use:   "add <type> <selector>",
+		Short: "Add a document type to a node",
+		Long: `Add a document type to a node. The selector may also be an expression
+selecting several nodes, such as "001-200/**" or "title:Chapter*"; nodes
+that already have the type are skipped.`,
 		Args:         cobra.ExactArgs(2),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -94,7 +134,21 @@ func newTypesAddCmd(svc TypesService) *cobra.Command {
 				return ErrNotInProject
 			}
 			isDryRun := GetDryRun()
-			result, err := svc.AddType(cmd.Context(), args[0], args[1], !isDryRun)
+			if selectsMany(args[1]) {
+				batch, err := svc.AddTypes(cmd.Context(), args[0], args[1], template, !isDryRun)
+				if err != nil {
+					return err
+				}
+				if jsonOutput || GetJSON() {
+					writeJSON(cmd.OutOrStdout(), batch)
+				} else if isDryRun {
+					writeTypesBatch(cmd.OutOrStdout(), batch, "Would add", "already has "+args[0])
+				} else {
+					writeTypesBatch(cmd.OutOrStdout(), batch, "Added", "already has "+args[0])
+				}
+				return nil
+			}
+			result, err := svc.AddType(cmd.Context(), args[0], args[1], template, !isDryRun)
 			if err != nil {
 				return err
 			}
@@ -115,6 +169,7 @@ func newTypesAddCmd(svc TypesService) *cobra.Command {
 	}
 
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output results as JSON")
+	cmd.Flags().StringVar(&template, "template", "", "Fill the new file from .linemark/templates/<name>.md")
 
 	return cmd
 }
@@ -123,8 +178,11 @@ func newTypesRemoveCmd(svc TypesService) *cobra.Command {
 	var jsonOutput bool
 
 	cmd := &cobra.Command{
-		Use:          "remove <type> <selector>",
-		Short:        "Remove a document type from a node",
+		Use:   "remove <type> <selector>",
+		Short: "Remove a document type from a node",
+		Long: `Remove a document type from a node. The selector may also be an
+expression selecting several nodes, such as "001-200/*" or "type:notes";
+nodes without the type are skipped.`,
 		Args:         cobra.ExactArgs(2),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -132,6 +190,20 @@ func newTypesRemoveCmd(svc TypesService) *cobra.Command {
 				return ErrNotInProject
 			}
 			isDryRun := GetDryRun()
+			if selectsMany(args[1]) {
+				batch, err := svc.RemoveTypes(cmd.Context(), args[0], args[1], !isDryRun)
+				if err != nil {
+					return err
+				}
+				if jsonOutput || GetJSON() {
+					writeJSON(cmd.OutOrStdout(), batch)
+				} else if isDryRun {
+					writeTypesBatch(cmd.OutOrStdout(), batch, "Would remove", "no "+args[0])
+				} else {
+					writeTypesBatch(cmd.OutOrStdout(), batch, "Removed", "no "+args[0])
+				}
+				return nil
+			}
 			result, err := svc.RemoveType(cmd.Context(), args[0], args[1], !isDryRun)
 			if err != nil {
 				return err
@@ -17,13 +17,18 @@ type mockTypesService struct {
 	addErr       error
 	removeResult *TypesModifyResult
 	removeErr    error
+	addTemplate  string
+	batchResult  *TypesBatchResult
+	batchErr     error
+	batchCalled  string
 }
 
 func (m *mockTypesService) ListTypes(ctx context.Context, selector string) (*TypesListResult, error) {
 	return m.listResult, m.listErr
 }
 
-func (m *mockTypesService) AddType(ctx context.Context, docType, selector string, apply bool) (*TypesModifyResult, error) {
+func (m *mockTypesService) AddType(ctx context.Context, docType, selector, template string, apply bool) (*TypesModifyResult, error) {
+	m.addTemplate = template
 	return m.addResult, m.addErr
 }
 
@@ -31,6 +36,16 @@ func (m *mockTypesService) RemoveType(ctx context.Context, docType, selector str
 	return m.removeResult, m.removeErr
 }
 
+func (m *mockTypesService) AddTypes(ctx context.Context, docType, selector, template string, apply bool) (*TypesBatchResult, error) {
+	m.batchCalled = "add"
+	return m.batchResult, m.batchErr
+}
+
+func (m *mockTypesService) RemoveTypes(ctx context.Context, docType, selector string, apply bool) (*TypesBatchResult, error) {
+	m.batchCalled = "remove"
+	return m.batchResult, m.batchErr
+}
+
 // typesListJSONOutput is a test-only type for parsing JSON output from lmk types list --json.
 type typesListJSONOutput struct {
 	Node struct {
@@ -228,6 +243,24 @@ func TestTypesAddCmd_HumanOutput(t *testing.T) {
 	}
 }
 
+func TestTypesAddCmd_TemplateFlag(t *testing.T) {
+	svc := &mockTypesService{
+		addResult: &TypesModifyResult{Node: NodeInfo{MP: "001", SID: "A3F7c9Qx7Lm2"}, Filename: "001_A3F7c9Qx7Lm2_beats.md"},
+	}
+	cmd := NewTypesCmd(svc)
+	cmd.SetArgs([]string{"add", "beats", "001", "--template", "beats-short"})
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if svc.addTemplate != "beats-short" {
+		t.Errorf("template = %q, want %q", svc.addTemplate, "beats-short")
+	}
+}
+
 func TestTypesAddCmd_JSONOutput(t *testing.T) {
 	svc := &mockTypesService{
 		addResult: &TypesModifyResult{
@@ -431,3 +464,78 @@ func TestTypesRemoveCmd_MissingArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestTypesCmd_BatchOutput(t *testing.T) {
+	batch := &TypesBatchResult{
+		Changed: []TypesModifyResult{{Node: NodeInfo{MP: "001-100", SID: "B8kQ2mNp4Rs1"}, Filename: "001-100_B8kQ2mNp4Rs1_notes.md"}},
+		Skipped: []NodeInfo{{MP: "001", SID: "A3F7c9Qx7Lm2"}},
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantCall string
+		want     string
+	}{
+		{"add", []string{"types", "add", "notes", "001/**"}, "add",
+			"Added 001-100_B8kQ2mNp4Rs1_notes.md\nSkipped 001 (A3F7c9Qx7Lm2): already has notes\n"},
+		{"add dry run", []string{"--dry-run", "types", "add", "notes", "001/**"}, "add",
+			"Would add 001-100_B8kQ2mNp4Rs1_notes.md\nSkipped 001 (A3F7c9Qx7Lm2): already has notes\n"},
+		{"remove", []string{"types", "remove", "notes", "title:*"}, "remove",
+			"Removed 001-100_B8kQ2mNp4Rs1_notes.md\nSkipped 001 (A3F7c9Qx7Lm2): no notes\n"},
+		{"remove dry run", []string{"--dry-run", "types", "remove", "notes", "001..002"}, "remove",
+			"Would remove 001-100_B8kQ2mNp4Rs1_notes.md\nSkipped 001 (A3F7c9Qx7Lm2): no notes\n"},
+		{"add json", []string{"types", "add", "--json", "notes", "001/*"}, "add",
+			`{"changed":[{"node":{"mp":"001-100","sid":"B8kQ2mNp4Rs1"},"filename":"001-100_B8kQ2mNp4Rs1_notes.md","planned":false}],"skipped":[{"mp":"001","sid":"A3F7c9Qx7Lm2"}],"planned":false}`},
+		{"remove json", []string{"--json", "types", "remove", "notes", "type:notes"}, "remove",
+			`{"changed":[{"node":{"mp":"001-100","sid":"B8kQ2mNp4Rs1"},"filename":"001-100_B8kQ2mNp4Rs1_notes.md","planned":false}],"skipped":[{"mp":"001","sid":"A3F7c9Qx7Lm2"}],"planned":false}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(func() { dryRun, jsonFlag = false, false })
+			svc := &mockTypesService{batchResult: batch}
+			root := NewRootCmd()
+			root.AddCommand(NewTypesCmd(svc))
+			buf := new(bytes.Buffer)
+			root.SetOut(buf)
+			root.SetErr(new(bytes.Buffer))
+			root.SetArgs(tt.args)
+
+			if err := root.Execute(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if svc.batchCalled != tt.wantCall {
+				t.Errorf("batch call = %q, want %q", svc.batchCalled, tt.wantCall)
+			}
+			if got := strings.TrimSpace(buf.String()); got != strings.TrimSpace(tt.want) {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTypesCmd_BatchServiceError(t *testing.T) {
+	for _, sub := range []string{"add", "remove"} {
+		t.Run(sub, func(t *testing.T) {
+			svc := &mockTypesService{batchErr: fmt.Errorf("lock acquisition failed")}
+			cmd := NewTypesCmd(svc)
+			cmd.SetArgs([]string{sub, "notes", "001/*"})
+			cmd.SetOut(new(bytes.Buffer))
+			cmd.SetErr(new(bytes.Buffer))
+
+			if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "lock acquisition failed") {
+				t.Errorf("error = %v, want the service error", err)
+			}
+		})
+	}
+}
+
+func TestSelectsMany(t *testing.T) {
+	for input, want := range map[string]bool{"001": false, "001/*": true, "type:notes": true, "nope!": false} {
+		if got := selectsMany(input); got != want {
+			t.Errorf("selectsMany(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
@@ -15,7 +15,7 @@ func TestBuildCommandTree_WithNilService(t *testing.T) {
 	}
 
 	// All subcommands should be registered
-	wantCommands := []string{"add", "check", "compact", "delete", "doctor", "init", "list", "move", "rename", "types"}
+	wantCommands := []string{"add", "check", "compact", "config", "copy", "delete", "diff", "doctor", "indent", "init", "join", "list", "merge", "merge-driver", "move", "outdent", "rename", "reorder", "snapshot", "split", "trash", "tui", "types"}
 	for _, name := range wantCommands {
 		found := false
 		for _, sub := range root.Commands() {
@@ -45,10 +45,24 @@ func TestBuildCommandTree_AllCommandsHandleNilService(t *testing.T) {
 		{[]string{"delete", "100"}, ErrNotInProject.Error()},
 		{[]string{"doctor"}, ErrNotInProject.Error()},
 		{[]string{"list"}, ErrNotInProject.Error()},
+		{[]string{"merge", "a", "b", "c"}, ErrNotInProject.Error()},
+		{[]string{"merge", "--resolve"}, ErrNotInProject.Error()},
+		{[]string{"diff"}, ErrNotInProject.Error()},
+		{[]string{"snapshot", "list"}, ErrNotInProject.Error()},
+		{[]string{"trash", "list"}, ErrNotInProject.Error()},
+		{[]string{"reorder", "--root", "SID001AABB"}, ErrNotInProject.Error()},
+		{[]string{"tui"}, ErrNotInProject.Error()},
 		{[]string{"move", "100", "--to", "200"}, ErrNotInProject.Error()},
+		{[]string{"indent", "100"}, ErrNotInProject.Error()},
+		{[]string{"copy", "100", "--to", "200"}, ErrNotInProject.Error()},
+		{[]string{"outdent", "100-100"}, ErrNotInProject.Error()},
+		{[]string{"split", "100", "--at-line", "5"}, ErrNotInProject.Error()},
+		{[]string{"join", "100", "200"}, ErrNotInProject.Error()},
 		{[]string{"rename", "100", "New"}, ErrNotInProject.Error()},
 		{[]string{"types", "list", "100"}, ErrNotInProject.Error()},
-		{[]string{"init", "--help"}, ""}, // init works without service
+		{[]string{"config", "list"}, ErrNotInProject.Error()},
+		{[]string{"init", "--help"}, ""},         // init works without service
+		{[]string{"merge-driver", "--help"}, ""}, // so does merge-driver
 	}
 	for _, tt := range commands {
 		t.Run(tt.args[0], func(t *testing.T) {
@@ -73,7 +87,7 @@ func TestBuildCommandTree_AllCommandsHandleNilService(t *testing.T) {
 func TestBuildCommandTree_SubcommandCount(t *testing.T) {
 	root := BuildCommandTree(nil, nil)
 
-	want := 10
+	want := 23
 	got := len(root.Commands())
 	if got != want {
 		t.Errorf("subcommands = %d, want %d", got, want)
@@ -88,7 +102,7 @@ func TestBuildCommandTree_WithService(t *testing.T) {
 		t.Fatal("expected root command, got nil")
 	}
 
-	want := 10
+	want := 23
 	got := len(root.Commands())
 	if got != want {
 		t.Errorf("subcommands = %d, want %d", got, want)
@@ -0,0 +1,369 @@
+// Package config reads and writes the project configuration file.
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/eykd/linemark-go/internal/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// file mirrors the on-disk YAML layout. Pointer and nil-able fields
+// distinguish keys that were omitted from keys set to their zero value.
+type file struct {
+	DocTypes  *docTypesSection  `yaml:"doc_types,omitempty"`
+	Templates *templatesSection `yaml:"templates,omitempty"`
+	Schemas   *schemasSection   `yaml:"schemas,omitempty"`
+	Lint      *lintSection      `yaml:"lint,omitempty"`
+	Numbering *numberingSection `yaml:"numbering,omitempty"`
+	SID       *sidSection       `yaml:"sid,omitempty"`
+	Compact   *compactSection   `yaml:"compact,omitempty"`
+}
+
+type docTypesSection struct {
+	Required []string                 `yaml:"required,omitempty,flow"`
+	Defaults []string                 `yaml:"defaults,omitempty,flow"`
+	Depths   map[int]*docTypeRuleFile `yaml:"depths,omitempty"`
+}
+
+type docTypeRuleFile struct {
+	Required []string `yaml:"required,omitempty,flow"`
+	Defaults []string `yaml:"defaults,omitempty,flow"`
+}
+
+type templatesSection struct {
+	DepthNames []string `yaml:"depth_names,omitempty,flow"`
+}
+
+type schemasSection struct {
+	Depths   map[int]map[string]*fieldRuleFile    `yaml:"depths,omitempty"`
+	DocTypes map[string]map[string]*fieldRuleFile `yaml:"doc_types,omitempty"`
+}
+
+type fieldRuleFile struct {
+	Required bool     `yaml:"required,omitempty"`
+	Allowed  []string `yaml:"allowed,omitempty,flow"`
+}
+
+type lintSection struct {
+	Rules         []string `yaml:"rules,omitempty,flow"`
+	MaxWords      *int     `yaml:"max_words,omitempty"`
+	BannedPhrases []string `yaml:"banned_phrases,omitempty"`
+}
+
+type numberingSection struct {
+	Tiers []int `yaml:"tiers,omitempty,flow"`
+	Width *int  `yaml:"width,omitempty"`
+}
+
+type sidSection struct {
+	Length *int `yaml:"length,omitempty"`
+}
+
+type compactSection struct {
+	WarningThreshold *int `yaml:"warning_threshold,omitempty"`
+}
+
+// Parse decodes YAML config data, overlaying it on domain.DefaultConfig.
+// Unknown keys are rejected. The result is not validated.
+func Parse(data []byte) (domain.Config, error) {
+	cfg := domain.DefaultConfig()
+
+	var f file
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return domain.Config{}, fmt.Errorf("%w: %v", domain.ErrInvalidConfig, err)
+	}
+
+	if f.DocTypes != nil {
+		if f.DocTypes.Required != nil {
+			cfg.RequiredDocTypes = f.DocTypes.Required
+		}
+		if f.DocTypes.Defaults != nil {
+			cfg.DefaultDocTypes = f.DocTypes.Defaults
+		}
+		for depth, rule := range f.DocTypes.Depths {
+			if rule == nil {
+				continue
+			}
+			if cfg.DepthDocTypes == nil {
+				cfg.DepthDocTypes = map[int]domain.DocTypeRule{}
+			}
+			cfg.DepthDocTypes[depth] = domain.DocTypeRule{Required: rule.Required, Defaults: rule.Defaults}
+		}
+	}
+	if f.Templates != nil {
+		cfg.DepthNames = f.Templates.DepthNames
+	}
+	if f.Schemas != nil {
+		cfg.DepthSchemas = schemasFromFile(f.Schemas.Depths)
+		cfg.DocTypeSchemas = schemasFromFile(f.Schemas.DocTypes)
+	}
+	if f.Lint != nil {
+		cfg.Lint.Rules = f.Lint.Rules
+		cfg.Lint.BannedPhrases = f.Lint.BannedPhrases
+		if f.Lint.MaxWords != nil {
+			cfg.Lint.MaxWords = *f.Lint.MaxWords
+		}
+	}
+	if f.Numbering != nil && f.Numbering.Tiers != nil {
+		cfg.Numbering.Tiers = f.Numbering.Tiers
+	}
+	if f.Numbering != nil && f.Numbering.Width != nil {
+		cfg.Numbering.Width = *f.Numbering.Width
+	}
+	if f.SID != nil && f.SID.Length != nil {
+		cfg.SIDLength = *f.SID.Length
+	}
+	if f.Compact != nil && f.Compact.WarningThreshold != nil {
+		cfg.CompactWarningThreshold = *f.Compact.WarningThreshold
+	}
+
+	return cfg, nil
+}
+
+// Marshal encodes the full config as YAML. The file layout holds only
+// strings, ints and maps of them, which always encode.
+func Marshal(cfg domain.Config) []byte {
+	docTypes := &docTypesSection{Required: cfg.RequiredDocTypes, Defaults: cfg.DefaultDocTypes}
+	width := cfg.Numbering.SegmentWidth()
+	for depth, rule := range cfg.DepthDocTypes {
+		if docTypes.Depths == nil {
+			docTypes.Depths = map[int]*docTypeRuleFile{}
+		}
+		docTypes.Depths[depth] = &docTypeRuleFile{Required: rule.Required, Defaults: rule.Defaults}
+	}
+	var templates *templatesSection
+	if len(cfg.DepthNames) > 0 {
+		templates = &templatesSection{DepthNames: cfg.DepthNames}
+	}
+	var schemas *schemasSection
+	if len(cfg.DepthSchemas) > 0 || len(cfg.DocTypeSchemas) > 0 {
+		schemas = &schemasSection{
+			Depths:   schemasToFile(cfg.DepthSchemas),
+			DocTypes: schemasToFile(cfg.DocTypeSchemas),
+		}
+	}
+	f := file{
+		DocTypes:  docTypes,
+		Templates: templates,
+		Schemas:   schemas,
+		Lint: &lintSection{
+			Rules:         cfg.Lint.Rules,
+			MaxWords:      &cfg.Lint.MaxWords,
+			BannedPhrases: cfg.Lint.BannedPhrases,
+		},
+		Numbering: &numberingSection{Tiers: cfg.Numbering.Tiers, Width: &width},
+		SID:       &sidSection{Length: &cfg.SIDLength},
+		Compact:   &compactSection{WarningThreshold: &cfg.CompactWarningThreshold},
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	_ = enc.Encode(&f)
+	return buf.Bytes()
+}
+
+// Update returns the config file data with one dotted key set to its value
+// in cfg, keeping every other key, and any comments, as written. A key whose
+// value encodes to nothing is removed along with any sections it empties, so
+// it falls back to its default. Keys the file does not name stay unwritten,
+// so they follow the built-in defaults.
+func Update(data []byte, cfg domain.Config, key string) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidConfig, err)
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%w: %s is not a mapping", domain.ErrInvalidConfig, domain.ConfigPath)
+	}
+
+	var full yaml.Node
+	if err := yaml.Unmarshal(Marshal(cfg), &full); err != nil {
+		return nil, err
+	}
+	path := strings.Split(key, ".")
+	if value := lookupNode(full.Content[0], path); value != nil {
+		setNode(root, path, value)
+	} else {
+		removeNode(root, path)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// lookupNode returns the value at a key path in a mapping, or nil.
+func lookupNode(mapping *yaml.Node, path []string) *yaml.Node {
+	for _, name := range path {
+		if mapping == nil || mapping.Kind != yaml.MappingNode {
+			return nil
+		}
+		i := mappingIndex(mapping, name)
+		if i < 0 {
+			return nil
+		}
+		mapping = mapping.Content[i+1]
+	}
+	return mapping
+}
+
+// setNode sets the value at a key path in a mapping, creating or replacing
+// the mappings along it.
+func setNode(mapping *yaml.Node, path []string, value *yaml.Node) {
+	for _, name := range path[:len(path)-1] {
+		i := mappingIndex(mapping, name)
+		if i < 0 {
+			mapping.Content = append(mapping.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Value: name}, &yaml.Node{Kind: yaml.MappingNode})
+			i = len(mapping.Content) - 2
+		}
+		if mapping.Content[i+1].Kind != yaml.MappingNode {
+			mapping.Content[i+1] = &yaml.Node{Kind: yaml.MappingNode}
+		}
+		mapping = mapping.Content[i+1]
+	}
+	last := path[len(path)-1]
+	if i := mappingIndex(mapping, last); i >= 0 {
+		mapping.Content[i+1] = value
+		return
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: last}, value)
+}
+
+// removeNode deletes the value at a key path in a mapping, and then any
+// mapping along the path it leaves empty.
+func removeNode(mapping *yaml.Node, path []string) {
+	i := mappingIndex(mapping, path[0])
+	if i < 0 {
+		return
+	}
+	if child := mapping.Content[i+1]; len(path) > 1 {
+		if child.Kind != yaml.MappingNode {
+			return
+		}
+		removeNode(child, path[1:])
+		if len(child.Content) > 0 {
+			return
+		}
+	}
+	mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+}
+
+// mappingIndex returns the index of a key in a mapping node, or -1.
+func mappingIndex(mapping *yaml.Node, name string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// schemasFromFile converts decoded schema sections, skipping fields with no rule body.
+func schemasFromFile[K comparable](in map[K]map[string]*fieldRuleFile) map[K]domain.Schema {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make(map[K]domain.Schema, len(in))
+	for target, fields := range in {
+		schema := domain.Schema{}
+		for name, rule := range fields {
+			if rule != nil {
+				schema[name] = domain.FieldRule{Required: rule.Required, Allowed: rule.Allowed}
+			}
+		}
+		out[target] = schema
+	}
+	return out
+}
+
+// schemasToFile converts schemas to their on-disk form.
+func schemasToFile[K comparable](in map[K]domain.Schema) map[K]map[string]*fieldRuleFile {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make(map[K]map[string]*fieldRuleFile, len(in))
+	for target, schema := range in {
+		fields := make(map[string]*fieldRuleFile, len(schema))
+		for name, rule := range schema {
+			fields[name] = &fieldRuleFile{Required: rule.Required, Allowed: rule.Allowed}
+		}
+		out[target] = fields
+	}
+	return out
+}
+
+// Store implements outline.ConfigStore using the project config file.
+type Store struct {
+	Root string
+}
+
+func (s *Store) path() string {
+	return filepath.Join(s.Root, domain.ConfigPath)
+}
+
+// LoadImpl reads, parses and validates the config file. A missing file
+// yields domain.DefaultConfig.
+func (s *Store) LoadImpl(_ context.Context) (domain.Config, error) {
+	data, err := os.ReadFile(s.path())
+	if os.IsNotExist(err) {
+		return domain.DefaultConfig(), nil
+	}
+	if err != nil {
+		return domain.Config{}, fmt.Errorf("reading %s: %w", domain.ConfigPath, err)
+	}
+	cfg, err := Parse(data)
+	if err != nil {
+		return domain.Config{}, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return domain.Config{}, err
+	}
+	return cfg, nil
+}
+
+// Load delegates to LoadImpl.
+func (s *Store) Load(ctx context.Context) (domain.Config, error) {
+	return s.LoadImpl(ctx)
+}
+
+// SaveConfigImpl writes one key of cfg to the config file, creating the
+// file and .linemark/ if needed, and leaves the file's other keys as they are.
+func (s *Store) SaveConfigImpl(_ context.Context, cfg domain.Config, key string) error {
+	path := s.path()
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading %s: %w", domain.ConfigPath, err)
+	}
+	data, err := Update(existing, cfg, key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating config directory: %w", err)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// SaveConfig delegates to SaveConfigImpl.
+func (s *Store) SaveConfig(ctx context.Context, cfg domain.Config, key string) error {
+	return s.SaveConfigImpl(ctx, cfg, key)
+}
@@ -0,0 +1,344 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		check func(t *testing.T, cfg domain.Config)
+	}{
+		{
+			name:  "empty input yields defaults",
+			input: "",
+			check: func(t *testing.T, cfg domain.Config) {
+				if !slices.Equal(cfg.RequiredDocTypes, []string{"draft", "notes"}) {
+					t.Errorf("RequiredDocTypes = %v, want defaults", cfg.RequiredDocTypes)
+				}
+			},
+		},
+		{
+			name:  "overrides required doc types",
+			input: "doc_types:\n  required: [draft, research]\n",
+			check: func(t *testing.T, cfg domain.Config) {
+				if !slices.Equal(cfg.RequiredDocTypes, []string{"draft", "research"}) {
+					t.Errorf("RequiredDocTypes = %v", cfg.RequiredDocTypes)
+				}
+			},
+		},
+		{
+			name:  "reads defaults and per-depth rules",
+			input: "doc_types:\n  defaults: [draft]\n  depths:\n    3:\n      required: [beats]\n      defaults: [beats]\n    4:\n",
+			check: func(t *testing.T, cfg domain.Config) {
+				if !slices.Equal(cfg.DefaultDocTypes, []string{"draft"}) {
+					t.Errorf("DefaultDocTypes = %v", cfg.DefaultDocTypes)
+				}
+				if !slices.Equal(cfg.RequiredDocTypesAt(3), []string{"draft", "notes", "beats"}) {
+					t.Errorf("RequiredDocTypesAt(3) = %v", cfg.RequiredDocTypesAt(3))
+				}
+				if _, ok := cfg.DepthDocTypes[4]; ok {
+					t.Error("empty depth entry should be ignored")
+				}
+			},
+		},
+		{
+			name:  "reads schemas",
+			input: "schemas:\n  depths:\n    3:\n      pov: {required: true}\n      status:\n        required: true\n        allowed: [draft, final]\n      mood:\n  doc_types:\n    notes:\n      tags: {required: true}\n",
+			check: func(t *testing.T, cfg domain.Config) {
+				want := domain.Schema{
+					"pov":    {Required: true},
+					"status": {Required: true, Allowed: []string{"draft", "final"}},
+				}
+				if !reflect.DeepEqual(cfg.DepthSchemas[3], want) {
+					t.Errorf("DepthSchemas[3] = %+v, want %+v", cfg.DepthSchemas[3], want)
+				}
+				if !cfg.DocTypeSchemas["notes"]["tags"].Required {
+					t.Errorf("DocTypeSchemas = %+v", cfg.DocTypeSchemas)
+				}
+			},
+		},
+		{
+			name:  "reads lint settings",
+			input: "lint:\n  rules: [doubled-words, todo]\n  banned_phrases:\n    - very unique\n",
+			check: func(t *testing.T, cfg domain.Config) {
+				if !slices.Equal(cfg.Lint.Rules, []string{"doubled-words", "todo"}) {
+					t.Errorf("Lint.Rules = %v", cfg.Lint.Rules)
+				}
+				if cfg.Lint.MaxWords != domain.DefaultLintMaxWords {
+					t.Errorf("Lint.MaxWords = %d, want default", cfg.Lint.MaxWords)
+				}
+				if !slices.Equal(cfg.Lint.BannedPhrases, []string{"very unique"}) {
+					t.Errorf("Lint.BannedPhrases = %v", cfg.Lint.BannedPhrases)
+				}
+			},
+		},
+		{
+			name:  "overrides numbering tiers",
+			input: "numbering:\n  tiers: [50, 5, 1]\n",
+			check: func(t *testing.T, cfg domain.Config) {
+				if !slices.Equal(cfg.Numbering.Tiers, []int{50, 5, 1}) {
+					t.Errorf("Tiers = %v", cfg.Numbering.Tiers)
+				}
+			},
+		},
+		{
+			name:  "overrides numbering width",
+			input: "numbering:\n  width: 4\n",
+			check: func(t *testing.T, cfg domain.Config) {
+				if cfg.Numbering.Width != 4 {
+					t.Errorf("Width = %d, want 4", cfg.Numbering.Width)
+				}
+				if !slices.Equal(cfg.Numbering.Tiers, []int{100, 10, 1}) {
+					t.Errorf("Tiers = %v, want default", cfg.Numbering.Tiers)
+				}
+			},
+		},
+		{
+			name:  "overrides sid length and compact threshold",
+			input: "sid:\n  length: 10\ncompact:\n  warning_threshold: 0\n",
+			check: func(t *testing.T, cfg domain.Config) {
+				if cfg.SIDLength != 10 {
+					t.Errorf("SIDLength = %d, want 10", cfg.SIDLength)
+				}
+				if cfg.CompactWarningThreshold != 0 {
+					t.Errorf("CompactWarningThreshold = %d, want 0", cfg.CompactWarningThreshold)
+				}
+			},
+		},
+		{
+			name:  "omitted sections keep defaults",
+			input: "sid:\n  length: 8\n",
+			check: func(t *testing.T, cfg domain.Config) {
+				if cfg.CompactWarningThreshold != domain.DefaultCompactWarningThreshold {
+					t.Errorf("CompactWarningThreshold = %d, want default", cfg.CompactWarningThreshold)
+				}
+				if !slices.Equal(cfg.Numbering.Tiers, []int{100, 10, 1}) {
+					t.Errorf("Tiers = %v, want default", cfg.Numbering.Tiers)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := Parse([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("Parse() error: %v", err)
+			}
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"malformed yaml", "doc_types: [unclosed\n"},
+		{"unknown key", "colour: blue\n"},
+		{"wrong value type", "sid:\n  length: twelve\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.input))
+			if !errors.Is(err, domain.ErrInvalidConfig) {
+				t.Errorf("Parse() error = %v, want ErrInvalidConfig", err)
+			}
+		})
+	}
+}
+
+func TestMarshal_RoundTrip(t *testing.T) {
+	cfg := domain.DefaultConfig()
+	cfg.RequiredDocTypes = []string{"draft", "beats"}
+	cfg.DefaultDocTypes = []string{"draft"}
+	cfg.DepthDocTypes = map[int]domain.DocTypeRule{2: {Required: []string{"summary"}}}
+	cfg.DepthNames = []string{"part", "chapter"}
+	cfg.DepthSchemas = map[int]domain.Schema{3: {"status": {Required: true, Allowed: []string{"draft", "final"}}}}
+	cfg.DocTypeSchemas = map[string]domain.Schema{"notes": {"tags": {Required: true}}}
+	cfg.Lint = domain.LintConfig{Rules: []string{"todo"}, MaxWords: 3000, BannedPhrases: []string{"very unique"}}
+	cfg.Numbering = domain.Numbering{Tiers: []int{20, 1}, Width: 4}
+	cfg.SIDLength = 9
+	cfg.CompactWarningThreshold = 7
+
+	data := Marshal(cfg)
+	got, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error: %v\n%s", err, data)
+	}
+
+	for _, key := range cfg.Keys() {
+		want, _ := cfg.Get(key)
+		have, _ := got.Get(key)
+		if have != want {
+			t.Errorf("%s = %q after round trip, want %q", key, have, want)
+		}
+	}
+}
+
+func TestMarshal_OmitsEmptySections(t *testing.T) {
+	data := Marshal(domain.DefaultConfig())
+
+	for _, section := range []string{"templates", "schemas"} {
+		if strings.Contains(string(data), section) {
+			t.Errorf("default config should not write a %s section:\n%s", section, data)
+		}
+	}
+}
+
+func TestMarshal_DepthSchemasOnly(t *testing.T) {
+	cfg := domain.DefaultConfig()
+	cfg.DepthSchemas = map[int]domain.Schema{2: {"pov": {Required: true}}}
+
+	got, err := Parse(Marshal(cfg))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+
+	if !got.DepthSchemas[2]["pov"].Required || got.DocTypeSchemas != nil {
+		t.Errorf("round trip = %+v / %+v", got.DepthSchemas, got.DocTypeSchemas)
+	}
+}
+
+func TestStore_LoadMissingFileYieldsDefaults(t *testing.T) {
+	store := &Store{Root: t.TempDir()}
+
+	cfg, err := store.Load(context.Background())
+
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.SIDLength != domain.DefaultSIDLength {
+		t.Errorf("SIDLength = %d, want default", cfg.SIDLength)
+	}
+}
+
+func TestStore_SaveThenLoad(t *testing.T) {
+	root := t.TempDir()
+	store := &Store{Root: root}
+	cfg := domain.DefaultConfig()
+	cfg.SIDLength = 8
+
+	if err := store.SaveConfig(context.Background(), cfg, "sid.length"); err != nil {
+		t.Fatalf("SaveConfig() error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, domain.ConfigPath)); err != nil {
+		t.Fatalf("config file not written: %v", err)
+	}
+
+	got, err := store.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if got.SIDLength != 8 {
+		t.Errorf("SIDLength = %d, want 8", got.SIDLength)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	cfg := domain.DefaultConfig()
+	cfg.SIDLength = 9
+	cfg.Lint.Rules = nil
+	cfg.DepthSchemas = map[int]domain.Schema{3: {"pov": {Required: true}}}
+
+	tests := []struct {
+		name     string
+		existing string
+		key      string
+		want     string
+	}{
+		{
+			name: "writes only the key to a new file",
+			key:  "sid.length",
+			want: "sid:\n  length: 9\n",
+		},
+		{
+			name:     "keeps other keys and comments",
+			existing: "# project settings\nnumbering:\n  width: 4 # wide\nsid:\n  length: 12\n",
+			key:      "sid.length",
+			want:     "# project settings\nnumbering:\n  width: 4 # wide\nsid:\n  length: 9\n",
+		},
+		{
+			name:     "adds nested keys",
+			existing: "sid:\n  length: 12\n",
+			key:      "schemas.depths.3.pov.required",
+			want:     "sid:\n  length: 12\nschemas:\n  depths:\n    3:\n      pov:\n        required: true\n",
+		},
+		{
+			name:     "removes a cleared key and the sections it empties",
+			existing: "lint:\n  rules: [todo]\nsid:\n  length: 12\n",
+			key:      "lint.rules",
+			want:     "sid:\n  length: 12\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Update([]byte(tt.existing), cfg, tt.key)
+
+			if err != nil {
+				t.Fatalf("Update() error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Update() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdate_Errors(t *testing.T) {
+	for _, existing := range []string{"sid: [unclosed\n", "- not\n- a mapping\n"} {
+		if _, err := Update([]byte(existing), domain.DefaultConfig(), "sid.length"); !errors.Is(err, domain.ErrInvalidConfig) {
+			t.Errorf("Update(%q) error = %v, want ErrInvalidConfig", existing, err)
+		}
+	}
+}
+
+func TestStore_SaveLeavesDefaultsUnwritten(t *testing.T) {
+	root := t.TempDir()
+	store := &Store{Root: root}
+	cfg := domain.DefaultConfig()
+	cfg.SIDLength = 8
+
+	if err := store.SaveConfig(context.Background(), cfg, "sid.length"); err != nil {
+		t.Fatalf("SaveConfig() error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, domain.ConfigPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "sid:\n  length: 8\n" {
+		t.Errorf("config file = %q, want only sid.length", data)
+	}
+}
+
+func TestStore_LoadRejectsInvalidValues(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, domain.ConfigPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("sid:\n  length: 3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := (&Store{Root: root}).Load(context.Background())
+
+	if !errors.Is(err, domain.ErrInvalidConfig) {
+		t.Errorf("Load() error = %v, want ErrInvalidConfig", err)
+	}
+}
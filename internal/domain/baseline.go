@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// BaselinePath is the check baseline file, relative to the project root.
+const BaselinePath = ".linemark/baseline"
+
+// SuppressKey is the frontmatter key listing rule IDs (lint rules or finding
+// types) that check should not report for that file.
+const SuppressKey = "lmk_ignore"
+
+// baselineHeader opens every written baseline file.
+const baselineHeader = "# lmk check baseline: known findings, one fingerprint per line\n"
+
+// Fingerprint identifies a finding by rule, SID and doc type, so that moving
+// or renaming a node does not change it. The SID and doc type come from the
+// finding or else its path; findings not tied to a node use their path.
+func (f Finding) Fingerprint() string {
+	sid, docType := f.SID, f.DocType
+	if sid == "" {
+		if pf, err := ParseFilename(f.Path); err == nil {
+			sid, docType = pf.SID, pf.DocType
+		}
+	}
+	if sid == "" {
+		return strings.TrimSpace(f.RuleID() + " " + f.Path)
+	}
+	return strings.TrimSpace(f.RuleID() + " " + sid + " " + docType)
+}
+
+// Baseline counts known findings by fingerprint.
+type Baseline map[string]int
+
+// NewBaseline records the fingerprints of findings.
+func NewBaseline(findings []Finding) Baseline {
+	b := Baseline{}
+	for _, f := range findings {
+		b[f.Fingerprint()]++
+	}
+	return b
+}
+
+// ParseBaseline reads a baseline file. Each line holds one fingerprint,
+// repeated once per occurrence; blank lines and # comments are ignored.
+func ParseBaseline(data string) Baseline {
+	b := Baseline{}
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		b[line]++
+	}
+	return b
+}
+
+// String renders the baseline in file format, sorted by fingerprint.
+func (b Baseline) String() string {
+	var sb strings.Builder
+	sb.WriteString(baselineHeader)
+	for _, fp := range slices.Sorted(maps.Keys(b)) {
+		for range b[fp] {
+			sb.WriteString(fp + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// Filter returns the findings the baseline does not cover and how many it
+// did. Each baseline occurrence covers one matching finding.
+func (b Baseline) Filter(findings []Finding) ([]Finding, int) {
+	remaining := maps.Clone(b)
+	var kept []Finding
+	covered := 0
+	for _, f := range findings {
+		fp := f.Fingerprint()
+		if remaining[fp] > 0 {
+			remaining[fp]--
+			covered++
+			continue
+		}
+		kept = append(kept, f)
+	}
+	return kept, covered
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"testing"
+)
+
+func TestFinding_Fingerprint(t *testing.T) {
+	tests := []struct {
+		name    string
+		finding Finding
+		want    string
+	}{
+		{"from path", Finding{Type: FindingSlugDrift, Path: "100-200_SID001AABB_draft_old-title.md"}, "slug_drift SID001AABB draft"},
+		{"moved node keeps fingerprint", Finding{Type: FindingSlugDrift, Path: "300_SID001AABB_draft_new-title.md"}, "slug_drift SID001AABB draft"},
+		{"lint rule", Finding{Type: FindingLint, Rule: "todo", Path: "100_SID001AABB_draft_a.md"}, "todo SID001AABB draft"},
+		{"explicit SID and doc type", Finding{Type: FindingMissingDocType, SID: "SID001AABB", DocType: "notes"}, "missing_doc_type SID001AABB notes"},
+		{"SID only", Finding{Type: FindingDuplicateSID, SID: "SID001AABB"}, "duplicate_sid SID001AABB"},
+		{"path fallback", Finding{Type: FindingInvalidConfig, Path: ConfigPath}, "invalid_config .linemark/config.yaml"},
+		{"no path", Finding{Type: FindingInvalidConfig}, "invalid_config"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.finding.Fingerprint(); got != tt.want {
+				t.Errorf("Fingerprint() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBaseline_RoundTrip(t *testing.T) {
+	findings := []Finding{
+		{Type: FindingLint, Rule: "todo", Path: "100_SID001AABB_draft_a.md"},
+		{Type: FindingLint, Rule: "todo", Path: "100_SID001AABB_draft_a.md"},
+		{Type: FindingDuplicateSID, SID: "SID002AABB"},
+	}
+
+	text := NewBaseline(findings).String()
+
+	want := baselineHeader + "duplicate_sid SID002AABB\ntodo SID001AABB draft\ntodo SID001AABB draft\n"
+	if text != want {
+		t.Errorf("String() = %q, want %q", text, want)
+	}
+	parsed := ParseBaseline("\n" + text + "  # trailing comment\n")
+	if len(parsed) != 2 || parsed["todo SID001AABB draft"] != 2 || parsed["duplicate_sid SID002AABB"] != 1 {
+		t.Errorf("ParseBaseline() = %v", parsed)
+	}
+}
+
+func TestBaseline_Filter(t *testing.T) {
+	baseline := Baseline{"todo SID001AABB draft": 1, "slug_drift SID009AABB draft": 1}
+	findings := []Finding{
+		{Type: FindingLint, Rule: "todo", Path: "100_SID001AABB_draft_a.md", Line: 3},
+		{Type: FindingLint, Rule: "todo", Path: "100_SID001AABB_draft_a.md", Line: 8},
+		{Type: FindingSlugDrift, Path: "200_SID002AABB_draft_b.md"},
+	}
+
+	kept, covered := baseline.Filter(findings)
+
+	if covered != 1 {
+		t.Errorf("covered = %d, want 1", covered)
+	}
+	if len(kept) != 2 || kept[0].Line != 8 || kept[1].Type != FindingSlugDrift {
+		t.Errorf("kept = %+v, want second todo and slug drift", kept)
+	}
+	if baseline["todo SID001AABB draft"] != 1 {
+		t.Error("Filter must not modify the baseline")
+	}
+}
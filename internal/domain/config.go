@@ -0,0 +1,446 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ConfigPath is the project configuration file location relative to the project root.
+const ConfigPath = ".linemark/config.yaml"
+
+// DefaultSIDLength is the number of characters in a newly generated SID.
+const DefaultSIDLength = 12
+
+// DefaultLintMaxWords is the draft length above which the max-length lint rule reports.
+const DefaultLintMaxWords = 10000
+
+// DefaultCompactWarningThreshold is the number of affected files above which
+// compact warns the user to review the plan first.
+const DefaultCompactWarningThreshold = 50
+
+const (
+	minSIDLength = 8
+	maxSIDLength = 12
+)
+
+// ruleIDRegex matches lint rule IDs such as "doubled-words".
+var ruleIDRegex = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// ErrInvalidConfig is returned when a configuration value fails validation.
+var ErrInvalidConfig = errors.New("invalid config")
+
+// ErrUnknownConfigKey is returned when getting or setting an unrecognised key.
+var ErrUnknownConfigKey = errors.New("unknown config key")
+
+// DocTypeRule lists doc types that apply in addition to the project-wide
+// lists for nodes at a particular depth.
+type DocTypeRule struct {
+	Required []string
+	Defaults []string
+}
+
+// LintConfig selects the prose lint rules run by check and their settings.
+type LintConfig struct {
+	Rules         []string
+	MaxWords      int
+	BannedPhrases []string
+}
+
+// Config holds project-level settings that override the built-in defaults.
+type Config struct {
+	RequiredDocTypes        []string
+	DefaultDocTypes         []string
+	DepthDocTypes           map[int]DocTypeRule
+	DepthNames              []string
+	DepthSchemas            map[int]Schema
+	DocTypeSchemas          map[string]Schema
+	Lint                    LintConfig
+	Numbering               Numbering
+	SIDLength               int
+	CompactWarningThreshold int
+}
+
+// DefaultConfig returns the settings used when a project has no config file.
+func DefaultConfig() Config {
+	return Config{
+		RequiredDocTypes:        []string{DocTypeDraft, DocTypeNotes},
+		DefaultDocTypes:         []string{DocTypeDraft, DocTypeNotes},
+		Lint:                    LintConfig{MaxWords: DefaultLintMaxWords},
+		Numbering:               Numbering{Tiers: append([]int(nil), DefaultNumbering.Tiers...), Width: DefaultSegmentWidth},
+		SIDLength:               DefaultSIDLength,
+		CompactWarningThreshold: DefaultCompactWarningThreshold,
+	}
+}
+
+// RequiredDocTypesAt returns the doc types a node at the given depth must have:
+// the project-wide list followed by any extra types for that depth.
+func (c Config) RequiredDocTypesAt(depth int) []string {
+	return unionDocTypes(c.RequiredDocTypes, c.DepthDocTypes[depth].Required)
+}
+
+// DefaultDocTypesAt returns the doc types created for a new node at the given depth.
+func (c Config) DefaultDocTypesAt(depth int) []string {
+	return unionDocTypes(c.DefaultDocTypes, c.DepthDocTypes[depth].Defaults)
+}
+
+// ConfiguredDocTypes returns every doc type the config names, project-wide
+// or at any depth.
+func (c Config) ConfiguredDocTypes() []string {
+	lists := [][]string{c.RequiredDocTypes, c.DefaultDocTypes}
+	for _, depth := range c.sortedDepths() {
+		lists = append(lists, c.DepthDocTypes[depth].Required, c.DepthDocTypes[depth].Defaults)
+	}
+	return unionDocTypes(lists...)
+}
+
+// unionDocTypes concatenates lists, keeping the first occurrence of each type.
+func unionDocTypes(lists ...[]string) []string {
+	var result []string
+	for _, list := range lists {
+		for _, dt := range list {
+			if !slices.Contains(result, dt) {
+				result = append(result, dt)
+			}
+		}
+	}
+	return result
+}
+
+// sortedDepths returns the depths that have doc type rules, in ascending order.
+func (c Config) sortedDepths() []int {
+	depths := make([]int, 0, len(c.DepthDocTypes))
+	for d := range c.DepthDocTypes {
+		depths = append(depths, d)
+	}
+	slices.Sort(depths)
+	return depths
+}
+
+// Validate checks every setting and returns all problems joined into one error.
+// Each joined error wraps ErrInvalidConfig.
+func (c Config) Validate() error {
+	var errs []error
+	invalid := func(key, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%w: %s: %s", ErrInvalidConfig, key, fmt.Sprintf(format, args...)))
+	}
+
+	checkDocTypes := func(key string, docTypes []string) {
+		for _, dt := range docTypes {
+			if err := ValidateDocType(dt); err != nil {
+				invalid(key, "%q is not a valid doc type", dt)
+			}
+		}
+	}
+	checkDocTypes("doc_types.required", c.RequiredDocTypes)
+	checkDocTypes("doc_types.defaults", c.DefaultDocTypes)
+	for _, depth := range c.sortedDepths() {
+		prefix := fmt.Sprintf("doc_types.depths.%d", depth)
+		if depth < 1 {
+			invalid(prefix, "depth must be at least 1")
+		}
+		checkDocTypes(prefix+".required", c.DepthDocTypes[depth].Required)
+		checkDocTypes(prefix+".defaults", c.DepthDocTypes[depth].Defaults)
+	}
+
+	for _, name := range c.DepthNames {
+		if err := ValidateDocType(name); err != nil {
+			invalid("templates.depth_names", "%q is not a valid depth name", name)
+		}
+	}
+
+	c.validateSchemas(invalid)
+
+	for _, id := range c.Lint.Rules {
+		if !ruleIDRegex.MatchString(id) {
+			invalid("lint.rules", "%q is not a valid rule ID", id)
+		}
+	}
+	if c.Lint.MaxWords < 1 {
+		invalid("lint.max_words", "must be positive")
+	}
+	for _, phrase := range c.Lint.BannedPhrases {
+		if strings.TrimSpace(phrase) == "" {
+			invalid("lint.banned_phrases", "phrases must not be empty")
+			break
+		}
+	}
+
+	if w := c.Numbering.Width; w != 0 && (w < DefaultSegmentWidth || w > MaxSegmentWidth) {
+		invalid("numbering.width", "must be between %d and %d", DefaultSegmentWidth, MaxSegmentWidth)
+	}
+	tiers := c.Numbering.Tiers
+	switch {
+	case len(tiers) == 0:
+		invalid("numbering.tiers", "must not be empty")
+	case tiers[0] > c.Numbering.MaxSibling():
+		invalid("numbering.tiers", "first tier %d exceeds %d", tiers[0], c.Numbering.MaxSibling())
+	case tiers[len(tiers)-1] != 1:
+		invalid("numbering.tiers", "last tier must be 1")
+	default:
+		for i := 1; i < len(tiers); i++ {
+			if tiers[i] >= tiers[i-1] {
+				invalid("numbering.tiers", "tiers must be strictly descending")
+				break
+			}
+		}
+	}
+
+	if c.SIDLength < minSIDLength || c.SIDLength > maxSIDLength {
+		invalid("sid.length", "must be between %d and %d", minSIDLength, maxSIDLength)
+	}
+
+	if c.CompactWarningThreshold < 0 {
+		invalid("compact.warning_threshold", "must not be negative")
+	}
+
+	return errors.Join(errs...)
+}
+
+// configKey describes how a dotted config key maps onto Config.
+type configKey struct {
+	name string
+	get  func(Config) string
+	set  func(*Config, string) error
+}
+
+var configKeys = []configKey{
+	{
+		name: "doc_types.required",
+		get:  func(c Config) string { return strings.Join(c.RequiredDocTypes, ",") },
+		set: func(c *Config, v string) error {
+			c.RequiredDocTypes = splitList(v)
+			return nil
+		},
+	},
+	{
+		name: "doc_types.defaults",
+		get:  func(c Config) string { return strings.Join(c.DefaultDocTypes, ",") },
+		set: func(c *Config, v string) error {
+			c.DefaultDocTypes = splitList(v)
+			return nil
+		},
+	},
+	{
+		name: "templates.depth_names",
+		get:  func(c Config) string { return strings.Join(c.DepthNames, ",") },
+		set: func(c *Config, v string) error {
+			c.DepthNames = splitList(v)
+			return nil
+		},
+	},
+	{
+		name: "lint.rules",
+		get:  func(c Config) string { return strings.Join(c.Lint.Rules, ",") },
+		set: func(c *Config, v string) error {
+			c.Lint.Rules = splitList(v)
+			return nil
+		},
+	},
+	{
+		name: "lint.max_words",
+		get:  func(c Config) string { return strconv.Itoa(c.Lint.MaxWords) },
+		set: func(c *Config, v string) error {
+			n, err := strconv.Atoi(strings.TrimSpace(v))
+			if err != nil {
+				return fmt.Errorf("%w: lint.max_words: %q is not an integer", ErrInvalidConfig, v)
+			}
+			c.Lint.MaxWords = n
+			return nil
+		},
+	},
+	{
+		name: "lint.banned_phrases",
+		get:  func(c Config) string { return strings.Join(c.Lint.BannedPhrases, ",") },
+		set: func(c *Config, v string) error {
+			c.Lint.BannedPhrases = splitList(v)
+			return nil
+		},
+	},
+	{
+		name: "numbering.tiers",
+		get:  func(c Config) string { return joinInts(c.Numbering.Tiers) },
+		set: func(c *Config, v string) error {
+			tiers, err := parseInts(v)
+			if err != nil {
+				return err
+			}
+			c.Numbering.Tiers = tiers
+			return nil
+		},
+	},
+	{
+		name: "numbering.width",
+		get:  func(c Config) string { return strconv.Itoa(c.Numbering.SegmentWidth()) },
+		set: func(c *Config, v string) error {
+			n, err := strconv.Atoi(strings.TrimSpace(v))
+			if err != nil {
+				return fmt.Errorf("%w: numbering.width: %q is not an integer", ErrInvalidConfig, v)
+			}
+			c.Numbering.Width = n
+			return nil
+		},
+	},
+	{
+		name: "sid.length",
+		get:  func(c Config) string { return strconv.Itoa(c.SIDLength) },
+		set: func(c *Config, v string) error {
+			n, err := strconv.Atoi(strings.TrimSpace(v))
+			if err != nil {
+				return fmt.Errorf("%w: sid.length: %q is not an integer", ErrInvalidConfig, v)
+			}
+			c.SIDLength = n
+			return nil
+		},
+	},
+	{
+		name: "compact.warning_threshold",
+		get:  func(c Config) string { return strconv.Itoa(c.CompactWarningThreshold) },
+		set: func(c *Config, v string) error {
+			n, err := strconv.Atoi(strings.TrimSpace(v))
+			if err != nil {
+				return fmt.Errorf("%w: compact.warning_threshold: %q is not an integer", ErrInvalidConfig, v)
+			}
+			c.CompactWarningThreshold = n
+			return nil
+		},
+	},
+}
+
+// depthKeyPrefix introduces per-depth doc type keys such as
+// "doc_types.depths.3.required".
+const depthKeyPrefix = "doc_types.depths."
+
+// ConfigKeys returns the fixed dotted config keys in display order.
+// Per-depth keys are reported by Config.Keys.
+func ConfigKeys() []string {
+	keys := make([]string, len(configKeys))
+	for i, k := range configKeys {
+		keys[i] = k.name
+	}
+	return keys
+}
+
+// Keys returns the fixed config keys followed by a required/defaults pair
+// for every depth that has doc type rules, then a required/allowed pair for
+// every schema field.
+func (c Config) Keys() []string {
+	keys := ConfigKeys()
+	for _, depth := range c.sortedDepths() {
+		prefix := fmt.Sprintf("%s%d.", depthKeyPrefix, depth)
+		keys = append(keys, prefix+"required", prefix+"defaults")
+	}
+	return append(keys, c.schemaKeys()...)
+}
+
+func lookupConfigKey(key string) (configKey, error) {
+	for _, k := range configKeys {
+		if k.name == key {
+			return k, nil
+		}
+	}
+	if k, ok := depthConfigKey(key); ok {
+		return k, nil
+	}
+	if k, ok := schemaConfigKey(key); ok {
+		return k, nil
+	}
+	return configKey{}, fmt.Errorf("%w: %q", ErrUnknownConfigKey, key)
+}
+
+// depthConfigKey builds accessors for a "doc_types.depths.<n>.<list>" key.
+func depthConfigKey(key string) (configKey, bool) {
+	rest, ok := strings.CutPrefix(key, depthKeyPrefix)
+	if !ok {
+		return configKey{}, false
+	}
+	depthStr, list, ok := strings.Cut(rest, ".")
+	depth, err := strconv.Atoi(depthStr)
+	if !ok || err != nil || (list != "required" && list != "defaults") {
+		return configKey{}, false
+	}
+
+	field := func(r *DocTypeRule) *[]string {
+		if list == "required" {
+			return &r.Required
+		}
+		return &r.Defaults
+	}
+
+	return configKey{
+		name: key,
+		get: func(c Config) string {
+			rule := c.DepthDocTypes[depth]
+			return strings.Join(*field(&rule), ",")
+		},
+		set: func(c *Config, v string) error {
+			rules := make(map[int]DocTypeRule, len(c.DepthDocTypes)+1)
+			for d, r := range c.DepthDocTypes {
+				rules[d] = r
+			}
+			rule := rules[depth]
+			*field(&rule) = splitList(v)
+			if len(rule.Required) == 0 && len(rule.Defaults) == 0 {
+				delete(rules, depth)
+			} else {
+				rules[depth] = rule
+			}
+			c.DepthDocTypes = rules
+			return nil
+		},
+	}, true
+}
+
+// Get returns the string form of the value stored under the dotted key.
+// List values are comma-separated.
+func (c Config) Get(key string) (string, error) {
+	k, err := lookupConfigKey(key)
+	if err != nil {
+		return "", err
+	}
+	return k.get(c), nil
+}
+
+// Set parses value and stores it under the dotted key. It does not validate
+// the resulting config; call Validate afterwards.
+func (c *Config) Set(key, value string) error {
+	k, err := lookupConfigKey(key)
+	if err != nil {
+		return err
+	}
+	return k.set(c, value)
+}
+
+// splitList splits a comma-separated list, dropping empty items.
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func joinInts(nums []int) string {
+	parts := make([]string, len(nums))
+	for i, n := range nums {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, ",")
+}
+
+func parseInts(v string) ([]int, error) {
+	var nums []int
+	for _, item := range splitList(v) {
+		n, err := strconv.Atoi(item)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q is not an integer", ErrInvalidConfig, item)
+		}
+		nums = append(nums, n)
+	}
+	return nums, nil
+}
@@ -0,0 +1,275 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDefaultConfig_IsValid(t *testing.T) {
+	cfg := DefaultConfig()
+
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("default config should be valid, got %v", err)
+	}
+	if !slices.Equal(cfg.RequiredDocTypes, []string{DocTypeDraft, DocTypeNotes}) {
+		t.Errorf("RequiredDocTypes = %v, want [draft notes]", cfg.RequiredDocTypes)
+	}
+	if !slices.Equal(cfg.Numbering.Tiers, []int{100, 10, 1}) {
+		t.Errorf("Numbering.Tiers = %v, want [100 10 1]", cfg.Numbering.Tiers)
+	}
+	if cfg.SIDLength != DefaultSIDLength {
+		t.Errorf("SIDLength = %d, want %d", cfg.SIDLength, DefaultSIDLength)
+	}
+	if cfg.CompactWarningThreshold != DefaultCompactWarningThreshold {
+		t.Errorf("CompactWarningThreshold = %d, want %d", cfg.CompactWarningThreshold, DefaultCompactWarningThreshold)
+	}
+}
+
+func TestDefaultConfig_DoesNotAliasDefaultNumbering(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Numbering.Tiers[0] = 50
+
+	if DefaultNumbering.Tiers[0] != 100 {
+		t.Errorf("DefaultNumbering mutated through DefaultConfig: %v", DefaultNumbering.Tiers)
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		wantKey string
+	}{
+		{"invalid doc type", func(c *Config) { c.RequiredDocTypes = []string{"Draft"} }, "doc_types.required"},
+		{"invalid default doc type", func(c *Config) { c.DefaultDocTypes = []string{"my-notes"} }, "doc_types.defaults"},
+		{"invalid depth", func(c *Config) { c.DepthDocTypes = map[int]DocTypeRule{0: {Required: []string{"beats"}}} }, "doc_types.depths.0"},
+		{"invalid depth doc type", func(c *Config) { c.DepthDocTypes = map[int]DocTypeRule{3: {Defaults: []string{"Beats"}}} }, "doc_types.depths.3.defaults"},
+		{"invalid depth name", func(c *Config) { c.DepthNames = []string{"Chapter"} }, "templates.depth_names"},
+		{"invalid rule id", func(c *Config) { c.Lint.Rules = []string{"Doubled_Words"} }, "lint.rules"},
+		{"non-positive max words", func(c *Config) { c.Lint.MaxWords = 0 }, "lint.max_words"},
+		{"empty banned phrase", func(c *Config) { c.Lint.BannedPhrases = []string{"very", " "} }, "lint.banned_phrases"},
+		{"empty tiers", func(c *Config) { c.Numbering.Tiers = nil }, "numbering.tiers"},
+		{"first tier too large", func(c *Config) { c.Numbering.Tiers = []int{1000, 1} }, "numbering.tiers"},
+		{"last tier not 1", func(c *Config) { c.Numbering.Tiers = []int{100, 10} }, "numbering.tiers"},
+		{"tiers not descending", func(c *Config) { c.Numbering.Tiers = []int{10, 100, 1} }, "numbering.tiers"},
+		{"segment too narrow", func(c *Config) { c.Numbering.Width = 2 }, "numbering.width"},
+		{"segment too wide", func(c *Config) { c.Numbering.Width = 7 }, "numbering.width"},
+		{"sid length too short", func(c *Config) { c.SIDLength = 4 }, "sid.length"},
+		{"sid length too long", func(c *Config) { c.SIDLength = 20 }, "sid.length"},
+		{"negative threshold", func(c *Config) { c.CompactWarningThreshold = -1 }, "compact.warning_threshold"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.modify(&cfg)
+
+			err := cfg.Validate()
+
+			if !errors.Is(err, ErrInvalidConfig) {
+				t.Fatalf("Validate() = %v, want ErrInvalidConfig", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantKey) {
+				t.Errorf("error %q should mention %q", err.Error(), tt.wantKey)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate_JoinsMultipleErrors(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.SIDLength = 0
+	cfg.CompactWarningThreshold = -5
+
+	err := cfg.Validate()
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("expected joined error, got %T", err)
+	}
+	if got := len(joined.Unwrap()); got != 2 {
+		t.Errorf("joined errors = %d, want 2", got)
+	}
+}
+
+func TestConfig_GetSet_RoundTrip(t *testing.T) {
+	tests := []struct {
+		key   string
+		value string
+	}{
+		{"doc_types.required", "draft,research,sources"},
+		{"doc_types.defaults", "draft,research"},
+		{"doc_types.depths.3.required", "beats"},
+		{"doc_types.depths.2.defaults", "outline,summary"},
+		{"templates.depth_names", "part,chapter,scene"},
+		{"lint.rules", "doubled-words,todo"},
+		{"lint.max_words", "4000"},
+		{"lint.banned_phrases", "very unique,suddenly"},
+		{"numbering.tiers", "50,5,1"},
+		{"numbering.width", "4"},
+		{"sid.length", "10"},
+		{"compact.warning_threshold", "200"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			cfg := DefaultConfig()
+
+			if err := cfg.Set(tt.key, tt.value); err != nil {
+				t.Fatalf("Set() error: %v", err)
+			}
+			got, err := cfg.Get(tt.key)
+			if err != nil {
+				t.Fatalf("Get() error: %v", err)
+			}
+			if got != tt.value {
+				t.Errorf("Get(%q) = %q, want %q", tt.key, got, tt.value)
+			}
+			if err := cfg.Validate(); err != nil {
+				t.Errorf("config invalid after Set: %v", err)
+			}
+		})
+	}
+}
+
+func TestConfig_Set_NormalisesListWhitespace(t *testing.T) {
+	cfg := DefaultConfig()
+
+	if err := cfg.Set("doc_types.required", " draft , notes ,, "); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+
+	if !slices.Equal(cfg.RequiredDocTypes, []string{"draft", "notes"}) {
+		t.Errorf("RequiredDocTypes = %v, want [draft notes]", cfg.RequiredDocTypes)
+	}
+}
+
+func TestConfig_Set_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		wantErr error
+	}{
+		{"unknown key", "no.such.key", "1", ErrUnknownConfigKey},
+		{"non-integer tier", "numbering.tiers", "100,ten,1", ErrInvalidConfig},
+		{"non-integer width", "numbering.width", "wide", ErrInvalidConfig},
+		{"non-integer sid length", "sid.length", "twelve", ErrInvalidConfig},
+		{"non-integer threshold", "compact.warning_threshold", "lots", ErrInvalidConfig},
+		{"non-integer max words", "lint.max_words", "many", ErrInvalidConfig},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+
+			err := cfg.Set(tt.key, tt.value)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Set() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_Get_UnknownKey(t *testing.T) {
+	_, err := DefaultConfig().Get("bogus")
+
+	if !errors.Is(err, ErrUnknownConfigKey) {
+		t.Errorf("Get() = %v, want ErrUnknownConfigKey", err)
+	}
+}
+
+func TestConfigKeys_ListsAllKeys(t *testing.T) {
+	want := []string{"doc_types.required", "doc_types.defaults", "templates.depth_names", "lint.rules", "lint.max_words", "lint.banned_phrases", "numbering.tiers", "numbering.width", "sid.length", "compact.warning_threshold"}
+
+	if got := ConfigKeys(); !slices.Equal(got, want) {
+		t.Errorf("ConfigKeys() = %v, want %v", got, want)
+	}
+}
+
+func TestConfig_DocTypesAt(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.RequiredDocTypes = []string{"draft", "research"}
+	cfg.DefaultDocTypes = []string{"draft", "notes"}
+	cfg.DepthDocTypes = map[int]DocTypeRule{
+		3: {Required: []string{"beats", "draft"}, Defaults: []string{"beats"}},
+	}
+
+	tests := []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{"required at depth 1", cfg.RequiredDocTypesAt(1), []string{"draft", "research"}},
+		{"required at depth 3", cfg.RequiredDocTypesAt(3), []string{"draft", "research", "beats"}},
+		{"defaults at depth 1", cfg.DefaultDocTypesAt(1), []string{"draft", "notes"}},
+		{"defaults at depth 3", cfg.DefaultDocTypesAt(3), []string{"draft", "notes", "beats"}},
+		{"configured", cfg.ConfiguredDocTypes(), []string{"draft", "research", "notes", "beats"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !slices.Equal(tt.got, tt.want) {
+				t.Errorf("got %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_Keys_IncludesDepthRules(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.DepthDocTypes = map[int]DocTypeRule{
+		3: {Required: []string{"beats"}},
+		2: {Defaults: []string{"summary"}},
+	}
+
+	keys := cfg.Keys()
+
+	want := append(ConfigKeys(),
+		"doc_types.depths.2.required", "doc_types.depths.2.defaults",
+		"doc_types.depths.3.required", "doc_types.depths.3.defaults",
+	)
+	if !slices.Equal(keys, want) {
+		t.Errorf("Keys() = %v, want %v", keys, want)
+	}
+}
+
+func TestConfig_SetDepthRule_ClearingRemovesDepth(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := cfg.Set("doc_types.depths.3.required", "beats"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cfg.Set("doc_types.depths.3.required", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := cfg.DepthDocTypes[3]; ok {
+		t.Errorf("depth 3 should be removed once both lists are empty: %v", cfg.DepthDocTypes)
+	}
+}
+
+func TestConfig_SetDepthRule_DoesNotAliasOriginal(t *testing.T) {
+	original := DefaultConfig()
+	original.DepthDocTypes = map[int]DocTypeRule{3: {Required: []string{"beats"}}}
+	cfg := original
+
+	if err := cfg.Set("doc_types.depths.2.required", "summary"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := original.DepthDocTypes[2]; ok {
+		t.Error("Set mutated the map shared with the original config")
+	}
+}
+
+func TestConfig_DepthKey_Malformed(t *testing.T) {
+	for _, key := range []string{"doc_types.depths.x.required", "doc_types.depths.3.optional", "doc_types.depths.3"} {
+		if _, err := DefaultConfig().Get(key); !errors.Is(err, ErrUnknownConfigKey) {
+			t.Errorf("Get(%q) = %v, want ErrUnknownConfigKey", key, err)
+		}
+	}
+}
@@ -33,10 +33,21 @@ func ValidateDocType(docType string) error {
 	return nil
 }
 
+// mpPatternSrc matches an MP: dash-separated segments of DefaultSegmentWidth
+// to MaxSegmentWidth digits. Whether the widths suit the project is left to
+// check.
+const mpPatternSrc = `\d{3,6}(?:-\d{3,6})*`
+
 var filenameRegex = regexp.MustCompile(
-	`^(\d{3}(?:-\d{3})*)_([a-zA-Z0-9]{8,12})_([a-z]+)(?:_(.+))?\.md$`,
+	`^(` + mpPatternSrc + `)_([a-zA-Z0-9]{8,12})_([a-z]+)(?:_(.+))?\.md$`,
 )
 
+// isZeroSegment reports whether an MP segment is all zeros, which no node
+// may use.
+func isZeroSegment(seg string) bool {
+	return strings.Trim(seg, "0") == ""
+}
+
 // ParseFilename parses a linemark filename into its components.
 func ParseFilename(filename string) (ParsedFile, error) {
 	matches := filenameRegex.FindStringSubmatch(filename)
@@ -47,7 +58,7 @@ func ParseFilename(filename string) (ParsedFile, error) {
 	mp := matches[1]
 	pathParts := strings.Split(mp, "-")
 	for _, part := range pathParts {
-		if part == "000" {
+		if isZeroSegment(part) {
 			return ParsedFile{}, ErrInvalidFilename
 		}
 	}
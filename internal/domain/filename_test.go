@@ -34,6 +34,17 @@ func TestParseFilename_ValidFilenames(t *testing.T) {
 				Depth:     1,
 			},
 		},
+		{
+			name:     "wide segments",
+			filename: "0100-1500_A3F7c9Qx7Lm2_notes.md",
+			want: ParsedFile{
+				MP:        "0100-1500",
+				SID:       "A3F7c9Qx7Lm2",
+				DocType:   "notes",
+				PathParts: []string{"0100", "1500"},
+				Depth:     2,
+			},
+		},
 		{
 			name:     "nested node with slug",
 			filename: "001-200-010_B8kQ2mNp4Rs1_draft_chapter-one.md",
@@ -176,7 +187,8 @@ func TestParseFilename_InvalidFilenames(t *testing.T) {
 		{"missing mp", "_A3F7c9Qx7Lm2_draft_slug.md"},
 		{"mp with leading zero segment 000", "000_A3F7c9Qx7Lm2_draft.md"},
 		{"mp segment too short", "01_A3F7c9Qx7Lm2_draft.md"},
-		{"mp segment too long", "0001_A3F7c9Qx7Lm2_draft.md"},
+		{"mp segment too long", "0000001_A3F7c9Qx7Lm2_draft.md"},
+		{"all-zero wide segment", "0000_A3F7c9Qx7Lm2_draft.md"},
 		{"sid too short 7 chars", "001_AbCd123_draft.md"},
 		{"sid too long 13 chars", "001_AbCd1234567890_draft.md"},
 		{"sid with special chars", "001_AbCd!@#$_draft.md"},
@@ -1,5 +1,7 @@
 package domain
 
+import "fmt"
+
 // FindingSeverity indicates how severe a finding is.
 type FindingSeverity string
 
@@ -22,8 +24,35 @@ const (
 	FindingMalformedFrontmatter FindingType = "malformed_frontmatter"
 	FindingOrphanedReservation  FindingType = "orphaned_reservation"
 	FindingMissingReservation   FindingType = "missing_reservation"
+	FindingInvalidConfig        FindingType = "invalid_config"
+	FindingMissingField         FindingType = "missing_field"
+	FindingInvalidField         FindingType = "invalid_field"
+	FindingLint                 FindingType = "lint"
+	FindingOrphanedNode         FindingType = "orphaned_node"
+	FindingDuplicateMP          FindingType = "duplicate_mp"
+	FindingSegmentWidth         FindingType = "segment_width"
 )
 
+// FindingTypes returns every finding type reported by check.
+func FindingTypes() []FindingType {
+	return []FindingType{
+		FindingInvalidFilename,
+		FindingDuplicateSID,
+		FindingSlugDrift,
+		FindingMissingDocType,
+		FindingMalformedFrontmatter,
+		FindingOrphanedReservation,
+		FindingMissingReservation,
+		FindingInvalidConfig,
+		FindingMissingField,
+		FindingInvalidField,
+		FindingLint,
+		FindingOrphanedNode,
+		FindingDuplicateMP,
+		FindingSegmentWidth,
+	}
+}
+
 // Document type constants identify the standard document types.
 const (
 	DocTypeDraft = "draft"
@@ -31,9 +60,48 @@ const (
 )
 
 // Finding represents a validation issue discovered during a check operation.
+// Rule identifies the lint rule that produced it, if any. Line and Column are
+// 1-based positions within Path, or zero when the finding applies to the
+// whole file or the column is unknown. EndLine and EndColumn, when set, mark
+// the position just past the end of the flagged text. SID and DocType name
+// the affected node and doc type when Path does not.
 type Finding struct {
-	Type     FindingType
-	Severity FindingSeverity
-	Message  string
-	Path     string
+	Type      FindingType
+	Severity  FindingSeverity
+	Message   string
+	Path      string
+	SID       string
+	DocType   string
+	Rule      string
+	Line      int
+	Column    int
+	EndLine   int
+	EndColumn int
+}
+
+// RuleID returns the ID used to select or skip the finding: its lint rule,
+// or its type for structural findings.
+func (f Finding) RuleID() string {
+	if f.Rule != "" {
+		return f.Rule
+	}
+	return string(f.Type)
+}
+
+// PositionError is an error located at a 1-based line and column of a file.
+// Column is zero when only the line is known.
+type PositionError struct {
+	Line   int
+	Column int
+	Err    error
+}
+
+// Error implements the error interface.
+func (e *PositionError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *PositionError) Unwrap() error {
+	return e.Err
 }
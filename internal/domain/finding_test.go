@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"errors"
+	"slices"
 	"testing"
 )
 
@@ -57,6 +59,12 @@ func TestFindingType_Constants(t *testing.T) {
 		{"missing doc type", FindingMissingDocType, "missing_doc_type"},
 		{"malformed frontmatter", FindingMalformedFrontmatter, "malformed_frontmatter"},
 		{"orphaned reservation", FindingOrphanedReservation, "orphaned_reservation"},
+		{"invalid config", FindingInvalidConfig, "invalid_config"},
+		{"missing field", FindingMissingField, "missing_field"},
+		{"invalid field", FindingInvalidField, "invalid_field"},
+		{"orphaned node", FindingOrphanedNode, "orphaned_node"},
+		{"duplicate MP", FindingDuplicateMP, "duplicate_mp"},
+		{"segment width", FindingSegmentWidth, "segment_width"},
 	}
 
 	for _, tt := range tests {
@@ -67,3 +75,45 @@ func TestFindingType_Constants(t *testing.T) {
 		})
 	}
 }
+
+func TestFinding_RuleID(t *testing.T) {
+	tests := []struct {
+		name    string
+		finding Finding
+		want    string
+	}{
+		{"finding type", Finding{Type: FindingSlugDrift}, "slug_drift"},
+		{"lint rule", Finding{Type: FindingLint, Rule: "todo"}, "todo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.finding.RuleID(); got != tt.want {
+				t.Errorf("RuleID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindingTypes_IncludesLint(t *testing.T) {
+	types := FindingTypes()
+
+	if len(types) != 14 {
+		t.Errorf("FindingTypes() has %d entries, want 14", len(types))
+	}
+	if !slices.Contains(types, FindingLint) || types[len(types)-1] != FindingSegmentWidth {
+		t.Errorf("FindingTypes() = %v, want lint included and segment_width last", types)
+	}
+}
+
+func TestPositionError(t *testing.T) {
+	inner := errors.New("bad indent")
+	err := &PositionError{Line: 3, Column: 2, Err: inner}
+
+	if err.Error() != "line 3: bad indent" {
+		t.Errorf("Error() = %q, want %q", err.Error(), "line 3: bad indent")
+	}
+	if !errors.Is(err, inner) {
+		t.Error("PositionError should unwrap to its cause")
+	}
+}
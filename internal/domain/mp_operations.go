@@ -6,12 +6,13 @@ import (
 	"strconv"
 )
 
-// Child returns a new MaterializedPath with the given segment appended.
-func (mp MaterializedPath) Child(segment int) (MaterializedPath, error) {
-	if segment < 1 || segment > 999 {
-		return MaterializedPath{}, fmt.Errorf("%w: segment %d out of range 1-999", ErrInvalidPath, segment)
+// Child returns a new MaterializedPath with the given segment appended,
+// formatted to the numbering's segment width.
+func (mp MaterializedPath) Child(n Numbering, segment int) (MaterializedPath, error) {
+	if segment < 1 || segment > n.MaxSibling() {
+		return MaterializedPath{}, fmt.Errorf("%w: segment %d out of range 1-%d", ErrInvalidPath, segment, n.MaxSibling())
 	}
-	return MaterializedPath{segments: append(slices.Clone(mp.segments), fmt.Sprintf("%03d", segment))}, nil
+	return NewMaterializedPath(n.ChildMP(mp.String(), segment))
 }
 
 // LastSegment returns the numeric value of the last path segment.
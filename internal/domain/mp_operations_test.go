@@ -8,19 +8,24 @@ func TestMaterializedPath_Child(t *testing.T) {
 	tests := []struct {
 		name    string
 		parent  string
+		width   int
 		segment int
 		want    string
 		wantErr bool
 	}{
-		{"root adds child", "001", 200, "001-200", false},
-		{"nested adds child", "001-200", 10, "001-200-010", false},
-		{"deep nesting", "001-200-010", 300, "001-200-010-300", false},
-		{"child at 100 spacing", "100", 100, "100-100", false},
-		{"child at max", "001", 999, "001-999", false},
-		{"child at min", "001", 1, "001-001", false},
-		{"segment zero rejected", "001", 0, "", true},
-		{"segment negative rejected", "001", -1, "", true},
-		{"segment over 999 rejected", "001", 1000, "", true},
+		{"root adds child", "001", 3, 200, "001-200", false},
+		{"nested adds child", "001-200", 3, 10, "001-200-010", false},
+		{"deep nesting", "001-200-010", 3, 300, "001-200-010-300", false},
+		{"child at 100 spacing", "100", 3, 100, "100-100", false},
+		{"child at max", "001", 3, 999, "001-999", false},
+		{"child at min", "001", 3, 1, "001-001", false},
+		{"default width", "001", 0, 20, "001-020", false},
+		{"wide segment", "0001", 4, 20, "0001-0020", false},
+		{"wide segment above 999", "0001", 4, 1000, "0001-1000", false},
+		{"segment zero rejected", "001", 3, 0, "", true},
+		{"segment negative rejected", "001", 3, -1, "", true},
+		{"segment over 999 rejected", "001", 3, 1000, "", true},
+		{"segment over width rejected", "0001", 4, 10000, "", true},
 	}
 
 	for _, tt := range tests {
@@ -30,7 +35,7 @@ func TestMaterializedPath_Child(t *testing.T) {
 				t.Fatalf("setup: unexpected error: %v", err)
 			}
 
-			child, err := mp.Child(tt.segment)
+			child, err := mp.Child(Numbering{Tiers: DefaultNumbering.Tiers, Width: tt.width}, tt.segment)
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("Child(%d) error = %v, wantErr %v", tt.segment, err, tt.wantErr)
 			}
@@ -10,14 +10,14 @@ import (
 // ErrInvalidPath is returned when a materialized path string is invalid.
 var ErrInvalidPath = errors.New("invalid materialized path")
 
-var segmentRegex = regexp.MustCompile(`^(\d{3}|[1-9]\d?)$`)
+var segmentRegex = regexp.MustCompile(`^(\d{3,6}|[1-9]\d?)$`)
 
 // MaterializedPath is a value object representing a node's position in the tree.
 type MaterializedPath struct {
 	segments []string
 }
 
-// NewMaterializedPath creates a MaterializedPath from a dash-separated string of 3-digit segments.
+// NewMaterializedPath creates a MaterializedPath from a dash-separated string of 3- to 6-digit segments.
 func NewMaterializedPath(s string) (MaterializedPath, error) {
 	if s == "" {
 		return MaterializedPath{}, ErrInvalidPath
@@ -25,7 +25,7 @@ func NewMaterializedPath(s string) (MaterializedPath, error) {
 
 	segments := strings.Split(s, "-")
 	for _, seg := range segments {
-		if !segmentRegex.MatchString(seg) || seg == "000" {
+		if !segmentRegex.MatchString(seg) || isZeroSegment(seg) {
 			return MaterializedPath{}, ErrInvalidPath
 		}
 	}
@@ -70,3 +70,17 @@ type Node struct {
 	Title     string
 	Documents []Document
 }
+
+// Slug returns the slug in the filename of the node's draft, or "" when the
+// node has no draft or the draft has no slug.
+func (n Node) Slug() string {
+	for _, d := range n.Documents {
+		if d.Type != DocTypeDraft {
+			continue
+		}
+		if pf, err := ParseFilename(d.Filename); err == nil {
+			return pf.Slug
+		}
+	}
+	return ""
+}
@@ -42,7 +42,7 @@ func TestNewMaterializedPath_InvalidPaths(t *testing.T) {
 		{"zero in second segment", "001-000"},
 		{"zero in third segment", "001-200-000"},
 		{"segment too short", "01"},
-		{"segment too long", "0001"},
+		{"segment too long", "0000001"},
 		{"letters in segment", "abc"},
 		{"mixed invalid segments", "001-ab-200"},
 		{"trailing dash", "001-"},
@@ -250,3 +250,24 @@ func TestNode_SortByMaterializedPath(t *testing.T) {
 		}
 	}
 }
+
+func TestNode_Slug(t *testing.T) {
+	tests := []struct {
+		name string
+		docs []Document
+		want string
+	}{
+		{"draft with slug", []Document{{Type: "notes", Filename: "100_SID001AABB_notes.md"}, {Type: "draft", Filename: "100_SID001AABB_draft_part-one.md"}}, "part-one"},
+		{"draft without slug", []Document{{Type: "draft", Filename: "100_SID001AABB_draft.md"}}, ""},
+		{"unparseable draft", []Document{{Type: "draft", Filename: "draft.md"}}, ""},
+		{"no draft", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (Node{Documents: tt.docs}).Slug(); got != tt.want {
+				t.Errorf("Slug() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,28 +2,88 @@ package domain
 
 import (
 	"errors"
+	"fmt"
 	"slices"
 )
 
 const (
-	maxSibling     = 999
 	initialSpacing = 100
+
+	// DefaultSegmentWidth is the number of digits in each MP segment.
+	DefaultSegmentWidth = 3
+	// MaxSegmentWidth is the widest MP segment a project may configure.
+	MaxSegmentWidth = 6
 )
 
-// ErrMaxSiblingsReached is returned when all 999 sibling slots are occupied.
-var ErrMaxSiblingsReached = errors.New("maximum siblings reached (999)")
+// ErrMaxSiblingsReached is returned when every sibling slot is occupied.
+var ErrMaxSiblingsReached = errors.New("maximum siblings reached")
 
 // ErrNoSlotAvailable is returned when no gap exists after the last sibling.
 var ErrNoSlotAvailable = errors.New("no slot available; compact renumbering recommended")
 
+// Numbering describes the tiered spacing used to allocate sibling numbers.
+// Tiers are tried from coarsest to finest. The first tier is also the spacing
+// given to the first child and the widest used when compacting. Width is the
+// number of digits in each MP segment; zero means DefaultSegmentWidth.
+type Numbering struct {
+	Tiers []int
+	Width int
+}
+
+// DefaultNumbering is the built-in 100/10/1 spacing with 3-digit segments.
+var DefaultNumbering = Numbering{Tiers: []int{initialSpacing, 10, 1}, Width: DefaultSegmentWidth}
+
+// SegmentWidth returns the number of digits in each MP segment.
+func (n Numbering) SegmentWidth() int {
+	if n.Width == 0 {
+		return DefaultSegmentWidth
+	}
+	return n.Width
+}
+
+// MaxSibling returns the highest sibling number a segment can hold.
+func (n Numbering) MaxSibling() int {
+	return maxSiblingFor(n.SegmentWidth())
+}
+
+// maxSiblingFor returns the highest number with the given count of digits.
+func maxSiblingFor(width int) int {
+	limit := 1
+	for range width {
+		limit *= 10
+	}
+	return limit - 1
+}
+
+// ChildMP appends the segment for num under parentMP, or returns the bare
+// segment for a root-level node.
+func (n Numbering) ChildMP(parentMP string, num int) string {
+	segment := fmt.Sprintf("%0*d", n.SegmentWidth(), num)
+	if parentMP == "" {
+		return segment
+	}
+	return parentMP + "-" + segment
+}
+
+// spacing returns the initial spacing, falling back to the default for an empty policy.
+func (n Numbering) spacing() int {
+	if len(n.Tiers) == 0 {
+		return initialSpacing
+	}
+	return n.Tiers[0]
+}
+
 // FindGap finds the best-tiered number strictly between low and high.
-// It tries 100s, then 10s, then 1s multiples.
-func FindGap(low, high int) (int, bool) {
+// It tries each tier from coarsest to finest, falling back to low+1.
+func (n Numbering) FindGap(low, high int) (int, bool) {
 	if high <= low+1 {
 		return 0, false
 	}
 
-	for _, tier := range []int{100, 10} {
+	for _, tier := range n.Tiers {
+		if tier <= 1 {
+			break
+		}
 		candidate := ((low / tier) + 1) * tier
 		if candidate > low && candidate < high {
 			return candidate, true
@@ -34,36 +94,31 @@ func FindGap(low, high int) (int, bool) {
 	return low + 1, true
 }
 
-func tierOf(n int) int {
-	if n%100 == 0 {
-		return 100
-	}
-	if n%10 == 0 {
-		return 10
+// tierOf returns the coarsest tier that divides num, or 1.
+func (n Numbering) tierOf(num int) int {
+	for _, tier := range n.Tiers {
+		if tier > 1 && num%tier == 0 {
+			return tier
+		}
 	}
 	return 1
 }
 
-func sortedCopy(occupied []int) []int {
-	sorted := slices.Clone(occupied)
-	slices.Sort(sorted)
-	return sorted
-}
-
 // NextSiblingNumber returns the next sibling number to append after existing siblings.
-func NextSiblingNumber(occupied []int) (int, error) {
+func (n Numbering) NextSiblingNumber(occupied []int) (int, error) {
 	if len(occupied) == 0 {
-		return initialSpacing, nil
+		return n.spacing(), nil
 	}
 
 	sorted := sortedCopy(occupied)
+	maxSibling := n.MaxSibling()
 
 	if len(sorted) >= maxSibling {
 		return 0, ErrMaxSiblingsReached
 	}
 
 	last := sorted[len(sorted)-1]
-	result, ok := FindGap(last, maxSibling+1)
+	result, ok := n.FindGap(last, maxSibling+1)
 	if !ok {
 		return 0, ErrNoSlotAvailable
 	}
@@ -71,10 +126,10 @@ func NextSiblingNumber(occupied []int) (int, error) {
 }
 
 // SiblingNumberBefore returns a sibling number to insert before the target.
-func SiblingNumberBefore(occupied []int, target int) (int, error) {
+func (n Numbering) SiblingNumberBefore(occupied []int, target int) (int, error) {
 	sorted := sortedCopy(occupied)
 
-	if len(sorted) >= maxSibling {
+	if len(sorted) >= n.MaxSibling() {
 		return 0, ErrMaxSiblingsReached
 	}
 
@@ -85,7 +140,7 @@ func SiblingNumberBefore(occupied []int, target int) (int, error) {
 		predecessor = sorted[idx-1]
 	}
 
-	result, ok := FindGap(predecessor, target)
+	result, ok := n.FindGap(predecessor, target)
 	if !ok {
 		return 0, ErrNoSlotAvailable
 	}
@@ -93,8 +148,9 @@ func SiblingNumberBefore(occupied []int, target int) (int, error) {
 }
 
 // SiblingNumberAfter returns a sibling number to insert after the target.
-func SiblingNumberAfter(occupied []int, target int) (int, error) {
+func (n Numbering) SiblingNumberAfter(occupied []int, target int) (int, error) {
 	sorted := sortedCopy(occupied)
+	maxSibling := n.MaxSibling()
 
 	if len(sorted) >= maxSibling {
 		return 0, ErrMaxSiblingsReached
@@ -109,7 +165,7 @@ func SiblingNumberAfter(occupied []int, target int) (int, error) {
 		successor = maxSibling + 1
 	}
 
-	directGap, directOK := FindGap(target, successor)
+	directGap, directOK := n.FindGap(target, successor)
 
 	// Check the next gap for a higher-tier result.
 	if successor <= maxSibling {
@@ -119,8 +175,8 @@ func SiblingNumberAfter(occupied []int, target int) (int, error) {
 		} else {
 			secondSuccessor = maxSibling + 1
 		}
-		skipGap, skipOK := FindGap(successor, secondSuccessor)
-		if skipOK && (!directOK || tierOf(skipGap) > tierOf(directGap)) {
+		skipGap, skipOK := n.FindGap(successor, secondSuccessor)
+		if skipOK && (!directOK || n.tierOf(skipGap) > n.tierOf(directGap)) {
 			return skipGap, nil
 		}
 	}
@@ -132,14 +188,195 @@ func SiblingNumberAfter(occupied []int, target int) (int, error) {
 	return 0, ErrNoSlotAvailable
 }
 
-// CompactNumbers returns a renumbered sequence at initial spacing.
-func CompactNumbers(count int) ([]int, error) {
-	if count > maxSibling/initialSpacing {
-		return nil, ErrMaxSiblingsReached
+// Rebalance opens a slot for a new sibling at index among the occupied
+// numbers, in sorted order, for when no gap exists there. It renumbers the
+// fewest neighbouring siblings it can, spreading them and the new sibling
+// evenly between the nearest numbers left in place; of equally small
+// windows, it takes the one leaving the widest spacing. It returns the new
+// sibling's number and the changed numbers, keyed by old number.
+func (n Numbering) Rebalance(occupied []int, index int) (int, map[int]int, error) {
+	sorted := slices.Compact(sortedCopy(occupied))
+	maxSibling := n.MaxSibling()
+
+	if len(sorted) >= maxSibling {
+		return 0, nil, ErrMaxSiblingsReached
+	}
+	index = min(max(index, 0), len(sorted))
+
+	// bounds returns the numbers left in place either side of the window of
+	// siblings sorted[lo:lo+size].
+	bounds := func(lo, size int) (int, int) {
+		low, high := 0, maxSibling+1
+		if lo > 0 {
+			low = sorted[lo-1]
+		}
+		if lo+size < len(sorted) {
+			high = sorted[lo+size]
+		}
+		return low, high
+	}
+
+	// Widen the window one sibling at a time. Renumbering every sibling
+	// always fits, since fewer than maxSibling are occupied.
+	for size := 1; ; size++ {
+		best, bestStep := 0, 0
+		for lo := max(index-size, 0); lo <= index && lo+size <= len(sorted); lo++ {
+			low, high := bounds(lo, size)
+			if step := (high - low) / (size + 2); step > bestStep {
+				best, bestStep = lo, step
+			}
+		}
+		if bestStep == 0 {
+			continue
+		}
+
+		low, high := bounds(best, size)
+		var num int
+		renumbered := map[int]int{}
+		for i := range size + 1 {
+			spread := low + (i+1)*(high-low)/(size+2)
+			switch {
+			case best+i == index:
+				num = spread
+			case best+i < index:
+				renumbered[sorted[best+i]] = spread
+			default:
+				renumbered[sorted[best+i-1]] = spread
+			}
+		}
+		for old, spread := range renumbered {
+			if old == spread {
+				delete(renumbered, old)
+			}
+		}
+		return num, renumbered, nil
+	}
+}
+
+// Arrange numbers siblings in a new order. current holds each sibling's
+// present number, or 0 for one that has none yet, such as a node added or
+// moved in from elsewhere. The longest run of present numbers that still
+// increases is kept, and the other siblings take tiered numbers in the gaps
+// around it; when a gap is too narrow, every sibling is renumbered as
+// CompactNumbers spaces them.
+func (n Numbering) Arrange(current []int) ([]int, error) {
+	maxSibling := n.MaxSibling()
+
+	// Find the longest increasing run of present numbers, preferring the
+	// earliest when several are as long.
+	length := make([]int, len(current))
+	prev := make([]int, len(current))
+	last := -1
+	for i, num := range current {
+		prev[i] = -1
+		if num <= 0 || num > maxSibling {
+			continue
+		}
+		length[i] = 1
+		for j := range i {
+			if length[j] > 0 && current[j] < num && length[j]+1 > length[i] {
+				length[i], prev[i] = length[j]+1, j
+			}
+		}
+		if last < 0 || length[i] > length[last] {
+			last = i
+		}
+	}
+	kept := make([]bool, len(current))
+	for i := last; i >= 0; i = prev[i] {
+		kept[i] = true
+	}
+
+	result := make([]int, len(current))
+	low := 0
+	for i := range current {
+		if kept[i] {
+			result[i], low = current[i], current[i]
+			continue
+		}
+		high := maxSibling + 1
+		for j := i + 1; j < len(current); j++ {
+			if kept[j] {
+				high = current[j]
+				break
+			}
+		}
+		num, ok := n.FindGap(low, high)
+		if !ok {
+			return n.CompactNumbers(len(current))
+		}
+		result[i], low = num, num
+	}
+	return result, nil
+}
+
+// CompactNumbers returns a renumbered sequence of count numbers at the
+// widest spacing that fits them all, as given by CompactSpacing.
+func (n Numbering) CompactNumbers(count int) ([]int, error) {
+	spacing, err := n.CompactSpacing(count)
+	if err != nil {
+		return nil, err
 	}
 	result := make([]int, count)
 	for i := range result {
-		result[i] = (i + 1) * initialSpacing
+		result[i] = (i + 1) * spacing
 	}
 	return result, nil
 }
+
+// CompactSpacing returns the widest spacing at which count siblings fit in
+// a segment. Candidates are the tiers, coarsest first, each followed by its
+// half when that lies above the next tier: 100, 50, 10, 5, 1 by default, so
+// nine children are spaced by 100, nineteen by 50 and ninety-nine by 10.
+func (n Numbering) CompactSpacing(count int) (int, error) {
+	tiers := n.Tiers
+	if len(tiers) == 0 {
+		tiers = DefaultNumbering.Tiers
+	}
+	var candidates []int
+	for i, tier := range tiers {
+		candidates = append(candidates, tier)
+		if half := tier / 2; tier%2 == 0 && (i+1 == len(tiers) || half > tiers[i+1]) {
+			candidates = append(candidates, half)
+		}
+	}
+	maxSibling := n.MaxSibling()
+	for _, spacing := range candidates {
+		if count*spacing <= maxSibling {
+			return spacing, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %d children do not fit in %d", ErrMaxSiblingsReached, count, maxSibling)
+}
+
+func sortedCopy(occupied []int) []int {
+	sorted := slices.Clone(occupied)
+	slices.Sort(sorted)
+	return sorted
+}
+
+// FindGap finds the best-tiered number strictly between low and high
+// using DefaultNumbering.
+func FindGap(low, high int) (int, bool) {
+	return DefaultNumbering.FindGap(low, high)
+}
+
+// NextSiblingNumber returns the next sibling number using DefaultNumbering.
+func NextSiblingNumber(occupied []int) (int, error) {
+	return DefaultNumbering.NextSiblingNumber(occupied)
+}
+
+// SiblingNumberBefore returns a sibling number before target using DefaultNumbering.
+func SiblingNumberBefore(occupied []int, target int) (int, error) {
+	return DefaultNumbering.SiblingNumberBefore(occupied, target)
+}
+
+// SiblingNumberAfter returns a sibling number after target using DefaultNumbering.
+func SiblingNumberAfter(occupied []int, target int) (int, error) {
+	return DefaultNumbering.SiblingNumberAfter(occupied, target)
+}
+
+// CompactNumbers returns a renumbered sequence using DefaultNumbering.
+func CompactNumbers(count int) ([]int, error) {
+	return DefaultNumbering.CompactNumbers(count)
+}
@@ -2,6 +2,8 @@ package domain
 
 import (
 	"errors"
+	"maps"
+	"slices"
 	"testing"
 )
 
@@ -327,13 +329,18 @@ func TestCompactNumbers(t *testing.T) {
 			want:  []int{100, 200, 300, 400, 500, 600, 700, 800, 900},
 		},
 		{
-			name:    "ten siblings exceeds 100-spacing capacity",
-			count:   10,
-			wantErr: ErrMaxSiblingsReached,
+			name:  "ten siblings narrow spacing to 50",
+			count: 10,
+			want:  []int{50, 100, 150, 200, 250, 300, 350, 400, 450, 500},
+		},
+		{
+			name:  "999 siblings fill every slot",
+			count: 999,
+			want:  rangeInts(1, 999),
 		},
 		{
-			name:    "999 siblings exceeds 100-spacing capacity",
-			count:   999,
+			name:    "1000 siblings exceed segment capacity",
+			count:   1000,
 			wantErr: ErrMaxSiblingsReached,
 		},
 	}
@@ -451,3 +458,240 @@ func TestFindGap(t *testing.T) {
 		})
 	}
 }
+
+func TestNumbering_CustomTiers(t *testing.T) {
+	n := Numbering{Tiers: []int{50, 5, 1}}
+
+	t.Run("first child uses first tier", func(t *testing.T) {
+		got, err := n.NextSiblingNumber(nil)
+		if err != nil || got != 50 {
+			t.Errorf("NextSiblingNumber(nil) = %d, %v; want 50, nil", got, err)
+		}
+	})
+
+	t.Run("append uses first tier", func(t *testing.T) {
+		got, err := n.NextSiblingNumber([]int{50, 100})
+		if err != nil || got != 150 {
+			t.Errorf("NextSiblingNumber = %d, %v; want 150, nil", got, err)
+		}
+	})
+
+	t.Run("gap falls back to middle tier", func(t *testing.T) {
+		got, ok := n.FindGap(50, 100)
+		if !ok || got != 55 {
+			t.Errorf("FindGap(50, 100) = %d, %v; want 55, true", got, ok)
+		}
+	})
+
+	t.Run("before prefers coarse tier", func(t *testing.T) {
+		got, err := n.SiblingNumberBefore([]int{100}, 100)
+		if err != nil || got != 50 {
+			t.Errorf("SiblingNumberBefore = %d, %v; want 50, nil", got, err)
+		}
+	})
+
+	t.Run("after skips to higher tier gap", func(t *testing.T) {
+		got, err := n.SiblingNumberAfter([]int{50, 51, 100}, 50)
+		if err != nil || got != 55 {
+			t.Errorf("SiblingNumberAfter = %d, %v; want 55, nil", got, err)
+		}
+	})
+
+	t.Run("compact uses first tier", func(t *testing.T) {
+		got, err := n.CompactNumbers(3)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []int{50, 100, 150}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("CompactNumbers(3) = %v, want %v", got, want)
+				break
+			}
+		}
+	})
+
+	t.Run("compact halves a tier before falling to the next", func(t *testing.T) {
+		got, err := n.CompactNumbers(20)
+		if err != nil || got[0] != 25 || got[19] != 500 {
+			t.Errorf("CompactNumbers(20) = %v, %v; want 25-spaced", got, err)
+		}
+	})
+}
+
+func TestNumbering_EmptyTiersFallsBackToDefaultSpacing(t *testing.T) {
+	var n Numbering
+
+	got, err := n.NextSiblingNumber(nil)
+	if err != nil || got != 100 {
+		t.Errorf("NextSiblingNumber(nil) = %d, %v; want 100, nil", got, err)
+	}
+	if gap, ok := n.FindGap(100, 200); !ok || gap != 101 {
+		t.Errorf("FindGap(100, 200) = %d, %v; want 101, true", gap, ok)
+	}
+	if tier := n.tierOf(300); tier != 1 {
+		t.Errorf("tierOf(300) = %d, want 1", tier)
+	}
+}
+
+func TestNumbering_CompactSpacing(t *testing.T) {
+	tests := []struct {
+		name      string
+		numbering Numbering
+		count     int
+		want      int
+	}{
+		{"few children keep the first tier", DefaultNumbering, 9, 100},
+		{"ten children halve it", DefaultNumbering, 10, 50},
+		{"twenty children use the next tier", DefaultNumbering, 20, 10},
+		{"hundreds of children", DefaultNumbering, 150, 5},
+		{"odd tiers are not halved", Numbering{Tiers: []int{75, 1}}, 14, 1},
+		{"wide segments fit more at the first tier", Numbering{Tiers: []int{100, 10, 1}, Width: 4}, 99, 100},
+		{"empty tiers use the default", Numbering{}, 10, 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.numbering.CompactSpacing(tt.count)
+			if err != nil || got != tt.want {
+				t.Errorf("CompactSpacing(%d) = %d, %v; want %d", tt.count, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNumbering_SegmentWidth(t *testing.T) {
+	wide := Numbering{Tiers: []int{100, 10, 1}, Width: 4}
+
+	if got := wide.MaxSibling(); got != 9999 {
+		t.Errorf("MaxSibling() = %d, want 9999", got)
+	}
+	if got := (Numbering{}).MaxSibling(); got != 999 {
+		t.Errorf("zero-width MaxSibling() = %d, want 999", got)
+	}
+	if got := wide.ChildMP("0100", 1500); got != "0100-1500" {
+		t.Errorf("ChildMP() = %q, want 0100-1500", got)
+	}
+	if got := DefaultNumbering.ChildMP("", 5); got != "005" {
+		t.Errorf("ChildMP() = %q, want 005", got)
+	}
+	if got, err := wide.NextSiblingNumber([]int{9900}); err != nil || got != 9910 {
+		t.Errorf("NextSiblingNumber() = %d, %v; want 9910", got, err)
+	}
+}
+
+func TestNumbering_Rebalance(t *testing.T) {
+	tests := []struct {
+		name     string
+		occupied []int
+		index    int
+		want     int
+		wantMap  map[int]int
+	}{
+		{
+			name:     "renumbers the following sibling when the preceding has no room",
+			occupied: []int{1, 2, 4},
+			index:    1,
+			want:     2,
+			wantMap:  map[int]int{2: 3},
+		},
+		{
+			name:     "renumbers the preceding sibling when the following has none",
+			occupied: []int{100, 101, 102},
+			index:    1,
+			want:     67,
+			wantMap:  map[int]int{100: 33},
+		},
+		{
+			name:     "prefers the neighbour leaving the widest spacing",
+			occupied: []int{100, 101},
+			index:    1,
+			want:     400,
+			wantMap:  map[int]int{101: 700},
+		},
+		{
+			name:     "widens the window at the end of the segment",
+			occupied: []int{100, 200, 998, 999},
+			index:    4,
+			want:     800,
+			wantMap:  map[int]int{998: 400, 999: 600},
+		},
+		{
+			name:     "renumbers every sibling when nothing smaller fits",
+			occupied: []int{1, 2, 3},
+			index:    0,
+			want:     200,
+			wantMap:  map[int]int{1: 400, 2: 600, 3: 800},
+		},
+		{
+			name:     "ignores duplicate numbers",
+			occupied: []int{100, 100, 101, 101, 102, 102},
+			index:    1,
+			want:     67,
+			wantMap:  map[int]int{100: 33},
+		},
+		{
+			name:     "leaves siblings whose number is unchanged out of the map",
+			occupied: []int{100, 300, 301},
+			index:    1,
+			want:     200,
+			wantMap:  map[int]int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, renumbered, err := DefaultNumbering.Rebalance(tt.occupied, tt.index)
+			if err != nil {
+				t.Fatalf("Rebalance() error: %v", err)
+			}
+			if got != tt.want || !maps.Equal(renumbered, tt.wantMap) {
+				t.Errorf("Rebalance() = %d, %v; want %d, %v", got, renumbered, tt.want, tt.wantMap)
+			}
+		})
+	}
+}
+
+func TestNumbering_Rebalance_Full(t *testing.T) {
+	_, _, err := DefaultNumbering.Rebalance(rangeInts(1, 999), 3)
+
+	if !errors.Is(err, ErrMaxSiblingsReached) {
+		t.Errorf("Rebalance() error = %v, want ErrMaxSiblingsReached", err)
+	}
+}
+
+func TestNumbering_Arrange(t *testing.T) {
+	tests := []struct {
+		name    string
+		current []int
+		want    []int
+	}{
+		{"keeps numbers still in order", []int{100, 200, 300}, []int{100, 200, 300}},
+		{"renumbers only the sibling out of order", []int{200, 100, 300}, []int{200, 210, 300}},
+		{"appends new siblings after the last", []int{100, 200, 0, 0}, []int{100, 200, 300, 400}},
+		{"fits a new sibling before the first", []int{0, 100}, []int{10, 100}},
+		{"numbers siblings that are all new", []int{0, 0, 0}, []int{100, 200, 300}},
+		{"ignores numbers too wide for a segment", []int{1500, 0}, []int{100, 200}},
+		{"compacts every sibling when a gap is too narrow", []int{1, 0, 2}, []int{100, 200, 300}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DefaultNumbering.Arrange(tt.current)
+			if err != nil {
+				t.Fatalf("Arrange() error: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Arrange(%v) = %v, want %v", tt.current, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNumbering_Arrange_TooManySiblings(t *testing.T) {
+	_, err := DefaultNumbering.Arrange(make([]int, 1000))
+
+	if !errors.Is(err, ErrMaxSiblingsReached) {
+		t.Errorf("Arrange() error = %v, want ErrMaxSiblingsReached", err)
+	}
+}
@@ -37,6 +37,7 @@ func BuildOutline(files []ParsedFile) (Outline, []Finding, error) {
 					Type:     FindingDuplicateSID,
 					Severity: SeverityWarning,
 					Message:  fmt.Sprintf("SID %s at MPs %s and %s", f.SID, g.mp, f.MP),
+					SID:      f.SID,
 				})
 			}
 		} else {
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// conflictSuffixRegex matches the copy markers editors and sync tools append
+// to a filename stem: "(1)", "(copy)", "(conflicted copy ...)", " copy 2"
+// and Syncthing's ".sync-conflict-..." suffix.
+var conflictSuffixRegex = regexp.MustCompile(
+	`(?i)(?:\s*\((?:\d+|copy(?:\s+\d+)?|[^)]*conflict[^)]*)\)|\s+copy(?:\s+\d+)?|\.sync-conflict-[^.]*)$`,
+)
+
+var (
+	recoverStemRegex = regexp.MustCompile(`^(` + mpPatternSrc + `)\s*_(.*)$`)
+	sidRegex         = regexp.MustCompile(`^[a-zA-Z0-9]{8,12}$`)
+	letterRegex      = regexp.MustCompile(`^[a-zA-Z]+$`)
+	wordRegex        = regexp.MustCompile(`^(?:[a-z]+|[A-Z]+|[A-Z][a-z]+)$`)
+)
+
+// RecoverFilename recognises a near-miss linemark filename and returns its
+// canonical components. It strips conflict-copy suffixes, tolerates spaces
+// around separators, lowercases the doc type and extension, and accepts a
+// filename whose SID was lost, in which case the returned SID is empty.
+// knownDocTypes are the doc types in use in the project, which tell a SID
+// without digits from a word. The slug is returned as found; callers
+// normalise it.
+func RecoverFilename(filename string, knownDocTypes []string) (ParsedFile, bool) {
+	ext := len(filename) - len(".md")
+	if ext <= 0 || !strings.EqualFold(filename[ext:], ".md") {
+		return ParsedFile{}, false
+	}
+	stem := strings.TrimSpace(filename[:ext])
+	for {
+		trimmed := strings.TrimSpace(conflictSuffixRegex.ReplaceAllString(stem, ""))
+		if trimmed == stem {
+			break
+		}
+		stem = trimmed
+	}
+
+	m := recoverStemRegex.FindStringSubmatch(stem)
+	if m == nil || slices.ContainsFunc(strings.Split(m[1], "-"), isZeroSegment) {
+		return ParsedFile{}, false
+	}
+	fields := strings.Split(m[2], "_")
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+
+	var sid string
+	if len(fields) > 1 && looksLikeSID(fields[0], fields[1], knownDocTypes) {
+		sid, fields = fields[0], fields[1:]
+	}
+	if !letterRegex.MatchString(fields[0]) {
+		return ParsedFile{}, false
+	}
+	slug := strings.Join(fields[1:], "_")
+	if strings.ContainsAny(slug, "/\\\x00") {
+		return ParsedFile{}, false
+	}
+
+	pathParts := strings.Split(m[1], "-")
+	return ParsedFile{
+		MP:        m[1],
+		SID:       sid,
+		DocType:   strings.ToLower(fields[0]),
+		Slug:      slug,
+		PathParts: pathParts,
+		Depth:     len(pathParts),
+	}, true
+}
+
+// looksLikeSID reports whether a field is a SID rather than a doc type,
+// given the field that follows it, which must then be a plausible doc type.
+// Generated SIDs usually contain a digit. One without is accepted only when
+// it is not shaped like a word and a known doc type follows, so that
+// "Research_notes" stays a doc type and slug.
+func looksLikeSID(field, next string, knownDocTypes []string) bool {
+	if !sidRegex.MatchString(field) || !letterRegex.MatchString(next) {
+		return false
+	}
+	if strings.ContainsAny(field, "0123456789") {
+		return true
+	}
+	return !wordRegex.MatchString(field) &&
+		!slices.Contains(knownDocTypes, strings.ToLower(field)) &&
+		slices.Contains(knownDocTypes, strings.ToLower(next))
+}
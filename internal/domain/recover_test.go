@@ -0,0 +1,106 @@
+package domain
+
+import "testing"
+
+func TestRecoverFilename(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		want     ParsedFile
+		wantOK   bool
+	}{
+		{
+			name:     "conflict suffix and spaces in slug",
+			filename: "001_A3F7c9Qx7Lm2_draft_Chapter One (1).md",
+			want:     ParsedFile{MP: "001", SID: "A3F7c9Qx7Lm2", DocType: "draft", Slug: "Chapter One", PathParts: []string{"001"}, Depth: 1},
+			wantOK:   true,
+		},
+		{
+			name:     "uppercase doc type and extension",
+			filename: "001-200_A3F7c9Qx7Lm2_NOTES.MD",
+			want:     ParsedFile{MP: "001-200", SID: "A3F7c9Qx7Lm2", DocType: "notes", PathParts: []string{"001", "200"}, Depth: 2},
+			wantOK:   true,
+		},
+		{
+			name:     "missing SID",
+			filename: "100_draft_hello.md",
+			want:     ParsedFile{MP: "100", DocType: "draft", Slug: "hello", PathParts: []string{"100"}, Depth: 1},
+			wantOK:   true,
+		},
+		{
+			name:     "single-case eight letter doc type is not a SID",
+			filename: "100_synopsis_hello.md",
+			want:     ParsedFile{MP: "100", DocType: "synopsis", Slug: "hello", PathParts: []string{"100"}, Depth: 1},
+			wantOK:   true,
+		},
+		{
+			name:     "capitalised word is not a SID",
+			filename: "001_Research_notes.md",
+			want:     ParsedFile{MP: "001", DocType: "research", Slug: "notes", PathParts: []string{"001"}, Depth: 1},
+			wantOK:   true,
+		},
+		{
+			name:     "SID without digits before a known doc type",
+			filename: "100_AbCdEfGhIjKl_draft_hello.md",
+			want:     ParsedFile{MP: "100", SID: "AbCdEfGhIjKl", DocType: "draft", Slug: "hello", PathParts: []string{"100"}, Depth: 1},
+			wantOK:   true,
+		},
+		{
+			name:     "mixed-case token before an unknown field is not a SID",
+			filename: "100_AbCdEfGhIjKl_chapter.md",
+			want:     ParsedFile{MP: "100", DocType: "abcdefghijkl", Slug: "chapter", PathParts: []string{"100"}, Depth: 1},
+			wantOK:   true,
+		},
+		{
+			name:     "SID with digits before a plausible doc type",
+			filename: "100_A3F7c9Qx7Lm2_beats.md",
+			want:     ParsedFile{MP: "100", SID: "A3F7c9Qx7Lm2", DocType: "beats", PathParts: []string{"100"}, Depth: 1},
+			wantOK:   true,
+		},
+		{
+			name:     "spaces around separators",
+			filename: "100 _ A3F7c9Qx7Lm2 _ notes .md",
+			want:     ParsedFile{MP: "100", SID: "A3F7c9Qx7Lm2", DocType: "notes", PathParts: []string{"100"}, Depth: 1},
+			wantOK:   true,
+		},
+		{
+			name:     "dropbox conflicted copy",
+			filename: "100_A3F7c9Qx7Lm2_notes (conflicted copy 2024-01-01).md",
+			want:     ParsedFile{MP: "100", SID: "A3F7c9Qx7Lm2", DocType: "notes", PathParts: []string{"100"}, Depth: 1},
+			wantOK:   true,
+		},
+		{
+			name:     "syncthing conflict",
+			filename: "100_A3F7c9Qx7Lm2_notes.sync-conflict-20240101-120000-ABCDEFG.md",
+			want:     ParsedFile{MP: "100", SID: "A3F7c9Qx7Lm2", DocType: "notes", PathParts: []string{"100"}, Depth: 1},
+			wantOK:   true,
+		},
+		{
+			name:     "finder copy",
+			filename: "100_A3F7c9Qx7Lm2_draft_hello copy 2.md",
+			want:     ParsedFile{MP: "100", SID: "A3F7c9Qx7Lm2", DocType: "draft", Slug: "hello", PathParts: []string{"100"}, Depth: 1},
+			wantOK:   true,
+		},
+		{"not markdown", "100_A3F7c9Qx7Lm2_notes.txt", ParsedFile{}, false},
+		{"no MP", "README.md", ParsedFile{}, false},
+		{"zero MP segment", "000_draft_hello.md", ParsedFile{}, false},
+		{"nothing after MP", "100_.md", ParsedFile{}, false},
+		{"doc type with digits", "100_A3F7c9Qx7Lm2_draft2.md", ParsedFile{}, false},
+		{"slug with separator", "100_draft_a\\b.md", ParsedFile{}, false},
+		{"bare extension", ".md", ParsedFile{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := RecoverFilename(tt.filename, []string{"draft", "notes"})
+
+			if ok != tt.wantOK {
+				t.Fatalf("RecoverFilename(%q) ok = %v, want %v", tt.filename, ok, tt.wantOK)
+			}
+			if got.MP != tt.want.MP || got.SID != tt.want.SID || got.DocType != tt.want.DocType ||
+				got.Slug != tt.want.Slug || got.Depth != tt.want.Depth || len(got.PathParts) != len(tt.want.PathParts) {
+				t.Errorf("RecoverFilename(%q) = %+v, want %+v", tt.filename, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package domain
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// fieldNameRegex restricts frontmatter field names to characters that fit in dotted config keys.
+var fieldNameRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// FieldRule constrains a single frontmatter field.
+type FieldRule struct {
+	Required bool
+	Allowed  []string
+}
+
+// Schema maps frontmatter field names to their rules.
+type Schema map[string]FieldRule
+
+// FieldViolation describes a frontmatter field that breaks its rule.
+// Missing is set when a required field is absent or empty; otherwise Value
+// is not one of Allowed.
+type FieldViolation struct {
+	Field   string
+	Missing bool
+	Value   string
+	Allowed []string
+}
+
+// Violations checks frontmatter fields against the schema, in field name order.
+// Fields holds scalar values; absent or empty values count as missing.
+func (s Schema) Violations(fields map[string]string) []FieldViolation {
+	var violations []FieldViolation
+	for _, name := range slices.Sorted(maps.Keys(s)) {
+		rule := s[name]
+		value := fields[name]
+		switch {
+		case value == "":
+			if rule.Required {
+				violations = append(violations, FieldViolation{Field: name, Missing: true})
+			}
+		case len(rule.Allowed) > 0 && !slices.Contains(rule.Allowed, value):
+			violations = append(violations, FieldViolation{Field: name, Value: value, Allowed: rule.Allowed})
+		}
+	}
+	return violations
+}
+
+// SchemaFor returns the frontmatter schema for a file of docType on a node
+// at depth. Depth schemas describe the node's draft and are merged over the
+// doc type schema; a field in both takes the depth rule.
+func (c Config) SchemaFor(depth int, docType string) Schema {
+	schema := Schema{}
+	maps.Copy(schema, c.DocTypeSchemas[docType])
+	if docType == DocTypeDraft {
+		maps.Copy(schema, c.DepthSchemas[depth])
+	}
+	return schema
+}
+
+// validateSchemas reports invalid schema targets and field names.
+func (c Config) validateSchemas(invalid func(key, format string, args ...any)) {
+	checkFields := func(prefix string, schema Schema) {
+		for _, name := range slices.Sorted(maps.Keys(schema)) {
+			if !fieldNameRegex.MatchString(name) {
+				invalid(prefix, "%q is not a valid field name", name)
+			}
+		}
+	}
+	for _, depth := range slices.Sorted(maps.Keys(c.DepthSchemas)) {
+		prefix := fmt.Sprintf("%s%d", depthSchemaKeyPrefix, depth)
+		if depth < 1 {
+			invalid(prefix, "depth must be at least 1")
+		}
+		checkFields(prefix, c.DepthSchemas[depth])
+	}
+	for _, docType := range slices.Sorted(maps.Keys(c.DocTypeSchemas)) {
+		prefix := docTypeSchemaKeyPrefix + docType
+		if err := ValidateDocType(docType); err != nil {
+			invalid(prefix, "%q is not a valid doc type", docType)
+		}
+		checkFields(prefix, c.DocTypeSchemas[docType])
+	}
+}
+
+// Schema keys have the form "schemas.depths.<n>.<field>.<required|allowed>"
+// or "schemas.doc_types.<type>.<field>.<required|allowed>".
+const (
+	depthSchemaKeyPrefix   = "schemas.depths."
+	docTypeSchemaKeyPrefix = "schemas.doc_types."
+)
+
+// schemaKeys returns the keys for every configured schema field.
+func (c Config) schemaKeys() []string {
+	var keys []string
+	add := func(prefix string, schema Schema) {
+		for _, name := range slices.Sorted(maps.Keys(schema)) {
+			keys = append(keys, prefix+"."+name+".required", prefix+"."+name+".allowed")
+		}
+	}
+	for _, depth := range slices.Sorted(maps.Keys(c.DepthSchemas)) {
+		add(fmt.Sprintf("%s%d", depthSchemaKeyPrefix, depth), c.DepthSchemas[depth])
+	}
+	for _, docType := range slices.Sorted(maps.Keys(c.DocTypeSchemas)) {
+		add(docTypeSchemaKeyPrefix+docType, c.DocTypeSchemas[docType])
+	}
+	return keys
+}
+
+// schemaConfigKey builds accessors for a schema field key.
+func schemaConfigKey(key string) (configKey, bool) {
+	if rest, ok := strings.CutPrefix(key, depthSchemaKeyPrefix); ok {
+		target, field, attr, ok := splitSchemaKey(rest)
+		depth, err := strconv.Atoi(target)
+		if !ok || err != nil {
+			return configKey{}, false
+		}
+		return fieldRuleKey(key, field, attr, func(c *Config) *map[int]Schema { return &c.DepthSchemas }, depth), true
+	}
+	if rest, ok := strings.CutPrefix(key, docTypeSchemaKeyPrefix); ok {
+		docType, field, attr, ok := splitSchemaKey(rest)
+		if !ok {
+			return configKey{}, false
+		}
+		return fieldRuleKey(key, field, attr, func(c *Config) *map[string]Schema { return &c.DocTypeSchemas }, docType), true
+	}
+	return configKey{}, false
+}
+
+// splitSchemaKey splits "<target>.<field>.<attr>", accepting only known attributes.
+func splitSchemaKey(rest string) (target, field, attr string, ok bool) {
+	parts := strings.Split(rest, ".")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+		return "", "", "", false
+	}
+	if parts[2] != "required" && parts[2] != "allowed" {
+		return "", "", "", false
+	}
+	return parts[0], parts[1], parts[2], true
+}
+
+// fieldRuleKey builds accessors for one attribute of a field rule stored in
+// a schema map. Set copies the map so configs sharing it are not mutated,
+// and drops rules and schemas that become empty.
+func fieldRuleKey[K comparable](name, field, attr string, schemas func(*Config) *map[K]Schema, target K) configKey {
+	return configKey{
+		name: name,
+		get: func(c Config) string {
+			rule := (*schemas(&c))[target][field]
+			if attr == "required" {
+				return strconv.FormatBool(rule.Required)
+			}
+			return strings.Join(rule.Allowed, ",")
+		},
+		set: func(c *Config, v string) error {
+			rule := (*schemas(c))[target][field]
+			if attr == "required" {
+				b, err := strconv.ParseBool(strings.TrimSpace(v))
+				if err != nil {
+					return fmt.Errorf("%w: %s: %q is not a boolean", ErrInvalidConfig, name, v)
+				}
+				rule.Required = b
+			} else {
+				rule.Allowed = splitList(v)
+			}
+
+			updated := maps.Clone(*schemas(c))
+			if updated == nil {
+				updated = map[K]Schema{}
+			}
+			schema := maps.Clone(updated[target])
+			if schema == nil {
+				schema = Schema{}
+			}
+			if rule.Required || len(rule.Allowed) > 0 {
+				schema[field] = rule
+			} else {
+				delete(schema, field)
+			}
+			if len(schema) > 0 {
+				updated[target] = schema
+			} else {
+				delete(updated, target)
+			}
+			*schemas(c) = updated
+			return nil
+		},
+	}
+}
@@ -0,0 +1,204 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSchema_Violations(t *testing.T) {
+	schema := Schema{
+		"pov":    {Required: true},
+		"status": {Required: true, Allowed: []string{"draft", "revised", "final"}},
+		"mood":   {Allowed: []string{"dark", "light"}},
+	}
+
+	tests := []struct {
+		name   string
+		fields map[string]string
+		want   []FieldViolation
+	}{
+		{
+			name:   "all valid",
+			fields: map[string]string{"pov": "Ann", "status": "draft", "mood": "dark"},
+		},
+		{
+			name:   "optional field may be absent",
+			fields: map[string]string{"pov": "Ann", "status": "final"},
+		},
+		{
+			name:   "missing and empty required fields",
+			fields: map[string]string{"pov": ""},
+			want: []FieldViolation{
+				{Field: "pov", Missing: true},
+				{Field: "status", Missing: true},
+			},
+		},
+		{
+			name:   "values outside allowed list",
+			fields: map[string]string{"pov": "Ann", "status": "wip", "mood": "grey"},
+			want: []FieldViolation{
+				{Field: "mood", Value: "grey", Allowed: []string{"dark", "light"}},
+				{Field: "status", Value: "wip", Allowed: []string{"draft", "revised", "final"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := schema.Violations(tt.fields)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Violations() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_SchemaFor(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.DocTypeSchemas = map[string]Schema{
+		"draft": {"status": {Allowed: []string{"draft", "final"}}},
+		"notes": {"tags": {Required: true}},
+	}
+	cfg.DepthSchemas = map[int]Schema{
+		3: {"pov": {Required: true}, "status": {Required: true, Allowed: []string{"draft"}}},
+	}
+
+	tests := []struct {
+		name    string
+		depth   int
+		docType string
+		want    Schema
+	}{
+		{"draft at unconfigured depth", 1, "draft", Schema{"status": {Allowed: []string{"draft", "final"}}}},
+		{"depth rule wins for draft", 3, "draft", Schema{"pov": {Required: true}, "status": {Required: true, Allowed: []string{"draft"}}}},
+		{"depth schema ignored for other doc types", 3, "notes", Schema{"tags": {Required: true}}},
+		{"nothing configured", 2, "beats", Schema{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cfg.SchemaFor(tt.depth, tt.docType); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SchemaFor() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_SchemaKeys_GetSet(t *testing.T) {
+	cfg := DefaultConfig()
+
+	steps := []struct{ key, value string }{
+		{"schemas.depths.3.pov.required", "true"},
+		{"schemas.depths.3.status.allowed", "draft,revised,final"},
+		{"schemas.doc_types.notes.tags.required", "true"},
+		{"schemas.doc_types.notes.tags.required", "false"},
+	}
+	for _, step := range steps {
+		if err := cfg.Set(step.key, step.value); err != nil {
+			t.Fatalf("Set(%q) error: %v", step.key, err)
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("config invalid: %v", err)
+	}
+
+	gets := map[string]string{
+		"schemas.depths.3.pov.required":         "true",
+		"schemas.depths.3.status.allowed":       "draft,revised,final",
+		"schemas.depths.3.status.required":      "false",
+		"schemas.doc_types.notes.tags.required": "false",
+	}
+	for key, want := range gets {
+		if got, err := cfg.Get(key); err != nil || got != want {
+			t.Errorf("Get(%q) = %q, %v; want %q", key, got, err, want)
+		}
+	}
+
+	wantKeys := []string{
+		"schemas.depths.3.pov.required", "schemas.depths.3.pov.allowed",
+		"schemas.depths.3.status.required", "schemas.depths.3.status.allowed",
+	}
+	if got := cfg.Keys(); !slices.Equal(got[len(got)-len(wantKeys):], wantKeys) {
+		t.Errorf("Keys() = %v, want to end with %v", got, wantKeys)
+	}
+	if _, ok := cfg.DocTypeSchemas["notes"]; ok {
+		t.Error("setting required=false on a bare field should drop the notes schema")
+	}
+}
+
+func TestConfig_Keys_IncludesDocTypeSchemas(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.DocTypeSchemas = map[string]Schema{"notes": {"tags": {Required: true}}}
+
+	keys := cfg.Keys()
+
+	want := []string{"schemas.doc_types.notes.tags.required", "schemas.doc_types.notes.tags.allowed"}
+	if !slices.Equal(keys[len(keys)-2:], want) {
+		t.Errorf("Keys() = %v, want to end with %v", keys, want)
+	}
+}
+
+func TestConfig_SchemaSet_ClearingDropsField(t *testing.T) {
+	cfg := DefaultConfig()
+	_ = cfg.Set("schemas.depths.3.pov.required", "true")
+	shared := cfg
+
+	if err := cfg.Set("schemas.depths.3.pov.required", "false"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cfg.DepthSchemas) != 0 {
+		t.Errorf("DepthSchemas = %v, want empty", cfg.DepthSchemas)
+	}
+	if !shared.DepthSchemas[3]["pov"].Required {
+		t.Error("Set mutated a schema shared with another config")
+	}
+}
+
+func TestConfig_SchemaKey_Errors(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := cfg.Set("schemas.depths.3.pov.required", "maybe"); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("non-boolean required = %v, want ErrInvalidConfig", err)
+	}
+
+	for _, key := range []string{
+		"schemas.depths.x.pov.required",
+		"schemas.depths.3.pov.optional",
+		"schemas.depths.3.pov",
+		"schemas.doc_types..pov.required",
+		"schemas.doc_types.notes.tags.allowed.extra",
+		"schemas.other.notes.tags.allowed",
+	} {
+		if _, err := DefaultConfig().Get(key); !errors.Is(err, ErrUnknownConfigKey) {
+			t.Errorf("Get(%q) = %v, want ErrUnknownConfigKey", key, err)
+		}
+	}
+}
+
+func TestConfig_Validate_Schemas(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		wantKey string
+	}{
+		{"invalid depth", func(c *Config) { c.DepthSchemas = map[int]Schema{0: {"pov": {Required: true}}} }, "schemas.depths.0"},
+		{"invalid field name", func(c *Config) { c.DepthSchemas = map[int]Schema{3: {"p o v": {Required: true}}} }, "schemas.depths.3"},
+		{"invalid doc type", func(c *Config) { c.DocTypeSchemas = map[string]Schema{"Notes": {"tags": {Required: true}}} }, "schemas.doc_types.Notes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.modify(&cfg)
+
+			err := cfg.Validate()
+
+			if !errors.Is(err, ErrInvalidConfig) || !strings.Contains(err.Error(), tt.wantKey) {
+				t.Errorf("Validate() = %v, want ErrInvalidConfig mentioning %q", err, tt.wantKey)
+			}
+		})
+	}
+}
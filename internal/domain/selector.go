@@ -5,12 +5,13 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"unicode"
 )
 
 // ErrInvalidSelector is returned when a selector string cannot be parsed.
 var ErrInvalidSelector = errors.New("invalid selector")
 
-// SelectorKind distinguishes between MP and SID selectors.
+// SelectorKind distinguishes between MP, SID, title and slug selectors.
 type SelectorKind int
 
 const (
@@ -18,32 +19,62 @@ const (
 	SelectorMP SelectorKind = iota
 	// SelectorSID indicates a stable ID selector.
 	SelectorSID
+	// SelectorTitle indicates a selector matching node titles ("title:").
+	SelectorTitle
+	// SelectorSlug indicates a selector matching draft slugs ("slug:").
+	SelectorSlug
 )
 
 const (
-	mpPrefix  = "mp:"
-	sidPrefix = "sid:"
+	mpPrefix    = "mp:"
+	sidPrefix   = "sid:"
+	titlePrefix = "title:"
+	slugPrefix  = "slug:"
 )
 
 var (
-	mpPattern  = regexp.MustCompile(`^\d{3}(?:-\d{3})*$`)
+	mpPattern  = regexp.MustCompile(`^` + mpPatternSrc + `$`)
 	sidPattern = regexp.MustCompile(`^[A-Za-z0-9]{8,12}$`)
 )
 
-// Selector is a value object representing a parsed node reference.
+// Selector is a value object representing a parsed node reference. A
+// relative selector names a base node and the steps leading from it to the
+// node it refers to.
 type Selector struct {
 	kind     SelectorKind
 	value    string
 	explicit bool
+	steps    []relStep
+	last     bool
 }
 
 // ParseSelector parses a selector string into a Selector value object.
+// Besides an MP or SID, optionally prefixed with "mp:" or "sid:", it accepts
+// "title:<name>" and "slug:<name>", which match nodes by name (see
+// Outline.Find), and relative selectors (see parseRelative).
 func ParseSelector(input string) (Selector, error) {
 	input = strings.TrimSpace(input)
 	if input == "" {
 		return Selector{}, fmt.Errorf("%w: empty input", ErrInvalidSelector)
 	}
 
+	if rest, ok := strings.CutPrefix(input, lastPrefix); ok {
+		sel, err := ParseSelector(rest)
+		if err != nil || sel.last {
+			return Selector{}, selectorErr(input)
+		}
+		sel.last = true
+		return sel, nil
+	}
+	sel, err := parseBase(input)
+	if err != nil {
+		return parseRelative(input, err)
+	}
+	return sel, nil
+}
+
+// parseBase parses a selector naming a node by MP, SID, title or slug.
+func parseBase(input string) (Selector, error) {
 	if value, ok := strings.CutPrefix(input, mpPrefix); ok {
 		if !isValidMP(value) {
 			return Selector{}, selectorErr(input)
@@ -56,6 +87,12 @@ func ParseSelector(input string) (Selector, error) {
 		}
 		return Selector{kind: SelectorSID, value: value, explicit: true}, nil
 	}
+	if value, ok := strings.CutPrefix(input, titlePrefix); ok {
+		return nameSelector(input, SelectorTitle, value)
+	}
+	if value, ok := strings.CutPrefix(input, slugPrefix); ok {
+		return nameSelector(input, SelectorSlug, value)
+	}
 
 	if strings.Contains(input, ":") {
 		return Selector{}, selectorErr(input)
@@ -71,6 +108,18 @@ func ParseSelector(input string) (Selector, error) {
 	return Selector{}, selectorErr(input)
 }
 
+// nameSelector returns a title or slug selector, rejecting names without a
+// letter or digit to match on. Relative steps after the name are split off
+// (see splitNameSteps).
+func nameSelector(input string, kind SelectorKind, value string) (Selector, error) {
+	value, steps := splitNameSteps(value)
+	value = strings.TrimSpace(value)
+	if foldName(value) == "" {
+		return Selector{}, selectorErr(input)
+	}
+	return Selector{kind: kind, value: value, explicit: true, steps: steps}, nil
+}
+
 func selectorErr(input string) error {
 	return fmt.Errorf("%w: %q", ErrInvalidSelector, input)
 }
@@ -80,7 +129,7 @@ func isValidMP(s string) bool {
 		return false
 	}
 	for _, seg := range strings.Split(s, "-") {
-		if seg == "000" {
+		if isZeroSegment(seg) {
 			return false
 		}
 	}
@@ -91,30 +140,65 @@ func isValidSID(s string) bool {
 	return sidPattern.MatchString(s)
 }
 
-// Kind returns the selector kind (SelectorMP or SelectorSID).
+// foldName reduces a title or slug to its lowercased letters and digits, so
+// that "Chapter One", "chapter-one" and "chapter one!" compare equal.
+func foldName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, s)
+}
+
+// Kind returns the selector kind, which for a relative selector is the kind
+// of its base.
 func (s Selector) Kind() SelectorKind {
 	return s.kind
 }
 
-// Value returns the selector value without any prefix.
+// ByName reports whether the selector matches nodes by title or slug rather
+// than naming one by MP or SID.
+func (s Selector) ByName() bool {
+	return s.kind == SelectorTitle || s.kind == SelectorSlug
+}
+
+// Direct reports whether the selector names a node by MP or SID alone, so
+// that it can be matched against filenames without building the outline.
+func (s Selector) Direct() bool {
+	return !s.ByName() && !s.Relative()
+}
+
+// Value returns the selector value without any prefix. For a relative
+// selector it is the value of its base.
 func (s Selector) Value() string {
 	return s.value
 }
 
 // Explicit returns whether the selector was explicitly prefixed (e.g., "mp:" or "sid:").
+// Title and slug selectors are always explicit.
 func (s Selector) Explicit() bool {
 	return s.explicit
 }
 
 // String returns the string representation, including prefix if explicitly provided.
 func (s Selector) String() string {
+	base := s.value
 	if s.explicit {
 		switch s.kind {
 		case SelectorMP:
-			return mpPrefix + s.value
+			base = mpPrefix + s.value
 		case SelectorSID:
-			return sidPrefix + s.value
+			base = sidPrefix + s.value
+		case SelectorTitle:
+			base = titlePrefix + s.value
+		case SelectorSlug:
+			base = slugPrefix + s.value
 		}
 	}
-	return s.value
+	base += formatSteps(s.steps)
+	if s.last {
+		return lastPrefix + base
+	}
+	return base
 }
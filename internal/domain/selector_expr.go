@@ -0,0 +1,306 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// ErrNodeNotFound is returned when a selector matches no node.
+var ErrNodeNotFound = errors.New("node not found")
+
+// ErrAmbiguousSelector is returned when a selector matches more than one node.
+var ErrAmbiguousSelector = errors.New("ambiguous selector")
+
+// ErrBadRange is returned when the ends of a sibling range are not siblings.
+var ErrBadRange = errors.New("range ends are not siblings")
+
+// ExprKind distinguishes the forms of a selector expression.
+type ExprKind int
+
+const (
+	// ExprNode selects the single node a Selector names.
+	ExprNode ExprKind = iota
+	// ExprSubtree selects a node and all its descendants ("<sel>/**").
+	ExprSubtree
+	// ExprChildren selects a node's children ("<sel>/*").
+	ExprChildren
+	// ExprRange selects a run of siblings, ends included ("<sel>..<sel>").
+	ExprRange
+	// ExprTitle selects the nodes whose title matches a glob ("title:<glob>").
+	ExprTitle
+	// ExprType selects the nodes that have a document type ("type:<type>").
+	ExprType
+)
+
+const (
+	subtreeSuffix  = "/**"
+	childrenSuffix = "/*"
+	rangeSep       = ".."
+	typePrefix     = "type:"
+	globChars      = "*?["
+)
+
+// SelectorExpr is a value object representing a parsed expression that
+// selects a set of nodes. A plain Selector is the expression selecting
+// just the node it names.
+type SelectorExpr struct {
+	kind    ExprKind
+	from    Selector
+	to      Selector
+	pattern string
+}
+
+// ParseSelectorExpr parses a selector expression: a selector, a subtree
+// ("001-200/**"), a node's children ("001-200/*"), a sibling range
+// ("001-200..001-500"), a title glob ("title:Chapter*", ignoring case) or a
+// document type ("type:notes"). A "title:" without glob characters is the
+// title selector naming a single node, and takes the rest of the input.
+func ParseSelectorExpr(input string) (SelectorExpr, error) {
+	input = strings.TrimSpace(input)
+
+	if pattern, ok := strings.CutPrefix(input, titlePrefix); ok {
+		pattern = strings.TrimSpace(pattern)
+		if !strings.ContainsAny(pattern, globChars) {
+			sel, err := ParseSelector(input)
+			if err != nil {
+				return SelectorExpr{}, err
+			}
+			return SelectorExpr{kind: ExprNode, from: sel}, nil
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return SelectorExpr{}, selectorErr(input)
+		}
+		return SelectorExpr{kind: ExprTitle, pattern: pattern}, nil
+	}
+	if docType, ok := strings.CutPrefix(input, typePrefix); ok {
+		if err := ValidateDocType(docType); err != nil {
+			return SelectorExpr{}, selectorErr(input)
+		}
+		return SelectorExpr{kind: ExprType, pattern: docType}, nil
+	}
+
+	kind := ExprNode
+	base := input
+	if b, ok := strings.CutSuffix(input, subtreeSuffix); ok {
+		kind, base = ExprSubtree, b
+	} else if b, ok := strings.CutSuffix(input, childrenSuffix); ok {
+		kind, base = ExprChildren, b
+	} else if from, to, ok := strings.Cut(input, rangeSep); ok {
+		fromSel, err := ParseSelector(from)
+		if err != nil {
+			return SelectorExpr{}, selectorErr(input)
+		}
+		toSel, err := ParseSelector(to)
+		if err != nil {
+			return SelectorExpr{}, selectorErr(input)
+		}
+		return SelectorExpr{kind: ExprRange, from: fromSel, to: toSel}, nil
+	}
+
+	sel, err := ParseSelector(base)
+	if err != nil {
+		if kind != ExprNode {
+			return SelectorExpr{}, selectorErr(input)
+		}
+		return SelectorExpr{}, err
+	}
+	return SelectorExpr{kind: kind, from: sel}, nil
+}
+
+// Kind returns the expression kind.
+func (e SelectorExpr) Kind() ExprKind {
+	return e.kind
+}
+
+// Single returns the selector of an expression that names a single node,
+// and false for expressions that select a set.
+func (e SelectorExpr) Single() (Selector, bool) {
+	return e.from, e.kind == ExprNode
+}
+
+// ByName reports whether the expression matches nodes by title or slug, so
+// that resolving it needs the nodes' titles.
+func (e SelectorExpr) ByName() bool {
+	return e.kind == ExprTitle || e.from.ByName() || e.to.ByName()
+}
+
+// String returns the expression in the form it is parsed from.
+func (e SelectorExpr) String() string {
+	switch e.kind {
+	case ExprSubtree:
+		return e.from.String() + subtreeSuffix
+	case ExprChildren:
+		return e.from.String() + childrenSuffix
+	case ExprRange:
+		return e.from.String() + rangeSep + e.to.String()
+	case ExprTitle:
+		return titlePrefix + e.pattern
+	case ExprType:
+		return typePrefix + e.pattern
+	}
+	return e.from.String()
+}
+
+// Find returns the node a selector names. A SID shared by several nodes is
+// ambiguous. Title and slug selectors match the node whose title or draft
+// slug equals the name, or failing that starts with it, or failing that
+// contains its letters and digits in order; names compare ignoring case and
+// everything but letters and digits. Several matches in the first tier that
+// has any are ambiguous, and the error is an *AmbiguousSelectorError listing
+// them. A relative selector then leads from the node its base names; a step
+// to a node that does not exist fails with ErrNoRelative.
+func (o Outline) Find(sel Selector) (Node, error) {
+	var matches []Node
+	switch sel.Kind() {
+	case SelectorTitle:
+		matches = matchName(o.Nodes, sel.Value(), func(n Node) string { return n.Title })
+	case SelectorSlug:
+		matches = matchName(o.Nodes, sel.Value(), Node.Slug)
+	default:
+		for _, n := range o.Nodes {
+			if (sel.Kind() == SelectorMP && n.MP.String() == sel.Value()) || (sel.Kind() == SelectorSID && n.SID == sel.Value()) {
+				matches = append(matches, n)
+			}
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return Node{}, fmt.Errorf("%s: %w", sel, ErrNodeNotFound)
+	case 1:
+		return o.follow(matches[0], sel)
+	}
+	err := &AmbiguousSelectorError{Selector: sel.String()}
+	for _, n := range matches {
+		err.Candidates = append(err.Candidates, Candidate{MP: n.MP.String(), SID: n.SID, Title: n.Title})
+	}
+	return Node{}, err
+}
+
+// matchName returns the nodes whose folded name equals the folded query, or
+// failing that starts with it, or failing that contains it as a subsequence.
+func matchName(nodes []Node, query string, name func(Node) string) []Node {
+	q := foldName(query)
+	tiers := []func(string) bool{
+		func(s string) bool { return s == q },
+		func(s string) bool { return strings.HasPrefix(s, q) },
+		func(s string) bool { return isSubsequence(q, s) },
+	}
+	for _, match := range tiers {
+		var found []Node
+		for _, n := range nodes {
+			if match(foldName(name(n))) {
+				found = append(found, n)
+			}
+		}
+		if len(found) > 0 {
+			return found
+		}
+	}
+	return nil
+}
+
+// isSubsequence reports whether the runes of sub appear in s in order.
+func isSubsequence(sub, s string) bool {
+	rest := []rune(sub)
+	for _, r := range s {
+		if len(rest) > 0 && rest[0] == r {
+			rest = rest[1:]
+		}
+	}
+	return len(rest) == 0
+}
+
+// Candidate identifies one of the nodes an ambiguous selector matched.
+type Candidate struct {
+	MP    string
+	SID   string
+	Title string
+}
+
+// AmbiguousSelectorError is returned when a selector matches more than one
+// node. It wraps ErrAmbiguousSelector.
+type AmbiguousSelectorError struct {
+	Selector   string
+	Candidates []Candidate
+}
+
+// Error implements the error interface, listing one candidate per line.
+func (e *AmbiguousSelectorError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %v; matches %d nodes:", e.Selector, ErrAmbiguousSelector, len(e.Candidates))
+	for _, c := range e.Candidates {
+		fmt.Fprintf(&b, "\n  %s  %s  %s", c.MP, c.SID, c.Title)
+	}
+	return b.String()
+}
+
+// Unwrap returns ErrAmbiguousSelector.
+func (e *AmbiguousSelectorError) Unwrap() error {
+	return ErrAmbiguousSelector
+}
+
+// Select returns the nodes an expression selects, in outline order. Title
+// and type expressions may select no nodes; the others fail when a node
+// they name does not exist.
+func (o Outline) Select(e SelectorExpr) ([]Node, error) {
+	var match func(Node) bool
+	switch e.kind {
+	case ExprTitle:
+		pattern := strings.ToLower(e.pattern)
+		match = func(n Node) bool {
+			ok, _ := path.Match(pattern, strings.ToLower(n.Title))
+			return ok
+		}
+	case ExprType:
+		match = func(n Node) bool {
+			for _, d := range n.Documents {
+				if d.Type == e.pattern {
+					return true
+				}
+			}
+			return false
+		}
+	case ExprRange:
+		from, err := o.Find(e.from)
+		if err != nil {
+			return nil, err
+		}
+		to, err := o.Find(e.to)
+		if err != nil {
+			return nil, err
+		}
+		parent, _ := from.MP.Parent()
+		if toParent, _ := to.MP.Parent(); !parent.Equal(toParent) {
+			return nil, fmt.Errorf("%s: %w", e, ErrBadRange)
+		}
+		low, high := min(from.MP.LastSegment(), to.MP.LastSegment()), max(from.MP.LastSegment(), to.MP.LastSegment())
+		match = func(n Node) bool {
+			p, _ := n.MP.Parent()
+			return p.Equal(parent) && n.MP.LastSegment() >= low && n.MP.LastSegment() <= high
+		}
+	default:
+		base, err := o.Find(e.from)
+		if err != nil {
+			return nil, err
+		}
+		if e.kind == ExprNode {
+			return []Node{base}, nil
+		}
+		match = func(n Node) bool {
+			if e.kind == ExprChildren {
+				return base.MP.IsAncestorOf(n.MP) && n.MP.Depth() == base.MP.Depth()+1
+			}
+			return n.MP.Equal(base.MP) || base.MP.IsAncestorOf(n.MP)
+		}
+	}
+
+	nodes := []Node{}
+	for _, n := range o.Nodes {
+		if match(n) {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes, nil
+}
@@ -0,0 +1,258 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseSelectorExpr(t *testing.T) {
+	tests := []struct {
+		input  string
+		kind   ExprKind
+		single bool
+		str    string
+	}{
+		{"001-200", ExprNode, true, "001-200"},
+		{" sid:A3F7c9Qx7Lm2 ", ExprNode, true, "sid:A3F7c9Qx7Lm2"},
+		{"001-200/**", ExprSubtree, false, "001-200/**"},
+		{"A3F7c9Qx7Lm2/*", ExprChildren, false, "A3F7c9Qx7Lm2/*"},
+		{"001-200..001-500", ExprRange, false, "001-200..001-500"},
+		{"mp:001..sid:A3F7c9Qx7Lm2", ExprRange, false, "mp:001..sid:A3F7c9Qx7Lm2"},
+		{"title: Chapter*", ExprTitle, false, "title:Chapter*"},
+		{"title:Chapter One", ExprNode, true, "title:Chapter One"},
+		{"title:Wait...", ExprNode, true, "title:Wait..."},
+		{"slug:chapter-one/**", ExprSubtree, false, "slug:chapter-one/**"},
+		{"type:notes", ExprType, false, "type:notes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			expr, err := ParseSelectorExpr(tt.input)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if expr.Kind() != tt.kind {
+				t.Errorf("Kind() = %v, want %v", expr.Kind(), tt.kind)
+			}
+			if _, single := expr.Single(); single != tt.single {
+				t.Errorf("Single() = %v, want %v", single, tt.single)
+			}
+			if expr.String() != tt.str {
+				t.Errorf("String() = %q, want %q", expr.String(), tt.str)
+			}
+		})
+	}
+}
+
+func TestParseSelectorExpr_Invalid(t *testing.T) {
+	for _, input := range []string{"", "title:", "title:[", "type:", "type:bad type", "nope!/**", "001/*x", "001..", "..001", "001..nope!", "nope!"} {
+		t.Run(input, func(t *testing.T) {
+			if _, err := ParseSelectorExpr(input); !errors.Is(err, ErrInvalidSelector) {
+				t.Errorf("ParseSelectorExpr(%q) error = %v, want ErrInvalidSelector", input, err)
+			}
+		})
+	}
+}
+
+// selectTestOutline returns two root nodes, the first with three children,
+// the second of which has a child.
+func selectTestOutline() Outline {
+	node := func(mp, sid, title string, types ...string) Node {
+		path, _ := NewMaterializedPath(mp)
+		n := Node{MP: path, SID: sid, Title: title}
+		slug := strings.ToLower(strings.ReplaceAll(title, " ", "-"))
+		for _, t := range types {
+			n.Documents = append(n.Documents, Document{Type: t, Filename: GenerateFilename(mp, sid, t, slug)})
+		}
+		return n
+	}
+	return Outline{Nodes: []Node{
+		node("100", "SID001AABB", "Part One", "draft", "notes"),
+		node("100-100", "SID002CCDD", "Chapter One", "draft"),
+		node("100-200", "SID003EEFF", "Chapter Two", "draft", "notes"),
+		node("100-200-100", "SID004GGHH", "Scene", "draft"),
+		node("100-300", "SID005IIJJ", "Interlude", "draft"),
+		node("200", "SID006KKLL", "Part Two", "draft"),
+	}}
+}
+
+func TestOutline_Select(t *testing.T) {
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{"100-200", []string{"100-200"}},
+		{"SID003EEFF", []string{"100-200"}},
+		{"100-200/**", []string{"100-200", "100-200-100"}},
+		{"100/*", []string{"100-100", "100-200", "100-300"}},
+		{"100-100..100-200", []string{"100-100", "100-200"}},
+		{"100-300..SID002CCDD", []string{"100-100", "100-200", "100-300"}},
+		{"100..200", []string{"100", "200"}},
+		{"title:chapter*", []string{"100-100", "100-200"}},
+		{"title:part ???", []string{"100", "200"}},
+		{"title:Epilogue*", []string{}},
+		{"type:notes", []string{"100", "100-200"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			expr, err := ParseSelectorExpr(tt.expr)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+
+			nodes, err := selectTestOutline().Select(expr)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := []string{}
+			for _, n := range nodes {
+				got = append(got, n.MP.String())
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Select(%s) = %v, want %v", tt.expr, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Select(%s) = %v, want %v", tt.expr, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestOutline_Select_Errors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want error
+	}{
+		{"300", ErrNodeNotFound},
+		{"300/**", ErrNodeNotFound},
+		{"300..200", ErrNodeNotFound},
+		{"100..300", ErrNodeNotFound},
+		{"100..100-200", ErrBadRange},
+		{"100-100..100-200-100", ErrBadRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			expr, _ := ParseSelectorExpr(tt.expr)
+
+			_, err := selectTestOutline().Select(expr)
+
+			if !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutline_Find_AmbiguousSID(t *testing.T) {
+	o := selectTestOutline()
+	o.Nodes[5].SID = "SID001AABB"
+	sel, _ := ParseSelector("SID001AABB")
+
+	_, err := o.Find(sel)
+
+	if !errors.Is(err, ErrAmbiguousSelector) {
+		t.Errorf("error = %v, want ErrAmbiguousSelector", err)
+	}
+}
+
+func TestOutline_Find_ByName(t *testing.T) {
+	tests := []struct {
+		selector string
+		want     string
+	}{
+		{"title:chapter two", "100-200"},
+		{"title:CHAPTER-ONE", "100-100"},
+		{"title:inter", "100-300"},
+		{"title:scn", "100-200-100"},
+		{"title:part o", "100"},
+		{"slug:part-two", "200"},
+		{"slug:intrlde", "100-300"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			sel, _ := ParseSelector(tt.selector)
+
+			n, err := selectTestOutline().Find(sel)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if n.MP.String() != tt.want {
+				t.Errorf("Find(%s) = %s, want %s", tt.selector, n.MP, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutline_Find_ByNameErrors(t *testing.T) {
+	o := selectTestOutline()
+	o.Nodes = append(o.Nodes, Node{MP: o.Nodes[5].MP, SID: "SID007MMNN", Title: "No Draft"})
+
+	tests := []struct {
+		selector       string
+		want           error
+		wantCandidates []string
+	}{
+		{"title:epilogue", ErrNodeNotFound, nil},
+		{"slug:no-draft", ErrNodeNotFound, nil},
+		{"title:chapter", ErrAmbiguousSelector, []string{"100-100", "100-200"}},
+		{"title:prt", ErrAmbiguousSelector, []string{"100", "100-200", "200"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			sel, _ := ParseSelector(tt.selector)
+
+			_, err := o.Find(sel)
+
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("error = %v, want %v", err, tt.want)
+			}
+			var ambiguous *AmbiguousSelectorError
+			if !errors.As(err, &ambiguous) {
+				return
+			}
+			var got []string
+			for _, c := range ambiguous.Candidates {
+				got = append(got, c.MP)
+			}
+			if !slices.Equal(got, tt.wantCandidates) {
+				t.Errorf("candidates = %v, want %v", got, tt.wantCandidates)
+			}
+		})
+	}
+}
+
+func TestAmbiguousSelectorError_Error(t *testing.T) {
+	err := &AmbiguousSelectorError{Selector: "title:chapter", Candidates: []Candidate{
+		{MP: "100-100", SID: "SID002CCDD", Title: "Chapter One"},
+		{MP: "100-200", SID: "SID003EEFF", Title: "Chapter Two"},
+	}}
+
+	want := "title:chapter: ambiguous selector; matches 2 nodes:\n" +
+		"  100-100  SID002CCDD  Chapter One\n" +
+		"  100-200  SID003EEFF  Chapter Two"
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestSelectorExpr_ByName(t *testing.T) {
+	for input, want := range map[string]bool{
+		"100/*": false, "type:notes": false, "title:Ch*": true, "title:Chapter": true, "100..slug:interlude": true,
+	} {
+		expr, _ := ParseSelectorExpr(input)
+		if got := expr.ByName(); got != want {
+			t.Errorf("ParseSelectorExpr(%q).ByName() = %v, want %v", input, got, want)
+		}
+	}
+}
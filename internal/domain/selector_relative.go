@@ -0,0 +1,202 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrNoRelative is returned when the node a relative selector leads to does
+// not exist. It wraps ErrNodeNotFound.
+var ErrNoRelative = fmt.Errorf("relative %w", ErrNodeNotFound)
+
+const (
+	lastPrefix = "last:"
+	stepChars  = "^+-/"
+)
+
+// relStep is one step of a relative selector: '^' to the parent, '+' n
+// siblings on (back when n is negative), or '/' to the nth child.
+type relStep struct {
+	op byte
+	n  int
+}
+
+// parseRelative parses a relative selector: an MP or SID followed by steps,
+// "^" (parent), "+N" and "-N" (the Nth sibling after or before) and "/N"
+// (the Nth child, from 1), as in "A3F7c9Qx7Lm2^/2". Input that is a valid
+// MP is never relative, so "100-200" names an MP rather than the 200th
+// sibling before 100, whatever the project's segment width. Conversely, a
+// dash followed by fewer than three or more than six digits cannot start an
+// MP segment and is a step: "100-2" is the 2nd sibling before 100, not a
+// child of it. Title and slug selectors take their steps in nameSelector.
+// baseErr is returned when input is not relative.
+func parseRelative(input string, baseErr error) (Selector, error) {
+	for i := len(input) - 1; i > 0; i-- {
+		if !strings.ContainsRune(stepChars, rune(input[i])) {
+			continue
+		}
+		steps, ok := parseSteps(input[i:])
+		if !ok {
+			continue
+		}
+		sel, err := parseBase(input[:i])
+		if err != nil {
+			continue
+		}
+		sel.steps = steps
+		return sel, nil
+	}
+	return Selector{}, baseErr
+}
+
+// splitNameSteps splits the steps off the end of a title or slug name. The
+// run of steps must start with "^", "+" or "/", which slugs never contain;
+// "-N" straight after the name stays part of it, as in "slug:part-2".
+// Matching ignores punctuation, so a title that itself ends like a step
+// ("Part 1/2") is still found without it ("title:Part 12").
+func splitNameSteps(name string) (string, []relStep) {
+	for i := 1; i < len(name); i++ {
+		if name[i] == '-' || !strings.ContainsRune(stepChars, rune(name[i])) {
+			continue
+		}
+		if steps, ok := parseSteps(name[i:]); ok {
+			return name[:i], steps
+		}
+	}
+	return name, nil
+}
+
+// parseSteps parses a run of steps, rejecting zero and zero-padded counts.
+func parseSteps(s string) ([]relStep, bool) {
+	var steps []relStep
+	for s != "" {
+		op := s[0]
+		if op == '^' {
+			steps = append(steps, relStep{op: op})
+			s = s[1:]
+			continue
+		}
+		if !strings.ContainsRune(stepChars, rune(op)) {
+			return nil, false
+		}
+		end := 1
+		for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+			end++
+		}
+		digits := s[1:end]
+		n, err := strconv.Atoi(digits)
+		if err != nil || digits[0] == '0' {
+			return nil, false
+		}
+		if op == '-' {
+			op, n = '+', -n
+		}
+		steps = append(steps, relStep{op: op, n: n})
+		s = s[end:]
+	}
+	return steps, true
+}
+
+// formatSteps returns steps in the form they are parsed from.
+func formatSteps(steps []relStep) string {
+	var b strings.Builder
+	for _, st := range steps {
+		switch {
+		case st.op == '^':
+			b.WriteByte('^')
+		case st.op == '/':
+			fmt.Fprintf(&b, "/%d", st.n)
+		default:
+			fmt.Fprintf(&b, "%+d", st.n)
+		}
+	}
+	return b.String()
+}
+
+// Relative reports whether the selector leads from its base to another
+// node, by steps or a "last:" prefix.
+func (s Selector) Relative() bool {
+	return len(s.steps) > 0 || s.last
+}
+
+// follow takes a relative selector's steps from the base node n, then to the
+// last child for a "last:" selector.
+func (o Outline) follow(n Node, sel Selector) (Node, error) {
+	for _, st := range sel.steps {
+		var err error
+		switch st.op {
+		case '^':
+			n, err = o.parentOf(n)
+		case '/':
+			n, err = nthNode(o.childrenOf(n), st.n, n.MP, "children")
+		default:
+			n, err = o.siblingOf(n, st.n)
+		}
+		if err != nil {
+			return Node{}, fmt.Errorf("%s: %w", sel, err)
+		}
+	}
+	if !sel.last {
+		return n, nil
+	}
+	children := o.childrenOf(n)
+	if len(children) == 0 {
+		return Node{}, fmt.Errorf("%s: %s has no children: %w", sel, n.MP, ErrNoRelative)
+	}
+	return children[len(children)-1], nil
+}
+
+// parentOf returns the node at n's parent MP.
+func (o Outline) parentOf(n Node) (Node, error) {
+	parent, ok := n.MP.Parent()
+	if !ok {
+		return Node{}, fmt.Errorf("%s is a root node: %w", n.MP, ErrNoRelative)
+	}
+	for _, p := range o.Nodes {
+		if p.MP.Equal(parent) {
+			return p, nil
+		}
+	}
+	return Node{}, fmt.Errorf("parent %s of %s: %w", parent, n.MP, ErrNoRelative)
+}
+
+// siblingOf returns the sibling offset places after n, or before it when
+// offset is negative, in outline order.
+func (o Outline) siblingOf(n Node, offset int) (Node, error) {
+	parent, _ := n.MP.Parent()
+	var siblings []Node
+	at := 0
+	for _, s := range o.Nodes {
+		if p, _ := s.MP.Parent(); p.Equal(parent) && s.MP.Depth() == n.MP.Depth() {
+			if s.MP.Equal(n.MP) {
+				at = len(siblings)
+			}
+			siblings = append(siblings, s)
+		}
+	}
+	if offset < 0 {
+		return nthNode(siblings[:at], at+offset+1, n.MP, "siblings before it")
+	}
+	return nthNode(siblings[at+1:], offset, n.MP, "siblings after it")
+}
+
+// childrenOf returns n's children in outline order.
+func (o Outline) childrenOf(n Node) []Node {
+	var children []Node
+	for _, c := range o.Nodes {
+		if n.MP.IsAncestorOf(c.MP) && c.MP.Depth() == n.MP.Depth()+1 {
+			children = append(children, c)
+		}
+	}
+	return children
+}
+
+// nthNode returns the ith of nodes, from 1, or an error saying how many of
+// what the node at mp has.
+func nthNode(nodes []Node, i int, mp MaterializedPath, what string) (Node, error) {
+	if i < 1 || i > len(nodes) {
+		return Node{}, fmt.Errorf("%s has %d %s: %w", mp, len(nodes), what, ErrNoRelative)
+	}
+	return nodes[i-1], nil
+}
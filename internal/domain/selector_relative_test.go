@@ -0,0 +1,183 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseSelector_Relative(t *testing.T) {
+	tests := []struct {
+		input string
+		kind  SelectorKind
+		value string
+		str   string
+	}{
+		{"SID003EEFF^", SelectorSID, "SID003EEFF", "SID003EEFF^"},
+		{"SID003EEFF+1", SelectorSID, "SID003EEFF", "SID003EEFF+1"},
+		{"SID003EEFF-2", SelectorSID, "SID003EEFF", "SID003EEFF-2"},
+		{"100-1", SelectorMP, "100", "100-1"},
+		{"sid:SID001AABB/2", SelectorSID, "SID001AABB", "sid:SID001AABB/2"},
+		{"100-200^+1", SelectorMP, "100-200", "100-200^+1"},
+		{"100/3/1", SelectorMP, "100", "100/3/1"},
+		{"100-200-1000000", SelectorMP, "100-200", "100-200-1000000"},
+		{"last:SID001AABB", SelectorSID, "SID001AABB", "last:SID001AABB"},
+		{"last: 100^", SelectorMP, "100", "last:100^"},
+		{"last:title:Part One", SelectorTitle, "Part One", "last:title:Part One"},
+		{"title:Chapter One^", SelectorTitle, "Chapter One", "title:Chapter One^"},
+		{"title:Part 1/2", SelectorTitle, "Part 1", "title:Part 1/2"},
+		{"title:Part^x^", SelectorTitle, "Part^x", "title:Part^x^"},
+		{"slug:foo/2", SelectorSlug, "foo", "slug:foo/2"},
+		{"slug:part-2^-1", SelectorSlug, "part-2", "slug:part-2^-1"},
+		{"last:slug:foo+1", SelectorSlug, "foo", "last:slug:foo+1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			sel, err := ParseSelector(tt.input)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sel.Kind() != tt.kind || sel.Value() != tt.value {
+				t.Errorf("base = %v %q, want %v %q", sel.Kind(), sel.Value(), tt.kind, tt.value)
+			}
+			if !sel.Relative() || sel.Direct() {
+				t.Errorf("Relative() = %v, Direct() = %v; want true, false", sel.Relative(), sel.Direct())
+			}
+			if sel.String() != tt.str {
+				t.Errorf("String() = %q, want %q", sel.String(), tt.str)
+			}
+		})
+	}
+}
+
+func TestParseSelector_RelativeNamesMPFirst(t *testing.T) {
+	sel, err := ParseSelector("100-200")
+
+	if err != nil || sel.Relative() || sel.Value() != "100-200" {
+		t.Errorf("ParseSelector(100-200) = %v (relative %v), %v; want the MP", sel, sel.Relative(), err)
+	}
+}
+
+func TestParseSelector_ShortSegmentIsStep(t *testing.T) {
+	// "2" and "20" cannot be MP segments, so they are sibling steps back
+	// from 100, not children of it.
+	for _, input := range []string{"100-2", "100-20"} {
+		sel, err := ParseSelector(input)
+
+		if err != nil || !sel.Relative() || sel.Value() != "100" || sel.String() != input {
+			t.Errorf("ParseSelector(%s) = %v (relative %v), %v; want steps back from 100", input, sel, sel.Relative(), err)
+		}
+	}
+}
+
+func TestParseSelector_NameStepsStayInName(t *testing.T) {
+	for _, input := range []string{"slug:part-2", "title:C++", "title:Either/Or", "title:Up^x"} {
+		sel, err := ParseSelector(input)
+
+		if err != nil || sel.Relative() || sel.String() != input {
+			t.Errorf("ParseSelector(%q) = %v (relative %v), %v; want a plain name selector", input, sel, sel.Relative(), err)
+		}
+	}
+}
+
+func TestParseSelector_RelativeInvalid(t *testing.T) {
+	for _, input := range []string{
+		"^", "SID003EEFF^x", "SID003EEFF+", "SID003EEFF+0", "SID003EEFF/01", "SID003EEFF*2",
+		"SID003EEFF+99999999999999999999", "nope!^", "last:", "last:last:100", "title:^", "slug:-/1",
+	} {
+		t.Run(input, func(t *testing.T) {
+			if _, err := ParseSelector(input); !errors.Is(err, ErrInvalidSelector) {
+				t.Errorf("ParseSelector(%q) error = %v, want ErrInvalidSelector", input, err)
+			}
+		})
+	}
+}
+
+func TestOutline_Find_Relative(t *testing.T) {
+	tests := []struct {
+		selector string
+		want     string
+	}{
+		{"SID003EEFF^", "100"},
+		{"SID004GGHH^^", "100"},
+		{"SID002CCDD+1", "100-200"},
+		{"SID002CCDD+2", "100-300"},
+		{"SID005IIJJ-2", "100-100"},
+		{"SID001AABB+1", "200"},
+		{"SID001AABB/1", "100-100"},
+		{"100/2/1", "100-200-100"},
+		{"last:100", "100-300"},
+		{"last:SID004GGHH^", "100-200-100"},
+		{"last:title:part one", "100-300"},
+		{"SID004GGHH^+1", "100-300"},
+		{"title:part one/2", "100-200"},
+		{"slug:part-one/2/1", "100-200-100"},
+		{"200-1", "100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			sel, err := ParseSelector(tt.selector)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+
+			n, err := selectTestOutline().Find(sel)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if n.MP.String() != tt.want {
+				t.Errorf("Find(%s) = %s, want %s", tt.selector, n.MP, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutline_Find_RelativeErrors(t *testing.T) {
+	orphan := selectTestOutline()
+	orphan.Nodes = append(orphan.Nodes[:2], orphan.Nodes[3:]...) // drop 100-200, leaving 100-200-100
+
+	tests := []struct {
+		outline  Outline
+		selector string
+		want     string
+	}{
+		{selectTestOutline(), "SID001AABB^", "SID001AABB^: 100 is a root node: relative node not found"},
+		{selectTestOutline(), "SID005IIJJ+1", "SID005IIJJ+1: 100-300 has 0 siblings after it: relative node not found"},
+		{selectTestOutline(), "SID003EEFF-2", "SID003EEFF-2: 100-200 has 1 siblings before it: relative node not found"},
+		{selectTestOutline(), "100/4", "100/4: 100 has 3 children: relative node not found"},
+		{selectTestOutline(), "last:200", "last:200: 200 has no children: relative node not found"},
+		{orphan, "SID004GGHH^", "SID004GGHH^: parent 100-200 of 100-200-100: relative node not found"},
+		{selectTestOutline(), "300^", "300^: node not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			sel, _ := ParseSelector(tt.selector)
+
+			_, err := tt.outline.Find(sel)
+
+			if !errors.Is(err, ErrNodeNotFound) {
+				t.Fatalf("error = %v, want ErrNodeNotFound", err)
+			}
+			if err.Error() != tt.want {
+				t.Errorf("error = %q, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectorExpr_Relative(t *testing.T) {
+	expr, err := ParseSelectorExpr("SID002CCDD..SID002CCDD+2")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	nodes, err := selectTestOutline().Select(expr)
+
+	if err != nil || len(nodes) != 3 {
+		t.Errorf("Select = %d nodes, %v; want 3", len(nodes), err)
+	}
+}
@@ -175,6 +175,8 @@ func TestParseSelector_Invalid(t *testing.T) {
 		{"spaces in value", "001 200"},
 		{"newline in value", "001\n200"},
 		{"colon without known prefix", "x:001"},
+		{"title prefix empty value", "title:"},
+		{"slug prefix punctuation only", "slug: -- "},
 	}
 
 	for _, tt := range tests {
@@ -202,6 +204,8 @@ func TestParseSelector_String(t *testing.T) {
 		{"explicit SID", "sid:A3F7c9Qx7Lm2", "sid:A3F7c9Qx7Lm2"},
 		{"implicit single segment MP", "001", "001"},
 		{"explicit single segment MP", "mp:001", "mp:001"},
+		{"title", "title: The Beginning ", "title:The Beginning"},
+		{"slug", "slug:chapter-one", "slug:chapter-one"},
 	}
 
 	for _, tt := range tests {
@@ -216,3 +220,30 @@ func TestParseSelector_String(t *testing.T) {
 		})
 	}
 }
+
+func TestParseSelector_ByName(t *testing.T) {
+	tests := []struct {
+		input  string
+		kind   SelectorKind
+		value  string
+		byName bool
+	}{
+		{"001-200", SelectorMP, "001-200", false},
+		{"sid:A3F7c9Qx7Lm2", SelectorSID, "A3F7c9Qx7Lm2", false},
+		{"title:Chapter One", SelectorTitle, "Chapter One", true},
+		{"slug: chapter-one", SelectorSlug, "chapter-one", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			sel, err := ParseSelector(tt.input)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sel.Kind() != tt.kind || sel.Value() != tt.value || sel.ByName() != tt.byName {
+				t.Errorf("got kind %v, value %q, ByName %v; want %v, %q, %v", sel.Kind(), sel.Value(), sel.ByName(), tt.kind, tt.value, tt.byName)
+			}
+		})
+	}
+}
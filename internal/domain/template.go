@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// TemplateDir is the directory holding document templates, relative to the project root.
+const TemplateDir = ".linemark/templates"
+
+// ErrInvalidTemplateName is returned when a template name could escape the
+// template directory or contains unexpected characters.
+var ErrInvalidTemplateName = errors.New("invalid template name")
+
+// templateNameRegex allows slash-separated lowercase segments such as "chapter/draft".
+var templateNameRegex = regexp.MustCompile(`^[a-z0-9_-]+(/[a-z0-9_-]+)*$`)
+
+// TemplateVars holds the values substituted into a document template.
+type TemplateVars struct {
+	Title       string
+	SID         string
+	MP          string
+	ParentTitle string
+	Date        string
+}
+
+// ExpandTemplate replaces the {{title}}, {{sid}}, {{mp}}, {{parent_title}}
+// and {{date}} placeholders in tmpl. Unknown placeholders are left as is.
+func ExpandTemplate(tmpl string, vars TemplateVars) string {
+	return strings.NewReplacer(
+		"{{title}}", vars.Title,
+		"{{sid}}", vars.SID,
+		"{{mp}}", vars.MP,
+		"{{parent_title}}", vars.ParentTitle,
+		"{{date}}", vars.Date,
+	).Replace(tmpl)
+}
+
+// ValidateTemplateName checks that name (without the .md extension) refers
+// to a file inside TemplateDir.
+func ValidateTemplateName(name string) error {
+	if !templateNameRegex.MatchString(name) {
+		return fmt.Errorf("%w: %q", ErrInvalidTemplateName, name)
+	}
+	return nil
+}
+
+// TemplateFile returns the template filename for a template name, relative to TemplateDir.
+func TemplateFile(name string) string {
+	return name + ".md"
+}
+
+// TemplateCandidates returns the template files to try for a doc type at a
+// depth, most specific first: "<depth dir>/<doctype>.md" then "<doctype>.md".
+func (c Config) TemplateCandidates(docType string, depth int) []string {
+	return []string{
+		TemplateFile(c.DepthName(depth) + "/" + docType),
+		TemplateFile(docType),
+	}
+}
+
+// DepthName returns the configured name for a depth (such as "chapter"),
+// falling back to the depth number.
+func (c Config) DepthName(depth int) string {
+	if depth >= 1 && depth <= len(c.DepthNames) {
+		return c.DepthNames[depth-1]
+	}
+	return strconv.Itoa(depth)
+}
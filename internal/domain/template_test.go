@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestExpandTemplate(t *testing.T) {
+	vars := TemplateVars{
+		Title:       "The Storm",
+		SID:         "ABCD1234EFGH",
+		MP:          "100-200",
+		ParentTitle: "Act One",
+		Date:        "2026-10-18",
+	}
+
+	tests := []struct {
+		name string
+		tmpl string
+		want string
+	}{
+		{"no placeholders", "plain text\n", "plain text\n"},
+		{"all placeholders", "{{title}} {{sid}} {{mp}} {{parent_title}} {{date}}", "The Storm ABCD1234EFGH 100-200 Act One 2026-10-18"},
+		{"repeated placeholder", "{{title}}/{{title}}", "The Storm/The Storm"},
+		{"unknown placeholder kept", "{{author}}", "{{author}}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExpandTemplate(tt.tmpl, vars); got != tt.want {
+				t.Errorf("ExpandTemplate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateTemplateName(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{"draft", false},
+		{"chapter/draft", false},
+		{"scene-alt", false},
+		{"", true},
+		{"../secrets", true},
+		{"/etc/passwd", true},
+		{"chapter//draft", true},
+		{"Draft", true},
+		{"draft.md", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTemplateName(tt.name)
+			if tt.wantErr && !errors.Is(err, ErrInvalidTemplateName) {
+				t.Errorf("ValidateTemplateName(%q) = %v, want ErrInvalidTemplateName", tt.name, err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("ValidateTemplateName(%q) = %v, want nil", tt.name, err)
+			}
+		})
+	}
+}
+
+func TestConfig_TemplateCandidates(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.DepthNames = []string{"part", "chapter"}
+
+	tests := []struct {
+		depth int
+		want  []string
+	}{
+		{2, []string{"chapter/draft.md", "draft.md"}},
+		{3, []string{"3/draft.md", "draft.md"}},
+	}
+
+	for _, tt := range tests {
+		if got := cfg.TemplateCandidates("draft", tt.depth); !slices.Equal(got, tt.want) {
+			t.Errorf("TemplateCandidates(draft, %d) = %v, want %v", tt.depth, got, tt.want)
+		}
+	}
+}
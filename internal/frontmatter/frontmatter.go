@@ -2,8 +2,11 @@ package frontmatter
 
 import (
 	"errors"
+	"regexp"
+	"strconv"
 	"strings"
 
+	"github.com/eykd/linemark-go/internal/domain"
 	"gopkg.in/yaml.v3"
 )
 
@@ -42,7 +45,35 @@ func Split(input string) (string, string, error) {
 		pos = nextPos
 	}
 
-	return "", "", errors.New("unclosed frontmatter")
+	return "", "", &domain.PositionError{Line: 1, Column: 1, Err: errors.New("unclosed frontmatter")}
+}
+
+// yamlLineRegex extracts the line number from a yaml.v3 syntax error.
+var yamlLineRegex = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+// unmarshal parses frontmatter into a document node. Syntax errors are
+// returned as *domain.PositionError with lines counted from the top of the
+// file, past the opening "---".
+func unmarshal(fm string) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(fm), &doc); err != nil {
+		m := yamlLineRegex.FindStringSubmatch(err.Error())
+		if m == nil {
+			return nil, err
+		}
+		line, _ := strconv.Atoi(m[1])
+		return nil, &domain.PositionError{Line: line + 1, Err: errors.New(m[2])}
+	}
+	return &doc, nil
+}
+
+// rootMapping returns the mapping at the root of a frontmatter document, or
+// nil when the frontmatter is blank, only comments, or not a mapping.
+func rootMapping(doc *yaml.Node) *yaml.Node {
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil
+	}
+	return doc.Content[0]
 }
 
 // findKeyIndex returns the index of a key in a YAML mapping node, or -1 if not found.
@@ -62,23 +93,100 @@ func GetTitle(input string) (string, error) {
 		return "", err
 	}
 
-	var doc yaml.Node
-	if err := yaml.Unmarshal([]byte(fm), &doc); err != nil {
+	doc, err := unmarshal(fm)
+	if err != nil {
 		return "", err
 	}
 
-	idx := findKeyIndex(doc.Content[0], "title")
+	mapping := rootMapping(doc)
+	if mapping == nil {
+		return "", nil
+	}
+	idx := findKeyIndex(mapping, "title")
 	if idx < 0 {
 		return "", nil
 	}
 
-	val := doc.Content[0].Content[idx+1]
+	val := mapping.Content[idx+1]
 	if val.Tag != "!!str" {
-		return "", errors.New("title is not a string")
+		return "", &domain.PositionError{Line: val.Line + 1, Column: val.Column, Err: errors.New("title is not a string")}
 	}
 	return val.Value, nil
 }
 
+// GetFields returns the top-level fields of a document's YAML frontmatter.
+// Scalar values are returned as written; null, sequence and mapping values
+// are returned as empty strings. Frontmatter that is blank, only comments,
+// or not a mapping has no fields.
+func GetFields(input string) (map[string]string, error) {
+	fields := map[string]string{}
+	fm, _, err := Split(input)
+	if fm == "" {
+		return fields, err
+	}
+
+	doc, err := unmarshal(fm)
+	if err != nil {
+		return nil, err
+	}
+
+	mapping := rootMapping(doc)
+	if mapping == nil {
+		return fields, nil
+	}
+	for i := 0; i < len(mapping.Content)-1; i += 2 {
+		val := mapping.Content[i+1]
+		if val.Kind == yaml.ScalarNode && val.Tag != "!!null" {
+			fields[mapping.Content[i].Value] = val.Value
+		} else {
+			fields[mapping.Content[i].Value] = ""
+		}
+	}
+	return fields, nil
+}
+
+// GetList returns a list field from a document's YAML frontmatter. A
+// sequence yields its scalar items; a scalar is split on commas. A missing
+// or null field, or frontmatter that is not a mapping, yields nil.
+func GetList(input, key string) ([]string, error) {
+	fm, _, err := Split(input)
+	if fm == "" {
+		return nil, err
+	}
+
+	doc, err := unmarshal(fm)
+	if err != nil {
+		return nil, err
+	}
+
+	mapping := rootMapping(doc)
+	if mapping == nil {
+		return nil, nil
+	}
+	idx := findKeyIndex(mapping, key)
+	if idx < 0 {
+		return nil, nil
+	}
+
+	var items []string
+	val := mapping.Content[idx+1]
+	switch {
+	case val.Kind == yaml.SequenceNode:
+		for _, item := range val.Content {
+			if item.Kind == yaml.ScalarNode {
+				items = append(items, item.Value)
+			}
+		}
+	case val.Kind == yaml.ScalarNode && val.Tag != "!!null":
+		for _, item := range strings.Split(val.Value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items, nil
+}
+
 // SetTitle sets or updates the title field in a document's YAML frontmatter.
 // It preserves unknown fields, field order, and comments using text-level
 // line replacement guided by yaml.Node line positions.
@@ -94,18 +202,18 @@ func SetTitle(input string, newTitle string) (string, error) {
 		return Serialize(titleLine, body), nil
 	}
 
-	var doc yaml.Node
-	if err := yaml.Unmarshal([]byte(fm), &doc); err != nil {
+	doc, err := unmarshal(fm)
+	if err != nil {
 		return "", err
 	}
 
-	mapping := doc.Content[0]
-	idx := findKeyIndex(mapping, "title")
-	if idx >= 0 {
-		keyLine := mapping.Content[idx].Line
-		lines := strings.SplitAfter(fm, "\n")
-		lines[keyLine-1] = titleLine
-		return Serialize(strings.Join(lines, ""), body), nil
+	if mapping := rootMapping(doc); mapping != nil {
+		if idx := findKeyIndex(mapping, "title"); idx >= 0 {
+			keyLine := mapping.Content[idx].Line
+			lines := strings.SplitAfter(fm, "\n")
+			lines[keyLine-1] = titleLine
+			return Serialize(strings.Join(lines, ""), body), nil
+		}
 	}
 
 	return Serialize(fm+titleLine, body), nil
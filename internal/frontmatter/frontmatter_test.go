@@ -1,8 +1,12 @@
 package frontmatter
 
 import (
+	"errors"
+	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/eykd/linemark-go/internal/domain"
 )
 
 func TestSplit(t *testing.T) {
@@ -156,6 +160,24 @@ func TestGetTitle(t *testing.T) {
 			"",
 			false,
 		},
+		{
+			"comment-only frontmatter",
+			"---\n# todo\n---\nBody",
+			"",
+			false,
+		},
+		{
+			"blank frontmatter",
+			"---\n\n---\nBody",
+			"",
+			false,
+		},
+		{
+			"non-mapping frontmatter",
+			"---\n- a\n---\n",
+			"",
+			false,
+		},
 		{
 			"bool title returns error",
 			"---\ntitle: true\n---\n",
@@ -212,6 +234,79 @@ func TestGetTitle(t *testing.T) {
 	}
 }
 
+func TestGetFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			"scalar fields",
+			"---\ntitle: My Title\npov: Ann\nstatus: \"draft\"\nwords: 1200\n---\nBody",
+			map[string]string{"title": "My Title", "pov": "Ann", "status": "draft", "words": "1200"},
+			false,
+		},
+		{
+			"null and collection values are empty",
+			"---\npov:\ntags: [a, b]\nmeta: {k: v}\n---\n",
+			map[string]string{"pov": "", "tags": "", "meta": ""},
+			false,
+		},
+		{
+			"no frontmatter",
+			"Just body",
+			map[string]string{},
+			false,
+		},
+		{
+			"comment-only frontmatter",
+			"---\n# todo\n---\n",
+			map[string]string{},
+			false,
+		},
+		{
+			"blank frontmatter",
+			"---\n\n---\n",
+			map[string]string{},
+			false,
+		},
+		{
+			"non-mapping frontmatter",
+			"---\n- a\n- b\n---\n",
+			map[string]string{},
+			false,
+		},
+		{
+			"malformed yaml",
+			"---\npov: [unclosed\n---\n",
+			nil,
+			true,
+		},
+		{
+			"unclosed frontmatter",
+			"---\npov: Ann\n",
+			nil,
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetFields(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetFields() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetFields() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestSetTitle(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -243,6 +338,18 @@ func TestSetTitle(t *testing.T) {
 				}
 			},
 		},
+		{
+			"comment-only frontmatter gains a title",
+			"---\n# todo\n---\nBody",
+			"New",
+			false,
+			func(t *testing.T, result string) {
+				t.Helper()
+				if result != "---\n# todo\ntitle: New\n---\nBody" {
+					t.Errorf("result = %q", result)
+				}
+			},
+		},
 		{
 			"preserves unknown fields",
 			"---\nauthor: Alice\ntitle: Old\ntags: [a, b]\n---\nBody",
@@ -508,3 +615,75 @@ func TestSerialize(t *testing.T) {
 		})
 	}
 }
+
+func TestGetTitle_ErrorPositions(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantLine   int
+		wantColumn int
+		wantMsg    string
+	}{
+		{"unclosed frontmatter", "---\ntitle: A\n", 1, 1, "unclosed frontmatter"},
+		{"yaml syntax error", "---\ntitle: A\n  pov: Ann\n---\n", 3, 0, "mapping values are not allowed in this context"},
+		{"non-string title", "---\npov: Ann\ntitle:\n  - a\n---\n", 4, 3, "title is not a string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GetTitle(tt.input)
+
+			var posErr *domain.PositionError
+			if !errors.As(err, &posErr) {
+				t.Fatalf("error = %v, want *domain.PositionError", err)
+			}
+			if posErr.Line != tt.wantLine || posErr.Column != tt.wantColumn {
+				t.Errorf("position = %d:%d, want %d:%d", posErr.Line, posErr.Column, tt.wantLine, tt.wantColumn)
+			}
+			if posErr.Err.Error() != tt.wantMsg {
+				t.Errorf("message = %q, want %q", posErr.Err.Error(), tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestGetTitle_UnpositionedYAMLError(t *testing.T) {
+	_, err := GetTitle("---\ntitle: \x01\n---\n")
+
+	var posErr *domain.PositionError
+	if err == nil || errors.As(err, &posErr) {
+		t.Errorf("error = %v, want plain yaml error", err)
+	}
+}
+
+func TestGetList(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{"sequence", "---\nlmk_ignore: [todo, slug_drift]\n---\n", []string{"todo", "slug_drift"}, false},
+		{"block sequence skips non-scalars", "---\nlmk_ignore:\n  - todo\n  - {a: b}\n---\n", []string{"todo"}, false},
+		{"comma-separated scalar", "---\nlmk_ignore: todo , passive-voice,\n---\n", []string{"todo", "passive-voice"}, false},
+		{"null", "---\nlmk_ignore:\n---\n", nil, false},
+		{"missing key", "---\ntitle: A\n---\n", nil, false},
+		{"no frontmatter", "Body\n", nil, false},
+		{"comment-only frontmatter", "---\n# todo\n---\n", nil, false},
+		{"blank frontmatter", "---\n\n---\n", nil, false},
+		{"non-mapping frontmatter", "---\n- a\n---\n", nil, false},
+		{"malformed yaml", "---\nlmk_ignore: [todo\n---\n", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetList(tt.input, "lmk_ignore")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetList() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetList() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package fs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+func TestOSBaselineStore_RoundTrip(t *testing.T) {
+	root := t.TempDir()
+	store := &OSBaselineStore{Root: root}
+	ctx := context.Background()
+
+	empty, err := store.LoadBaseline(ctx)
+	if err != nil || len(empty) != 0 {
+		t.Fatalf("LoadBaseline() on missing file = %v, %v; want empty", empty, err)
+	}
+
+	want := domain.Baseline{"slug_drift SID001AABB draft": 1, "todo SID001AABB draft": 2}
+	if err := store.SaveBaseline(ctx, want); err != nil {
+		t.Fatalf("SaveBaseline() error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, ".linemark", "baseline")); err != nil {
+		t.Fatalf("baseline file not written: %v", err)
+	}
+
+	got, err := store.LoadBaseline(ctx)
+	if err != nil {
+		t.Fatalf("LoadBaseline() error: %v", err)
+	}
+	if len(got) != 2 || got["todo SID001AABB draft"] != 2 {
+		t.Errorf("LoadBaseline() = %v, want %v", got, want)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/eykd/linemark-go/internal/domain"
 	"github.com/eykd/linemark-go/internal/frontmatter"
 	"github.com/eykd/linemark-go/internal/sid"
 	"github.com/eykd/linemark-go/internal/slug"
@@ -108,12 +109,17 @@ func (cr *OSContentReader) ReadFile(ctx context.Context, filename string) (strin
 }
 
 // SIDReserver implements outline.SIDReserver using a random io.Reader.
+// A zero Length uses the default SID length.
 type SIDReserver struct {
-	Rand io.Reader
+	Rand   io.Reader
+	Length int
 }
 
 // ReserveImpl generates a new SID using the configured random source.
 func (r *SIDReserver) ReserveImpl(_ context.Context) (string, error) {
+	if r.Length > 0 {
+		return sid.GenerateLength(r.Rand, r.Length)
+	}
 	return sid.Generate(r.Rand)
 }
 
@@ -134,6 +140,16 @@ type FMAdapter struct{}
 // GetTitle extracts the title from frontmatter content.
 func (FMAdapter) GetTitle(input string) (string, error) { return frontmatter.GetTitle(input) }
 
+// GetFields extracts the top-level fields from frontmatter content.
+func (FMAdapter) GetFields(input string) (map[string]string, error) {
+	return frontmatter.GetFields(input)
+}
+
+// GetList extracts a list field from frontmatter content.
+func (FMAdapter) GetList(input, key string) ([]string, error) {
+	return frontmatter.GetList(input, key)
+}
+
 // SetTitle updates the title in frontmatter content.
 func (FMAdapter) SetTitle(input, newTitle string) (string, error) {
 	return frontmatter.SetTitle(input, newTitle)
@@ -186,6 +202,68 @@ func (s *OSReservationStore) CreateReservation(ctx context.Context, sid string)
 	return s.CreateReservationImpl(ctx, sid)
 }
 
+// OSTemplateReader implements outline.TemplateReader using files under
+// .linemark/templates/.
+type OSTemplateReader struct {
+	Root string
+}
+
+// ReadTemplateImpl reads a template by its path relative to the template directory.
+// A missing template yields an error wrapping fs.ErrNotExist.
+func (r *OSTemplateReader) ReadTemplateImpl(_ context.Context, name string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(r.Root, filepath.FromSlash(domain.TemplateDir), filepath.FromSlash(name)))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// ReadTemplate delegates to ReadTemplateImpl.
+func (r *OSTemplateReader) ReadTemplate(ctx context.Context, name string) (string, error) {
+	return r.ReadTemplateImpl(ctx, name)
+}
+
+// OSBaselineStore implements outline.BaselineStore using .linemark/baseline.
+type OSBaselineStore struct {
+	Root string
+}
+
+// baselinePath returns the filesystem path of the baseline file.
+func (s *OSBaselineStore) baselinePath() string {
+	return filepath.Join(s.Root, filepath.FromSlash(domain.BaselinePath))
+}
+
+// LoadBaselineImpl reads the baseline file; a missing file yields an empty baseline.
+func (s *OSBaselineStore) LoadBaselineImpl(_ context.Context) (domain.Baseline, error) {
+	data, err := os.ReadFile(s.baselinePath())
+	if os.IsNotExist(err) {
+		return domain.Baseline{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading baseline: %w", err)
+	}
+	return domain.ParseBaseline(string(data)), nil
+}
+
+// LoadBaseline delegates to LoadBaselineImpl.
+func (s *OSBaselineStore) LoadBaseline(ctx context.Context) (domain.Baseline, error) {
+	return s.LoadBaselineImpl(ctx)
+}
+
+// SaveBaselineImpl writes the baseline file, creating .linemark/ if needed.
+func (s *OSBaselineStore) SaveBaselineImpl(_ context.Context, b domain.Baseline) error {
+	path := s.baselinePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating baseline directory: %w", err)
+	}
+	return os.WriteFile(path, []byte(b.String()), 0o644)
+}
+
+// SaveBaseline delegates to SaveBaselineImpl.
+func (s *OSBaselineStore) SaveBaseline(ctx context.Context, b domain.Baseline) error {
+	return s.SaveBaselineImpl(ctx, b)
+}
+
 // FindProjectRootImpl walks up from the current working directory looking for a .linemark/ directory.
 func FindProjectRootImpl() (string, error) {
 	dir, err := os.Getwd()
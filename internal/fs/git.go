@@ -0,0 +1,127 @@
+package fs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/eykd/linemark-go/internal/outline"
+)
+
+// OSSnapshot implements outline.Snapshot over a directory on disk.
+type OSSnapshot struct {
+	Root string
+}
+
+// ReadDir lists the directory's files.
+func (s *OSSnapshot) ReadDir(ctx context.Context) ([]string, error) {
+	return (&OSReader{Root: s.Root}).ReadDir(ctx)
+}
+
+// ReadFile reads a file in the directory.
+func (s *OSSnapshot) ReadFile(ctx context.Context, filename string) (string, error) {
+	return (&OSContentReader{Root: s.Root}).ReadFile(ctx, filename)
+}
+
+// gitImpl runs git in dir and returns its standard output.
+func gitImpl(ctx context.Context, dir string, args ...string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
+
+// GitSnapshot implements outline.Snapshot over the project directory as it
+// stands in a git revision.
+type GitSnapshot struct {
+	Root string
+	Rev  string
+}
+
+// ReadDirImpl lists the files of the project directory in the revision.
+func (s *GitSnapshot) ReadDirImpl(ctx context.Context) ([]string, error) {
+	out, err := gitImpl(ctx, s.Root, "ls-tree", "-z", s.Rev, "./")
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range strings.Split(strings.TrimSuffix(out, "\x00"), "\x00") {
+		meta, name, ok := strings.Cut(entry, "\t")
+		if ok && strings.Fields(meta)[1] == "blob" {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// ReadDir delegates to ReadDirImpl.
+func (s *GitSnapshot) ReadDir(ctx context.Context) ([]string, error) {
+	return s.ReadDirImpl(ctx)
+}
+
+// ReadFileImpl reads a file of the project directory in the revision.
+func (s *GitSnapshot) ReadFileImpl(ctx context.Context, filename string) (string, error) {
+	return gitImpl(ctx, s.Root, "show", s.Rev+":./"+filename)
+}
+
+// ReadFile delegates to ReadFileImpl.
+func (s *GitSnapshot) ReadFile(ctx context.Context, filename string) (string, error) {
+	return s.ReadFileImpl(ctx, filename)
+}
+
+// GitMergeSource implements outline.MergeSource for a git merge that
+// stopped with conflicts: base is the merge base, ours HEAD and theirs
+// MERGE_HEAD.
+type GitMergeSource struct {
+	Root string
+}
+
+// MergeSnapshotsImpl locates the three sides of the in-progress merge.
+func (g *GitMergeSource) MergeSnapshotsImpl(ctx context.Context) (base, ours, theirs outline.Snapshot, err error) {
+	if _, err := gitImpl(ctx, g.Root, "rev-parse", "-q", "--verify", "MERGE_HEAD"); err != nil {
+		return nil, nil, nil, fmt.Errorf("%w: %v", outline.ErrNoMergeInProgress, err)
+	}
+	mergeBase, err := gitImpl(ctx, g.Root, "merge-base", "HEAD", "MERGE_HEAD")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return &GitSnapshot{Root: g.Root, Rev: strings.TrimSpace(mergeBase)},
+		&GitSnapshot{Root: g.Root, Rev: "HEAD"},
+		&GitSnapshot{Root: g.Root, Rev: "MERGE_HEAD"}, nil
+}
+
+// MergeSnapshots delegates to MergeSnapshotsImpl.
+func (g *GitMergeSource) MergeSnapshots(ctx context.Context) (base, ours, theirs outline.Snapshot, err error) {
+	return g.MergeSnapshotsImpl(ctx)
+}
+
+// GitSnapshotResolver implements outline.SnapshotResolver: a ref naming a
+// directory is read from disk, and any other ref is taken as a git revision
+// of the project directory.
+type GitSnapshotResolver struct {
+	Root string
+}
+
+// ResolveSnapshotImpl returns the snapshot for a directory or git revision.
+func (r *GitSnapshotResolver) ResolveSnapshotImpl(ctx context.Context, ref string) (outline.Snapshot, error) {
+	if info, err := os.Stat(ref); err == nil && info.IsDir() {
+		return &OSSnapshot{Root: ref}, nil
+	}
+	rev, err := gitImpl(ctx, r.Root, "rev-parse", "-q", "--verify", ref+"^{commit}")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", outline.ErrSnapshotNotFound, ref)
+	}
+	return &GitSnapshot{Root: r.Root, Rev: strings.TrimSpace(rev)}, nil
+}
+
+// ResolveSnapshot delegates to ResolveSnapshotImpl.
+func (r *GitSnapshotResolver) ResolveSnapshot(ctx context.Context, ref string) (outline.Snapshot, error) {
+	return r.ResolveSnapshotImpl(ctx, ref)
+}
@@ -0,0 +1,100 @@
+package fs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/eykd/linemark-go/internal/outline"
+)
+
+// initGitRepo creates a repository with one commit holding a project in
+// the "book" subdirectory and returns the project root.
+func initGitRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	root := filepath.Join(repo, "book")
+	if err := os.MkdirAll(filepath.Join(root, "assets"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{
+		"100_SID001AABB_draft_one.md": "one\n",
+		"assets/cover.png":            "png",
+	} {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "-A"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "base"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	return root
+}
+
+func TestGitSnapshot_ReadsProjectAtRevision(t *testing.T) {
+	root := initGitRepo(t)
+	snap := &GitSnapshot{Root: root, Rev: "HEAD"}
+	ctx := context.Background()
+
+	names, err := snap.ReadDir(ctx)
+	if err != nil {
+		t.Fatalf("ReadDir() error: %v", err)
+	}
+	if !slices.Equal(names, []string{"100_SID001AABB_draft_one.md"}) {
+		t.Errorf("ReadDir() = %v, want only the project's files", names)
+	}
+
+	content, err := snap.ReadFile(ctx, "100_SID001AABB_draft_one.md")
+	if err != nil || content != "one\n" {
+		t.Errorf("ReadFile() = %q, %v; want %q", content, err, "one\n")
+	}
+	if _, err := snap.ReadFile(ctx, "missing.md"); err == nil {
+		t.Error("ReadFile() of a missing file should fail")
+	}
+}
+
+func TestGitMergeSource_NoMergeInProgress(t *testing.T) {
+	root := initGitRepo(t)
+
+	_, _, _, err := (&GitMergeSource{Root: root}).MergeSnapshots(context.Background())
+
+	if !errors.Is(err, outline.ErrNoMergeInProgress) {
+		t.Errorf("MergeSnapshots() error = %v, want ErrNoMergeInProgress", err)
+	}
+}
+
+func TestGitSnapshotResolver_ResolvesDirectoriesAndRevisions(t *testing.T) {
+	root := initGitRepo(t)
+	resolver := &GitSnapshotResolver{Root: root}
+	ctx := context.Background()
+
+	dir, err := resolver.ResolveSnapshot(ctx, root)
+	if snap, ok := dir.(*OSSnapshot); err != nil || !ok || snap.Root != root {
+		t.Errorf("ResolveSnapshot(dir) = %#v, %v; want an OSSnapshot", dir, err)
+	}
+
+	rev, err := resolver.ResolveSnapshot(ctx, "HEAD")
+	if err != nil {
+		t.Fatalf("ResolveSnapshot(HEAD) error: %v", err)
+	}
+	if names, err := rev.ReadDir(ctx); err != nil || len(names) != 1 {
+		t.Errorf("HEAD snapshot lists %v, %v; want the committed file", names, err)
+	}
+
+	if _, err := resolver.ResolveSnapshot(ctx, "no-such-branch"); !errors.Is(err, outline.ErrSnapshotNotFound) {
+		t.Errorf("ResolveSnapshot(unknown) error = %v, want ErrSnapshotNotFound", err)
+	}
+}
@@ -0,0 +1,233 @@
+package fs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/eykd/linemark-go/internal/outline"
+)
+
+// SnapshotsDir is where snapshots live, relative to the project root.
+const SnapshotsDir = ".linemark/snapshots"
+
+// snapshotManifest is the stored form of a snapshot: its metadata and the
+// content hash of each file, by filename.
+type snapshotManifest struct {
+	ID      string            `json:"id"`
+	Created time.Time         `json:"created"`
+	Message string            `json:"message,omitempty"`
+	Files   map[string]string `json:"files"`
+}
+
+// info returns the manifest's metadata.
+func (m *snapshotManifest) info() outline.SnapshotInfo {
+	return outline.SnapshotInfo{ID: m.ID, Created: m.Created, Message: m.Message, Files: len(m.Files)}
+}
+
+// OSSnapshotStore implements outline.SnapshotStore under .linemark/snapshots.
+// File contents are stored gzip-compressed under objects/, named by their
+// SHA-256, so a file unchanged between snapshots is stored once; each
+// snapshot is a JSON manifest named by its ID. A nil Now uses time.Now.
+type OSSnapshotStore struct {
+	Root string
+	Now  func() time.Time
+}
+
+// dir returns the filesystem path of the snapshots directory.
+func (s *OSSnapshotStore) dir(parts ...string) string {
+	return filepath.Join(append([]string{s.Root, filepath.FromSlash(SnapshotsDir)}, parts...)...)
+}
+
+// SaveSnapshotImpl stores the files and a manifest under a new ID derived
+// from the current time.
+func (s *OSSnapshotStore) SaveSnapshotImpl(_ context.Context, message string, files map[string]string) (outline.SnapshotInfo, error) {
+	if err := os.MkdirAll(s.dir("objects"), 0o755); err != nil {
+		return outline.SnapshotInfo{}, fmt.Errorf("creating snapshot directory: %w", err)
+	}
+
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	m := &snapshotManifest{Created: now().UTC(), Message: message, Files: map[string]string{}}
+	for name, content := range files {
+		sum := sha256.Sum256([]byte(content))
+		hash := hex.EncodeToString(sum[:])
+		if err := s.writeObject(hash, content); err != nil {
+			return outline.SnapshotInfo{}, err
+		}
+		m.Files[name] = hash
+	}
+
+	// IDs sort by creation time; a second snapshot within the same second
+	// takes a numeric suffix.
+	base := m.Created.Format("20060102T150405Z")
+	for n := 1; ; n++ {
+		m.ID = base
+		if n > 1 {
+			m.ID = fmt.Sprintf("%s-%d", base, n)
+		}
+		data, _ := json.MarshalIndent(m, "", "  ")
+		f, err := os.OpenFile(s.dir(m.ID+".json"), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return outline.SnapshotInfo{}, fmt.Errorf("writing snapshot manifest: %w", err)
+		}
+		_, err = f.Write(append(data, '\n'))
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return outline.SnapshotInfo{}, fmt.Errorf("writing snapshot manifest: %w", err)
+		}
+		return m.info(), nil
+	}
+}
+
+// SaveSnapshot delegates to SaveSnapshotImpl.
+func (s *OSSnapshotStore) SaveSnapshot(ctx context.Context, message string, files map[string]string) (outline.SnapshotInfo, error) {
+	return s.SaveSnapshotImpl(ctx, message, files)
+}
+
+// writeObject stores content compressed under its hash, unless an object
+// with that hash already exists.
+func (s *OSSnapshotStore) writeObject(hash, content string) error {
+	path := s.dir("objects", hash+".gz")
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(content)); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	// Write to a temporary name first so a partial object is never mistaken
+	// for a complete one.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("writing snapshot object: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// readManifest loads the manifest of a snapshot.
+func (s *OSSnapshotStore) readManifest(id string) (*snapshotManifest, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) || strings.HasPrefix(id, ".") {
+		return nil, fmt.Errorf("%w: %s", outline.ErrNoSuchSnapshot, id)
+	}
+	data, err := os.ReadFile(s.dir(id + ".json"))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", outline.ErrNoSuchSnapshot, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading snapshot %s: %w", id, err)
+	}
+	var m snapshotManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("reading snapshot %s: %w", id, err)
+	}
+	return &m, nil
+}
+
+// ListSnapshotsImpl returns every stored snapshot, oldest first.
+func (s *OSSnapshotStore) ListSnapshotsImpl(_ context.Context) ([]outline.SnapshotInfo, error) {
+	entries, err := os.ReadDir(s.dir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading snapshots: %w", err)
+	}
+	var infos []outline.SnapshotInfo
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || e.IsDir() {
+			continue
+		}
+		m, err := s.readManifest(id)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, m.info())
+	}
+	slices.SortFunc(infos, func(a, b outline.SnapshotInfo) int {
+		if c := a.Created.Compare(b.Created); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return infos, nil
+}
+
+// ListSnapshots delegates to ListSnapshotsImpl.
+func (s *OSSnapshotStore) ListSnapshots(ctx context.Context) ([]outline.SnapshotInfo, error) {
+	return s.ListSnapshotsImpl(ctx)
+}
+
+// OpenSnapshotImpl opens a stored snapshot for reading.
+func (s *OSSnapshotStore) OpenSnapshotImpl(_ context.Context, id string) (outline.Snapshot, error) {
+	m, err := s.readManifest(id)
+	if err != nil {
+		return nil, err
+	}
+	return &storedSnapshot{store: s, manifest: m}, nil
+}
+
+// OpenSnapshot delegates to OpenSnapshotImpl.
+func (s *OSSnapshotStore) OpenSnapshot(ctx context.Context, id string) (outline.Snapshot, error) {
+	return s.OpenSnapshotImpl(ctx, id)
+}
+
+// storedSnapshot implements outline.Snapshot over a stored manifest.
+type storedSnapshot struct {
+	store    *OSSnapshotStore
+	manifest *snapshotManifest
+}
+
+// ReadDir lists the snapshot's files.
+func (ss *storedSnapshot) ReadDir(_ context.Context) ([]string, error) {
+	names := make([]string, 0, len(ss.manifest.Files))
+	for name := range ss.manifest.Files {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// ReadFile decompresses a file's stored content.
+func (ss *storedSnapshot) ReadFile(_ context.Context, filename string) (string, error) {
+	hash, ok := ss.manifest.Files[filename]
+	if !ok {
+		return "", fmt.Errorf("%s: %w", filename, os.ErrNotExist)
+	}
+	f, err := os.Open(ss.store.dir("objects", hash+".gz"))
+	if err != nil {
+		return "", fmt.Errorf("reading snapshot object: %w", err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return "", fmt.Errorf("reading snapshot object %s: %w", hash, err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		return "", fmt.Errorf("reading snapshot object %s: %w", hash, err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,74 @@
+package fs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/eykd/linemark-go/internal/outline"
+)
+
+func TestOSSnapshotStore_RoundTrip(t *testing.T) {
+	root := t.TempDir()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	store := &OSSnapshotStore{Root: root, Now: func() time.Time { return now }}
+	ctx := context.Background()
+
+	if infos, err := store.ListSnapshots(ctx); err != nil || len(infos) != 0 {
+		t.Fatalf("ListSnapshots() before any snapshot = %v, %v; want none", infos, err)
+	}
+
+	files := map[string]string{
+		"100_SID001AABB_draft_a.md": "same\n",
+		"100_SID001AABB_notes.md":   "same\n",
+	}
+	first, err := store.SaveSnapshot(ctx, "before restructure", files)
+	if err != nil {
+		t.Fatalf("SaveSnapshot() error: %v", err)
+	}
+	second, err := store.SaveSnapshot(ctx, "", map[string]string{"100_SID001AABB_draft_a.md": "changed\n"})
+	if err != nil {
+		t.Fatalf("SaveSnapshot() error: %v", err)
+	}
+	if first.ID != "20260301T120000Z" || second.ID != "20260301T120000Z-2" || first.Files != 2 {
+		t.Errorf("IDs = %q, %q; files = %d", first.ID, second.ID, first.Files)
+	}
+
+	objects, _ := os.ReadDir(filepath.Join(root, ".linemark", "snapshots", "objects"))
+	if len(objects) != 2 {
+		t.Errorf("stored %d objects, want 2 (identical contents share one)", len(objects))
+	}
+
+	infos, err := store.ListSnapshots(ctx)
+	if err != nil || len(infos) != 2 || infos[0].Message != "before restructure" || infos[1].ID != second.ID {
+		t.Errorf("ListSnapshots() = %+v, %v", infos, err)
+	}
+
+	snap, err := store.OpenSnapshot(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("OpenSnapshot() error: %v", err)
+	}
+	names, _ := snap.ReadDir(ctx)
+	if len(names) != 2 || names[0] != "100_SID001AABB_draft_a.md" {
+		t.Errorf("ReadDir() = %v", names)
+	}
+	if content, err := snap.ReadFile(ctx, "100_SID001AABB_notes.md"); err != nil || content != "same\n" {
+		t.Errorf("ReadFile() = %q, %v", content, err)
+	}
+	if _, err := snap.ReadFile(ctx, "missing.md"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("ReadFile(missing) error = %v, want not exist", err)
+	}
+}
+
+func TestOSSnapshotStore_UnknownSnapshot(t *testing.T) {
+	store := &OSSnapshotStore{Root: t.TempDir()}
+
+	for _, id := range []string{"20260301T120000Z", "../config", ""} {
+		if _, err := store.OpenSnapshot(context.Background(), id); !errors.Is(err, outline.ErrNoSuchSnapshot) {
+			t.Errorf("OpenSnapshot(%q) error = %v, want ErrNoSuchSnapshot", id, err)
+		}
+	}
+}
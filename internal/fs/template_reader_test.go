@@ -0,0 +1,35 @@
+package fs
+
+import (
+	"context"
+	"errors"
+	iofs "io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOSTemplateReader_ReadTemplate(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, ".linemark", "templates", "chapter")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "draft.md"), []byte("# {{title}}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	reader := &OSTemplateReader{Root: root}
+
+	got, err := reader.ReadTemplate(context.Background(), "chapter/draft.md")
+	if err != nil {
+		t.Fatalf("ReadTemplate() error: %v", err)
+	}
+	if got != "# {{title}}\n" {
+		t.Errorf("ReadTemplate() = %q", got)
+	}
+
+	_, err = reader.ReadTemplate(context.Background(), "notes.md")
+	if !errors.Is(err, iofs.ErrNotExist) {
+		t.Errorf("missing template error = %v, want fs.ErrNotExist", err)
+	}
+}
@@ -0,0 +1,187 @@
+package fs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/eykd/linemark-go/internal/outline"
+)
+
+// TrashDir is where archived nodes live, relative to the project root.
+const TrashDir = ".linemark/trash"
+
+// trashEntryFile names the metadata file in each trash entry's directory.
+const trashEntryFile = "entry.json"
+
+// trashRecord is the stored form of an outline.TrashEntry.
+type trashRecord struct {
+	ID        string    `json:"id"`
+	Deleted   time.Time `json:"deleted"`
+	SID       string    `json:"sid"`
+	MP        string    `json:"mp"`
+	ParentSID string    `json:"parent_sid,omitempty"`
+	Slug      string    `json:"slug,omitempty"`
+	Files     []string  `json:"files"`
+}
+
+// OSTrashStore implements outline.TrashStore under .linemark/trash. Each
+// archived node gets a directory named by its ID, holding its files under
+// their original names and an entry.json recording where it stood in the
+// outline. A nil Now uses time.Now.
+type OSTrashStore struct {
+	Root string
+	Now  func() time.Time
+}
+
+// dir returns the filesystem path of the trash directory.
+func (s *OSTrashStore) dir(parts ...string) string {
+	return filepath.Join(append([]string{s.Root, filepath.FromSlash(TrashDir)}, parts...)...)
+}
+
+// TrashImpl moves the entry's files from the project root into a new trash
+// directory named by the deletion time.
+func (s *OSTrashStore) TrashImpl(_ context.Context, entry outline.TrashEntry) (outline.TrashEntry, error) {
+	if err := os.MkdirAll(s.dir(), 0o755); err != nil {
+		return outline.TrashEntry{}, fmt.Errorf("creating trash directory: %w", err)
+	}
+
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	entry.Deleted = now().UTC()
+
+	// IDs sort by deletion time; a second deletion within the same second
+	// takes a numeric suffix.
+	base := entry.Deleted.Format("20060102T150405Z")
+	for n := 1; ; n++ {
+		entry.ID = base
+		if n > 1 {
+			entry.ID = fmt.Sprintf("%s-%d", base, n)
+		}
+		err := os.Mkdir(s.dir(entry.ID), 0o755)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return outline.TrashEntry{}, fmt.Errorf("creating trash entry: %w", err)
+		}
+		break
+	}
+
+	data, _ := json.MarshalIndent(trashRecord(entry), "", "  ")
+	if err := os.WriteFile(s.dir(entry.ID, trashEntryFile), append(data, '\n'), 0o644); err != nil {
+		return outline.TrashEntry{}, fmt.Errorf("writing trash entry: %w", err)
+	}
+	for _, name := range entry.Files {
+		if err := checkFree(s.dir(entry.ID, name), name); err != nil {
+			return outline.TrashEntry{}, err
+		}
+		if err := os.Rename(filepath.Join(s.Root, name), s.dir(entry.ID, name)); err != nil {
+			return outline.TrashEntry{}, fmt.Errorf("moving %s to trash: %w", name, err)
+		}
+	}
+	return entry, nil
+}
+
+// Trash delegates to TrashImpl.
+func (s *OSTrashStore) Trash(ctx context.Context, entry outline.TrashEntry) (outline.TrashEntry, error) {
+	return s.TrashImpl(ctx, entry)
+}
+
+// ListTrashImpl returns every archived node, oldest first.
+func (s *OSTrashStore) ListTrashImpl(_ context.Context) ([]outline.TrashEntry, error) {
+	dirs, err := os.ReadDir(s.dir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading trash: %w", err)
+	}
+	var entries []outline.TrashEntry
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(s.dir(d.Name(), trashEntryFile))
+		if err != nil {
+			return nil, fmt.Errorf("reading trash entry %s: %w", d.Name(), err)
+		}
+		var rec trashRecord
+		if err := json.Unmarshal(data, &rec); err != nil {
+			return nil, fmt.Errorf("reading trash entry %s: %w", d.Name(), err)
+		}
+		rec.ID = d.Name()
+		entries = append(entries, outline.TrashEntry(rec))
+	}
+	slices.SortFunc(entries, func(a, b outline.TrashEntry) int {
+		if c := a.Deleted.Compare(b.Deleted); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return entries, nil
+}
+
+// ListTrash delegates to ListTrashImpl.
+func (s *OSTrashStore) ListTrash(ctx context.Context) ([]outline.TrashEntry, error) {
+	return s.ListTrashImpl(ctx)
+}
+
+// UntrashImpl moves a trash entry's files back into the project root under
+// the given names, then removes the entry. Nothing is moved if any of the
+// names is already taken.
+func (s *OSTrashStore) UntrashImpl(_ context.Context, id string, names map[string]string) error {
+	if id == "" || strings.ContainsAny(id, `/\`) || strings.HasPrefix(id, ".") {
+		return fmt.Errorf("%w: %s", outline.ErrNoSuchTrash, id)
+	}
+	if _, err := os.Stat(s.dir(id, trashEntryFile)); os.IsNotExist(err) {
+		return fmt.Errorf("%w: %s", outline.ErrNoSuchTrash, id)
+	}
+	for _, oldName := range slices.Sorted(maps.Keys(names)) {
+		if err := checkFree(filepath.Join(s.Root, names[oldName]), names[oldName]); err != nil {
+			return err
+		}
+	}
+	for _, oldName := range slices.Sorted(maps.Keys(names)) {
+		if err := os.Rename(s.dir(id, oldName), filepath.Join(s.Root, names[oldName])); err != nil {
+			return fmt.Errorf("restoring %s from trash: %w", oldName, err)
+		}
+	}
+	return os.RemoveAll(s.dir(id))
+}
+
+// checkFree returns outline.ErrTrashConflict when path, shown as name,
+// already exists, as os.Rename would silently replace it.
+func checkFree(path, name string) error {
+	_, err := os.Lstat(path)
+	if err == nil {
+		return fmt.Errorf("%w: %s", outline.ErrTrashConflict, name)
+	}
+	if !os.IsNotExist(err) {
+		return fmt.Errorf("checking %s: %w", name, err)
+	}
+	return nil
+}
+
+// Untrash delegates to UntrashImpl.
+func (s *OSTrashStore) Untrash(ctx context.Context, id string, names map[string]string) error {
+	return s.UntrashImpl(ctx, id, names)
+}
+
+// EmptyTrashImpl permanently deletes everything in the trash.
+func (s *OSTrashStore) EmptyTrashImpl(_ context.Context) error {
+	return os.RemoveAll(s.dir())
+}
+
+// EmptyTrash delegates to EmptyTrashImpl.
+func (s *OSTrashStore) EmptyTrash(ctx context.Context) error {
+	return s.EmptyTrashImpl(ctx)
+}
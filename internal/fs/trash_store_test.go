@@ -0,0 +1,131 @@
+package fs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/eykd/linemark-go/internal/outline"
+)
+
+func TestOSTrashStore_RoundTrip(t *testing.T) {
+	root := t.TempDir()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	store := &OSTrashStore{Root: root, Now: func() time.Time { return now }}
+	ctx := context.Background()
+
+	if entries, err := store.ListTrash(ctx); err != nil || len(entries) != 0 {
+		t.Fatalf("ListTrash() before any deletion = %v, %v; want none", entries, err)
+	}
+
+	for _, name := range []string{"100-200_SID002AABB_draft_b.md", "100-200_SID002AABB_notes.md", "300_SID003AABB_draft_c.md"} {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	first, err := store.Trash(ctx, outline.TrashEntry{
+		SID: "SID002AABB", MP: "100-200", ParentSID: "SID001AABB", Slug: "b",
+		Files: []string{"100-200_SID002AABB_draft_b.md", "100-200_SID002AABB_notes.md"},
+	})
+	if err != nil {
+		t.Fatalf("Trash() error: %v", err)
+	}
+	second, err := store.Trash(ctx, outline.TrashEntry{SID: "SID003AABB", MP: "300", Files: []string{"300_SID003AABB_draft_c.md"}})
+	if err != nil {
+		t.Fatalf("Trash() error: %v", err)
+	}
+	if first.ID != "20260301T120000Z" || second.ID != "20260301T120000Z-2" || !first.Deleted.Equal(now) {
+		t.Errorf("entries = %+v, %+v", first, second)
+	}
+	if left, _ := (&OSReader{Root: root}).ReadDir(ctx); len(left) != 0 {
+		t.Errorf("project still holds %v", left)
+	}
+
+	entries, err := store.ListTrash(ctx)
+	if err != nil || len(entries) != 2 || entries[0].ParentSID != "SID001AABB" || len(entries[0].Files) != 2 || entries[1].ID != second.ID {
+		t.Errorf("ListTrash() = %+v, %v", entries, err)
+	}
+
+	err = store.Untrash(ctx, first.ID, map[string]string{
+		"100-200_SID002AABB_draft_b.md": "100-300_SID002AABB_draft_b.md",
+		"100-200_SID002AABB_notes.md":   "100-300_SID002AABB_notes.md",
+	})
+	if err != nil {
+		t.Fatalf("Untrash() error: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(root, "100-300_SID002AABB_draft_b.md")); err != nil || string(data) != "100-200_SID002AABB_draft_b.md" {
+		t.Errorf("restored draft = %q, %v", data, err)
+	}
+	if entries, _ := store.ListTrash(ctx); len(entries) != 1 {
+		t.Errorf("ListTrash() after restore = %+v, want one entry", entries)
+	}
+
+	if err := store.EmptyTrash(ctx); err != nil {
+		t.Fatalf("EmptyTrash() error: %v", err)
+	}
+	if entries, _ := store.ListTrash(ctx); len(entries) != 0 {
+		t.Errorf("ListTrash() after empty = %+v", entries)
+	}
+}
+
+func TestOSTrashStore_UntrashUnknown(t *testing.T) {
+	store := &OSTrashStore{Root: t.TempDir()}
+
+	for _, id := range []string{"", "../x", ".hidden", "20260301T120000Z"} {
+		if err := store.Untrash(context.Background(), id, nil); !errors.Is(err, outline.ErrNoSuchTrash) {
+			t.Errorf("Untrash(%q) error = %v, want ErrNoSuchTrash", id, err)
+		}
+	}
+}
+
+func TestOSTrashStore_RefusesToOverwrite(t *testing.T) {
+	root := t.TempDir()
+	store := &OSTrashStore{Root: root}
+	ctx := context.Background()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("100_SID001AABB_draft_a.md", "trashed draft")
+	write("100_SID001AABB_notes.md", "trashed notes")
+
+	entry, err := store.Trash(ctx, outline.TrashEntry{
+		SID: "SID001AABB", MP: "100",
+		Files: []string{"100_SID001AABB_draft_a.md", "100_SID001AABB_notes.md"},
+	})
+	if err != nil {
+		t.Fatalf("Trash() error: %v", err)
+	}
+	write("100_SID001AABB_notes.md", "new notes")
+
+	err = store.Untrash(ctx, entry.ID, map[string]string{
+		"100_SID001AABB_draft_a.md": "100_SID001AABB_draft_a.md",
+		"100_SID001AABB_notes.md":   "100_SID001AABB_notes.md",
+	})
+
+	if !errors.Is(err, outline.ErrTrashConflict) {
+		t.Fatalf("Untrash() error = %v, want ErrTrashConflict", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(root, "100_SID001AABB_notes.md")); string(data) != "new notes" {
+		t.Errorf("existing notes = %q, want them kept", data)
+	}
+	if _, err := os.Stat(filepath.Join(root, "100_SID001AABB_draft_a.md")); !os.IsNotExist(err) {
+		t.Errorf("draft restored despite the conflict: %v", err)
+	}
+	if entries, _ := store.ListTrash(ctx); len(entries) != 1 {
+		t.Errorf("ListTrash() = %+v, want the entry kept", entries)
+	}
+
+	write("200_SID002AABB_draft_b.md", "twice listed")
+	_, err = store.Trash(ctx, outline.TrashEntry{
+		SID: "SID002AABB", MP: "200",
+		Files: []string{"200_SID002AABB_draft_b.md", "200_SID002AABB_draft_b.md"},
+	})
+	if !errors.Is(err, outline.ErrTrashConflict) {
+		t.Errorf("Trash() with a repeated file error = %v, want ErrTrashConflict", err)
+	}
+}
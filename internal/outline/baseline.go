@@ -0,0 +1,66 @@
+package outline
+
+import (
+	"context"
+	"slices"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+// WriteBaseline runs Check without the existing baseline and saves the
+// resulting findings as the new baseline, acquiring an advisory lock first.
+func (s *OutlineService) WriteBaseline(ctx context.Context, opts ...CheckOption) (*CheckResult, error) {
+	if err := s.lockForWrite(ctx); err != nil {
+		return nil, err
+	}
+	defer s.locker.Unlock()
+
+	result, err := s.Check(ctx, append(opts, CheckIgnoreBaseline())...)
+	if err != nil {
+		return nil, err
+	}
+	if s.baselineStore != nil {
+		if err := s.baselineStore.SaveBaseline(ctx, domain.NewBaseline(result.Findings)); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// suppressInlineImpl drops findings whose file lists their rule ID under the
+// lmk_ignore frontmatter key. Only findings on content files are eligible;
+// unreadable or malformed files suppress nothing.
+func (s *OutlineService) suppressInlineImpl(ctx context.Context, findings []domain.Finding) ([]domain.Finding, int) {
+	if s.contentReader == nil {
+		return findings, 0
+	}
+	ignored := map[string][]string{}
+	var kept []domain.Finding
+	suppressed := 0
+	for _, f := range findings {
+		ids, ok := ignored[f.Path]
+		if !ok {
+			ids = s.inlineSuppressionsImpl(ctx, f.Path)
+			ignored[f.Path] = ids
+		}
+		if slices.Contains(ids, f.RuleID()) {
+			suppressed++
+			continue
+		}
+		kept = append(kept, f)
+	}
+	return kept, suppressed
+}
+
+// inlineSuppressionsImpl reads the lmk_ignore list from a content file.
+func (s *OutlineService) inlineSuppressionsImpl(ctx context.Context, path string) []string {
+	if _, err := domain.ParseFilename(path); err != nil {
+		return nil
+	}
+	content, err := s.contentReader.ReadFile(ctx, path)
+	if err != nil {
+		return nil
+	}
+	ids, _ := s.fmHandler.GetList(content, domain.SuppressKey)
+	return ids
+}
@@ -0,0 +1,203 @@
+package outline
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+// fakeBaselineStore is a test double for the BaselineStore interface.
+type fakeBaselineStore struct {
+	baseline domain.Baseline
+	loadErr  error
+	saveErr  error
+	saved    domain.Baseline
+}
+
+func (f *fakeBaselineStore) LoadBaseline(_ context.Context) (domain.Baseline, error) {
+	return f.baseline, f.loadErr
+}
+
+func (f *fakeBaselineStore) SaveBaseline(_ context.Context, b domain.Baseline) error {
+	f.saved = b
+	return f.saveErr
+}
+
+// baselineTestService has a draft with two TODO markers and a node missing its notes.
+func baselineTestService(store BaselineStore, locker Locker, draft string) *OutlineService {
+	cfg := domain.DefaultConfig()
+	cfg.Lint.Rules = []string{RuleTODO}
+	reader := &fakeDirectoryReader{files: []string{"100_SID001AABB_draft_act.md"}}
+	content := &fakeContentReader{contents: map[string]string{"100_SID001AABB_draft_act.md": draft}}
+	opts := []Option{WithConfig(cfg), WithContentReader(content)}
+	if store != nil {
+		opts = append(opts, WithBaselineStore(store))
+	}
+	return NewOutlineService(reader, nil, locker, nil, opts...)
+}
+
+const baselineTestDraft = "---\ntitle: Act\n---\nTODO one\nTODO two\n"
+
+func TestOutlineService_Check_Baseline(t *testing.T) {
+	tests := []struct {
+		name           string
+		store          *fakeBaselineStore
+		opts           []CheckOption
+		wantFindings   int
+		wantSuppressed int
+	}{
+		{"no store reports everything", nil, nil, 3, 0},
+		{"empty baseline", &fakeBaselineStore{baseline: domain.Baseline{}}, nil, 3, 0},
+		{
+			name:           "baseline covers known findings by count",
+			store:          &fakeBaselineStore{baseline: domain.Baseline{"todo SID001AABB draft": 1, "missing_doc_type SID001AABB notes": 1}},
+			wantFindings:   1,
+			wantSuppressed: 2,
+		},
+		{
+			name:         "ignore baseline",
+			store:        &fakeBaselineStore{baseline: domain.Baseline{"todo SID001AABB draft": 2}},
+			opts:         []CheckOption{CheckIgnoreBaseline()},
+			wantFindings: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var store BaselineStore
+			if tt.store != nil {
+				store = tt.store
+			}
+			svc := baselineTestService(store, &mockLocker{}, baselineTestDraft)
+
+			result, err := svc.Check(context.Background(), tt.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(result.Findings) != tt.wantFindings || result.Suppressed != tt.wantSuppressed {
+				t.Errorf("findings = %d (suppressed %d), want %d (suppressed %d): %+v",
+					len(result.Findings), result.Suppressed, tt.wantFindings, tt.wantSuppressed, result.Findings)
+			}
+		})
+	}
+}
+
+func TestOutlineService_Check_BaselineLoadError(t *testing.T) {
+	loadErr := errors.New("unreadable baseline")
+	svc := baselineTestService(&fakeBaselineStore{loadErr: loadErr}, &mockLocker{}, baselineTestDraft)
+
+	_, err := svc.Check(context.Background())
+
+	if !errors.Is(err, loadErr) {
+		t.Errorf("error = %v, want %v", err, loadErr)
+	}
+}
+
+func TestOutlineService_Check_InlineSuppression(t *testing.T) {
+	tests := []struct {
+		name           string
+		draft          string
+		wantFindings   int
+		wantSuppressed int
+	}{
+		{"lint rule", "---\ntitle: Act\nlmk_ignore: [todo]\n---\nTODO one\nTODO two\n", 1, 2},
+		{"finding type on other file is kept", "---\ntitle: Act\nlmk_ignore: missing_doc_type\n---\nTODO one\n", 2, 0},
+		{"no suppressions", "---\ntitle: Act\n---\nTODO one\n", 2, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := baselineTestService(nil, &mockLocker{}, tt.draft)
+			svc.fmHandler = fmAdapter{}
+
+			result, err := svc.Check(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(result.Findings) != tt.wantFindings || result.Suppressed != tt.wantSuppressed {
+				t.Errorf("findings = %d (suppressed %d), want %d (suppressed %d): %+v",
+					len(result.Findings), result.Suppressed, tt.wantFindings, tt.wantSuppressed, result.Findings)
+			}
+		})
+	}
+}
+
+func TestOutlineService_Check_InlineSuppressionSkipsUnreadableFiles(t *testing.T) {
+	reader := &fakeDirectoryReader{files: []string{"100_SID001AABB_draft_act.md", "bad name.md"}}
+	svc := NewOutlineService(reader, nil, &mockLocker{}, nil,
+		WithContentReader(&fakeContentReader{err: errors.New("io error")}))
+
+	result, err := svc.Check(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Suppressed != 0 || len(result.Findings) == 0 {
+		t.Errorf("result = %+v, want findings reported and none suppressed", result)
+	}
+}
+
+func TestOutlineService_WriteBaseline(t *testing.T) {
+	store := &fakeBaselineStore{baseline: domain.Baseline{"todo SID001AABB draft": 2}}
+	locker := &mockLocker{}
+	svc := baselineTestService(store, locker, baselineTestDraft)
+
+	result, err := svc.WriteBaseline(context.Background(), CheckSkipRules(string(domain.FindingMissingDocType)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !locker.tryLockCalled || !locker.unlockCalled {
+		t.Error("WriteBaseline should acquire and release the lock")
+	}
+	if len(result.Findings) != 2 {
+		t.Errorf("findings = %d, want 2 (existing baseline ignored)", len(result.Findings))
+	}
+	if len(store.saved) != 1 || store.saved["todo SID001AABB draft"] != 2 {
+		t.Errorf("saved = %v, want two todo occurrences", store.saved)
+	}
+}
+
+func TestOutlineService_WriteBaseline_Errors(t *testing.T) {
+	lockErr := errors.New("locked")
+	saveErr := errors.New("disk full")
+	loadErr := errors.New("unreadable")
+
+	tests := []struct {
+		name    string
+		store   *fakeBaselineStore
+		locker  *mockLocker
+		opts    []CheckOption
+		wantErr error
+	}{
+		{"lock failure", &fakeBaselineStore{}, &mockLocker{tryLockErr: lockErr}, nil, lockErr},
+		{"save failure", &fakeBaselineStore{saveErr: saveErr}, &mockLocker{}, nil, saveErr},
+		{"unknown rule", &fakeBaselineStore{loadErr: loadErr}, &mockLocker{}, []CheckOption{CheckRules("nope")}, ErrUnknownRule},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := baselineTestService(tt.store, tt.locker, baselineTestDraft)
+
+			_, err := svc.WriteBaseline(context.Background(), tt.opts...)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOutlineService_WriteBaseline_WithoutStore(t *testing.T) {
+	svc := baselineTestService(nil, &mockLocker{}, baselineTestDraft)
+
+	result, err := svc.WriteBaseline(context.Background())
+
+	if err != nil || len(result.Findings) != 3 {
+		t.Errorf("WriteBaseline() = %+v, %v; want 3 findings", result, err)
+	}
+}
@@ -0,0 +1,161 @@
+package outline
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+// CopyResult holds the result of a copy operation. SIDs maps each copied
+// node's SID to the SID reserved for its copy, Files maps each copied file
+// to the file created from it, and Rebalanced holds the renames of
+// neighbouring siblings renumbered to open a gap.
+type CopyResult struct {
+	SIDs       map[string]string
+	Files      map[string]string
+	Rebalanced map[string]string
+}
+
+// CopyOption configures the Copy method.
+type CopyOption func(*copyConfig)
+
+type copyConfig struct {
+	recursive   bool
+	noRebalance bool
+}
+
+// CopyRecursive controls whether Copy copies the node's descendants too.
+// It is disabled by default, so only the node itself is copied.
+func CopyRecursive(enabled bool) CopyOption { return func(c *copyConfig) { c.recursive = enabled } }
+
+// CopyRebalance controls whether Copy renumbers neighbouring siblings when
+// no gap exists at the target position, as MoveRebalance does for Move.
+func CopyRebalance(enabled bool) CopyOption { return func(c *copyConfig) { c.noRebalance = !enabled } }
+
+// Copy duplicates a node, or with CopyRecursive its whole subtree, under a
+// new parent, acquiring an advisory lock first. The copy is placed as Move
+// places a node. Every copied node gets a freshly reserved SID, and each of
+// its files is copied with references to the copied files and SIDs updated.
+// When apply is false, nothing is reserved or written, and the planned
+// copies carry a placeholder SID.
+func (s *OutlineService) Copy(ctx context.Context, source, target domain.Selector, before, after string, apply bool, opts ...CopyOption) (*CopyResult, error) {
+	if s.contentReader == nil {
+		return nil, ErrContentUnavailable
+	}
+	var cfg copyConfig
+	for _, o := range opts {
+		o(&cfg)
+	}
+
+	if err := s.lockForWrite(ctx); err != nil {
+		return nil, err
+	}
+	defer s.locker.Unlock()
+
+	parsed, err := s.readAndParse(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceMP, _, err := s.resolveTarget(ctx, parsed, source)
+	if err != nil {
+		return nil, err
+	}
+	targetMP, _, err := s.resolveTarget(ctx, parsed, target)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.recursive && (targetMP == sourceMP || isDescendantMP(targetMP, sourceMP)) {
+		return nil, fmt.Errorf("cannot copy %s into its own subtree at %s: %w", sourceMP, targetMP, ErrCycleDetected)
+	}
+
+	num, rebalanced, err := s.siblingSlot(parsed, targetMP, "", before, after, !cfg.noRebalance)
+	if err != nil {
+		return nil, err
+	}
+	newRootMP := s.config.Numbering.ChildMP(targetMP, num)
+
+	result := &CopyResult{SIDs: map[string]string{}, Files: map[string]string{}, Rebalanced: rebalanced}
+	var copied []domain.ParsedFile
+	for _, pf := range parsed {
+		if pf.MP != sourceMP && !(cfg.recursive && isDescendantMP(pf.MP, sourceMP)) {
+			continue
+		}
+		newSID, ok := result.SIDs[pf.SID]
+		if !ok {
+			newSID = newSIDPlaceholder
+			if apply {
+				if newSID, err = s.reserver.Reserve(ctx); err != nil {
+					return nil, err
+				}
+			}
+			result.SIDs[pf.SID] = newSID
+		}
+		newMP := newRootMP + pf.MP[len(sourceMP):]
+		result.Files[reconstructFilename(pf)] = domain.GenerateFilename(newMP, newSID, pf.DocType, pf.Slug)
+		copied = append(copied, pf)
+	}
+
+	if !apply {
+		return result, nil
+	}
+	if err := s.applyCopyImpl(ctx, copied, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// applyCopyImpl records the reserved SIDs and writes the copied files. All
+// contents are read before neighbours are renumbered, since that may rename
+// the files being copied.
+func (s *OutlineService) applyCopyImpl(ctx context.Context, copied []domain.ParsedFile, result *CopyResult) error {
+	if s.reservationStore != nil {
+		for _, sid := range slices.Sorted(maps.Values(result.SIDs)) {
+			if err := s.reservationStore.CreateReservation(ctx, sid); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Filenames come first so that a file's whole name is replaced rather
+	// than just the SID within it.
+	var pairs []string
+	for _, oldName := range slices.Sorted(maps.Keys(result.Files)) {
+		pairs = append(pairs, oldName, result.Files[oldName])
+	}
+	for _, oldSID := range slices.Sorted(maps.Keys(result.SIDs)) {
+		pairs = append(pairs, oldSID, result.SIDs[oldSID])
+	}
+	replacer := strings.NewReplacer(pairs...)
+
+	contents := map[string]string{}
+	for _, pf := range copied {
+		oldName := reconstructFilename(pf)
+		content, err := s.contentReader.ReadFile(ctx, oldName)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", oldName, err)
+		}
+		contents[result.Files[oldName]] = replacer.Replace(content)
+	}
+
+	if err := applyRenames(ctx, s.renamer, result.Rebalanced); err != nil {
+		return err
+	}
+	for i, newName := range slices.Sorted(maps.Keys(contents)) {
+		if err := s.writer.WriteFile(ctx, newName, contents[newName]); err != nil {
+			// Until a copy exists, the renumbered neighbours can go back.
+			if i == 0 {
+				if rbErr := rollbackRenames(ctx, s.renamer, renamePairs(result.Rebalanced)); rbErr != nil {
+					return fmt.Errorf("%w; rollback failed: %v", err, rbErr)
+				}
+			}
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+package outline
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"testing"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+// copyTestContents gives each moveTestFiles file content that refers to its
+// own SID and, for the chapter, to the scene below it.
+func copyTestContents() map[string]string {
+	return map[string]string{
+		"100_SID001AABB_draft_part-one.md":      "---\ntitle: Part One\n---\n",
+		"100_SID001AABB_notes.md":               "sid: SID001AABB\n",
+		"100-200_SID002CCDD_draft_chapter.md":   "---\ntitle: Chapter\n---\nSee [scene](100-200-100_SID003EEFF_draft_scene.md).\n",
+		"100-200-100_SID003EEFF_draft_scene.md": "---\ntitle: Scene\n---\nOnce.\n",
+		"200_SID004GGHH_draft_part-two.md":      "---\ntitle: Part Two\n---\n",
+	}
+}
+
+type copyTestDeps struct {
+	renamer      *fakeFileRenamer
+	writer       *fakeFileWriter
+	reserver     *sequenceSIDReserver
+	reservations *fakeReservationStore
+	content      *fakeContentReader
+}
+
+func newCopyTestService(files []string) (*OutlineService, *copyTestDeps) {
+	deps := &copyTestDeps{
+		renamer:      &fakeFileRenamer{},
+		writer:       &fakeFileWriter{},
+		reserver:     &sequenceSIDReserver{sids: []string{"SIDN01AABB", "SIDN02AABB", "SIDN03AABB"}},
+		reservations: &fakeReservationStore{},
+		content:      &fakeContentReader{contents: copyTestContents()},
+	}
+	svc := NewOutlineService(&fakeDirectoryReader{files: files}, deps.writer, &mockLocker{}, deps.reserver,
+		WithRenamer(deps.renamer), WithContentReader(deps.content), WithReservationStore(deps.reservations))
+	return svc, deps
+}
+
+func TestOutlineService_Copy_SingleNode(t *testing.T) {
+	svc, deps := newCopyTestService(moveTestFiles())
+	source, _ := domain.ParseSelector("100")
+	target, _ := domain.ParseSelector("200")
+
+	result, err := svc.Copy(context.Background(), source, target, "", "", true)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := map[string]string{"SID001AABB": "SIDN01AABB"}; !maps.Equal(result.SIDs, want) {
+		t.Errorf("SIDs = %v, want %v", result.SIDs, want)
+	}
+	want := map[string]string{
+		"200-100_SIDN01AABB_draft_part-one.md": "---\ntitle: Part One\n---\n",
+		"200-100_SIDN01AABB_notes.md":          "sid: SIDN01AABB\n",
+	}
+	if !maps.Equal(deps.writer.written, want) {
+		t.Errorf("written = %v, want %v", deps.writer.written, want)
+	}
+	if len(deps.renamer.renames) != 0 {
+		t.Errorf("renames = %v, want none", deps.renamer.renames)
+	}
+	if len(deps.reservations.created) != 1 {
+		t.Errorf("reservations = %v, want one", deps.reservations.created)
+	}
+}
+
+func TestOutlineService_Copy_Recursive(t *testing.T) {
+	svc, deps := newCopyTestService(moveTestFiles())
+	source, _ := domain.ParseSelector("SID002CCDD")
+	target, _ := domain.ParseSelector("100")
+
+	result, err := svc.Copy(context.Background(), source, target, "", "", true, CopyRecursive(true))
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantFiles := map[string]string{
+		"100-200_SID002CCDD_draft_chapter.md":   "100-300_SIDN01AABB_draft_chapter.md",
+		"100-200-100_SID003EEFF_draft_scene.md": "100-300-100_SIDN02AABB_draft_scene.md",
+	}
+	if !maps.Equal(result.Files, wantFiles) {
+		t.Errorf("files = %v, want %v", result.Files, wantFiles)
+	}
+	want := map[string]string{
+		"100-300_SIDN01AABB_draft_chapter.md":   "---\ntitle: Chapter\n---\nSee [scene](100-300-100_SIDN02AABB_draft_scene.md).\n",
+		"100-300-100_SIDN02AABB_draft_scene.md": "---\ntitle: Scene\n---\nOnce.\n",
+	}
+	if !maps.Equal(deps.writer.written, want) {
+		t.Errorf("written = %v, want %v", deps.writer.written, want)
+	}
+}
+
+func TestOutlineService_Copy_Placement(t *testing.T) {
+	files := []string{
+		"100_SID001AABB_draft_one.md",
+		"101_SID002CCDD_draft_two.md",
+		"200_SID003EEFF_draft_three.md",
+	}
+
+	t.Run("before a sibling places the copy ahead of it", func(t *testing.T) {
+		svc, deps := newCopyTestService(append(files, "200-100_SID004GGHH_draft_x.md"))
+		deps.content.contents = map[string]string{"200-100_SID004GGHH_draft_x.md": "x"}
+		source, _ := domain.ParseSelector("200-100")
+		target, _ := domain.ParseSelector("200")
+
+		result, err := svc.Copy(context.Background(), source, target, "200-100", "", true)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := result.Files["200-100_SID004GGHH_draft_x.md"]; got != "200-010_SIDN01AABB_draft_x.md" {
+			t.Errorf("copy = %q, want it placed before the original", got)
+		}
+	})
+
+	t.Run("disabled rebalancing fails", func(t *testing.T) {
+		svc, _ := newCopyTestService(append(files, "200-100_SID004GGHH_draft_x.md", "200-101_SID005IIJJ_draft_y.md"))
+		source, _ := domain.ParseSelector("100")
+		target, _ := domain.ParseSelector("200")
+
+		_, err := svc.Copy(context.Background(), source, target, "200-101", "", true, CopyRebalance(false))
+
+		if !errors.Is(err, domain.ErrNoSlotAvailable) {
+			t.Errorf("error = %v, want ErrNoSlotAvailable", err)
+		}
+	})
+
+	t.Run("copy rebalances neighbours that move", func(t *testing.T) {
+		svc, deps := newCopyTestService(append(files, "200-100_SID004GGHH_draft_x.md", "200-101_SID005IIJJ_draft_y.md"))
+		deps.content.contents = map[string]string{"100_SID001AABB_draft_one.md": "one"}
+		source, _ := domain.ParseSelector("100")
+		target, _ := domain.ParseSelector("200")
+
+		result, err := svc.Copy(context.Background(), source, target, "200-101", "", true)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Rebalanced) == 0 || len(deps.renamer.renames) != len(result.Rebalanced) {
+			t.Errorf("rebalanced = %v, renamed %v", result.Rebalanced, deps.renamer.renames)
+		}
+	})
+}
+
+func TestOutlineService_Copy_DryRun(t *testing.T) {
+	svc, deps := newCopyTestService(moveTestFiles())
+	source, _ := domain.ParseSelector("100")
+	target, _ := domain.ParseSelector("200")
+
+	result, err := svc.Copy(context.Background(), source, target, "", "", false, CopyRecursive(true))
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.SIDs) != 3 || len(result.Files) != 4 {
+		t.Errorf("result = %+v, want three nodes and four files planned", result)
+	}
+	if len(deps.writer.written) != 0 || len(deps.reservations.created) != 0 || len(deps.reserver.sids) != 3 {
+		t.Errorf("dry run wrote %v, recorded %v and reserved %d SIDs",
+			deps.writer.written, deps.reservations.created, 3-len(deps.reserver.sids))
+	}
+	for oldSID, newSID := range result.SIDs {
+		if newSID != newSIDPlaceholder {
+			t.Errorf("planned SID for %s = %q, want %q", oldSID, newSID, newSIDPlaceholder)
+		}
+	}
+	if got := result.Files["100_SID001AABB_notes.md"]; got != "200-100_"+newSIDPlaceholder+"_notes.md" {
+		t.Errorf("planned notes file = %q", got)
+	}
+}
+
+func TestOutlineService_Copy_Errors(t *testing.T) {
+	lockErr := errors.New("locked")
+	readErr := errors.New("read failed")
+	reserveErr := errors.New("no more SIDs")
+	contentErr := errors.New("unreadable")
+	writeErr := errors.New("disk full")
+	createErr := errors.New("no reservations")
+	renameErr := errors.New("rename failed")
+
+	tests := []struct {
+		name   string
+		source string
+		target string
+		before string
+		opts   []CopyOption
+		setup  func(*OutlineService, *copyTestDeps)
+		want   error
+	}{
+		{"no content reader", "100", "200", "", nil, func(s *OutlineService, _ *copyTestDeps) { s.contentReader = nil }, ErrContentUnavailable},
+		{"locked", "100", "200", "", nil, func(s *OutlineService, _ *copyTestDeps) { s.locker = &mockLocker{tryLockErr: lockErr} }, lockErr},
+		{"unreadable directory", "100", "200", "", nil, func(s *OutlineService, _ *copyTestDeps) { s.reader = &fakeDirectoryReader{err: readErr} }, readErr},
+		{"missing source", "SID999ZZZZ", "200", "", nil, nil, ErrNodeNotFound},
+		{"missing target", "100", "900", "", nil, nil, ErrNodeNotFound},
+		{"recursive into itself", "100", "100", "", []CopyOption{CopyRecursive(true)}, nil, ErrCycleDetected},
+		{"recursive into a descendant", "100", "100-200", "", []CopyOption{CopyRecursive(true)}, nil, ErrCycleDetected},
+		{"reserve fails", "100", "200", "", nil, func(_ *OutlineService, d *copyTestDeps) { d.reserver.err = reserveErr }, reserveErr},
+		{"reservation fails", "100", "200", "", nil, func(_ *OutlineService, d *copyTestDeps) { d.reservations.createErr = createErr }, createErr},
+		{"content unreadable", "100", "200", "", nil, func(_ *OutlineService, d *copyTestDeps) { d.content.err = contentErr }, contentErr},
+		{"write fails", "100", "200", "", nil, func(_ *OutlineService, d *copyTestDeps) { d.writer.writeErr = writeErr }, writeErr},
+		{"rebalance rename fails", "100-200-100", "100", "100-200", nil, func(s *OutlineService, d *copyTestDeps) {
+			s.reader = &fakeDirectoryReader{files: append(moveTestFiles(), "100-199_SID009ZZZZ_draft_tight.md")}
+			d.renamer.err = renameErr
+		}, renameErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, deps := newCopyTestService(moveTestFiles())
+			if tt.setup != nil {
+				tt.setup(svc, deps)
+			}
+			source, _ := domain.ParseSelector(tt.source)
+			target, _ := domain.ParseSelector(tt.target)
+
+			_, err := svc.Copy(context.Background(), source, target, tt.before, "", true, tt.opts...)
+
+			if !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutlineService_Copy_WriteErrorRollsBackRebalance(t *testing.T) {
+	svc, deps := newCopyTestService(append(moveTestFiles(), "100-199_SID009ZZZZ_draft_tight.md"))
+	deps.writer.writeErr = errors.New("disk full")
+	source, _ := domain.ParseSelector("100-200-100")
+	target, _ := domain.ParseSelector("100")
+
+	_, err := svc.Copy(context.Background(), source, target, "100-200", "", true)
+
+	if !errors.Is(err, deps.writer.writeErr) {
+		t.Fatalf("error = %v, want %v", err, deps.writer.writeErr)
+	}
+	if len(deps.renamer.renames) == 0 || len(deps.renamer.renames)%2 != 0 {
+		t.Fatalf("renames = %v, want rebalance renames each reversed", deps.renamer.renames)
+	}
+	moved := map[string]int{}
+	for _, r := range deps.renamer.renames {
+		moved[r[0]]++
+		moved[r[1]]--
+	}
+	for name, n := range moved {
+		if n != 0 {
+			t.Errorf("%s not restored: renames = %v", name, deps.renamer.renames)
+		}
+	}
+}
+
+func TestOutlineService_Copy_NonRecursiveIntoItself(t *testing.T) {
+	svc, deps := newCopyTestService(moveTestFiles())
+	source, _ := domain.ParseSelector("100-200")
+
+	result, err := svc.Copy(context.Background(), source, source, "", "", true)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{"100-200_SID002CCDD_draft_chapter.md": "100-200-200_SIDN01AABB_draft_chapter.md"}
+	if !maps.Equal(result.Files, want) || len(deps.writer.written) != 1 {
+		t.Errorf("files = %v, written %v; want %v", result.Files, deps.writer.written, want)
+	}
+}
@@ -14,6 +14,12 @@ func (slugAdapter) Slug(s string) string { return slug.Slug(s) }
 type fmAdapter struct{}
 
 func (fmAdapter) GetTitle(input string) (string, error) { return frontmatter.GetTitle(input) }
+func (fmAdapter) GetFields(input string) (map[string]string, error) {
+	return frontmatter.GetFields(input)
+}
+func (fmAdapter) GetList(input, key string) ([]string, error) {
+	return frontmatter.GetList(input, key)
+}
 func (fmAdapter) SetTitle(input, newTitle string) (string, error) {
 	return frontmatter.SetTitle(input, newTitle)
 }
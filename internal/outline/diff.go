@@ -0,0 +1,242 @@
+package outline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+// ErrSnapshotNotFound is returned when a diff names a revision or directory
+// that cannot be found.
+var ErrSnapshotNotFound = errors.New("no such revision or directory")
+
+// SnapshotResolver abstracts locating a past or external state of the
+// outline, such as a version-control revision or another directory.
+type SnapshotResolver interface {
+	ResolveSnapshot(ctx context.Context, ref string) (Snapshot, error)
+}
+
+// DiffStatus classifies a node in a structural diff.
+type DiffStatus string
+
+// Diff statuses.
+const (
+	DiffAdded   DiffStatus = "added"
+	DiffDeleted DiffStatus = "deleted"
+	DiffChanged DiffStatus = "changed"
+)
+
+// NodeDiff describes how one node, keyed by SID, differs between two
+// outline states. MPs, parents and titles are empty on the side where the
+// node is absent. A node counts as moved when its parent changed or its
+// order among its remaining siblings did, not when renumbering alone
+// changed its MP.
+type NodeDiff struct {
+	SID            string
+	Status         DiffStatus
+	OldMP          string
+	NewMP          string
+	OldParent      string
+	NewParent      string
+	OldTitle       string
+	NewTitle       string
+	Moved          bool
+	Retitled       bool
+	DocsAdded      []string
+	DocsRemoved    []string
+	ContentChanged []string
+}
+
+// sortMP is the MP a node is listed at: its new MP, or its old one when it
+// was deleted.
+func (d NodeDiff) sortMP() string {
+	if d.NewMP == "" {
+		return d.OldMP
+	}
+	return d.NewMP
+}
+
+// DiffResult lists the nodes that differ, in outline order.
+type DiffResult struct {
+	Nodes []NodeDiff
+}
+
+// projectSnapshot is the service's own directory viewed as a Snapshot.
+type projectSnapshot struct {
+	DirectoryReader
+	ContentReader
+}
+
+// resolveSnapshot returns the snapshot for ref, where "" names the project
+// directory itself.
+func (s *OutlineService) resolveSnapshot(ctx context.Context, ref string) (Snapshot, error) {
+	if ref == "" {
+		return projectSnapshot{s.reader, s.contentReader}, nil
+	}
+	if s.snapshotResolver == nil {
+		return nil, fmt.Errorf("%w: %s", ErrSnapshotNotFound, ref)
+	}
+	return s.snapshotResolver.ResolveSnapshot(ctx, ref)
+}
+
+// Diff compares the outline at two refs node by node, keyed by SID. A ref
+// names a revision or directory known to the SnapshotResolver; "" names the
+// project directory.
+func (s *OutlineService) Diff(ctx context.Context, from, to string) (*DiffResult, error) {
+	var snaps [2]Snapshot
+	for i, ref := range []string{from, to} {
+		snap, err := s.resolveSnapshot(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		snaps[i] = snap
+	}
+	return s.diffImpl(ctx, snaps[0], snaps[1])
+}
+
+// diffImpl performs the I/O operations for Diff and DiffSnapshots.
+func (s *OutlineService) diffImpl(ctx context.Context, from, to Snapshot) (*DiffResult, error) {
+	before, err := readSnapshotImpl(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	after, err := readSnapshotImpl(ctx, to)
+	if err != nil {
+		return nil, err
+	}
+	return &DiffResult{Nodes: s.diffSides(before, after)}, nil
+}
+
+// diffSides compares two sides by SID and returns the nodes that differ,
+// ordered by MP, using the old MP for deleted nodes.
+func (s *OutlineService) diffSides(before, after mergeSide) []NodeDiff {
+	stable := stableSiblings(before, after)
+
+	var diffs []NodeDiff
+	for _, sid := range slices.Sorted(maps.Keys(before)) {
+		if after[sid] == nil {
+			diffs = append(diffs, NodeDiff{
+				SID: sid, Status: DiffDeleted,
+				OldMP: before[sid].mp, OldParent: before[sid].parent, OldTitle: s.sideTitle(before[sid]),
+			})
+		}
+	}
+	for _, sid := range slices.Sorted(maps.Keys(after)) {
+		n := after[sid]
+		d := NodeDiff{SID: sid, Status: DiffAdded, NewMP: n.mp, NewParent: n.parent, NewTitle: s.sideTitle(n)}
+		o := before[sid]
+		if o == nil {
+			diffs = append(diffs, d)
+			continue
+		}
+		d.Status, d.OldMP, d.OldParent, d.OldTitle = DiffChanged, o.mp, o.parent, s.sideTitle(o)
+		d.Moved = o.parent != n.parent || !stable[sid]
+		d.Retitled = d.OldTitle != d.NewTitle
+		for _, docType := range slices.Sorted(maps.Keys(n.docs)) {
+			od, ok := o.docs[docType]
+			switch {
+			case !ok:
+				d.DocsAdded = append(d.DocsAdded, docType)
+			case s.contentChanged(od.content, n.docs[docType].content, d.NewTitle):
+				d.ContentChanged = append(d.ContentChanged, docType)
+			}
+		}
+		for _, docType := range slices.Sorted(maps.Keys(o.docs)) {
+			if _, ok := n.docs[docType]; !ok {
+				d.DocsRemoved = append(d.DocsRemoved, docType)
+			}
+		}
+		if d.Moved || d.Retitled || d.DocsAdded != nil || d.DocsRemoved != nil || d.ContentChanged != nil {
+			diffs = append(diffs, d)
+		}
+	}
+
+	slices.SortStableFunc(diffs, func(a, b NodeDiff) int {
+		return strings.Compare(a.sortMP(), b.sortMP())
+	})
+	return diffs
+}
+
+// sideTitle returns the title in a node's draft, or "" when it has none.
+func (s *OutlineService) sideTitle(n *mergeNode) string {
+	title, _ := s.fmHandler.GetTitle(n.docs[domain.DocTypeDraft].content)
+	return title
+}
+
+// contentChanged reports whether a document changed other than by taking
+// the node's new title.
+func (s *OutlineService) contentChanged(before, after, newTitle string) bool {
+	if before == after {
+		return false
+	}
+	retitled, err := s.fmHandler.SetTitle(before, newTitle)
+	return err != nil || retitled != after
+}
+
+// stableSiblings returns the SIDs that keep both their parent and their
+// order among the siblings present on both sides: the longest common
+// subsequence of each parent's children. The rest of those siblings moved.
+func stableSiblings(before, after mergeSide) map[string]bool {
+	stable := map[string]bool{}
+	oldKids, newKids := siblingOrder(before, after), siblingOrder(after, before)
+	for parent, a := range oldKids {
+		for _, sid := range longestCommonSubsequence(a, newKids[parent]) {
+			stable[sid] = true
+		}
+	}
+	return stable
+}
+
+// siblingOrder lists each parent's children on side, in sibling order,
+// keeping only those with the same parent on other.
+func siblingOrder(side, other mergeSide) map[string][]string {
+	kids := map[string][]string{}
+	for _, sid := range slices.Sorted(maps.Keys(side)) {
+		n := side[sid]
+		if o := other[sid]; o != nil && o.parent == n.parent {
+			kids[n.parent] = append(kids[n.parent], sid)
+		}
+	}
+	for _, list := range kids {
+		slices.SortStableFunc(list, func(a, b string) int { return side[a].num - side[b].num })
+	}
+	return kids
+}
+
+// longestCommonSubsequence returns a longest sequence of elements that
+// appear in both a and b in the same order.
+func longestCommonSubsequence(a, b []string) []string {
+	// lengths[i][j] is the LCS length of a[i:] and b[j:].
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+
+	var lcs []string
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			lcs = append(lcs, a[i])
+			i, j = i+1, j+1
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return lcs
+}
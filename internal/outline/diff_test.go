@@ -0,0 +1,197 @@
+package outline
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// fakeSnapshotResolver is a test double for the SnapshotResolver interface.
+type fakeSnapshotResolver struct {
+	snapshots map[string]Snapshot
+	err       error
+}
+
+func (f *fakeSnapshotResolver) ResolveSnapshot(_ context.Context, ref string) (Snapshot, error) {
+	return f.snapshots[ref], f.err
+}
+
+// draftWithTitle returns draft content with the given title and body.
+func draftWithTitle(title, body string) string {
+	return formatFrontmatter(fmAdapter{}, title) + body
+}
+
+// diffBase is a part with three chapters.
+var diffBase = map[string]string{
+	"100_SIDPARTAA1_draft_part.md":      draftWithTitle("Part", ""),
+	"100-100_SIDCHAPAA1_draft_one.md":   draftWithTitle("One", "one\n"),
+	"100-100_SIDCHAPAA1_notes.md":       "notes\n",
+	"100-200_SIDCHAPAA2_draft_two.md":   draftWithTitle("Two", ""),
+	"100-300_SIDCHAPAA3_draft_three.md": draftWithTitle("Three", ""),
+}
+
+// runDiff diffs the "old" state against the project directory.
+func runDiff(t *testing.T, old, current map[string]string) []NodeDiff {
+	t.Helper()
+	project := &fakeSnapshot{files: current}
+	resolver := &fakeSnapshotResolver{snapshots: map[string]Snapshot{"old": &fakeSnapshot{files: old}}}
+	svc := NewOutlineService(project, nil, nil, nil, WithContentReader(project), WithSnapshotResolver(resolver))
+
+	result, err := svc.Diff(context.Background(), "old", "")
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	return result.Nodes
+}
+
+func TestOutlineService_Diff(t *testing.T) {
+	tests := []struct {
+		name    string
+		current map[string]string
+		want    []NodeDiff
+	}{
+		{
+			name:    "no changes",
+			current: diffBase,
+		},
+		{
+			name: "renumbering alone is not a move",
+			current: withChanges(diffBase, map[string]string{
+				"100-200_SIDCHAPAA2_draft_two.md":   "",
+				"100-300_SIDCHAPAA3_draft_three.md": "",
+				"100-110_SIDCHAPAA2_draft_two.md":   draftWithTitle("Two", ""),
+				"100-120_SIDCHAPAA3_draft_three.md": draftWithTitle("Three", ""),
+			}),
+		},
+		{
+			name: "reordered siblings report only the moved node",
+			current: withChanges(diffBase, map[string]string{
+				"100-300_SIDCHAPAA3_draft_three.md": "",
+				"100-050_SIDCHAPAA3_draft_three.md": draftWithTitle("Three", ""),
+			}),
+			want: []NodeDiff{{
+				SID: "SIDCHAPAA3", Status: DiffChanged, OldMP: "100-300", NewMP: "100-050",
+				OldParent: "SIDPARTAA1", NewParent: "SIDPARTAA1", OldTitle: "Three", NewTitle: "Three", Moved: true,
+			}},
+		},
+		{
+			name: "moved under a new parent",
+			current: withChanges(diffBase, map[string]string{
+				"100-300_SIDCHAPAA3_draft_three.md":     "",
+				"100-100-100_SIDCHAPAA3_draft_three.md": draftWithTitle("Three", ""),
+			}),
+			want: []NodeDiff{{
+				SID: "SIDCHAPAA3", Status: DiffChanged, OldMP: "100-300", NewMP: "100-100-100",
+				OldParent: "SIDPARTAA1", NewParent: "SIDCHAPAA1", OldTitle: "Three", NewTitle: "Three", Moved: true,
+			}},
+		},
+		{
+			name: "added and deleted, listed in outline order",
+			current: withChanges(diffBase, map[string]string{
+				"100-200_SIDCHAPAA2_draft_two.md": "",
+				"050_SIDNEWAAA1_draft_preface.md": draftWithTitle("Preface", ""),
+			}),
+			want: []NodeDiff{
+				{SID: "SIDNEWAAA1", Status: DiffAdded, NewMP: "050", NewTitle: "Preface"},
+				{SID: "SIDCHAPAA2", Status: DiffDeleted, OldMP: "100-200", OldParent: "SIDPARTAA1", OldTitle: "Two"},
+			},
+		},
+		{
+			name: "retitled without other edits",
+			current: withChanges(diffBase, map[string]string{
+				"100-100_SIDCHAPAA1_draft_one.md":   "",
+				"100-100_SIDCHAPAA1_draft_first.md": draftWithTitle("First", "one\n"),
+			}),
+			want: []NodeDiff{{
+				SID: "SIDCHAPAA1", Status: DiffChanged, OldMP: "100-100", NewMP: "100-100",
+				OldParent: "SIDPARTAA1", NewParent: "SIDPARTAA1", OldTitle: "One", NewTitle: "First", Retitled: true,
+			}},
+		},
+		{
+			name: "doc types and content changed",
+			current: withChanges(diffBase, map[string]string{
+				"100-100_SIDCHAPAA1_draft_one.md": draftWithTitle("One", "one, revised\n"),
+				"100-100_SIDCHAPAA1_notes.md":     "",
+				"100-100_SIDCHAPAA1_research.md":  "sources\n",
+			}),
+			want: []NodeDiff{{
+				SID: "SIDCHAPAA1", Status: DiffChanged, OldMP: "100-100", NewMP: "100-100",
+				OldParent: "SIDPARTAA1", NewParent: "SIDPARTAA1", OldTitle: "One", NewTitle: "One",
+				DocsAdded: []string{"research"}, DocsRemoved: []string{"notes"}, ContentChanged: []string{"draft"},
+			}},
+		},
+		{
+			name: "content changed in a document without frontmatter",
+			current: withChanges(diffBase, map[string]string{
+				"100-100_SIDCHAPAA1_notes.md": "more notes\n",
+			}),
+			want: []NodeDiff{{
+				SID: "SIDCHAPAA1", Status: DiffChanged, OldMP: "100-100", NewMP: "100-100",
+				OldParent: "SIDPARTAA1", NewParent: "SIDPARTAA1", OldTitle: "One", NewTitle: "One",
+				ContentChanged: []string{"notes"},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := runDiff(t, diffBase, tt.current)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff():\n got %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutlineService_Diff_Errors(t *testing.T) {
+	resolveErr := errors.New("bad revision")
+	readErr := errors.New("unreadable")
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		project *fakeSnapshot
+		wantErr error
+	}{
+		{"no resolver", nil, &fakeSnapshot{}, ErrSnapshotNotFound},
+		{"resolver failure", []Option{WithSnapshotResolver(&fakeSnapshotResolver{err: resolveErr})}, &fakeSnapshot{}, resolveErr},
+		{
+			"project unreadable",
+			[]Option{WithSnapshotResolver(&fakeSnapshotResolver{snapshots: map[string]Snapshot{"HEAD": &fakeSnapshot{}}})},
+			&fakeSnapshot{dirErr: readErr},
+			readErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option{WithContentReader(tt.project)}, tt.opts...)
+			svc := NewOutlineService(tt.project, nil, nil, nil, opts...)
+
+			_, err := svc.Diff(context.Background(), "HEAD", "")
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLongestCommonSubsequence(t *testing.T) {
+	tests := []struct {
+		a, b, want []string
+	}{
+		{[]string{"a", "b", "x"}, []string{"x", "a", "b"}, []string{"a", "b"}},
+		{[]string{"x", "a", "b"}, []string{"a", "b", "x"}, []string{"a", "b"}},
+		{[]string{"a", "b"}, []string{"b", "a"}, []string{"b"}},
+		{nil, []string{"a"}, nil},
+	}
+
+	for _, tt := range tests {
+		if got := longestCommonSubsequence(tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("longestCommonSubsequence(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
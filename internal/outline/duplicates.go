@@ -0,0 +1,257 @@
+package outline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+// ErrNotDuplicate is returned when --keep names an MP that does not share its SID with another node.
+var ErrNotDuplicate = errors.New("no duplicate SID at MP")
+
+// duplicateGroup lists the MPs sharing one SID, in order of first appearance.
+type duplicateGroup struct {
+	sid string
+	mps []string
+}
+
+// findDuplicateGroups returns the SIDs used at more than one MP.
+func findDuplicateGroups(parsed []domain.ParsedFile) []duplicateGroup {
+	var groups []duplicateGroup
+	index := map[string]int{}
+	for _, pf := range parsed {
+		i, ok := index[pf.SID]
+		if !ok {
+			index[pf.SID] = len(groups)
+			groups = append(groups, duplicateGroup{sid: pf.SID, mps: []string{pf.MP}})
+			continue
+		}
+		if !slices.Contains(groups[i].mps, pf.MP) {
+			groups[i].mps = append(groups[i].mps, pf.MP)
+		}
+	}
+	return slices.DeleteFunc(groups, func(g duplicateGroup) bool { return len(g.mps) < 2 })
+}
+
+// keeperMP picks the MP that keeps the group's SID: the one named in keep,
+// else the first listed.
+func (g duplicateGroup) keeperMP(keep []string) string {
+	for _, mp := range g.mps {
+		if slices.Contains(keep, mp) {
+			return mp
+		}
+	}
+	return g.mps[0]
+}
+
+// validateKeep checks that every kept MP belongs to a duplicate group.
+func validateKeep(groups []duplicateGroup, keep []string) error {
+	for _, mp := range keep {
+		if !slices.ContainsFunc(groups, func(g duplicateGroup) bool { return slices.Contains(g.mps, mp) }) {
+			return fmt.Errorf("%w: %s", ErrNotDuplicate, mp)
+		}
+	}
+	return nil
+}
+
+// fixDuplicateSIDsImpl gives every non-kept node of each duplicate SID group
+// a freshly reserved SID, renames its files and rewrites links to the old
+// filenames and SID in every content file. Parsed files are updated in place.
+func (s *OutlineService) fixDuplicateSIDsImpl(ctx context.Context, parsed []domain.ParsedFile, keep []string) ([]RepairAction, error) {
+	groups := findDuplicateGroups(parsed)
+	if err := validateKeep(groups, keep); err != nil {
+		return nil, err
+	}
+
+	var repairs []RepairAction
+	renamed := map[string]string{}
+	holders := map[string]map[string]string{}
+	for _, g := range groups {
+		keeper := g.keeperMP(keep)
+		holders[g.sid] = map[string]string{keeper: g.sid}
+		for _, mp := range g.mps {
+			if mp == keeper {
+				continue
+			}
+			newSID, err := s.reserver.Reserve(ctx)
+			if err != nil {
+				return nil, err
+			}
+			holders[g.sid][mp] = newSID
+			for i, pf := range parsed {
+				if pf.SID != g.sid || pf.MP != mp {
+					continue
+				}
+				oldName := reconstructFilename(pf)
+				newName := domain.GenerateFilename(pf.MP, newSID, pf.DocType, pf.Slug)
+				if err := s.renamer.RenameFile(ctx, oldName, newName); err != nil {
+					return nil, err
+				}
+				parsed[i].SID = newSID
+				renamed[oldName] = newName
+				repairs = append(repairs, RepairAction{Type: domain.FindingDuplicateSID, Old: oldName, New: newName})
+			}
+		}
+	}
+
+	if err := s.rewriteLinksImpl(ctx, parsed, renamed, holders); err != nil {
+		return nil, err
+	}
+	return repairs, nil
+}
+
+// sidLinkRegex matches a link target naming a node by SID alone: a Markdown
+// link target or a wiki link, either optionally with the sid: prefix.
+func sidLinkRegex(sid string) *regexp.Regexp {
+	return regexp.MustCompile(`(\]\((?:sid:)?|\[\[(?:sid:)?)` + regexp.QuoteMeta(sid) + `\b`)
+}
+
+// rewriteLinksImpl replaces references to renamed files in every readable
+// content file. holders maps each duplicate SID to the MPs that held it and
+// the SID each holds now. A link naming a node by that SID alone is
+// ambiguous, so it is taken to mean the nearest holder at or above the
+// linking file: within a re-issued node's subtree it is rewritten to the new
+// SID, elsewhere it keeps naming the node that kept the SID. Parsed files
+// must already carry their new SIDs.
+func (s *OutlineService) rewriteLinksImpl(ctx context.Context, parsed []domain.ParsedFile, renamed map[string]string, holders map[string]map[string]string) error {
+	if s.contentReader == nil || len(renamed) == 0 {
+		return nil
+	}
+	var pairs []string
+	for _, oldName := range slices.Sorted(maps.Keys(renamed)) {
+		pairs = append(pairs, oldName, renamed[oldName])
+	}
+	replacer := strings.NewReplacer(pairs...)
+
+	for _, pf := range parsed {
+		filename := reconstructFilename(pf)
+		content, err := s.contentReader.ReadFile(ctx, filename)
+		if err != nil {
+			continue
+		}
+		updated := replacer.Replace(content)
+		for oldSID, held := range holders {
+			if sid := held[nearestHolderMP(held, pf.MP)]; sid != "" && sid != oldSID {
+				updated = sidLinkRegex(oldSID).ReplaceAllString(updated, "${1}"+sid)
+			}
+		}
+		if updated != content {
+			if err := s.writer.WriteFile(ctx, filename, updated); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// nearestHolderMP returns the deepest MP of held that is mp itself or one of
+// its ancestors, or "" if there is none.
+func nearestHolderMP(held map[string]string, mp string) string {
+	var nearest string
+	for holder := range held {
+		if (mp == holder || strings.HasPrefix(mp, holder+"-")) && len(holder) > len(nearest) {
+			nearest = holder
+		}
+	}
+	return nearest
+}
+
+// sidsByMP returns the SIDs at each MP in order of first appearance.
+func sidsByMP(parsed []domain.ParsedFile) map[string][]string {
+	sidsAt := map[string][]string{}
+	for _, pf := range parsed {
+		if !slices.Contains(sidsAt[pf.MP], pf.SID) {
+			sidsAt[pf.MP] = append(sidsAt[pf.MP], pf.SID)
+		}
+	}
+	return sidsAt
+}
+
+// duplicateMPs returns, for each MP held by more than one node, its SIDs in
+// order of first appearance. MPs are returned in sorted order.
+func duplicateMPs(parsed []domain.ParsedFile) ([]string, map[string][]string) {
+	sidsAt := sidsByMP(parsed)
+	maps.DeleteFunc(sidsAt, func(_ string, sids []string) bool { return len(sids) < 2 })
+	return slices.Sorted(maps.Keys(sidsAt)), sidsAt
+}
+
+// findDuplicateMPFindings reports every node sharing its MP with an earlier one.
+func findDuplicateMPFindings(parsed []domain.ParsedFile) []domain.Finding {
+	var findings []domain.Finding
+	mps, sidsAt := duplicateMPs(parsed)
+	for _, mp := range mps {
+		for _, sid := range sidsAt[mp][1:] {
+			findings = append(findings, domain.Finding{
+				Type:     domain.FindingDuplicateMP,
+				Severity: domain.SeverityError,
+				Message:  fmt.Sprintf("node %s shares MP %s with node %s", sid, mp, sidsAt[mp][0]),
+				SID:      sid,
+			})
+		}
+	}
+	return findings
+}
+
+// fixDuplicateMPsImpl leaves the first node at each shared MP in place and
+// renumbers the others, with their subtrees, into free gaps after it.
+// Descendant filenames record only their own MP, so a descendant is matched
+// to a node by rank: the nth SID at a descendant MP goes with the nth node at
+// the shared MP, and descendants held by one SID stay with the first node.
+// Shared MPs are handled shallowest first, so nested collisions left over are
+// renumbered in turn. If a rename fails, the completed ones are rolled back.
+// Parsed files are updated in place.
+func (s *OutlineService) fixDuplicateMPsImpl(ctx context.Context, parsed []domain.ParsedFile) ([]RepairAction, error) {
+	var repairs []RepairAction
+	var completed [][2]string
+	for {
+		mps, _ := duplicateMPs(parsed)
+		if len(mps) == 0 {
+			return repairs, nil
+		}
+		mp := mps[0]
+		sidsAt := sidsByMP(parsed)
+		parent := parentMP(mp)
+		after := lastSegmentNum(mp)
+		for rank := 1; rank < len(sidsAt[mp]); rank++ {
+			occupied := slices.Compact(slices.Sorted(slices.Values(collectOccupiedChildNums(parsed, parent, ""))))
+			num, err := s.config.Numbering.SiblingNumberAfter(occupied, after)
+			if err != nil {
+				return nil, rollbackDuplicateMPs(ctx, s.renamer, completed, err)
+			}
+			newMP := s.config.Numbering.ChildMP(parent, num)
+			for i, pf := range parsed {
+				if pf.MP != mp && !strings.HasPrefix(pf.MP, mp+"-") {
+					continue
+				}
+				if slices.Index(sidsAt[pf.MP], pf.SID) != rank {
+					continue
+				}
+				oldName := reconstructFilename(pf)
+				pf.MP = newMP + strings.TrimPrefix(pf.MP, mp)
+				pf.PathParts = strings.Split(pf.MP, "-")
+				newName := reconstructFilename(pf)
+				if err := s.renamer.RenameFile(ctx, oldName, newName); err != nil {
+					return nil, rollbackDuplicateMPs(ctx, s.renamer, completed, fmt.Errorf("rename %s -> %s: %w", oldName, newName, err))
+				}
+				completed = append(completed, [2]string{oldName, newName})
+				parsed[i] = pf
+				repairs = append(repairs, RepairAction{Type: domain.FindingDuplicateMP, Old: oldName, New: newName})
+			}
+			after = num
+		}
+	}
+}
+
+// rollbackDuplicateMPs undoes the completed renumbering renames after err.
+func rollbackDuplicateMPs(ctx context.Context, renamer FileRenamer, completed [][2]string, err error) error {
+	if rbErr := rollbackRenames(ctx, renamer, completed); rbErr != nil {
+		return fmt.Errorf("%w; rollback failed: %v", err, rbErr)
+	}
+	return err
+}
@@ -0,0 +1,332 @@
+package outline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+// duplicateTestFiles has SID001AABB at MPs 100 and 200; 300 links to the 200 draft.
+var duplicateTestFiles = []string{
+	"100_SID001AABB_draft_first.md",
+	"100_SID001AABB_notes.md",
+	"200_SID001AABB_draft_second.md",
+	"200_SID001AABB_notes.md",
+	"300_SID003AABB_draft_third.md",
+	"300_SID003AABB_notes.md",
+}
+
+func duplicateTestService(renamer FileRenamer, writer *fakeFileWriter, reserver SIDReserver) *OutlineService {
+	content := &fakeContentReader{contents: map[string]string{
+		"100_SID001AABB_draft_first.md": "---\ntitle: first\n---\n",
+		"300_SID003AABB_draft_third.md": "---\ntitle: third\n---\nSee [second](200_SID001AABB_draft_second.md).\n",
+	}}
+	reader := &fakeDirectoryReader{files: duplicateTestFiles}
+	return NewOutlineService(reader, writer, &mockLocker{}, reserver,
+		WithRenamer(renamer), WithContentReader(content))
+}
+
+func TestOutlineService_Repair_FixDuplicates(t *testing.T) {
+	tests := []struct {
+		name        string
+		keep        []string
+		wantRenames [][2]string
+	}{
+		{
+			name: "first node keeps the SID",
+			wantRenames: [][2]string{
+				{"200_SID001AABB_draft_second.md", "200_SIDNEWAABB_draft_second.md"},
+				{"200_SID001AABB_notes.md", "200_SIDNEWAABB_notes.md"},
+			},
+		},
+		{
+			name: "keep chooses the node",
+			keep: []string{"200"},
+			wantRenames: [][2]string{
+				{"100_SID001AABB_draft_first.md", "100_SIDNEWAABB_draft_first.md"},
+				{"100_SID001AABB_notes.md", "100_SIDNEWAABB_notes.md"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renamer := &fakeFileRenamer{}
+			svc := duplicateTestService(renamer, &fakeFileWriter{}, &fakeSIDReserver{sid: "SIDNEWAABB"})
+
+			result, err := svc.Repair(context.Background(), RepairFixDuplicates(), RepairKeep(tt.keep...))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(renamer.renames) != len(tt.wantRenames) {
+				t.Fatalf("renames = %v, want %v", renamer.renames, tt.wantRenames)
+			}
+			for i, want := range tt.wantRenames {
+				if renamer.renames[i] != want {
+					t.Errorf("rename[%d] = %v, want %v", i, renamer.renames[i], want)
+				}
+				if r := result.Repairs[i]; r.Type != domain.FindingDuplicateSID || r.Old != want[0] || r.New != want[1] {
+					t.Errorf("repair[%d] = %+v, want duplicate_sid %v", i, r, want)
+				}
+			}
+			for _, f := range result.Unrepaired {
+				if f.Type == domain.FindingDuplicateSID {
+					t.Errorf("duplicate SID still unrepaired: %+v", f)
+				}
+			}
+		})
+	}
+}
+
+func TestOutlineService_Repair_FixDuplicatesRewritesLinks(t *testing.T) {
+	writer := &fakeFileWriter{}
+	svc := duplicateTestService(&fakeFileRenamer{}, writer, &fakeSIDReserver{sid: "SIDNEWAABB"})
+
+	if _, err := svc.Repair(context.Background(), RepairFixDuplicates()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "---\ntitle: third\n---\nSee [second](200_SIDNEWAABB_draft_second.md).\n"
+	if got := writer.written["300_SID003AABB_draft_third.md"]; got != want {
+		t.Errorf("linking file = %q, want %q", got, want)
+	}
+	if _, ok := writer.written["100_SID001AABB_draft_first.md"]; ok {
+		t.Error("files without links should not be rewritten")
+	}
+}
+
+func TestOutlineService_Repair_FixDuplicatesRewritesSIDLinks(t *testing.T) {
+	// The re-issued node's notes are read back under their new name.
+	contents := map[string]string{
+		"100_SID001AABB_notes.md":       "Unlike [the copy](200_SID001AABB_draft_second.md), see [[SID001AABB]].\n",
+		"200_SIDNEWAABB_notes.md":       "Back to [this node](SID001AABB) and [[sid:SID001AABB|here]]; not SID001AABBX.\n",
+		"300_SID003AABB_draft_third.md": "---\ntitle: third\n---\nSee [first](sid:SID001AABB).\n",
+	}
+	writer := &fakeFileWriter{}
+	svc := NewOutlineService(&fakeDirectoryReader{files: duplicateTestFiles}, writer, &mockLocker{}, &fakeSIDReserver{sid: "SIDNEWAABB"},
+		WithRenamer(&fakeFileRenamer{}), WithContentReader(&fakeContentReader{contents: contents}))
+
+	if _, err := svc.Repair(context.Background(), RepairFixDuplicates()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]string{
+		"100_SID001AABB_notes.md": "Unlike [the copy](200_SIDNEWAABB_draft_second.md), see [[SID001AABB]].\n",
+		"200_SIDNEWAABB_notes.md": "Back to [this node](SIDNEWAABB) and [[sid:SIDNEWAABB|here]]; not SID001AABBX.\n",
+	}
+	if !maps.Equal(writer.written, want) {
+		t.Errorf("written = %q, want %q", writer.written, want)
+	}
+}
+
+func TestOutlineService_Repair_WithoutFixDuplicatesLeavesThemUnrepaired(t *testing.T) {
+	renamer := &fakeFileRenamer{}
+	svc := duplicateTestService(renamer, &fakeFileWriter{}, &fakeSIDReserver{sid: "SIDNEWAABB"})
+
+	result, err := svc.Repair(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(renamer.renames) != 0 || len(result.Unrepaired) == 0 {
+		t.Errorf("renames = %v, unrepaired = %v; want none renamed and duplicates reported", renamer.renames, result.Unrepaired)
+	}
+}
+
+func TestOutlineService_Repair_FixDuplicatesErrors(t *testing.T) {
+	reserveErr := errors.New("no entropy")
+	renameErr := errors.New("rename failed")
+	writeErr := errors.New("disk full")
+
+	tests := []struct {
+		name     string
+		keep     []string
+		renamer  FileRenamer
+		writer   *fakeFileWriter
+		reserver SIDReserver
+		wantErr  error
+	}{
+		{"keep names a non-duplicate", []string{"300"}, &fakeFileRenamer{}, &fakeFileWriter{}, &fakeSIDReserver{sid: "SIDNEWAABB"}, ErrNotDuplicate},
+		{"reserve failure", nil, &fakeFileRenamer{}, &fakeFileWriter{}, &fakeSIDReserver{err: reserveErr}, reserveErr},
+		{"rename failure", nil, &fakeFileRenamer{err: renameErr}, &fakeFileWriter{}, &fakeSIDReserver{sid: "SIDNEWAABB"}, renameErr},
+		{"link rewrite failure", nil, &fakeFileRenamer{}, &fakeFileWriter{writeErr: writeErr}, &fakeSIDReserver{sid: "SIDNEWAABB"}, writeErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := duplicateTestService(tt.renamer, tt.writer, tt.reserver)
+
+			_, err := svc.Repair(context.Background(), RepairFixDuplicates(), RepairKeep(tt.keep...))
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOutlineService_Repair_FixDuplicatesSkipsUnreadableFiles(t *testing.T) {
+	renamer := &fakeFileRenamer{}
+	reader := &fakeDirectoryReader{files: duplicateTestFiles}
+	svc := NewOutlineService(reader, &fakeFileWriter{}, &mockLocker{}, &fakeSIDReserver{sid: "SIDNEWAABB"},
+		WithRenamer(renamer), WithContentReader(&fakeContentReader{err: errors.New("io error")}))
+
+	if _, err := svc.Repair(context.Background(), RepairFixDuplicates()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(renamer.renames) != 2 {
+		t.Errorf("renames = %v, want the two files at 200", renamer.renames)
+	}
+}
+
+// duplicateMPTestFiles has three merged nodes at 100-200, each but the last
+// with a child at 100-200-100, and a further node at 100-300.
+var duplicateMPTestFiles = []string{
+	"100_SID001AABB_draft_root.md",
+	"100-200_SID002AABB_draft_ours.md",
+	"100-200_SID002AABB_notes.md",
+	"100-200_SID003AABB_draft_theirs.md",
+	"100-200_SID004AABB_draft_also.md",
+	"100-200-100_SID005AABB_draft_child.md",
+	"100-200-100_SID007AABB_draft_their-child.md",
+	"100-200-100-100_SID008AABB_draft_grandchild.md",
+	"100-200-100-100_SID009AABB_draft_their-grandchild.md",
+	"100-300_SID006AABB_draft_next.md",
+}
+
+func TestOutlineService_Check_DuplicateMPs(t *testing.T) {
+	svc := NewOutlineService(&fakeDirectoryReader{files: duplicateMPTestFiles}, nil, &mockLocker{}, nil)
+
+	result, err := svc.Check(context.Background(), CheckRules(string(domain.FindingDuplicateMP)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{
+		"node SID003AABB shares MP 100-200 with node SID002AABB",
+		"node SID004AABB shares MP 100-200 with node SID002AABB",
+		"node SID007AABB shares MP 100-200-100 with node SID005AABB",
+		"node SID009AABB shares MP 100-200-100-100 with node SID008AABB",
+	}
+	if len(result.Findings) != len(want) {
+		t.Fatalf("findings = %+v, want %d", result.Findings, len(want))
+	}
+	for i, f := range result.Findings {
+		if f.Message != want[i] || f.SID == "" {
+			t.Errorf("finding[%d] = %+v, want %q", i, f, want[i])
+		}
+	}
+}
+
+func TestOutlineService_Repair_RenumbersDuplicateMPs(t *testing.T) {
+	renamer := &fakeFileRenamer{}
+	svc := NewOutlineService(&fakeDirectoryReader{files: duplicateMPTestFiles}, &fakeFileWriter{}, &mockLocker{}, nil,
+		WithRenamer(renamer))
+
+	result, err := svc.Repair(context.Background(), RepairFixDuplicates())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := [][2]string{
+		{"100-200_SID003AABB_draft_theirs.md", "100-400_SID003AABB_draft_theirs.md"},
+		{"100-200-100_SID007AABB_draft_their-child.md", "100-400-100_SID007AABB_draft_their-child.md"},
+		{"100-200-100-100_SID009AABB_draft_their-grandchild.md", "100-400-100-100_SID009AABB_draft_their-grandchild.md"},
+		{"100-200_SID004AABB_draft_also.md", "100-500_SID004AABB_draft_also.md"},
+	}
+	if len(renamer.renames) != len(want) {
+		t.Fatalf("renames = %v, want %v", renamer.renames, want)
+	}
+	for i, w := range want {
+		if renamer.renames[i] != w {
+			t.Errorf("rename[%d] = %v, want %v", i, renamer.renames[i], w)
+		}
+		if r := result.Repairs[i]; r.Type != domain.FindingDuplicateMP || r.Old != w[0] || r.New != w[1] {
+			t.Errorf("repair[%d] = %+v, want duplicate_mp %v", i, r, w)
+		}
+	}
+}
+
+func TestOutlineService_Repair_DuplicateMPErrors(t *testing.T) {
+	renameErr := errors.New("rename failed")
+	full := []string{"100_SID001AABB_draft_a.md", "100_SID002AABB_draft_b.md"}
+	for n := 101; n <= 999; n++ {
+		full = append(full, domain.GenerateFilename(domain.DefaultNumbering.ChildMP("", n), fmt.Sprintf("SID%dAABB", n), "notes", ""))
+	}
+
+	tests := []struct {
+		name    string
+		files   []string
+		renamer FileRenamer
+		wantErr error
+	}{
+		{"rename failure", duplicateMPTestFiles, &fakeFileRenamer{err: renameErr}, renameErr},
+		{"no free gap", full, &fakeFileRenamer{}, domain.ErrNoSlotAvailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewOutlineService(&fakeDirectoryReader{files: tt.files}, &fakeFileWriter{}, &mockLocker{}, nil,
+				WithRenamer(tt.renamer))
+
+			_, err := svc.Repair(context.Background(), RepairFixDuplicates())
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOutlineService_Repair_DuplicateMPsNeedOptIn(t *testing.T) {
+	renamer := &fakeFileRenamer{}
+	svc := NewOutlineService(&fakeDirectoryReader{files: duplicateMPTestFiles}, &fakeFileWriter{}, &mockLocker{}, nil,
+		WithRenamer(renamer))
+
+	result, err := svc.Repair(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(renamer.renames) != 0 {
+		t.Errorf("renames = %v, want none without RepairFixDuplicates", renamer.renames)
+	}
+	var unrepaired int
+	for _, f := range result.Unrepaired {
+		if f.Type == domain.FindingDuplicateMP {
+			unrepaired++
+		}
+	}
+	if unrepaired != 4 {
+		t.Errorf("unrepaired = %+v, want 4 duplicate_mp findings", result.Unrepaired)
+	}
+}
+
+func TestOutlineService_Repair_DuplicateMPRollback(t *testing.T) {
+	renamer := &fakeFileRenamer{err: errors.New("rename failed"), failOnFile: "100-200_SID004AABB_draft_also.md"}
+	svc := NewOutlineService(&fakeDirectoryReader{files: duplicateMPTestFiles}, &fakeFileWriter{}, &mockLocker{}, nil,
+		WithRenamer(renamer))
+
+	if _, err := svc.Repair(context.Background(), RepairFixDuplicates()); err == nil {
+		t.Fatal("expected error")
+	}
+
+	want := [][2]string{
+		{"100-200_SID003AABB_draft_theirs.md", "100-400_SID003AABB_draft_theirs.md"},
+		{"100-200-100_SID007AABB_draft_their-child.md", "100-400-100_SID007AABB_draft_their-child.md"},
+		{"100-200-100-100_SID009AABB_draft_their-grandchild.md", "100-400-100-100_SID009AABB_draft_their-grandchild.md"},
+		{"100-400-100-100_SID009AABB_draft_their-grandchild.md", "100-200-100-100_SID009AABB_draft_their-grandchild.md"},
+		{"100-400-100_SID007AABB_draft_their-child.md", "100-200-100_SID007AABB_draft_their-child.md"},
+		{"100-400_SID003AABB_draft_theirs.md", "100-200_SID003AABB_draft_theirs.md"},
+	}
+	if !slices.Equal(renamer.renames, want) {
+		t.Errorf("renames = %v, want %v", renamer.renames, want)
+	}
+}
@@ -0,0 +1,458 @@
+package outline
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+// ErrOutlineChanged is returned when applying edits to an outline whose
+// nodes changed on disk after the edited tree was loaded.
+var ErrOutlineChanged = errors.New("outline changed on disk since it was loaded; reload it and edit again")
+
+// EditNode is a node in an outline edited as a whole, as in the TUI. SID is
+// empty for a node not yet created. Types lists the node's document types
+// other than the draft.
+type EditNode struct {
+	SID      string
+	Title    string
+	Types    []string
+	Children []*EditNode
+}
+
+// EditAddition describes a node created by ApplyEdits. SID is empty when
+// the edits are only planned.
+type EditAddition struct {
+	SID   string
+	MP    string
+	Title string
+}
+
+// EditPlan holds the changes that make the outline match an edited tree:
+// files renamed, deleted and written in place, and nodes added.
+type EditPlan struct {
+	Renames map[string]string
+	Deleted []string
+	Written []string
+	Added   []EditAddition
+}
+
+// editSource is a node as it stands on disk.
+type editSource struct {
+	mp    string
+	files []domain.ParsedFile
+}
+
+// draft returns the node's draft file, if it has one.
+func (src *editSource) draft() (domain.ParsedFile, bool) {
+	i := slices.IndexFunc(src.files, func(pf domain.ParsedFile) bool { return pf.DocType == domain.DocTypeDraft })
+	if i < 0 {
+		return domain.ParsedFile{}, false
+	}
+	return src.files[i], true
+}
+
+// indexEditSources groups parsed files by SID, failing on the problems
+// that leave an outline without a single tree to edit.
+func indexEditSources(parsed []domain.ParsedFile) (map[string]*editSource, error) {
+	sources := map[string]*editSource{}
+	mpSIDs := map[string]string{}
+	for _, pf := range parsed {
+		if other, ok := mpSIDs[pf.MP]; ok && other != pf.SID {
+			return nil, fmt.Errorf("%s and %s share %s; run doctor to renumber them", other, pf.SID, pf.MP)
+		}
+		mpSIDs[pf.MP] = pf.SID
+		src, ok := sources[pf.SID]
+		if !ok {
+			src = &editSource{mp: pf.MP}
+			sources[pf.SID] = src
+		}
+		if src.mp != pf.MP {
+			return nil, fmt.Errorf("%s is at both %s and %s; run doctor to repair it", pf.SID, src.mp, pf.MP)
+		}
+		src.files = append(src.files, pf)
+	}
+	for sid, src := range sources {
+		if parent := parentMP(src.mp); parent != "" && mpSIDs[parent] == "" {
+			return nil, fmt.Errorf("%s has no parent at %s; run doctor to repair it", sid, parent)
+		}
+	}
+	return sources, nil
+}
+
+// EditTree loads the outline as a tree of EditNodes, children in order,
+// for editing and passing back to ApplyEdits.
+func (s *OutlineService) EditTree(ctx context.Context) ([]*EditNode, error) {
+	parsed, err := s.readAndParse(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sources, err := indexEditSources(parsed)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parents sit at shallower depths, so they are placed before their
+	// children, which arrive in numeric order.
+	sids := slices.Collect(maps.Keys(sources))
+	slices.SortFunc(sids, func(a, b string) int {
+		ma, mb := sources[a].mp, sources[b].mp
+		return cmp.Or(
+			cmp.Compare(strings.Count(ma, "-"), strings.Count(mb, "-")),
+			cmp.Compare(lastSegmentNum(ma), lastSegmentNum(mb)),
+		)
+	})
+
+	var roots []*EditNode
+	byMP := map[string]*EditNode{}
+	for _, sid := range sids {
+		src := sources[sid]
+		node := &EditNode{SID: sid, Title: s.editTitleImpl(ctx, src)}
+		for _, pf := range src.files {
+			if pf.DocType != domain.DocTypeDraft {
+				node.Types = append(node.Types, pf.DocType)
+			}
+		}
+		slices.Sort(node.Types)
+		byMP[src.mp] = node
+		if parent := parentMP(src.mp); parent != "" {
+			byMP[parent].Children = append(byMP[parent].Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+	return roots, nil
+}
+
+// editTitleImpl returns a node's title from its draft frontmatter, falling
+// back to the draft's slug, then the SID, when the title cannot be read.
+func (s *OutlineService) editTitleImpl(ctx context.Context, src *editSource) string {
+	draft, _ := src.draft()
+	return cmp.Or(s.nodeTitleImpl(ctx, src.files, src.mp), draft.Slug, src.files[0].SID)
+}
+
+// editAddition is a node to create once the edits are applied.
+type editAddition struct {
+	node        *EditNode
+	mp          string
+	parentTitle string
+}
+
+// editPlanner accumulates the changes that turn the outline on disk into an
+// edited tree.
+type editPlanner struct {
+	s         *OutlineService
+	sources   map[string]*editSource
+	seen      map[string]bool
+	plan      *EditPlan
+	writes    map[string]string
+	additions []editAddition
+}
+
+// ApplyEdits makes the outline match an edited tree, as loaded by EditTree,
+// acquiring an advisory lock first. loaded lists the SIDs EditTree returned;
+// when the nodes on disk no longer match them, the edits fail with
+// ErrOutlineChanged rather than delete nodes the editor never saw. Nodes
+// keep their numbers where their order allows and the others take numbers
+// in the gaps, so only nodes that moved are renamed. Nodes left out of the
+// tree are deleted with their files, nodes without a SID are created with
+// the default document types for their depth, and changed titles and types
+// are written. When apply is false, the changes are planned but not made.
+func (s *OutlineService) ApplyEdits(ctx context.Context, loaded []string, roots []*EditNode, apply bool) (*EditPlan, error) {
+	if err := s.lockForWrite(ctx); err != nil {
+		return nil, err
+	}
+	defer s.locker.Unlock()
+
+	parsed, err := s.readAndParse(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sources, err := indexEditSources(parsed)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkLoadedSIDs(sources, loaded); err != nil {
+		return nil, err
+	}
+
+	p := &editPlanner{
+		s:       s,
+		sources: sources,
+		seen:    map[string]bool{},
+		plan:    &EditPlan{Renames: map[string]string{}},
+		writes:  map[string]string{},
+	}
+	if err := p.place(ctx, roots, "", true, "", ""); err != nil {
+		return nil, err
+	}
+	for sid, src := range sources {
+		if p.seen[sid] {
+			continue
+		}
+		for _, pf := range src.files {
+			p.plan.Deleted = append(p.plan.Deleted, reconstructFilename(pf))
+		}
+	}
+	slices.Sort(p.plan.Deleted)
+	p.plan.Written = slices.Sorted(maps.Keys(p.writes))
+
+	if !apply {
+		return p.plan, nil
+	}
+	if err := p.applyImpl(ctx); err != nil {
+		return nil, err
+	}
+	return p.plan, nil
+}
+
+// checkLoadedSIDs fails with ErrOutlineChanged, naming the nodes added and
+// removed, when the nodes on disk are not the ones loaded for editing.
+func checkLoadedSIDs(sources map[string]*editSource, loaded []string) error {
+	was := map[string]bool{}
+	for _, sid := range loaded {
+		was[sid] = true
+	}
+	var added, removed []string
+	for _, sid := range slices.Sorted(maps.Keys(sources)) {
+		if !was[sid] {
+			added = append(added, sources[sid].mp)
+		}
+	}
+	for _, sid := range slices.Sorted(maps.Keys(was)) {
+		if sources[sid] == nil {
+			removed = append(removed, sid)
+		}
+	}
+	var changes []string
+	if len(added) > 0 {
+		changes = append(changes, "added "+strings.Join(added, ", "))
+	}
+	if len(removed) > 0 {
+		changes = append(changes, "removed "+strings.Join(removed, ", "))
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrOutlineChanged, strings.Join(changes, "; "))
+}
+
+// place plans the children of a node, given where it stood on disk and
+// where it stands after the edits. oldParent is only meaningful when
+// parentExisted is set.
+func (p *editPlanner) place(ctx context.Context, children []*EditNode, oldParent string, parentExisted bool, newParent, parentTitle string) error {
+	current := make([]int, len(children))
+	for i, child := range children {
+		if err := p.check(child); err != nil {
+			return err
+		}
+		if src := p.sources[child.SID]; src != nil && parentExisted && parentMP(src.mp) == oldParent {
+			current[i] = lastSegmentNum(src.mp)
+		}
+	}
+	nums, err := p.s.config.Numbering.Arrange(current)
+	if err != nil {
+		return fmt.Errorf("placing children of %q: %w", newParent, err)
+	}
+
+	for i, child := range children {
+		mp := p.s.config.Numbering.ChildMP(newParent, nums[i])
+		src := p.sources[child.SID]
+		if src == nil {
+			p.plan.Added = append(p.plan.Added, EditAddition{MP: mp, Title: child.Title})
+			p.additions = append(p.additions, editAddition{node: child, mp: mp, parentTitle: parentTitle})
+			if err := p.place(ctx, child.Children, "", false, mp, child.Title); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := p.updateImpl(ctx, child, src, mp, parentTitle); err != nil {
+			return err
+		}
+		if err := p.place(ctx, child.Children, src.mp, true, mp, child.Title); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// check validates an edited node and records its SID as seen.
+func (p *editPlanner) check(node *EditNode) error {
+	if strings.TrimSpace(node.Title) == "" {
+		return ErrEmptyTitle
+	}
+	for i, docType := range node.Types {
+		if err := domain.ValidateDocType(docType); err != nil {
+			return err
+		}
+		if docType == domain.DocTypeDraft || slices.Contains(node.Types[:i], docType) {
+			return fmt.Errorf("%w: %s", ErrTypeAlreadyExists, docType)
+		}
+	}
+	if node.SID == "" {
+		return nil
+	}
+	if p.sources[node.SID] == nil {
+		return fmt.Errorf("%w: %s", ErrNodeNotFound, node.SID)
+	}
+	if p.seen[node.SID] {
+		return fmt.Errorf("%s appears twice in the edited outline", node.SID)
+	}
+	p.seen[node.SID] = true
+	return nil
+}
+
+// updateImpl plans the renames, deletions and writes for an existing node
+// now at mp.
+func (p *editPlanner) updateImpl(ctx context.Context, node *EditNode, src *editSource, mp, parentTitle string) error {
+	draft, hasDraft := src.draft()
+	retitled := node.Title != p.s.editTitleImpl(ctx, src)
+	slug := draft.Slug
+	if retitled {
+		slug = p.s.slugifier.Slug(node.Title)
+	}
+
+	types := map[string]bool{}
+	for _, pf := range src.files {
+		types[pf.DocType] = true
+		oldName := reconstructFilename(pf)
+		if pf.DocType == domain.DocTypeDraft {
+			newName := domain.GenerateFilename(mp, node.SID, pf.DocType, slug)
+			if newName != oldName {
+				p.plan.Renames[oldName] = newName
+			}
+			continue
+		}
+		if !slices.Contains(node.Types, pf.DocType) {
+			p.plan.Deleted = append(p.plan.Deleted, oldName)
+			continue
+		}
+		if newName := domain.GenerateFilename(mp, node.SID, pf.DocType, pf.Slug); newName != oldName {
+			p.plan.Renames[oldName] = newName
+		}
+	}
+
+	if retitled {
+		content := formatFrontmatter(p.s.fmHandler, node.Title)
+		if hasDraft && p.s.contentReader != nil {
+			old, err := p.s.contentReader.ReadFile(ctx, reconstructFilename(draft))
+			if err != nil {
+				return err
+			}
+			if content, err = p.s.fmHandler.SetTitle(old, node.Title); err != nil {
+				return fmt.Errorf("retitling %s: %w", node.SID, err)
+			}
+		}
+		p.writes[domain.GenerateFilename(mp, node.SID, domain.DocTypeDraft, slug)] = content
+	}
+
+	depth := strings.Count(mp, "-") + 1
+	vars := func() domain.TemplateVars { return p.s.editVars(node, node.SID, mp, parentTitle) }
+	for _, docType := range node.Types {
+		if types[docType] {
+			continue
+		}
+		content, err := p.s.renderTemplateImpl(ctx, "", docType, depth, vars)
+		if err != nil {
+			return err
+		}
+		p.writes[domain.GenerateFilename(mp, node.SID, docType, "")] = content
+	}
+	return nil
+}
+
+// editVars returns the template placeholder values for an edited node.
+func (s *OutlineService) editVars(node *EditNode, sid, mp, parentTitle string) domain.TemplateVars {
+	return domain.TemplateVars{
+		Title:       node.Title,
+		SID:         sid,
+		MP:          mp,
+		ParentTitle: parentTitle,
+		Date:        s.now().Format(time.DateOnly),
+	}
+}
+
+// applyImpl makes the planned changes: renames first, since they can be
+// rolled back, then deletions, writes and new nodes.
+func (p *editPlanner) applyImpl(ctx context.Context) error {
+	s := p.s
+	if err := applyRenames(ctx, s.renamer, p.plan.Renames); err != nil {
+		return err
+	}
+	for _, name := range p.plan.Deleted {
+		if err := s.deleter.DeleteFile(ctx, name); err != nil {
+			return fmt.Errorf("delete %s: %w", name, err)
+		}
+	}
+	for _, name := range p.plan.Written {
+		if err := s.writer.WriteFile(ctx, name, p.writes[name]); err != nil {
+			return err
+		}
+	}
+	for i, a := range p.additions {
+		sid, err := s.addEditedNodeImpl(ctx, a)
+		if err != nil {
+			return err
+		}
+		p.plan.Added[i].SID = sid
+	}
+	return nil
+}
+
+// addEditedNodeImpl reserves a SID for a node created in the edits and
+// writes its draft and other document types.
+func (s *OutlineService) addEditedNodeImpl(ctx context.Context, a editAddition) (string, error) {
+	sid, err := s.reserver.Reserve(ctx)
+	if err != nil {
+		return "", err
+	}
+	if s.reservationStore != nil {
+		if err := s.reservationStore.CreateReservation(ctx, sid); err != nil {
+			return "", err
+		}
+	}
+
+	depth := strings.Count(a.mp, "-") + 1
+	vars := func() domain.TemplateVars { return s.editVars(a.node, sid, a.mp, a.parentTitle) }
+	draft, err := s.renderTemplateImpl(ctx, "", domain.DocTypeDraft, depth, vars)
+	if err != nil {
+		return "", err
+	}
+	content := formatFrontmatter(s.fmHandler, a.node.Title)
+	if draft != "" {
+		if content, err = s.fmHandler.SetTitle(draft, a.node.Title); err != nil {
+			return "", fmt.Errorf("applying draft template: %w", err)
+		}
+	}
+	filename := domain.GenerateFilename(a.mp, sid, domain.DocTypeDraft, s.slugifier.Slug(a.node.Title))
+	if err := s.writer.WriteFile(ctx, filename, content); err != nil {
+		return "", err
+	}
+
+	docTypes := s.config.DefaultDocTypesAt(depth)
+	for _, docType := range a.node.Types {
+		if !slices.Contains(docTypes, docType) {
+			docTypes = append(docTypes, docType)
+		}
+	}
+	for _, docType := range docTypes {
+		if docType == domain.DocTypeDraft {
+			continue
+		}
+		content, err := s.renderTemplateImpl(ctx, "", docType, depth, vars)
+		if err != nil {
+			return "", err
+		}
+		if err := s.writer.WriteFile(ctx, domain.GenerateFilename(a.mp, sid, docType, ""), content); err != nil {
+			return "", err
+		}
+	}
+	return sid, nil
+}
@@ -0,0 +1,519 @@
+package outline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+// editTestFiles has a book with notes and three chapters, the second with a
+// scene, and an appendix.
+var editTestFiles = []string{
+	"100_SID000AABB_draft_book.md",
+	"100_SID000AABB_notes.md",
+	"100-100_SID001AABB_draft_one.md",
+	"100-200_SID002AABB_draft_two.md",
+	"100-200-100_SID004AABB_draft_scene.md",
+	"100-300_SID003AABB_draft_three.md",
+	"200_SID005AABB_draft_appendix.md",
+}
+
+var editTestContents = map[string]string{
+	"100_SID000AABB_draft_book.md":      "---\ntitle: Book\n---\n",
+	"100-100_SID001AABB_draft_one.md":   "---\ntitle: One\n---\n",
+	"100-300_SID003AABB_draft_three.md": "---\ntitle: Three\n---\nThe end.\n",
+}
+
+// sequenceSIDReserver hands out SIDs in turn.
+type sequenceSIDReserver struct {
+	sids []string
+	err  error
+}
+
+func (r *sequenceSIDReserver) Reserve(_ context.Context) (string, error) {
+	if r.err != nil {
+		return "", r.err
+	}
+	sid := r.sids[0]
+	r.sids = r.sids[1:]
+	return sid, nil
+}
+
+type editTestDeps struct {
+	renamer  *fakeFileRenamer
+	deleter  *fakeFileDeleter
+	writer   *fakeFileWriter
+	reserver *sequenceSIDReserver
+}
+
+func newEditTestService(files []string, opts ...Option) (*OutlineService, *editTestDeps) {
+	deps := &editTestDeps{
+		renamer:  &fakeFileRenamer{},
+		deleter:  &fakeFileDeleter{},
+		writer:   &fakeFileWriter{},
+		reserver: &sequenceSIDReserver{sids: []string{"SID100AABB", "SID101AABB"}},
+	}
+	opts = append([]Option{
+		WithRenamer(deps.renamer),
+		WithDeleter(deps.deleter),
+		WithContentReader(&fakeContentReader{contents: editTestContents}),
+		WithClock(fixedClock),
+	}, opts...)
+	svc := NewOutlineService(&fakeDirectoryReader{files: files}, deps.writer, &mockLocker{}, deps.reserver, opts...)
+	return svc, deps
+}
+
+// editTreeString renders an edit tree one node per line, indented by depth.
+func editTreeString(nodes []*EditNode, depth int) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		fmt.Fprintf(&b, "%s%s %s %v\n", strings.Repeat("  ", depth), n.SID, n.Title, n.Types)
+		b.WriteString(editTreeString(n.Children, depth+1))
+	}
+	return b.String()
+}
+
+func loadEditTree(t *testing.T, svc *OutlineService) []*EditNode {
+	t.Helper()
+	roots, err := svc.EditTree(context.Background())
+	if err != nil {
+		t.Fatalf("EditTree() error: %v", err)
+	}
+	return roots
+}
+
+// loadedSIDs returns the SIDs of the tree EditTree loads, as an editor
+// passes them back to ApplyEdits.
+func loadedSIDs(t *testing.T, svc *OutlineService) []string {
+	t.Helper()
+	var sids []string
+	var walk func(nodes []*EditNode)
+	walk = func(nodes []*EditNode) {
+		for _, n := range nodes {
+			sids = append(sids, n.SID)
+			walk(n.Children)
+		}
+	}
+	walk(loadEditTree(t, svc))
+	return sids
+}
+
+func TestOutlineService_EditTree(t *testing.T) {
+	files := append(slices.Clone(editTestFiles), "300_SID006AABB_notes.md")
+	svc, _ := newEditTestService(files)
+
+	roots := loadEditTree(t, svc)
+
+	want := `SID000AABB Book [notes]
+  SID001AABB One []
+  SID002AABB two []
+    SID004AABB scene []
+  SID003AABB Three []
+SID005AABB appendix []
+SID006AABB SID006AABB [notes]
+`
+	if got := editTreeString(roots, 0); got != want {
+		t.Errorf("EditTree() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestOutlineService_EditTree_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   []string
+		readErr error
+		wantMsg string
+	}{
+		{"read failure", nil, errors.New("disk gone"), "disk gone"},
+		{"shared MP", []string{"100_SID001AABB_draft_a.md", "100_SID002AABB_draft_b.md"}, nil, "SID001AABB and SID002AABB share 100"},
+		{"SID at two MPs", []string{"100_SID001AABB_draft_a.md", "200_SID001AABB_notes.md"}, nil, "SID001AABB is at both 100 and 200"},
+		{"orphan", []string{"100-100_SID001AABB_draft_a.md"}, nil, "SID001AABB has no parent at 100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewOutlineService(&fakeDirectoryReader{files: tt.files, err: tt.readErr}, nil, &mockLocker{}, nil)
+
+			_, err := svc.EditTree(context.Background())
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("EditTree() error = %v, want it to mention %q", err, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestOutlineService_ApplyEdits_Unchanged(t *testing.T) {
+	svc, deps := newEditTestService(editTestFiles)
+
+	plan, err := svc.ApplyEdits(context.Background(), loadedSIDs(t, svc), loadEditTree(t, svc), true)
+	if err != nil {
+		t.Fatalf("ApplyEdits() error: %v", err)
+	}
+
+	if len(plan.Renames) != 0 || len(plan.Deleted) != 0 || len(plan.Written) != 0 || len(plan.Added) != 0 {
+		t.Errorf("ApplyEdits() = %+v, want no changes", plan)
+	}
+	if len(deps.renamer.renames) != 0 || len(deps.writer.written) != 0 {
+		t.Errorf("unchanged tree touched files: %v, %v", deps.renamer.renames, deps.writer.written)
+	}
+}
+
+func TestOutlineService_ApplyEdits_RenamesOnlyMovedNodes(t *testing.T) {
+	svc, deps := newEditTestService(editTestFiles)
+	roots := loadEditTree(t, svc)
+	book, appendix := roots[0], roots[1]
+	one, two, three := book.Children[0], book.Children[1], book.Children[2]
+
+	// Swap the first two chapters and indent the appendix under the book.
+	book.Children = []*EditNode{two, one, three, appendix}
+	roots = []*EditNode{book}
+
+	plan, err := svc.ApplyEdits(context.Background(), loadedSIDs(t, svc), roots, true)
+	if err != nil {
+		t.Fatalf("ApplyEdits() error: %v", err)
+	}
+
+	want := map[string]string{
+		"100-100_SID001AABB_draft_one.md":  "100-210_SID001AABB_draft_one.md",
+		"200_SID005AABB_draft_appendix.md": "100-400_SID005AABB_draft_appendix.md",
+	}
+	if !maps.Equal(plan.Renames, want) {
+		t.Errorf("Renames = %v, want %v", plan.Renames, want)
+	}
+	if len(deps.renamer.renames) != len(want) {
+		t.Errorf("renames applied = %v, want %d", deps.renamer.renames, len(want))
+	}
+}
+
+func TestOutlineService_ApplyEdits_MovesSubtrees(t *testing.T) {
+	svc, _ := newEditTestService(editTestFiles)
+	roots := loadEditTree(t, svc)
+	book := roots[0]
+	two := book.Children[1]
+
+	// Outdent the second chapter, with its scene, to follow the book.
+	book.Children = slices.Delete(book.Children, 1, 2)
+	roots = []*EditNode{book, two, roots[1]}
+
+	plan, err := svc.ApplyEdits(context.Background(), loadedSIDs(t, svc), roots, false)
+	if err != nil {
+		t.Fatalf("ApplyEdits() error: %v", err)
+	}
+
+	want := map[string]string{
+		"100-200_SID002AABB_draft_two.md":       "110_SID002AABB_draft_two.md",
+		"100-200-100_SID004AABB_draft_scene.md": "110-100_SID004AABB_draft_scene.md",
+	}
+	if !maps.Equal(plan.Renames, want) {
+		t.Errorf("Renames = %v, want %v", plan.Renames, want)
+	}
+}
+
+func TestOutlineService_ApplyEdits_TitlesAndTypes(t *testing.T) {
+	templates := &fakeTemplateReader{templates: map[string]string{"summary.md": "Summary of {{title}} in {{parent_title}}"}}
+	svc, deps := newEditTestService(editTestFiles, WithTemplateReader(templates))
+	roots := loadEditTree(t, svc)
+	book := roots[0]
+	book.Types = nil
+	book.Children[0].Types = []string{"summary"}
+	book.Children[2].Title = "Finale"
+
+	plan, err := svc.ApplyEdits(context.Background(), loadedSIDs(t, svc), roots, true)
+	if err != nil {
+		t.Fatalf("ApplyEdits() error: %v", err)
+	}
+
+	wantRenames := map[string]string{"100-300_SID003AABB_draft_three.md": "100-300_SID003AABB_draft_finale.md"}
+	if !maps.Equal(plan.Renames, wantRenames) {
+		t.Errorf("Renames = %v, want %v", plan.Renames, wantRenames)
+	}
+	if want := []string{"100_SID000AABB_notes.md"}; !slices.Equal(plan.Deleted, want) || !slices.Equal(deps.deleter.deleted, want) {
+		t.Errorf("Deleted = %v (applied %v), want %v", plan.Deleted, deps.deleter.deleted, want)
+	}
+	wantWritten := []string{"100-100_SID001AABB_summary.md", "100-300_SID003AABB_draft_finale.md"}
+	if !slices.Equal(plan.Written, wantWritten) {
+		t.Errorf("Written = %v, want %v", plan.Written, wantWritten)
+	}
+	if got := deps.writer.written["100-300_SID003AABB_draft_finale.md"]; !strings.Contains(got, "title: Finale") || !strings.Contains(got, "The end.") {
+		t.Errorf("retitled draft = %q, want the new title and the old body", got)
+	}
+	if got := deps.writer.written["100-100_SID001AABB_summary.md"]; got != "Summary of One in Book" {
+		t.Errorf("summary = %q, want it filled from the template", got)
+	}
+}
+
+func TestOutlineService_ApplyEdits_RetitleWithoutDraft(t *testing.T) {
+	svc, deps := newEditTestService([]string{"100_SID006AABB_notes.md"})
+	roots := loadEditTree(t, svc)
+	roots[0].Title = "Found"
+
+	if _, err := svc.ApplyEdits(context.Background(), loadedSIDs(t, svc), roots, true); err != nil {
+		t.Fatalf("ApplyEdits() error: %v", err)
+	}
+
+	if got := deps.writer.written["100_SID006AABB_draft_found.md"]; !strings.Contains(got, "title: Found") {
+		t.Errorf("draft = %q, want one written with the title", got)
+	}
+}
+
+func TestOutlineService_ApplyEdits_DeletesAndAdds(t *testing.T) {
+	reservations := &fakeReservationStore{}
+	svc, deps := newEditTestService(editTestFiles, WithReservationStore(reservations))
+	roots := loadEditTree(t, svc)
+	book := roots[0]
+
+	// Drop the second chapter and its scene, and add an epilogue with a coda.
+	coda := &EditNode{Title: "Coda", Types: []string{"summary"}}
+	epilogue := &EditNode{Title: "Epilogue", Children: []*EditNode{coda}}
+	book.Children = []*EditNode{book.Children[0], book.Children[2], epilogue}
+
+	planned, err := svc.ApplyEdits(context.Background(), loadedSIDs(t, svc), roots, false)
+	if err != nil {
+		t.Fatalf("ApplyEdits() dry run error: %v", err)
+	}
+	wantAdded := []EditAddition{{MP: "100-400", Title: "Epilogue"}, {MP: "100-400-100", Title: "Coda"}}
+	if !slices.Equal(planned.Added, wantAdded) {
+		t.Errorf("planned Added = %v, want %v", planned.Added, wantAdded)
+	}
+	wantDeleted := []string{"100-200-100_SID004AABB_draft_scene.md", "100-200_SID002AABB_draft_two.md"}
+	if !slices.Equal(planned.Deleted, wantDeleted) {
+		t.Errorf("planned Deleted = %v, want %v", planned.Deleted, wantDeleted)
+	}
+	if len(deps.deleter.deleted) != 0 || len(deps.writer.written) != 0 {
+		t.Fatalf("dry run changed files: %v, %v", deps.deleter.deleted, deps.writer.written)
+	}
+
+	applied, err := svc.ApplyEdits(context.Background(), loadedSIDs(t, svc), roots, true)
+	if err != nil {
+		t.Fatalf("ApplyEdits() error: %v", err)
+	}
+	if applied.Added[0].SID != "SID100AABB" || applied.Added[1].SID != "SID101AABB" {
+		t.Errorf("Added = %v, want reserved SIDs", applied.Added)
+	}
+	if !slices.Equal(reservations.created, []string{"SID100AABB", "SID101AABB"}) {
+		t.Errorf("reservations = %v, want both new SIDs", reservations.created)
+	}
+	if !slices.Equal(deps.deleter.deleted, wantDeleted) {
+		t.Errorf("deleted = %v, want %v", deps.deleter.deleted, wantDeleted)
+	}
+	for _, name := range []string{
+		"100-400_SID100AABB_draft_epilogue.md",
+		"100-400_SID100AABB_notes.md",
+		"100-400-100_SID101AABB_draft_coda.md",
+		"100-400-100_SID101AABB_notes.md",
+		"100-400-100_SID101AABB_summary.md",
+	} {
+		if _, ok := deps.writer.written[name]; !ok {
+			t.Errorf("%s not written; wrote %v", name, slices.Sorted(maps.Keys(deps.writer.written)))
+		}
+	}
+	if got := deps.writer.written["100-400_SID100AABB_draft_epilogue.md"]; !strings.Contains(got, "title: Epilogue") {
+		t.Errorf("epilogue draft = %q, want its title", got)
+	}
+}
+
+func TestOutlineService_ApplyEdits_NewNodeFromTemplate(t *testing.T) {
+	templates := &fakeTemplateReader{templates: map[string]string{"draft.md": "---\ntitle: x\n---\nUnder {{parent_title}} on {{date}}\n"}}
+	svc, deps := newEditTestService(editTestFiles, WithTemplateReader(templates))
+	roots := loadEditTree(t, svc)
+	roots[1].Children = []*EditNode{{Title: "Sources"}}
+
+	if _, err := svc.ApplyEdits(context.Background(), loadedSIDs(t, svc), roots, true); err != nil {
+		t.Fatalf("ApplyEdits() error: %v", err)
+	}
+
+	got := deps.writer.written["200-100_SID100AABB_draft_sources.md"]
+	if !strings.Contains(got, "title: Sources") || !strings.Contains(got, "Under appendix on 2026-03-14") {
+		t.Errorf("draft = %q, want the template with the title set", got)
+	}
+}
+
+func TestOutlineService_ApplyEdits_Errors(t *testing.T) {
+	editRoots := func(edit func(book *EditNode)) func(t *testing.T, svc *OutlineService) []*EditNode {
+		return func(t *testing.T, svc *OutlineService) []*EditNode {
+			roots := loadEditTree(t, svc)
+			edit(roots[0])
+			return roots
+		}
+	}
+	unchanged := editRoots(func(*EditNode) {})
+	retitle := editRoots(func(book *EditNode) { book.Title = "Tome" })
+	addNode := editRoots(func(book *EditNode) { book.Children = append(book.Children, &EditNode{Title: "New"}) })
+	addType := editRoots(func(book *EditNode) { book.Children[0].Types = []string{"summary"} })
+	failingTemplates := WithTemplateReader(&fakeTemplateReader{err: errors.New("template unreadable")})
+
+	tests := []struct {
+		name    string
+		files   []string
+		roots   func(t *testing.T, svc *OutlineService) []*EditNode
+		setup   func(svc *OutlineService, deps *editTestDeps)
+		wantErr error
+		wantMsg string
+	}{
+		{
+			name:    "lock held",
+			roots:   unchanged,
+			setup:   func(svc *OutlineService, _ *editTestDeps) { svc.locker = &mockLocker{tryLockErr: errors.New("locked")} },
+			wantMsg: "locked",
+		},
+		{
+			name:  "read failure",
+			roots: unchanged,
+			setup: func(svc *OutlineService, _ *editTestDeps) {
+				svc.reader = &fakeDirectoryReader{err: errors.New("disk gone")}
+			},
+			wantMsg: "disk gone",
+		},
+		{
+			name:  "unhealthy outline",
+			roots: unchanged,
+			setup: func(svc *OutlineService, _ *editTestDeps) {
+				svc.reader = &fakeDirectoryReader{files: []string{"100-100_SID001AABB_draft_a.md"}}
+			},
+			wantMsg: "no parent",
+		},
+		{name: "empty title", roots: editRoots(func(book *EditNode) { book.Title = " " }), wantErr: ErrEmptyTitle},
+		{name: "invalid type", roots: editRoots(func(book *EditNode) { book.Types = []string{"Bad Type"} }), wantErr: domain.ErrInvalidDocType},
+		{name: "draft as a type", roots: editRoots(func(book *EditNode) { book.Types = []string{"draft"} }), wantErr: ErrTypeAlreadyExists},
+		{name: "repeated type", roots: editRoots(func(book *EditNode) { book.Types = []string{"notes", "notes"} }), wantErr: ErrTypeAlreadyExists},
+		{name: "unknown SID", roots: editRoots(func(book *EditNode) { book.Children[0].SID = "SID999AABB" }), wantErr: ErrNodeNotFound},
+		{
+			name:    "repeated SID",
+			roots:   editRoots(func(book *EditNode) { book.Children = append(book.Children, book.Children[0]) }),
+			wantMsg: "SID001AABB appears twice",
+		},
+		{
+			name:    "invalid node under a new node",
+			roots:   editRoots(func(book *EditNode) { book.Children = []*EditNode{{Title: "New", Children: []*EditNode{{}}}} }),
+			wantErr: ErrEmptyTitle,
+		},
+		{
+			name:    "invalid node under an existing node",
+			roots:   editRoots(func(book *EditNode) { book.Children[1].Children[0].Title = "" }),
+			wantErr: ErrEmptyTitle,
+		},
+		{
+			name: "too many children",
+			roots: editRoots(func(book *EditNode) {
+				for range 1000 {
+					book.Children = append(book.Children, &EditNode{Title: "New"})
+				}
+			}),
+			wantErr: domain.ErrMaxSiblingsReached,
+		},
+		{
+			name:  "unreadable draft",
+			roots: retitle,
+			setup: func(svc *OutlineService, _ *editTestDeps) {
+				svc.contentReader = &fakeContentReader{err: errors.New("draft unreadable")}
+			},
+			wantMsg: "draft unreadable",
+		},
+		{name: "unreadable type template", roots: addType, setup: func(svc *OutlineService, _ *editTestDeps) { failingTemplates(svc) }, wantMsg: "template unreadable"},
+		{
+			name:    "rename failure",
+			roots:   retitle,
+			setup:   func(_ *OutlineService, deps *editTestDeps) { deps.renamer.err = errors.New("rename refused") },
+			wantMsg: "rename refused",
+		},
+		{
+			name:    "delete failure",
+			roots:   editRoots(func(book *EditNode) { book.Types = nil }),
+			setup:   func(_ *OutlineService, deps *editTestDeps) { deps.deleter.err = errors.New("delete refused") },
+			wantMsg: "delete 100_SID000AABB_notes.md: delete refused",
+		},
+		{
+			name:    "write failure",
+			roots:   retitle,
+			setup:   func(_ *OutlineService, deps *editTestDeps) { deps.writer.writeErr = errors.New("disk full") },
+			wantMsg: "disk full",
+		},
+		{
+			name:    "reserve failure",
+			roots:   addNode,
+			setup:   func(_ *OutlineService, deps *editTestDeps) { deps.reserver.err = errors.New("no SIDs left") },
+			wantMsg: "no SIDs left",
+		},
+		{
+			name:  "reservation failure",
+			roots: addNode,
+			setup: func(svc *OutlineService, _ *editTestDeps) {
+				svc.reservationStore = &fakeReservationStore{createErr: errors.New("marker refused")}
+			},
+			wantMsg: "marker refused",
+		},
+		{name: "unreadable draft template", roots: addNode, setup: func(svc *OutlineService, _ *editTestDeps) { failingTemplates(svc) }, wantMsg: "template unreadable"},
+		{
+			name:    "new draft write failure",
+			roots:   addNode,
+			setup:   func(_ *OutlineService, deps *editTestDeps) { deps.writer.writeErr = errors.New("disk full") },
+			wantMsg: "disk full",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, deps := newEditTestService(editTestFiles)
+			roots, loaded := tt.roots(t, svc), loadedSIDs(t, svc)
+			if tt.setup != nil {
+				tt.setup(svc, deps)
+			}
+
+			_, err := svc.ApplyEdits(context.Background(), loaded, roots, true)
+
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantMsg != "" && !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("error = %q, want it to mention %q", err, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestOutlineService_ApplyEdits_OutlineChangedOnDisk(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   []string
+		wantMsg string
+	}{
+		{
+			name:    "node added",
+			files:   append(slices.Clone(editTestFiles), "300_SID006AABB_draft_late.md"),
+			wantMsg: "added 300",
+		},
+		{
+			name:    "node removed",
+			files:   slices.DeleteFunc(slices.Clone(editTestFiles), func(name string) bool { return strings.Contains(name, "SID005AABB") }),
+			wantMsg: "removed SID005AABB",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, deps := newEditTestService(editTestFiles)
+			roots, loaded := loadEditTree(t, svc), loadedSIDs(t, svc)
+			roots[0].Title = "Tome"
+			svc.reader = &fakeDirectoryReader{files: tt.files}
+
+			_, err := svc.ApplyEdits(context.Background(), loaded, roots, true)
+
+			if !errors.Is(err, ErrOutlineChanged) || !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Fatalf("error = %v, want ErrOutlineChanged mentioning %q", err, tt.wantMsg)
+			}
+			if len(deps.deleter.deleted) > 0 || len(deps.renamer.renames) > 0 || len(deps.writer.written) > 0 {
+				t.Errorf("files changed: deleted %v, renamed %v, wrote %v", deps.deleter.deleted, deps.renamer.renames, deps.writer.written)
+			}
+		})
+	}
+}
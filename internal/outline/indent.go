@@ -0,0 +1,99 @@
+package outline
+
+import (
+	"context"
+	"errors"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+// ErrNoPreviousSibling is returned when indenting a node that is the first
+// of its siblings.
+var ErrNoPreviousSibling = errors.New("no previous sibling to indent under")
+
+// ErrAlreadyTopLevel is returned when outdenting a root-level node.
+var ErrAlreadyTopLevel = errors.New("node is already at the top level")
+
+// Indent makes a node the last child of its previous sibling, acquiring an
+// advisory lock first. Its subtree moves with it, as with Move.
+func (s *OutlineService) Indent(ctx context.Context, sel domain.Selector, apply bool, opts ...MoveOption) (*MoveResult, error) {
+	var cfg moveConfig
+	for _, o := range opts {
+		o(&cfg)
+	}
+
+	if err := s.lockForWrite(ctx); err != nil {
+		return nil, err
+	}
+	defer s.locker.Unlock()
+
+	parsed, err := s.readAndParse(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceMP, _, err := s.resolveTarget(ctx, parsed, sel)
+	if err != nil {
+		return nil, err
+	}
+
+	previousMP := adjacentSibling(parsed, sourceMP, false)
+	if previousMP == "" {
+		return nil, ErrNoPreviousSibling
+	}
+
+	return s.moveNode(ctx, parsed, sourceMP, previousMP, "", "", apply, cfg)
+}
+
+// Outdent makes a node the next sibling after its parent, acquiring an
+// advisory lock first. Its subtree moves with it, as with Move.
+func (s *OutlineService) Outdent(ctx context.Context, sel domain.Selector, apply bool, opts ...MoveOption) (*MoveResult, error) {
+	var cfg moveConfig
+	for _, o := range opts {
+		o(&cfg)
+	}
+
+	if err := s.lockForWrite(ctx); err != nil {
+		return nil, err
+	}
+	defer s.locker.Unlock()
+
+	parsed, err := s.readAndParse(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceMP, _, err := s.resolveTarget(ctx, parsed, sel)
+	if err != nil {
+		return nil, err
+	}
+
+	parent := parentMP(sourceMP)
+	if parent == "" {
+		return nil, ErrAlreadyTopLevel
+	}
+
+	// Taking the slot before the parent's next sibling keeps the node
+	// directly after its parent; with no next sibling, it is appended.
+	return s.moveNode(ctx, parsed, sourceMP, parentMP(parent), adjacentSibling(parsed, parent, true), "", apply, cfg)
+}
+
+// adjacentSibling returns the MP of the sibling just after mp when next is
+// true, or just before it otherwise, or "" when there is none.
+func adjacentSibling(parsed []domain.ParsedFile, mp string, next bool) string {
+	parent, num := parentMP(mp), lastSegmentNum(mp)
+	var found string
+	for _, pf := range parsed {
+		if !isDirectChild(pf, parent) {
+			continue
+		}
+		n := lastSegmentNum(pf.MP)
+		if next && n > num && (found == "" || n < lastSegmentNum(found)) {
+			found = pf.MP
+		}
+		if !next && n < num && (found == "" || n > lastSegmentNum(found)) {
+			found = pf.MP
+		}
+	}
+	return found
+}
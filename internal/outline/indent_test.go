@@ -0,0 +1,221 @@
+package outline
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"testing"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+// indentTestFiles returns a fixture:
+//
+//	100 (part-one, SID001AABB) with notes
+//	  100-100 (intro, SID005IIJJ)
+//	  100-200 (chapter, SID002CCDD)
+//	    100-200-100 (scene, SID003EEFF)
+//	200 (part-two, SID004GGHH)
+func indentTestFiles() []string {
+	return []string{
+		"100_SID001AABB_draft_part-one.md",
+		"100_SID001AABB_notes.md",
+		"100-100_SID005IIJJ_draft_intro.md",
+		"100-200_SID002CCDD_draft_chapter.md",
+		"100-200-100_SID003EEFF_draft_scene.md",
+		"200_SID004GGHH_draft_part-two.md",
+	}
+}
+
+func TestOutlineService_Indent(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector string
+		want     map[string]string
+	}{
+		{
+			name:     "root node becomes last child of previous root",
+			selector: "200",
+			want:     map[string]string{"200_SID004GGHH_draft_part-two.md": "100-300_SID004GGHH_draft_part-two.md"},
+		},
+		{
+			name:     "subtree moves with the node",
+			selector: "SID002CCDD",
+			want: map[string]string{
+				"100-200_SID002CCDD_draft_chapter.md":   "100-100-100_SID002CCDD_draft_chapter.md",
+				"100-200-100_SID003EEFF_draft_scene.md": "100-100-100-100_SID003EEFF_draft_scene.md",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renamer := &fakeFileRenamer{}
+			svc := newMoveTestService(indentTestFiles(), renamer)
+
+			sel, _ := domain.ParseSelector(tt.selector)
+			result, err := svc.Indent(context.Background(), sel, true)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !maps.Equal(result.Renames, tt.want) {
+				t.Errorf("renames = %v, want %v", result.Renames, tt.want)
+			}
+			if len(renamer.renames) != len(tt.want) {
+				t.Errorf("files renamed = %d, want %d", len(renamer.renames), len(tt.want))
+			}
+		})
+	}
+}
+
+func TestOutlineService_Outdent(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector string
+		want     map[string]string
+	}{
+		{
+			name:     "node lands between its parent and the parent's next sibling",
+			selector: "100-200",
+			want: map[string]string{
+				"100-200_SID002CCDD_draft_chapter.md":   "110_SID002CCDD_draft_chapter.md",
+				"100-200-100_SID003EEFF_draft_scene.md": "110-100_SID003EEFF_draft_scene.md",
+			},
+		},
+		{
+			name:     "first child also lands after its parent",
+			selector: "SID005IIJJ",
+			want:     map[string]string{"100-100_SID005IIJJ_draft_intro.md": "110_SID005IIJJ_draft_intro.md"},
+		},
+		{
+			name:     "node is appended when its parent is the last sibling",
+			selector: "100-200-100",
+			want:     map[string]string{"100-200-100_SID003EEFF_draft_scene.md": "100-300_SID003EEFF_draft_scene.md"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renamer := &fakeFileRenamer{}
+			svc := newMoveTestService(indentTestFiles(), renamer)
+
+			sel, _ := domain.ParseSelector(tt.selector)
+			result, err := svc.Outdent(context.Background(), sel, true)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !maps.Equal(result.Renames, tt.want) {
+				t.Errorf("renames = %v, want %v", result.Renames, tt.want)
+			}
+			if len(renamer.renames) != len(tt.want) {
+				t.Errorf("files renamed = %d, want %d", len(renamer.renames), len(tt.want))
+			}
+		})
+	}
+}
+
+func TestOutlineService_IndentOutdent_DryRun(t *testing.T) {
+	renamer := &fakeFileRenamer{}
+	svc := newMoveTestService(indentTestFiles(), renamer)
+	sel, _ := domain.ParseSelector("200")
+
+	indented, err := svc.Indent(context.Background(), sel, false)
+	if err != nil || len(indented.Renames) != 1 {
+		t.Fatalf("Indent = %v, %v; want one planned rename", indented, err)
+	}
+	sel, _ = domain.ParseSelector("100-200")
+	outdented, err := svc.Outdent(context.Background(), sel, false)
+	if err != nil || len(outdented.Renames) != 2 {
+		t.Fatalf("Outdent = %v, %v; want two planned renames", outdented, err)
+	}
+	if len(renamer.renames) != 0 {
+		t.Errorf("files renamed = %d, want 0 (dry run)", len(renamer.renames))
+	}
+}
+
+func TestOutlineService_Outdent_Rebalance(t *testing.T) {
+	files := []string{
+		"100_SID001AABB_draft_one.md",
+		"100-100_SID002CCDD_draft_child.md",
+		"101_SID003EEFF_draft_two.md",
+	}
+	sel, _ := domain.ParseSelector("100-100")
+
+	t.Run("neighbours are renumbered by default", func(t *testing.T) {
+		svc := newMoveTestService(files, &fakeFileRenamer{})
+
+		result, err := svc.Outdent(context.Background(), sel, false)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Rebalanced) == 0 {
+			t.Error("expected neighbouring siblings to be renumbered")
+		}
+	})
+
+	t.Run("disabled rebalancing fails", func(t *testing.T) {
+		svc := newMoveTestService(files, &fakeFileRenamer{})
+
+		_, err := svc.Outdent(context.Background(), sel, false, MoveRebalance(false))
+
+		if !errors.Is(err, domain.ErrNoSlotAvailable) {
+			t.Errorf("error = %v, want ErrNoSlotAvailable", err)
+		}
+	})
+
+	t.Run("indent takes the option too", func(t *testing.T) {
+		svc := newMoveTestService(files, &fakeFileRenamer{})
+		two, _ := domain.ParseSelector("101")
+
+		_, err := svc.Indent(context.Background(), two, false, MoveRebalance(false))
+
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestOutlineService_IndentOutdent_Errors(t *testing.T) {
+	lockErr := errors.New("locked")
+	readErr := errors.New("read failed")
+	tests := []struct {
+		name     string
+		op       func(*OutlineService, context.Context, domain.Selector, bool, ...MoveOption) (*MoveResult, error)
+		selector string
+		lockErr  error
+		readErr  error
+		want     error
+	}{
+		{"indent first root", (*OutlineService).Indent, "100", nil, nil, ErrNoPreviousSibling},
+		{"indent first child", (*OutlineService).Indent, "100-100", nil, nil, ErrNoPreviousSibling},
+		{"indent missing node", (*OutlineService).Indent, "SID999ZZZZ", nil, nil, ErrNodeNotFound},
+		{"indent while locked", (*OutlineService).Indent, "200", lockErr, nil, lockErr},
+		{"indent unreadable", (*OutlineService).Indent, "200", nil, readErr, readErr},
+		{"outdent root", (*OutlineService).Outdent, "200", nil, nil, ErrAlreadyTopLevel},
+		{"outdent missing node", (*OutlineService).Outdent, "300", nil, nil, ErrNodeNotFound},
+		{"outdent while locked", (*OutlineService).Outdent, "100-200", lockErr, nil, lockErr},
+		{"outdent unreadable", (*OutlineService).Outdent, "100-200", nil, readErr, readErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renamer := &fakeFileRenamer{}
+			reader := &fakeDirectoryReader{files: indentTestFiles(), err: tt.readErr}
+			svc := NewOutlineService(reader, nil, &mockLocker{tryLockErr: tt.lockErr}, nil)
+			svc.renamer = renamer
+
+			sel, _ := domain.ParseSelector(tt.selector)
+			_, err := tt.op(svc, context.Background(), sel, true)
+
+			if !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+			if len(renamer.renames) != 0 {
+				t.Errorf("files renamed = %d, want 0", len(renamer.renames))
+			}
+		})
+	}
+}
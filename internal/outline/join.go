@@ -0,0 +1,155 @@
+package outline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+// ErrJoinHasChildren is returned when the node joined into another still
+// has children.
+var ErrJoinHasChildren = errors.New("node to join has children; move them first")
+
+// ErrJoinNotSiblings is returned when the nodes to join have different
+// parents.
+var ErrJoinNotSiblings = errors.New("nodes to join are not siblings")
+
+// JoinResult holds the result of a join operation. The node at RemovedMP
+// was joined into the node at MP: Written lists that node's files given
+// the removed node's text, and Deleted the removed node's files.
+type JoinResult struct {
+	MP         string
+	SID        string
+	RemovedMP  string
+	RemovedSID string
+	Written    []string
+	Deleted    []string
+}
+
+// Join appends the documents of the second node to the matching documents
+// of the first and deletes the second, acquiring an advisory lock first.
+// The second node's frontmatter is dropped; a document type the first node
+// lacks is given to it whole. The nodes must be siblings, the second must
+// have no children, and its SID stays reserved. When apply is false, nothing is written.
+func (s *OutlineService) Join(ctx context.Context, first, second domain.Selector, apply bool) (*JoinResult, error) {
+	if s.contentReader == nil {
+		return nil, ErrContentUnavailable
+	}
+	if err := s.lockForWrite(ctx); err != nil {
+		return nil, err
+	}
+	defer s.locker.Unlock()
+
+	parsed, err := s.readAndParse(ctx)
+	if err != nil {
+		return nil, err
+	}
+	keepMP, keepSID, err := s.resolveTarget(ctx, parsed, first)
+	if err != nil {
+		return nil, err
+	}
+	removeMP, removeSID, err := s.resolveTarget(ctx, parsed, second)
+	if err != nil {
+		return nil, err
+	}
+	if keepMP == removeMP {
+		return nil, fmt.Errorf("cannot join %s into itself", keepMP)
+	}
+	if parentMP(keepMP) != parentMP(removeMP) {
+		return nil, fmt.Errorf("cannot join %s into %s: %w", removeMP, keepMP, ErrJoinNotSiblings)
+	}
+
+	kept := map[string]string{}
+	var removed []domain.ParsedFile
+	for _, pf := range parsed {
+		switch {
+		case pf.MP == keepMP:
+			kept[pf.DocType] = reconstructFilename(pf)
+		case pf.MP == removeMP:
+			removed = append(removed, pf)
+		case isDescendantMP(pf.MP, removeMP):
+			return nil, fmt.Errorf("%s: %w", removeMP, ErrJoinHasChildren)
+		}
+	}
+
+	writes := map[string]string{}
+	result := &JoinResult{MP: keepMP, SID: keepSID, RemovedMP: removeMP, RemovedSID: removeSID}
+	for _, pf := range removed {
+		name := reconstructFilename(pf)
+		result.Deleted = append(result.Deleted, name)
+		content, err := s.contentReader.ReadFile(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", name, err)
+		}
+
+		target, ok := kept[pf.DocType]
+		if !ok {
+			writes[domain.GenerateFilename(keepMP, keepSID, pf.DocType, pf.Slug)] = content
+			continue
+		}
+		existing, err := s.contentReader.ReadFile(ctx, target)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", target, err)
+		}
+		if joined := joinContent(existing, content); joined != existing {
+			writes[target] = joined
+		}
+	}
+	slices.Sort(result.Deleted)
+	result.Written = slices.Sorted(maps.Keys(writes))
+
+	if !apply {
+		return result, nil
+	}
+	if err := s.applyJoinImpl(ctx, result, writes); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// applyJoinImpl writes the joined documents before deleting the removed
+// node's files, so a failure part way never loses text.
+func (s *OutlineService) applyJoinImpl(ctx context.Context, result *JoinResult, writes map[string]string) error {
+	if s.reservationStore != nil {
+		reserved, err := s.reservationStore.HasReservation(ctx, result.RemovedSID)
+		if err != nil {
+			return err
+		}
+		if !reserved {
+			if err := s.reservationStore.CreateReservation(ctx, result.RemovedSID); err != nil {
+				return err
+			}
+		}
+	}
+	for _, name := range result.Written {
+		if err := s.writer.WriteFile(ctx, name, writes[name]); err != nil {
+			return err
+		}
+	}
+	for _, name := range result.Deleted {
+		if err := s.deleter.DeleteFile(ctx, name); err != nil {
+			return fmt.Errorf("delete %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// joinContent appends the body of addition, without its frontmatter, to
+// content after a blank line. A blank body leaves content unchanged, and
+// content with a blank body takes the addition's body in its place.
+func joinContent(content, addition string) string {
+	body := strings.TrimLeft(addition[bodyOffset(addition):], "\n")
+	if strings.TrimSpace(body) == "" {
+		return content
+	}
+	offset := bodyOffset(content)
+	if strings.TrimSpace(content[offset:]) == "" {
+		return content[:offset] + body
+	}
+	return strings.TrimRight(content, "\n") + "\n\n" + body
+}
@@ -0,0 +1,240 @@
+package outline
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+// joinTestFiles returns a fixture of three root nodes, the second with a
+// notes file the first lacks.
+func joinTestFiles() []string {
+	return []string{
+		"100_SID001AABB_draft_one.md",
+		"200_SID002CCDD_draft_two.md",
+		"200_SID002CCDD_notes.md",
+		"300_SID003EEFF_draft_three.md",
+		"300_SID003EEFF_characters_cast.md",
+		"300-100_SID004GGHH_draft_child.md",
+	}
+}
+
+func joinTestContents() map[string]string {
+	return map[string]string{
+		"100_SID001AABB_draft_one.md":       "---\ntitle: One\n---\nFirst.\n",
+		"200_SID002CCDD_draft_two.md":       "---\ntitle: Two\n---\nSecond.\n",
+		"200_SID002CCDD_notes.md":           "Remember.\n",
+		"300_SID003EEFF_draft_three.md":     "---\ntitle: Three\n---\n",
+		"300_SID003EEFF_characters_cast.md": "Cast.\n",
+	}
+}
+
+// failOnFileContentReader fails to read one file and reads the rest from
+// the embedded fake.
+type failOnFileContentReader struct {
+	*fakeContentReader
+	file string
+	err  error
+}
+
+func (f *failOnFileContentReader) ReadFile(ctx context.Context, filename string) (string, error) {
+	if filename == f.file {
+		return "", f.err
+	}
+	return f.fakeContentReader.ReadFile(ctx, filename)
+}
+
+type joinTestDeps struct {
+	writer       *fakeFileWriter
+	deleter      *fakeFileDeleter
+	reservations *fakeReservationStore
+	content      *fakeContentReader
+}
+
+func newJoinTestService() (*OutlineService, *joinTestDeps) {
+	deps := &joinTestDeps{
+		writer:       &fakeFileWriter{},
+		deleter:      &fakeFileDeleter{},
+		reservations: &fakeReservationStore{reservations: map[string]bool{}},
+		content:      &fakeContentReader{contents: joinTestContents()},
+	}
+	svc := NewOutlineService(&fakeDirectoryReader{files: joinTestFiles()}, deps.writer, &mockLocker{}, &fakeSIDReserver{},
+		WithDeleter(deps.deleter), WithContentReader(deps.content), WithReservationStore(deps.reservations))
+	return svc, deps
+}
+
+func TestOutlineService_Join(t *testing.T) {
+	svc, deps := newJoinTestService()
+	first, _ := domain.ParseSelector("100")
+	second, _ := domain.ParseSelector("sid:SID002CCDD")
+
+	result, err := svc.Join(context.Background(), first, second, true)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := &JoinResult{
+		MP: "100", SID: "SID001AABB", RemovedMP: "200", RemovedSID: "SID002CCDD",
+		Written: []string{"100_SID001AABB_draft_one.md", "100_SID001AABB_notes.md"},
+		Deleted: []string{"200_SID002CCDD_draft_two.md", "200_SID002CCDD_notes.md"},
+	}
+	if result.MP != want.MP || result.SID != want.SID || result.RemovedMP != want.RemovedMP || result.RemovedSID != want.RemovedSID ||
+		!slices.Equal(result.Written, want.Written) || !slices.Equal(result.Deleted, want.Deleted) {
+		t.Errorf("result = %+v, want %+v", result, want)
+	}
+	if got := deps.writer.written["100_SID001AABB_draft_one.md"]; got != "---\ntitle: One\n---\nFirst.\n\nSecond.\n" {
+		t.Errorf("joined draft = %q", got)
+	}
+	if got := deps.writer.written["100_SID001AABB_notes.md"]; got != "Remember.\n" {
+		t.Errorf("moved notes = %q", got)
+	}
+	if !slices.Equal(deps.deleter.deleted, want.Deleted) {
+		t.Errorf("deleted = %v, want %v", deps.deleter.deleted, want.Deleted)
+	}
+	if !slices.Equal(deps.reservations.created, []string{"SID002CCDD"}) {
+		t.Errorf("reservations = %v, want the removed SID", deps.reservations.created)
+	}
+}
+
+func TestOutlineService_Join_KeepsExistingReservationAndSlug(t *testing.T) {
+	svc, deps := newJoinTestService()
+	deps.reservations.reservations["SID001AABB"] = true
+	first, _ := domain.ParseSelector("300")
+	second, _ := domain.ParseSelector("100")
+
+	result, err := svc.Join(context.Background(), first, second, true)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(result.Written, []string{"300_SID003EEFF_draft_three.md"}) {
+		t.Errorf("written = %v", result.Written)
+	}
+	if got := deps.writer.written["300_SID003EEFF_draft_three.md"]; got != "---\ntitle: Three\n---\nFirst.\n" {
+		t.Errorf("joined draft = %q", got)
+	}
+	if len(deps.reservations.created) != 0 {
+		t.Errorf("reservations = %v, want none created", deps.reservations.created)
+	}
+}
+
+func TestOutlineService_Join_DryRun(t *testing.T) {
+	svc, deps := newJoinTestService()
+	first, _ := domain.ParseSelector("100")
+	second, _ := domain.ParseSelector("200")
+
+	result, err := svc.Join(context.Background(), first, second, false)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Written) != 2 || len(deps.writer.written) != 0 || len(deps.deleter.deleted) != 0 {
+		t.Errorf("result = %+v, written %v, deleted %v", result, deps.writer.written, deps.deleter.deleted)
+	}
+}
+
+func TestOutlineService_Join_SkipsUnchangedDocuments(t *testing.T) {
+	svc, deps := newJoinTestService()
+	deps.content.contents["200_SID002CCDD_draft_two.md"] = "---\ntitle: Two\n---\n\n"
+	first, _ := domain.ParseSelector("100")
+	second, _ := domain.ParseSelector("200")
+
+	result, err := svc.Join(context.Background(), first, second, true)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(result.Written, []string{"100_SID001AABB_notes.md"}) {
+		t.Errorf("written = %v, want only the notes", result.Written)
+	}
+}
+
+func TestOutlineService_Join_Errors(t *testing.T) {
+	lockErr := errors.New("locked")
+	readErr := errors.New("read failed")
+	contentErr := errors.New("unreadable")
+	hasErr := errors.New("reservations unreadable")
+	createErr := errors.New("no reservations")
+	writeErr := errors.New("disk full")
+	deleteErr := errors.New("permission denied")
+
+	tests := []struct {
+		name   string
+		first  string
+		second string
+		setup  func(*OutlineService, *joinTestDeps)
+		want   error
+	}{
+		{"no content reader", "100", "200", func(s *OutlineService, _ *joinTestDeps) { s.contentReader = nil }, ErrContentUnavailable},
+		{"locked", "100", "200", func(s *OutlineService, _ *joinTestDeps) { s.locker = &mockLocker{tryLockErr: lockErr} }, lockErr},
+		{"unreadable directory", "100", "200", func(s *OutlineService, _ *joinTestDeps) { s.reader = &fakeDirectoryReader{err: readErr} }, readErr},
+		{"missing first", "900", "200", nil, ErrNodeNotFound},
+		{"missing second", "100", "900", nil, ErrNodeNotFound},
+		{"second has children", "100", "300", nil, ErrJoinHasChildren},
+		{"ancestor into descendant", "300-100", "300", nil, ErrJoinNotSiblings},
+		{"descendant into ancestor", "300", "300-100", nil, ErrJoinNotSiblings},
+		{"different parents", "100", "300-100", nil, ErrJoinNotSiblings},
+		{"second unreadable", "100", "200", func(_ *OutlineService, d *joinTestDeps) { d.content.err = contentErr }, contentErr},
+		{"first unreadable", "100", "200", func(s *OutlineService, d *joinTestDeps) {
+			s.contentReader = &failOnFileContentReader{fakeContentReader: d.content, file: "100_SID001AABB_draft_one.md", err: contentErr}
+		}, contentErr},
+		{"reservation check fails", "100", "200", func(_ *OutlineService, d *joinTestDeps) { d.reservations.hasErr = hasErr }, hasErr},
+		{"reservation fails", "100", "200", func(_ *OutlineService, d *joinTestDeps) { d.reservations.createErr = createErr }, createErr},
+		{"write fails", "100", "200", func(_ *OutlineService, d *joinTestDeps) { d.writer.writeErr = writeErr }, writeErr},
+		{"delete fails", "100", "200", func(_ *OutlineService, d *joinTestDeps) { d.deleter.err = deleteErr }, deleteErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, deps := newJoinTestService()
+			if tt.setup != nil {
+				tt.setup(svc, deps)
+			}
+			first, _ := domain.ParseSelector(tt.first)
+			second, _ := domain.ParseSelector(tt.second)
+
+			_, err := svc.Join(context.Background(), first, second, true)
+
+			if !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutlineService_Join_IntoItself(t *testing.T) {
+	svc, _ := newJoinTestService()
+	first, _ := domain.ParseSelector("100")
+	second, _ := domain.ParseSelector("sid:SID001AABB")
+
+	_, err := svc.Join(context.Background(), first, second, true)
+
+	if err == nil {
+		t.Error("expected an error joining a node into itself")
+	}
+}
+
+func TestJoinContent(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		addition string
+		want     string
+	}{
+		{"appends after a blank line", "a\n", "b\n", "a\n\nb\n"},
+		{"drops the addition's frontmatter", "---\ntitle: A\n---\na\n\n\n", "---\ntitle: B\n---\n\nb\n", "---\ntitle: A\n---\na\n\nb\n"},
+		{"blank addition", "a\n", "---\ntitle: B\n---\n\n", "a\n"},
+		{"blank content", "---\ntitle: A\n---\n\n", "b\n", "---\ntitle: A\n---\nb\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := joinContent(tt.content, tt.addition); got != tt.want {
+				t.Errorf("joinContent = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
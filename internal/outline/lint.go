@@ -0,0 +1,305 @@
+package outline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+// ErrUnknownRule is returned when a rule filter names neither a lint rule
+// nor a finding type.
+var ErrUnknownRule = errors.New("unknown rule")
+
+// Built-in lint rule IDs.
+const (
+	RuleMaxLength     = "max-length"
+	RuleBannedPhrases = "banned-phrases"
+	RuleDoubledWords  = "doubled-words"
+	RulePassiveVoice  = "passive-voice"
+	RuleTODO          = "todo"
+)
+
+// LintDocument is a draft handed to lint rules. Body holds the text after
+// the frontmatter; BodyOffset is its byte offset within Content.
+type LintDocument struct {
+	Path       string
+	SID        string
+	Content    string
+	Body       string
+	BodyOffset int
+}
+
+// newLintDocument splits content into frontmatter and body.
+func newLintDocument(path, sid, content string) LintDocument {
+	offset := bodyOffset(content)
+	return LintDocument{Path: path, SID: sid, Content: content, Body: content[offset:], BodyOffset: offset}
+}
+
+// bodyOffset returns the byte offset where the body starts, just past the
+// closing "---" line of the frontmatter, or 0 when there is none.
+func bodyOffset(content string) int {
+	if !strings.HasPrefix(content, "---\n") {
+		return 0
+	}
+	pos := 4
+	for pos < len(content) {
+		end := strings.IndexByte(content[pos:], '\n')
+		if end < 0 {
+			if content[pos:] == "---" {
+				return len(content)
+			}
+			return 0
+		}
+		if content[pos:pos+end] == "---" {
+			return pos + end + 1
+		}
+		pos += end + 1
+	}
+	return 0
+}
+
+// Finding builds a lint finding for the rule covering the byte range
+// [start, end) within Body.
+func (d LintDocument) Finding(rule, message string, start, end int) domain.Finding {
+	line, col := position(d.Content, d.BodyOffset+start)
+	endLine, endCol := position(d.Content, d.BodyOffset+end)
+	return domain.Finding{
+		Type:      domain.FindingLint,
+		Severity:  domain.SeverityWarning,
+		Message:   message,
+		Path:      d.Path,
+		Rule:      rule,
+		Line:      line,
+		Column:    col,
+		EndLine:   endLine,
+		EndColumn: endCol,
+	}
+}
+
+// position converts a byte offset into a 1-based line and rune column.
+func position(content string, offset int) (line, col int) {
+	before := content[:offset]
+	line = strings.Count(before, "\n") + 1
+	lineStart := strings.LastIndexByte(before, '\n') + 1
+	return line, utf8.RuneCountInString(before[lineStart:]) + 1
+}
+
+// LintRule is a prose check run over every draft by Check.
+type LintRule interface {
+	ID() string
+	Lint(doc LintDocument) []domain.Finding
+}
+
+// builtinLintRules returns the built-in rules configured from cfg.
+func builtinLintRules(cfg domain.LintConfig) []LintRule {
+	return []LintRule{
+		maxLengthRule{max: cfg.MaxWords},
+		bannedPhrasesRule{phrases: cfg.BannedPhrases},
+		doubledWordsRule{},
+		passiveVoiceRule{},
+		todoRule{},
+	}
+}
+
+// maxLengthRule reports drafts with more words than the configured maximum.
+type maxLengthRule struct {
+	max int
+}
+
+func (maxLengthRule) ID() string { return RuleMaxLength }
+
+func (r maxLengthRule) Lint(doc LintDocument) []domain.Finding {
+	words := len(strings.Fields(doc.Body))
+	if words <= r.max {
+		return nil
+	}
+	return []domain.Finding{doc.Finding(RuleMaxLength, fmt.Sprintf("node %s draft has %d words, over the %d word limit", doc.SID, words, r.max), 0, len(doc.Body))}
+}
+
+// bannedPhrasesRule reports each occurrence of a configured phrase, ignoring case.
+type bannedPhrasesRule struct {
+	phrases []string
+}
+
+func (bannedPhrasesRule) ID() string { return RuleBannedPhrases }
+
+func (r bannedPhrasesRule) Lint(doc LintDocument) []domain.Finding {
+	var findings []domain.Finding
+	for _, phrase := range r.phrases {
+		re := phraseRegex(phrase)
+		if re == nil {
+			continue
+		}
+		for _, loc := range re.FindAllStringIndex(doc.Body, -1) {
+			findings = append(findings, doc.Finding(RuleBannedPhrases, fmt.Sprintf("banned phrase %q", phrase), loc[0], loc[1]))
+		}
+	}
+	return findings
+}
+
+// phraseRegex matches phrase case-insensitively, on word boundaries where
+// the phrase starts or ends with a word character. A blank phrase matches
+// nothing, and yields nil.
+func phraseRegex(phrase string) *regexp.Regexp {
+	if strings.TrimSpace(phrase) == "" {
+		return nil
+	}
+	pattern := regexp.QuoteMeta(phrase)
+	if wordRegex.MatchString(phrase[:1]) {
+		pattern = `\b` + pattern
+	}
+	if wordRegex.MatchString(phrase[len(phrase)-1:]) {
+		pattern += `\b`
+	}
+	return regexp.MustCompile(`(?i)` + pattern)
+}
+
+// wordRegex matches a word for doubled-word detection.
+var wordRegex = regexp.MustCompile(`[\p{L}\p{N}']+`)
+
+// doubledWordsRule reports a word repeated with only whitespace between, such as "the the".
+type doubledWordsRule struct{}
+
+func (doubledWordsRule) ID() string { return RuleDoubledWords }
+
+func (doubledWordsRule) Lint(doc LintDocument) []domain.Finding {
+	var findings []domain.Finding
+	words := wordRegex.FindAllStringIndex(doc.Body, -1)
+	for i := 1; i < len(words); i++ {
+		prev, cur := words[i-1], words[i]
+		if strings.TrimSpace(doc.Body[prev[1]:cur[0]]) != "" {
+			continue
+		}
+		word := doc.Body[cur[0]:cur[1]]
+		if strings.EqualFold(doc.Body[prev[0]:prev[1]], word) {
+			findings = append(findings, doc.Finding(RuleDoubledWords, fmt.Sprintf("doubled word %q", word), prev[0], cur[1]))
+		}
+	}
+	return findings
+}
+
+// passiveRegex matches a form of "to be" followed by a past participle:
+// a regular "-ed" form or a common irregular one.
+var passiveRegex = regexp.MustCompile(`(?i)\b(?:am|is|are|was|were|be|been|being)\s+(?:\w+ed|known|made|built|done|seen|taken|given|found|told|held|kept|left|lost|paid|sent|sold|thought|brought|caught|taught|bought|written|spoken|broken|chosen|driven|forgotten|hidden|shown|stolen|thrown|worn)\b`)
+
+// passiveVoiceRule reports likely passive constructions.
+type passiveVoiceRule struct{}
+
+func (passiveVoiceRule) ID() string { return RulePassiveVoice }
+
+func (passiveVoiceRule) Lint(doc LintDocument) []domain.Finding {
+	var findings []domain.Finding
+	for _, loc := range passiveRegex.FindAllStringIndex(doc.Body, -1) {
+		phrase := strings.Join(strings.Fields(doc.Body[loc[0]:loc[1]]), " ")
+		findings = append(findings, doc.Finding(RulePassiveVoice, fmt.Sprintf("possible passive voice %q", phrase), loc[0], loc[1]))
+	}
+	return findings
+}
+
+// todoRegex matches markers for unfinished text.
+var todoRegex = regexp.MustCompile(`\b(?:TODO|FIXME|TK)\b`)
+
+// todoRule reports TODO, FIXME and TK markers left in a draft.
+type todoRule struct{}
+
+func (todoRule) ID() string { return RuleTODO }
+
+func (todoRule) Lint(doc LintDocument) []domain.Finding {
+	var findings []domain.Finding
+	for _, loc := range todoRegex.FindAllStringIndex(doc.Body, -1) {
+		findings = append(findings, doc.Finding(RuleTODO, fmt.Sprintf("%s marker left in draft", doc.Body[loc[0]:loc[1]]), loc[0], loc[1]))
+	}
+	return findings
+}
+
+// allLintRules returns the built-in rules followed by those registered with WithLintRules.
+func (s *OutlineService) allLintRules() []LintRule {
+	return append(builtinLintRules(s.config.Lint), s.lintRules...)
+}
+
+// validateRuleFilter rejects IDs that name neither a lint rule nor a finding type.
+func validateRuleFilter(rules []LintRule, ids []string) error {
+	for _, id := range ids {
+		if !slices.ContainsFunc(rules, func(r LintRule) bool { return r.ID() == id }) &&
+			!slices.Contains(domain.FindingTypes(), domain.FindingType(id)) {
+			return fmt.Errorf("%w: %q", ErrUnknownRule, id)
+		}
+	}
+	return nil
+}
+
+// findUnknownLintRuleFindings reports lint.rules entries that match no registered rule.
+func findUnknownLintRuleFindings(rules []LintRule, enabled []string) []domain.Finding {
+	var findings []domain.Finding
+	for _, id := range enabled {
+		if !slices.ContainsFunc(rules, func(r LintRule) bool { return r.ID() == id }) {
+			findings = append(findings, domain.Finding{
+				Type:     domain.FindingInvalidConfig,
+				Severity: domain.SeverityError,
+				Message:  fmt.Sprintf("lint.rules: unknown rule %q", id),
+				Path:     domain.ConfigPath,
+			})
+		}
+	}
+	return findings
+}
+
+// enabledLintRules selects the rules to run: those named by the check
+// filter if any, otherwise those enabled in config, minus skipped rules.
+func enabledLintRules(rules []LintRule, configured []string, cfg checkConfig) []LintRule {
+	enabled := configured
+	if len(cfg.rules) > 0 {
+		enabled = cfg.rules
+	}
+	var selected []LintRule
+	for _, r := range rules {
+		if slices.Contains(enabled, r.ID()) && !slices.Contains(cfg.skip, r.ID()) {
+			selected = append(selected, r)
+		}
+	}
+	return selected
+}
+
+// filterFindings keeps findings whose rule ID passes the check filter.
+func filterFindings(findings []domain.Finding, cfg checkConfig) []domain.Finding {
+	var kept []domain.Finding
+	for _, f := range findings {
+		if len(cfg.rules) > 0 && !slices.Contains(cfg.rules, f.RuleID()) {
+			continue
+		}
+		if slices.Contains(cfg.skip, f.RuleID()) {
+			continue
+		}
+		kept = append(kept, f)
+	}
+	return kept
+}
+
+// findLintFindingsImpl runs the lint rules over every readable draft.
+func (s *OutlineService) findLintFindingsImpl(ctx context.Context, parsed []domain.ParsedFile, rules []LintRule) []domain.Finding {
+	if s.contentReader == nil || len(rules) == 0 {
+		return nil
+	}
+	var findings []domain.Finding
+	for _, pf := range parsed {
+		if pf.DocType != domain.DocTypeDraft {
+			continue
+		}
+		filename := reconstructFilename(pf)
+		content, err := s.contentReader.ReadFile(ctx, filename)
+		if err != nil {
+			continue
+		}
+		doc := newLintDocument(filename, pf.SID, content)
+		for _, r := range rules {
+			findings = append(findings, r.Lint(doc)...)
+		}
+	}
+	return findings
+}
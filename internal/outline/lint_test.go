@@ -0,0 +1,262 @@
+package outline
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+func TestBodyOffset(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    int
+	}{
+		{"no frontmatter", "Hello\n", 0},
+		{"frontmatter and body", "---\ntitle: A\n---\nBody\n", 17},
+		{"frontmatter without trailing newline", "---\ntitle: A\n---", 16},
+		{"unclosed frontmatter", "---\ntitle: A\n", 0},
+		{"unclosed frontmatter without newline", "---\ntitle: A", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bodyOffset(tt.content); got != tt.want {
+				t.Errorf("bodyOffset(%q) = %d, want %d", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintDocument_FindingPosition(t *testing.T) {
+	doc := newLintDocument("a.md", "SID001AABB", "---\ntitle: A\n---\nFirst line\nsécond TODO\n")
+
+	f := doc.Finding(RuleTODO, "msg", len("First line\nsécond "), len("First line\nsécond TODO"))
+
+	if f.Line != 5 || f.Column != 8 || f.EndLine != 5 || f.EndColumn != 12 {
+		t.Errorf("range = %d:%d-%d:%d, want 5:8-5:12", f.Line, f.Column, f.EndLine, f.EndColumn)
+	}
+	if f.Type != domain.FindingLint || f.Severity != domain.SeverityWarning || f.Rule != RuleTODO || f.Path != "a.md" {
+		t.Errorf("finding = %+v, want lint warning for %s on a.md", f, RuleTODO)
+	}
+}
+
+func TestBuiltinLintRules(t *testing.T) {
+	cfg := domain.LintConfig{MaxWords: 3, BannedPhrases: []string{"suddenly", "very ", "...", "", "  "}}
+	rules := map[string]LintRule{}
+	for _, r := range builtinLintRules(cfg) {
+		rules[r.ID()] = r
+	}
+
+	tests := []struct {
+		rule         string
+		body         string
+		wantMessages []string
+	}{
+		{RuleMaxLength, "one two three", nil},
+		{RuleMaxLength, "one two three four", []string{"node SID001AABB draft has 4 words, over the 3 word limit"}},
+		{RuleBannedPhrases, "Suddenly, a very big wave... Suddenness", []string{
+			`banned phrase "suddenly"`, `banned phrase "very "`, `banned phrase "..."`,
+		}},
+		{RuleBannedPhrases, "Everyone suddenlyish", nil},
+		{RuleDoubledWords, "the the cat sat on The\nthe mat", []string{`doubled word "the"`, `doubled word "the"`}},
+		{RuleDoubledWords, "that, that is", nil},
+		{RulePassiveVoice, "The ship was  wrecked and the map was written.", []string{
+			`possible passive voice "was wrecked"`, `possible passive voice "was written"`,
+		}},
+		{RulePassiveVoice, "She wrecked the ship.", nil},
+		{RuleTODO, "TODO fix this. FIXME too. TK", []string{
+			"TODO marker left in draft", "FIXME marker left in draft", "TK marker left in draft",
+		}},
+		{RuleTODO, "todo lists are fine", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule+"/"+tt.body, func(t *testing.T) {
+			doc := newLintDocument("a.md", "SID001AABB", tt.body)
+
+			findings := rules[tt.rule].Lint(doc)
+
+			if len(findings) != len(tt.wantMessages) {
+				t.Fatalf("findings = %+v, want messages %q", findings, tt.wantMessages)
+			}
+			for i, f := range findings {
+				if f.Message != tt.wantMessages[i] {
+					t.Errorf("finding[%d].Message = %q, want %q", i, f.Message, tt.wantMessages[i])
+				}
+				if f.Rule != tt.rule {
+					t.Errorf("finding[%d].Rule = %q, want %q", i, f.Rule, tt.rule)
+				}
+			}
+		})
+	}
+}
+
+// stubLintRule flags every draft it sees.
+type stubLintRule struct{}
+
+func (stubLintRule) ID() string { return "house-style" }
+
+func (stubLintRule) Lint(doc LintDocument) []domain.Finding {
+	return []domain.Finding{doc.Finding("house-style", "house style", 0, 0)}
+}
+
+func lintTestService(cfg domain.Config, opts ...Option) *OutlineService {
+	reader := &fakeDirectoryReader{files: []string{
+		"100_SID001AABB_draft_act.md",
+		"100_SID001AABB_notes.md",
+		"200_SID002AABB_draft_storm.md",
+	}}
+	content := &fakeContentReader{contents: map[string]string{
+		"100_SID001AABB_draft_act.md":   "---\ntitle: Act\n---\nTODO write the the act\n",
+		"100_SID001AABB_notes.md":       "TODO notes are not linted\n",
+		"200_SID002AABB_draft_storm.md": "---\ntitle: Storm\n---\nIt rained.\n",
+	}}
+	opts = append([]Option{WithConfig(cfg), WithContentReader(content)}, opts...)
+	return NewOutlineService(reader, nil, &mockLocker{}, nil, opts...)
+}
+
+func TestOutlineService_Check_Lint(t *testing.T) {
+	enabled := domain.DefaultConfig()
+	enabled.Lint.Rules = []string{RuleTODO, RuleDoubledWords}
+
+	tests := []struct {
+		name      string
+		cfg       domain.Config
+		svcOpts   []Option
+		checkOpts []CheckOption
+		want      []string
+	}{
+		{
+			name: "no rules enabled by default",
+			cfg:  domain.DefaultConfig(),
+			want: []string{"missing_doc_type"},
+		},
+		{
+			name: "configured rules run on drafts only",
+			cfg:  enabled,
+			want: []string{"missing_doc_type", "doubled-words@100_SID001AABB_draft_act.md:4:12", "todo@100_SID001AABB_draft_act.md:4:1"},
+		},
+		{
+			name:      "rule filter runs unconfigured lint rule and hides other findings",
+			cfg:       domain.DefaultConfig(),
+			checkOpts: []CheckOption{CheckRules(RulePassiveVoice, RuleDoubledWords)},
+			want:      []string{"doubled-words@100_SID001AABB_draft_act.md:4:12"},
+		},
+		{
+			name:      "rule filter accepts finding types",
+			cfg:       enabled,
+			checkOpts: []CheckOption{CheckRules(string(domain.FindingMissingDocType))},
+			want:      []string{"missing_doc_type"},
+		},
+		{
+			name:      "skip rule drops lint rule and finding type",
+			cfg:       enabled,
+			checkOpts: []CheckOption{CheckSkipRules(RuleTODO, string(domain.FindingMissingDocType))},
+			want:      []string{"doubled-words@100_SID001AABB_draft_act.md:4:12"},
+		},
+		{
+			name:      "registered rule",
+			cfg:       domain.DefaultConfig(),
+			svcOpts:   []Option{WithLintRules(stubLintRule{})},
+			checkOpts: []CheckOption{CheckRules("house-style")},
+			want:      []string{"house-style@100_SID001AABB_draft_act.md:4:1", "house-style@200_SID002AABB_draft_storm.md:4:1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := lintTestService(tt.cfg, tt.svcOpts...)
+
+			result, err := svc.Check(context.Background(), tt.checkOpts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var got []string
+			for _, f := range result.Findings {
+				if f.Type != domain.FindingLint {
+					got = append(got, f.RuleID())
+					continue
+				}
+				got = append(got, f.Rule+"@"+f.Path+":"+strconv.Itoa(f.Line)+":"+strconv.Itoa(f.Column))
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("findings = %q, want %q", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("finding[%d] = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestOutlineService_Check_LintSkipsUnreadableDrafts(t *testing.T) {
+	cfg := domain.DefaultConfig()
+	cfg.Lint.Rules = []string{RuleTODO}
+	reader := &fakeDirectoryReader{files: []string{"100_SID001AABB_draft_act.md", "100_SID001AABB_notes.md"}}
+	svc := NewOutlineService(reader, nil, &mockLocker{}, nil,
+		WithConfig(cfg), WithContentReader(&fakeContentReader{err: errors.New("io error")}))
+
+	result, err := svc.Check(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, f := range result.Findings {
+		if f.Type == domain.FindingLint {
+			t.Errorf("unexpected lint finding %+v", f)
+		}
+	}
+}
+
+func TestOutlineService_Check_UnknownConfiguredLintRule(t *testing.T) {
+	cfg := domain.DefaultConfig()
+	cfg.Lint.Rules = []string{RuleTODO, "no-such-rule"}
+	svc := lintTestService(cfg)
+
+	result, err := svc.Check(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := domain.Finding{
+		Type:     domain.FindingInvalidConfig,
+		Severity: domain.SeverityError,
+		Message:  `lint.rules: unknown rule "no-such-rule"`,
+		Path:     domain.ConfigPath,
+	}
+	for _, f := range result.Findings {
+		if f == want {
+			return
+		}
+	}
+	t.Errorf("findings = %+v, want %+v", result.Findings, want)
+}
+
+func TestOutlineService_Check_UnknownRuleFilter(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  CheckOption
+	}{
+		{"rule", CheckRules("no-such-rule")},
+		{"skip rule", CheckSkipRules("no-such-rule")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := lintTestService(domain.DefaultConfig())
+
+			_, err := svc.Check(context.Background(), tt.opt)
+
+			if !errors.Is(err, ErrUnknownRule) {
+				t.Errorf("error = %v, want ErrUnknownRule", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,572 @@
+package outline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+// ErrNoMergeInProgress is returned when resolving a merge but none is under way.
+var ErrNoMergeInProgress = errors.New("no merge in progress")
+
+// ErrMergeDuplicateSID is returned when a side of a merge holds a SID at more
+// than one MP, which merging by SID would lose files from.
+var ErrMergeDuplicateSID = errors.New("SID is held by more than one node")
+
+// Snapshot is a read-only view of an outline directory, such as one side of
+// a merge.
+type Snapshot interface {
+	DirectoryReader
+	ContentReader
+}
+
+// MergeSource abstracts locating the base, ours and theirs sides of an
+// in-progress version-control merge of the project.
+type MergeSource interface {
+	MergeSnapshots(ctx context.Context) (base, ours, theirs Snapshot, err error)
+}
+
+// MergeConflictKind classifies a change the three-way merge could not
+// reconcile on its own.
+type MergeConflictKind string
+
+// Merge conflict kinds.
+const (
+	// MergeConflictMove means a node moved to different parents on each
+	// side, or the moves formed a cycle; ours is kept.
+	MergeConflictMove MergeConflictKind = "move"
+	// MergeConflictDelete means a node or document was deleted on one side
+	// and changed on the other; the changed version is kept.
+	MergeConflictDelete MergeConflictKind = "delete"
+	// MergeConflictContent means a document changed differently on each
+	// side; both versions are kept between conflict markers.
+	MergeConflictContent MergeConflictKind = "content"
+)
+
+// MergeConflict describes one conflict. Path is the merged filename of the
+// affected document, or of the node's first document for node conflicts.
+type MergeConflict struct {
+	Kind    MergeConflictKind
+	SID     string
+	DocType string
+	Path    string
+	Message string
+}
+
+// MergeResult lists the files a merge writes and deletes in the target
+// directory, and the conflicts it found.
+type MergeResult struct {
+	Written   []string
+	Deleted   []string
+	Conflicts []MergeConflict
+}
+
+// mergeDoc is one document of a node on one side of a merge.
+type mergeDoc struct {
+	slug    string
+	content string
+}
+
+// mergeNode is a node keyed by SID: its parent's SID ("" at the root), its
+// sibling number and its documents by type. Keying structure by SID rather
+// than MP lets a move on one side merge cleanly with edits on the other.
+// The MP it was read at is kept for reporting only, and others lists the
+// MPs of further nodes sharing its SID, whose documents are not read.
+type mergeNode struct {
+	parent string
+	num    int
+	docs   map[string]mergeDoc
+	mp     string
+	others []string
+}
+
+// sameAs reports whether two versions of a node differ only in position
+// among their siblings, which renumbering changes wholesale.
+func (n *mergeNode) sameAs(other *mergeNode) bool {
+	return n.parent == other.parent && maps.Equal(n.docs, other.docs)
+}
+
+// mergeSide holds one side of a merge by SID.
+type mergeSide map[string]*mergeNode
+
+// pick3 merges one value three ways: a side that changed it from base wins,
+// and ours wins when both changed it differently, which is reported.
+func pick3[T comparable](base, ours, theirs T) (T, bool) {
+	switch {
+	case ours == base:
+		return theirs, false
+	case theirs == base || theirs == ours:
+		return ours, false
+	}
+	return ours, true
+}
+
+// conflictMarkers keeps both versions of a document, git style.
+func conflictMarkers(ours, theirs string) string {
+	withNewline := func(s string) string {
+		if s != "" && !strings.HasSuffix(s, "\n") {
+			return s + "\n"
+		}
+		return s
+	}
+	return "<<<<<<< ours\n" + withNewline(ours) + "=======\n" + withNewline(theirs) + ">>>>>>> theirs\n"
+}
+
+// MergeDocument merges three versions of a document's content as a merge
+// does: a side that changed it wins, and when both changed it differently
+// both versions are kept between conflict markers and the clash reported.
+func MergeDocument(base, ours, theirs string) (string, bool) {
+	content, clash := pick3(base, ours, theirs)
+	if clash {
+		return conflictMarkers(ours, theirs), true
+	}
+	return content, false
+}
+
+// describeParent names a parent SID for messages.
+func describeParent(sid string) string {
+	if sid == "" {
+		return "the root"
+	}
+	return sid
+}
+
+// readSnapshotImpl reads every validly named document of a snapshot into
+// nodes keyed by SID. A node's parent is the nearest ancestor MP that holds
+// a node. A SID held at several MPs is read at the first only.
+func readSnapshotImpl(ctx context.Context, snap Snapshot) (mergeSide, error) {
+	names, err := snap.ReadDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+	parsed, _ := parseFilesWithFindings(names)
+
+	sidAt := map[string]string{}
+	for _, pf := range parsed {
+		if _, ok := sidAt[pf.MP]; !ok {
+			sidAt[pf.MP] = pf.SID
+		}
+	}
+
+	side := mergeSide{}
+	for _, pf := range parsed {
+		n, ok := side[pf.SID]
+		if ok && n.mp != pf.MP {
+			if !slices.Contains(n.others, pf.MP) {
+				n.others = append(n.others, pf.MP)
+			}
+			continue
+		}
+		content, err := snap.ReadFile(ctx, reconstructFilename(pf))
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			n = &mergeNode{docs: map[string]mergeDoc{}, mp: pf.MP}
+			n.num, _ = strconv.Atoi(pf.PathParts[len(pf.PathParts)-1])
+			for p := parentMP(pf.MP); p != ""; p = parentMP(p) {
+				if sid, ok := sidAt[p]; ok {
+					n.parent = sid
+					break
+				}
+			}
+			side[pf.SID] = n
+		}
+		n.docs[pf.DocType] = mergeDoc{slug: pf.Slug, content: content}
+	}
+	return side, nil
+}
+
+// mergeSides merges the nodes of three sides by SID.
+func mergeSides(base, ours, theirs mergeSide) (map[string]*mergeNode, []MergeConflict) {
+	sids := slices.Sorted(maps.Keys(base))
+	sids = append(sids, slices.Sorted(maps.Keys(ours))...)
+	sids = append(sids, slices.Sorted(maps.Keys(theirs))...)
+	slices.Sort(sids)
+
+	merged := map[string]*mergeNode{}
+	var conflicts []MergeConflict
+	for _, sid := range slices.Compact(sids) {
+		b, o, t := base[sid], ours[sid], theirs[sid]
+		var n *mergeNode
+		switch {
+		case o == nil && t == nil:
+			continue
+		case b == nil && o != nil:
+			n = o
+		case b == nil:
+			n = t
+		case o == nil || t == nil:
+			kept, side := o, "theirs"
+			if o == nil {
+				kept, side = t, "ours"
+			}
+			if kept.sameAs(b) {
+				continue
+			}
+			n = kept
+			conflicts = append(conflicts, MergeConflict{
+				Kind:    MergeConflictDelete,
+				SID:     sid,
+				Message: fmt.Sprintf("node %s was deleted on %s but changed on the other side; kept", sid, side),
+			})
+		default:
+			var nodeConflicts []MergeConflict
+			n, nodeConflicts = mergeNodes(sid, b, o, t)
+			conflicts = append(conflicts, nodeConflicts...)
+			if len(n.docs) == 0 {
+				continue
+			}
+		}
+		clone := *n
+		merged[sid] = &clone
+	}
+	return merged, conflicts
+}
+
+// mergeNodes merges a node present on all three sides.
+func mergeNodes(sid string, b, o, t *mergeNode) (*mergeNode, []MergeConflict) {
+	var conflicts []MergeConflict
+	parent, clash := pick3(b.parent, o.parent, t.parent)
+	if clash {
+		conflicts = append(conflicts, MergeConflict{
+			Kind: MergeConflictMove,
+			SID:  sid,
+			Message: fmt.Sprintf("node %s moved under %s on ours but under %s on theirs; kept ours",
+				sid, describeParent(o.parent), describeParent(t.parent)),
+		})
+	}
+
+	// A sibling number only means something under its own parent.
+	num := o.num
+	switch {
+	case parent != o.parent:
+		num = t.num
+	case o.parent == t.parent:
+		num, _ = pick3(b.num, o.num, t.num)
+	}
+
+	docs, docConflicts := mergeDocs(sid, b.docs, o.docs, t.docs)
+	return &mergeNode{parent: parent, num: num, docs: docs}, append(conflicts, docConflicts...)
+}
+
+// mergeDocs merges a node's documents by type.
+func mergeDocs(sid string, base, ours, theirs map[string]mergeDoc) (map[string]mergeDoc, []MergeConflict) {
+	types := slices.Sorted(maps.Keys(base))
+	types = append(types, slices.Sorted(maps.Keys(ours))...)
+	types = append(types, slices.Sorted(maps.Keys(theirs))...)
+	slices.Sort(types)
+
+	docs := map[string]mergeDoc{}
+	var conflicts []MergeConflict
+	for _, docType := range slices.Compact(types) {
+		bd, inBase := base[docType]
+		od, inOurs := ours[docType]
+		td, inTheirs := theirs[docType]
+		switch {
+		case !inOurs && !inTheirs:
+			continue
+		case !inOurs || !inTheirs:
+			kept, side := od, "theirs"
+			if !inOurs {
+				kept, side = td, "ours"
+			}
+			if inBase && kept == bd {
+				continue
+			}
+			if inBase {
+				conflicts = append(conflicts, MergeConflict{
+					Kind:    MergeConflictDelete,
+					SID:     sid,
+					DocType: docType,
+					Message: fmt.Sprintf("%s of node %s was deleted on %s but changed on the other side; kept", docType, sid, side),
+				})
+			}
+			docs[docType] = kept
+		default:
+			slug, _ := pick3(bd.slug, od.slug, td.slug)
+			content, clash := MergeDocument(bd.content, od.content, td.content)
+			if clash {
+				conflicts = append(conflicts, MergeConflict{
+					Kind:    MergeConflictContent,
+					SID:     sid,
+					DocType: docType,
+					Message: fmt.Sprintf("%s of node %s changed on both sides; kept both between conflict markers", docType, sid),
+				})
+			}
+			docs[docType] = mergeDoc{slug: slug, content: content}
+		}
+	}
+	return docs, conflicts
+}
+
+// restoreParents brings back deleted nodes that still have children in the
+// merge, taking each from ours, theirs or base in that order.
+func restoreParents(merged map[string]*mergeNode, base, ours, theirs mergeSide) []MergeConflict {
+	var conflicts []MergeConflict
+	for restored := true; restored; {
+		restored = false
+		for _, sid := range slices.Sorted(maps.Keys(merged)) {
+			parent := merged[sid].parent
+			if parent == "" || merged[parent] != nil {
+				continue
+			}
+			var p *mergeNode
+			for _, side := range []mergeSide{ours, theirs, base} {
+				if p = side[parent]; p != nil {
+					break
+				}
+			}
+			clone := *p
+			merged[parent] = &clone
+			restored = true
+			conflicts = append(conflicts, MergeConflict{
+				Kind:    MergeConflictDelete,
+				SID:     parent,
+				Message: fmt.Sprintf("node %s was deleted but node %s under it was kept; restored", parent, sid),
+			})
+		}
+	}
+	return conflicts
+}
+
+// breakCycles undoes moves that, merged, make a node its own ancestor: each
+// node on the cycle goes back to its place in ours, or to the root when ours
+// does not have it.
+func breakCycles(merged map[string]*mergeNode, ours mergeSide) []MergeConflict {
+	var conflicts []MergeConflict
+	for _, sid := range slices.Sorted(maps.Keys(merged)) {
+		for cycle := cycleFrom(merged, sid); cycle != nil; cycle = cycleFrom(merged, sid) {
+			for _, member := range cycle {
+				n := merged[member]
+				if o := ours[member]; o != nil {
+					n.parent, n.num = o.parent, o.num
+				} else {
+					n.parent = ""
+				}
+			}
+			conflicts = append(conflicts, MergeConflict{
+				Kind:    MergeConflictMove,
+				SID:     sid,
+				Message: fmt.Sprintf("moves on each side put nodes %s inside each other; kept ours", strings.Join(cycle, ", ")),
+			})
+		}
+	}
+	return conflicts
+}
+
+// cycleFrom returns the nodes of the cycle reached by following parents
+// from sid, or nil when the chain ends at the root.
+func cycleFrom(merged map[string]*mergeNode, sid string) []string {
+	seen := map[string]int{}
+	var chain []string
+	for cur := sid; cur != ""; cur = merged[cur].parent {
+		if i, ok := seen[cur]; ok {
+			return chain[i:]
+		}
+		seen[cur] = len(chain)
+		chain = append(chain, cur)
+	}
+	return nil
+}
+
+// layoutMerged renumbers siblings whose merged numbers collide, moving the
+// later one into a free gap after it, and returns each node's MP. Nodes that
+// keep the number they have in ours sort first among equals.
+func layoutMerged(merged map[string]*mergeNode, ours mergeSide, numbering domain.Numbering) (map[string]string, error) {
+	children := map[string][]string{}
+	for _, sid := range slices.Sorted(maps.Keys(merged)) {
+		children[merged[sid].parent] = append(children[merged[sid].parent], sid)
+	}
+
+	keepsOurs := func(sid string) bool {
+		o := ours[sid]
+		return o != nil && o.parent == merged[sid].parent && o.num == merged[sid].num
+	}
+	for _, kids := range children {
+		slices.SortStableFunc(kids, func(a, b string) int {
+			if d := merged[a].num - merged[b].num; d != 0 {
+				return d
+			}
+			if keepsOurs(a) && !keepsOurs(b) {
+				return -1
+			}
+			if keepsOurs(b) && !keepsOurs(a) {
+				return 1
+			}
+			return 0
+		})
+		var occupied []int
+		for _, sid := range kids {
+			occupied = append(occupied, merged[sid].num)
+		}
+		taken := map[int]bool{}
+		for _, sid := range kids {
+			n := merged[sid]
+			if taken[n.num] {
+				num, err := numbering.SiblingNumberAfter(slices.Compact(slices.Sorted(slices.Values(occupied))), n.num)
+				if err != nil {
+					return nil, err
+				}
+				n.num = num
+				occupied = append(occupied, num)
+			}
+			taken[n.num] = true
+		}
+	}
+
+	mps := map[string]string{}
+	var place func(parent, parentMP string)
+	place = func(parent, parentMP string) {
+		for _, sid := range children[parent] {
+			mps[sid] = numbering.ChildMP(parentMP, merged[sid].num)
+			place(sid, mps[sid])
+		}
+	}
+	place("", "")
+	return mps, nil
+}
+
+// mergeOutlines merges three sides into the merged files, by filename, and
+// the conflicts found along the way.
+func (s *OutlineService) mergeOutlines(base, ours, theirs mergeSide) (map[string]string, []MergeConflict, error) {
+	merged, conflicts := mergeSides(base, ours, theirs)
+	conflicts = append(conflicts, restoreParents(merged, base, ours, theirs)...)
+	conflicts = append(conflicts, breakCycles(merged, ours)...)
+
+	mps, err := layoutMerged(merged, ours, s.config.Numbering)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	files := map[string]string{}
+	paths := map[[2]string]string{}
+	for sid, n := range merged {
+		for docType, doc := range n.docs {
+			name := domain.GenerateFilename(mps[sid], sid, docType, doc.slug)
+			files[name] = doc.content
+			paths[[2]string{sid, docType}] = name
+		}
+	}
+	for i, c := range conflicts {
+		if c.DocType != "" {
+			conflicts[i].Path = paths[[2]string{c.SID, c.DocType}]
+			continue
+		}
+		if n := merged[c.SID]; n != nil {
+			first := slices.Sorted(maps.Keys(n.docs))[0]
+			conflicts[i].Path = paths[[2]string{c.SID, first}]
+		}
+	}
+	return files, conflicts, nil
+}
+
+// Merge merges three snapshots of an outline, keyed by SID, into the
+// service's directory, acquiring an advisory lock first. Files the merge
+// drops are deleted and changed ones written. A side holding a SID at more
+// than one MP is refused with ErrMergeDuplicateSID. When apply is false, the
+// changes are planned but not made.
+func (s *OutlineService) Merge(ctx context.Context, base, ours, theirs Snapshot, apply bool) (*MergeResult, error) {
+	if err := s.lockForWrite(ctx); err != nil {
+		return nil, err
+	}
+	defer s.locker.Unlock()
+
+	return s.mergeImpl(ctx, base, ours, theirs, apply)
+}
+
+// ResolveMerge merges the sides of the project's in-progress
+// version-control merge into the project, acquiring an advisory lock first.
+func (s *OutlineService) ResolveMerge(ctx context.Context, apply bool) (*MergeResult, error) {
+	if err := s.lockForWrite(ctx); err != nil {
+		return nil, err
+	}
+	defer s.locker.Unlock()
+
+	if s.mergeSource == nil {
+		return nil, ErrNoMergeInProgress
+	}
+	base, ours, theirs, err := s.mergeSource.MergeSnapshots(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.mergeImpl(ctx, base, ours, theirs, apply)
+}
+
+// mergeImpl performs the I/O operations for Merge and ResolveMerge.
+func (s *OutlineService) mergeImpl(ctx context.Context, base, ours, theirs Snapshot, apply bool) (*MergeResult, error) {
+	var sides [3]mergeSide
+	for i, snap := range []Snapshot{base, ours, theirs} {
+		side, err := readSnapshotImpl(ctx, snap)
+		if err != nil {
+			return nil, err
+		}
+		for _, sid := range slices.Sorted(maps.Keys(side)) {
+			if n := side[sid]; len(n.others) > 0 {
+				return nil, fmt.Errorf("%s: %w: %s at %s and %s; run lmk doctor --apply --fix-duplicates on that side first",
+					[]string{"base", "ours", "theirs"}[i], ErrMergeDuplicateSID, sid, n.mp, strings.Join(n.others, ", "))
+			}
+		}
+		sides[i] = side
+	}
+
+	files, conflicts, err := s.mergeOutlines(sides[0], sides[1], sides[2])
+	if err != nil {
+		return nil, err
+	}
+	written, deleted, err := s.syncFilesImpl(ctx, files, nil, apply)
+	if err != nil {
+		return nil, err
+	}
+	return &MergeResult{Written: written, Deleted: deleted, Conflicts: conflicts}, nil
+}
+
+// syncFilesImpl makes the project directory hold files, by filename: it
+// writes the files whose content differs and deletes the validly named
+// files that are not among them. When inScope is non-nil, only the files it
+// accepts are candidates for deletion. When apply is false, the changes are
+// planned but not made.
+func (s *OutlineService) syncFilesImpl(ctx context.Context, files map[string]string, inScope func(domain.ParsedFile) bool, apply bool) (written, deleted []string, err error) {
+	current, err := s.reader.ReadDir(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	parsed, _ := parseFilesWithFindings(current)
+	existing := map[string]bool{}
+	for _, pf := range parsed {
+		name := reconstructFilename(pf)
+		existing[name] = true
+		if _, ok := files[name]; !ok && (inScope == nil || inScope(pf)) {
+			deleted = append(deleted, name)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		if existing[name] {
+			if content, err := s.contentReader.ReadFile(ctx, name); err == nil && content == files[name] {
+				continue
+			}
+		}
+		written = append(written, name)
+		if apply {
+			if err := s.writer.WriteFile(ctx, name, files[name]); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	if apply {
+		for _, name := range deleted {
+			if err := s.deleter.DeleteFile(ctx, name); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	return written, deleted, nil
+}
@@ -0,0 +1,600 @@
+package outline
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+// fakeSnapshot is a test double for the Snapshot interface.
+type fakeSnapshot struct {
+	files   map[string]string
+	dirErr  error
+	readErr error
+}
+
+func (f *fakeSnapshot) ReadDir(_ context.Context) ([]string, error) {
+	return slices.Sorted(maps.Keys(f.files)), f.dirErr
+}
+
+func (f *fakeSnapshot) ReadFile(_ context.Context, filename string) (string, error) {
+	return f.files[filename], f.readErr
+}
+
+// fakeMergeSource is a test double for the MergeSource interface.
+type fakeMergeSource struct {
+	base, ours, theirs Snapshot
+	err                error
+}
+
+func (f *fakeMergeSource) MergeSnapshots(_ context.Context) (Snapshot, Snapshot, Snapshot, error) {
+	return f.base, f.ours, f.theirs, f.err
+}
+
+// mergeBase is a part with two chapters, the first with a scene.
+var mergeBase = map[string]string{
+	"100_SIDPARTAA1_draft_part.md":      "part\n",
+	"100-100_SIDCHAPAA1_draft_one.md":   "one\n",
+	"100-100-100_SIDSCENEA1_draft_s.md": "scene\n",
+	"100-200_SIDCHAPAA2_draft_two.md":   "two\n",
+}
+
+// withChanges copies files, deleting keys mapped to "" and setting the rest.
+func withChanges(files map[string]string, changes map[string]string) map[string]string {
+	out := maps.Clone(files)
+	for name, content := range changes {
+		if content == "" {
+			delete(out, name)
+			continue
+		}
+		out[name] = content
+	}
+	return out
+}
+
+// runMerge merges into a target holding ours and returns the merged
+// directory and the result.
+func runMerge(t *testing.T, base, ours, theirs map[string]string) (map[string]string, *MergeResult) {
+	t.Helper()
+	writer := &fakeFileWriter{}
+	deleter := &fakeFileDeleter{}
+	target := &fakeSnapshot{files: ours}
+	svc := NewOutlineService(target, writer, &mockLocker{}, nil,
+		WithDeleter(deleter), WithContentReader(target))
+
+	result, err := svc.Merge(context.Background(),
+		&fakeSnapshot{files: base}, &fakeSnapshot{files: ours}, &fakeSnapshot{files: theirs}, true)
+	if err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+
+	out := maps.Clone(ours)
+	for _, name := range deleter.deleted {
+		delete(out, name)
+	}
+	maps.Copy(out, writer.written)
+	return out, result
+}
+
+func assertFiles(t *testing.T, got, want map[string]string) {
+	t.Helper()
+	if !maps.Equal(got, want) {
+		t.Errorf("merged files:\n got %v\nwant %v", got, want)
+	}
+}
+
+func TestOutlineService_Merge_CleanMerges(t *testing.T) {
+	tests := []struct {
+		name   string
+		base   map[string]string
+		ours   map[string]string
+		theirs map[string]string
+		want   map[string]string
+	}{
+		{
+			name: "renumbering on ours, edit and new scene on theirs",
+			ours: withChanges(mergeBase, map[string]string{
+				"100-100_SIDCHAPAA1_draft_one.md":   "",
+				"100-100-100_SIDSCENEA1_draft_s.md": "",
+				"100-200_SIDCHAPAA2_draft_two.md":   "",
+				"100-100_SIDCHAPAA2_draft_two.md":   "two\n",
+				"100-200_SIDCHAPAA1_draft_one.md":   "one\n",
+				"100-200-100_SIDSCENEA1_draft_s.md": "scene\n",
+			}),
+			theirs: withChanges(mergeBase, map[string]string{
+				"100-100-100_SIDSCENEA1_draft_s.md": "scene, revised\n",
+				"100-100-200_SIDSCENEA2_draft_t.md": "new scene\n",
+			}),
+			want: map[string]string{
+				"100_SIDPARTAA1_draft_part.md":      "part\n",
+				"100-100_SIDCHAPAA2_draft_two.md":   "two\n",
+				"100-200_SIDCHAPAA1_draft_one.md":   "one\n",
+				"100-200-100_SIDSCENEA1_draft_s.md": "scene, revised\n",
+				"100-200-200_SIDSCENEA2_draft_t.md": "new scene\n",
+			},
+		},
+		{
+			name: "rename on theirs, move on ours",
+			ours: withChanges(mergeBase, map[string]string{
+				"100-100-100_SIDSCENEA1_draft_s.md": "",
+				"100-200-100_SIDSCENEA1_draft_s.md": "scene\n",
+			}),
+			theirs: withChanges(mergeBase, map[string]string{
+				"100-100-100_SIDSCENEA1_draft_s.md":       "",
+				"100-100-100_SIDSCENEA1_draft_renamed.md": "scene\n",
+			}),
+			want: withChanges(mergeBase, map[string]string{
+				"100-100-100_SIDSCENEA1_draft_s.md":       "",
+				"100-200-100_SIDSCENEA1_draft_renamed.md": "scene\n",
+			}),
+		},
+		{
+			name: "delete on theirs of an unchanged node",
+			ours: mergeBase,
+			theirs: withChanges(mergeBase, map[string]string{
+				"100-200_SIDCHAPAA2_draft_two.md": "",
+			}),
+			want: withChanges(mergeBase, map[string]string{
+				"100-200_SIDCHAPAA2_draft_two.md": "",
+			}),
+		},
+		{
+			name: "delete on ours of a node theirs only renumbered",
+			ours: withChanges(mergeBase, map[string]string{
+				"100-200_SIDCHAPAA2_draft_two.md": "",
+			}),
+			theirs: withChanges(mergeBase, map[string]string{
+				"100-200_SIDCHAPAA2_draft_two.md": "",
+				"100-300_SIDCHAPAA2_draft_two.md": "two\n",
+			}),
+			want: withChanges(mergeBase, map[string]string{
+				"100-200_SIDCHAPAA2_draft_two.md": "",
+			}),
+		},
+		{
+			name: "both add under one parent at the same number",
+			ours: withChanges(mergeBase, map[string]string{
+				"100-300_SIDOURSAA1_draft_ours.md": "ours\n",
+			}),
+			theirs: withChanges(mergeBase, map[string]string{
+				"100-300_SIDTHEIRS1_draft_theirs.md": "theirs\n",
+				"100-300_SIDTHEIRS1_notes.md":        "notes\n",
+			}),
+			want: withChanges(mergeBase, map[string]string{
+				"100-300_SIDOURSAA1_draft_ours.md":   "ours\n",
+				"100-400_SIDTHEIRS1_draft_theirs.md": "theirs\n",
+				"100-400_SIDTHEIRS1_notes.md":        "notes\n",
+			}),
+		},
+		{
+			name: "deleted on both sides",
+			ours: withChanges(mergeBase, map[string]string{
+				"100-200_SIDCHAPAA2_draft_two.md": "",
+			}),
+			theirs: withChanges(mergeBase, map[string]string{
+				"100-200_SIDCHAPAA2_draft_two.md": "",
+			}),
+			want: withChanges(mergeBase, map[string]string{
+				"100-200_SIDCHAPAA2_draft_two.md": "",
+			}),
+		},
+		{
+			name: "each side deletes a different document of a node",
+			base: withChanges(mergeBase, map[string]string{
+				"100-200_SIDCHAPAA2_notes.md": "notes\n",
+			}),
+			ours: mergeBase,
+			theirs: withChanges(mergeBase, map[string]string{
+				"100-200_SIDCHAPAA2_draft_two.md": "",
+				"100-200_SIDCHAPAA2_notes.md":     "notes\n",
+			}),
+			want: withChanges(mergeBase, map[string]string{
+				"100-200_SIDCHAPAA2_draft_two.md": "",
+			}),
+		},
+		{
+			name: "notes deleted on both sides",
+			base: withChanges(mergeBase, map[string]string{
+				"100-200_SIDCHAPAA2_notes.md": "notes\n",
+			}),
+			ours:   mergeBase,
+			theirs: mergeBase,
+			want:   mergeBase,
+		},
+		{
+			name: "three nodes added under one parent at the same number",
+			ours: withChanges(mergeBase, map[string]string{
+				"100-300_SIDOURSAA1_draft_ours.md": "ours\n",
+			}),
+			theirs: withChanges(mergeBase, map[string]string{
+				"100-300_SIDTHEIRS1_draft_one.md": "one\n",
+				"100-300_SIDTHEIRS2_draft_two.md": "two\n",
+			}),
+			want: withChanges(mergeBase, map[string]string{
+				"100-300_SIDOURSAA1_draft_ours.md": "ours\n",
+				"100-400_SIDTHEIRS1_draft_one.md":  "one\n",
+				"100-500_SIDTHEIRS2_draft_two.md":  "two\n",
+			}),
+		},
+		{
+			name: "notes deleted on ours, untouched on theirs",
+			ours: mergeBase,
+			theirs: withChanges(mergeBase, map[string]string{
+				"100-200_SIDCHAPAA2_notes.md": "added\n",
+			}),
+			want: withChanges(mergeBase, map[string]string{
+				"100-200_SIDCHAPAA2_notes.md": "added\n",
+			}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := tt.base
+			if base == nil {
+				base = mergeBase
+			}
+			got, result := runMerge(t, base, tt.ours, tt.theirs)
+
+			assertFiles(t, got, tt.want)
+			if len(result.Conflicts) != 0 {
+				t.Errorf("conflicts = %+v, want none", result.Conflicts)
+			}
+		})
+	}
+}
+
+func TestOutlineService_Merge_Conflicts(t *testing.T) {
+	tests := []struct {
+		name     string
+		ours     map[string]string
+		theirs   map[string]string
+		want     map[string]string
+		wantKind MergeConflictKind
+		wantPath string
+	}{
+		{
+			name: "moved to different parents",
+			ours: withChanges(mergeBase, map[string]string{
+				"100-100-100_SIDSCENEA1_draft_s.md": "",
+				"100-200-100_SIDSCENEA1_draft_s.md": "scene\n",
+			}),
+			theirs: withChanges(mergeBase, map[string]string{
+				"100-100-100_SIDSCENEA1_draft_s.md": "",
+				"200_SIDSCENEA1_draft_s.md":         "scene\n",
+			}),
+			want: withChanges(mergeBase, map[string]string{
+				"100-100-100_SIDSCENEA1_draft_s.md": "",
+				"100-200-100_SIDSCENEA1_draft_s.md": "scene\n",
+			}),
+			wantKind: MergeConflictMove,
+			wantPath: "100-200-100_SIDSCENEA1_draft_s.md",
+		},
+		{
+			name: "deleted on ours, edited on theirs",
+			ours: withChanges(mergeBase, map[string]string{
+				"100-200_SIDCHAPAA2_draft_two.md": "",
+			}),
+			theirs: withChanges(mergeBase, map[string]string{
+				"100-200_SIDCHAPAA2_draft_two.md": "two, revised\n",
+			}),
+			want: withChanges(mergeBase, map[string]string{
+				"100-200_SIDCHAPAA2_draft_two.md": "two, revised\n",
+			}),
+			wantKind: MergeConflictDelete,
+			wantPath: "100-200_SIDCHAPAA2_draft_two.md",
+		},
+		{
+			name: "edited on ours, deleted on theirs",
+			ours: withChanges(mergeBase, map[string]string{
+				"100-200_SIDCHAPAA2_draft_two.md": "two, revised\n",
+			}),
+			theirs: withChanges(mergeBase, map[string]string{
+				"100-200_SIDCHAPAA2_draft_two.md": "",
+			}),
+			want: withChanges(mergeBase, map[string]string{
+				"100-200_SIDCHAPAA2_draft_two.md": "two, revised\n",
+			}),
+			wantKind: MergeConflictDelete,
+			wantPath: "100-200_SIDCHAPAA2_draft_two.md",
+		},
+		{
+			name: "document deleted on theirs, edited on ours",
+			ours: withChanges(mergeBase, map[string]string{
+				"100-200_SIDCHAPAA2_draft_two.md": "two, revised\n",
+				"100-200_SIDCHAPAA2_notes.md":     "notes\n",
+			}),
+			theirs: withChanges(mergeBase, map[string]string{
+				"100-200_SIDCHAPAA2_draft_two.md": "",
+				"100-200_SIDCHAPAA2_notes.md":     "notes\n",
+			}),
+			want: withChanges(mergeBase, map[string]string{
+				"100-200_SIDCHAPAA2_draft_two.md": "two, revised\n",
+				"100-200_SIDCHAPAA2_notes.md":     "notes\n",
+			}),
+			wantKind: MergeConflictDelete,
+			wantPath: "100-200_SIDCHAPAA2_draft_two.md",
+		},
+		{
+			name: "edited differently on each side",
+			ours: withChanges(mergeBase, map[string]string{
+				"100-200_SIDCHAPAA2_draft_two.md": "ours",
+			}),
+			theirs: withChanges(mergeBase, map[string]string{
+				"100-200_SIDCHAPAA2_draft_two.md": "theirs\n",
+			}),
+			want: withChanges(mergeBase, map[string]string{
+				"100-200_SIDCHAPAA2_draft_two.md": "<<<<<<< ours\nours\n=======\ntheirs\n>>>>>>> theirs\n",
+			}),
+			wantKind: MergeConflictContent,
+			wantPath: "100-200_SIDCHAPAA2_draft_two.md",
+		},
+		{
+			name: "parent deleted on ours, child added on theirs",
+			ours: withChanges(mergeBase, map[string]string{
+				"100-200_SIDCHAPAA2_draft_two.md": "",
+			}),
+			theirs: withChanges(mergeBase, map[string]string{
+				"100-200-100_SIDSCENEA2_draft_t.md": "new scene\n",
+			}),
+			want: withChanges(mergeBase, map[string]string{
+				"100-200-100_SIDSCENEA2_draft_t.md": "new scene\n",
+			}),
+			wantKind: MergeConflictDelete,
+			wantPath: "100-200_SIDCHAPAA2_draft_two.md",
+		},
+		{
+			name: "moves that nest two chapters in each other",
+			ours: withChanges(mergeBase, map[string]string{
+				"100-100_SIDCHAPAA1_draft_one.md":       "",
+				"100-100-100_SIDSCENEA1_draft_s.md":     "",
+				"100-200-100_SIDCHAPAA1_draft_one.md":   "one\n",
+				"100-200-100-100_SIDSCENEA1_draft_s.md": "scene\n",
+			}),
+			theirs: withChanges(mergeBase, map[string]string{
+				"100-200_SIDCHAPAA2_draft_two.md":     "",
+				"100-100-200_SIDCHAPAA2_draft_two.md": "two\n",
+			}),
+			want: withChanges(mergeBase, map[string]string{
+				"100-100_SIDCHAPAA1_draft_one.md":       "",
+				"100-100-100_SIDSCENEA1_draft_s.md":     "",
+				"100-200-100_SIDCHAPAA1_draft_one.md":   "one\n",
+				"100-200-100-100_SIDSCENEA1_draft_s.md": "scene\n",
+			}),
+			wantKind: MergeConflictMove,
+			wantPath: "100-200-100_SIDCHAPAA1_draft_one.md",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, result := runMerge(t, mergeBase, tt.ours, tt.theirs)
+
+			assertFiles(t, got, tt.want)
+			if len(result.Conflicts) != 1 {
+				t.Fatalf("conflicts = %+v, want one", result.Conflicts)
+			}
+			if c := result.Conflicts[0]; c.Kind != tt.wantKind || c.Path != tt.wantPath || c.Message == "" {
+				t.Errorf("conflict = %+v, want %s at %s", c, tt.wantKind, tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestOutlineService_Merge_CycleThroughNodeAddedOnTheirs(t *testing.T) {
+	base := map[string]string{
+		"100_SIDAAAAAA1_draft_a.md": "a\n",
+		"200_SIDCCCCCC1_draft_c.md": "c\n",
+	}
+	// Ours moves A under C; theirs adds B under A and moves C under B.
+	ours := map[string]string{
+		"100_SIDCCCCCC1_draft_c.md":     "c\n",
+		"100-100_SIDAAAAAA1_draft_a.md": "a\n",
+	}
+	theirs := map[string]string{
+		"100_SIDAAAAAA1_draft_a.md":         "a\n",
+		"100-100_SIDBBBBBB1_draft_b.md":     "b\n",
+		"100-100-100_SIDCCCCCC1_draft_c.md": "c\n",
+	}
+
+	got, result := runMerge(t, base, ours, theirs)
+
+	want := map[string]string{
+		"100_SIDCCCCCC1_draft_c.md":     "c\n",
+		"100-100_SIDAAAAAA1_draft_a.md": "a\n",
+		"200_SIDBBBBBB1_draft_b.md":     "b\n",
+	}
+	assertFiles(t, got, want)
+	if len(result.Conflicts) != 1 || result.Conflicts[0].Kind != MergeConflictMove {
+		t.Errorf("conflicts = %+v, want one move conflict", result.Conflicts)
+	}
+}
+
+func TestOutlineService_Merge_DryRunAndUnchangedFiles(t *testing.T) {
+	theirs := withChanges(mergeBase, map[string]string{
+		"100-200_SIDCHAPAA2_draft_two.md": "",
+		"100-300_SIDCHAPAA3_draft_new.md": "new\n",
+	})
+	writer := &fakeFileWriter{}
+	deleter := &fakeFileDeleter{}
+	target := &fakeSnapshot{files: mergeBase}
+	svc := NewOutlineService(target, writer, &mockLocker{}, nil, WithDeleter(deleter), WithContentReader(target))
+
+	result, err := svc.Merge(context.Background(),
+		&fakeSnapshot{files: mergeBase}, &fakeSnapshot{files: mergeBase}, &fakeSnapshot{files: theirs}, false)
+	if err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+
+	if len(writer.written) != 0 || len(deleter.deleted) != 0 {
+		t.Errorf("dry run wrote %v and deleted %v", writer.written, deleter.deleted)
+	}
+	if strings.Join(result.Written, ",") != "100-300_SIDCHAPAA3_draft_new.md" ||
+		strings.Join(result.Deleted, ",") != "100-200_SIDCHAPAA2_draft_two.md" {
+		t.Errorf("result = %+v, want only the new file written and the deleted one removed", result)
+	}
+}
+
+func TestOutlineService_Merge_Errors(t *testing.T) {
+	snapErr := errors.New("unreadable snapshot")
+	lockErr := errors.New("locked")
+	writeErr := errors.New("disk full")
+	deleteErr := errors.New("permission denied")
+	theirs := withChanges(mergeBase, map[string]string{
+		"100-200_SIDCHAPAA2_draft_two.md": "",
+		"100-300_SIDCHAPAA3_draft_new.md": "new\n",
+	})
+	crowded := map[string]string{}
+	for n := 1; n <= 999; n++ {
+		crowded[domain.GenerateFilename(domain.DefaultNumbering.ChildMP("", n), "SIDBASE"+domain.DefaultNumbering.ChildMP("", n), "draft", "")] = "x"
+	}
+
+	base := &fakeSnapshot{files: mergeBase}
+	changed := &fakeSnapshot{files: theirs}
+
+	tests := []struct {
+		name               string
+		base, ours, theirs *fakeSnapshot
+		target             *fakeSnapshot
+		locker             *mockLocker
+		writer             *fakeFileWriter
+		deleter            *fakeFileDeleter
+		wantErr            error
+	}{
+		{name: "lock", locker: &mockLocker{tryLockErr: lockErr}, wantErr: lockErr},
+		{name: "snapshot listing", base: &fakeSnapshot{dirErr: snapErr}, wantErr: snapErr},
+		{name: "snapshot contents", base: &fakeSnapshot{files: mergeBase, readErr: snapErr}, wantErr: snapErr},
+		{name: "target listing", target: &fakeSnapshot{dirErr: snapErr}, wantErr: snapErr},
+		{name: "write", base: base, ours: base, theirs: changed, target: base, writer: &fakeFileWriter{writeErr: writeErr}, wantErr: writeErr},
+		{name: "delete", base: base, ours: base, theirs: changed, target: base, deleter: &fakeFileDeleter{err: deleteErr}, wantErr: deleteErr},
+		{
+			name:    "no free number",
+			ours:    &fakeSnapshot{files: crowded},
+			theirs:  &fakeSnapshot{files: map[string]string{"500_SIDTHEIRS1_draft.md": "t"}},
+			wantErr: domain.ErrMaxSiblingsReached,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orEmpty := func(f *fakeSnapshot) *fakeSnapshot {
+				if f == nil {
+					return &fakeSnapshot{}
+				}
+				return f
+			}
+			if tt.locker == nil {
+				tt.locker = &mockLocker{}
+			}
+			if tt.writer == nil {
+				tt.writer = &fakeFileWriter{}
+			}
+			if tt.deleter == nil {
+				tt.deleter = &fakeFileDeleter{}
+			}
+			target := orEmpty(tt.target)
+			svc := NewOutlineService(target, tt.writer, tt.locker, nil, WithDeleter(tt.deleter), WithContentReader(target))
+
+			_, err := svc.Merge(context.Background(), orEmpty(tt.base), orEmpty(tt.ours), orEmpty(tt.theirs), true)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOutlineService_Merge_RefusesDuplicateSIDs(t *testing.T) {
+	// A bad earlier merge left chapter two's SID on a second node in ours.
+	ours := withChanges(mergeBase, map[string]string{
+		"100-300_SIDCHAPAA2_draft_copy.md": "copy\n",
+		"100-300_SIDCHAPAA2_notes.md":      "copy notes\n",
+	})
+	writer, deleter := &fakeFileWriter{}, &fakeFileDeleter{}
+	target := &fakeSnapshot{files: ours}
+	svc := NewOutlineService(target, writer, &mockLocker{}, nil, WithDeleter(deleter), WithContentReader(target))
+
+	_, err := svc.Merge(context.Background(),
+		&fakeSnapshot{files: mergeBase}, &fakeSnapshot{files: ours}, &fakeSnapshot{files: mergeBase}, true)
+
+	if !errors.Is(err, ErrMergeDuplicateSID) {
+		t.Fatalf("error = %v, want %v", err, ErrMergeDuplicateSID)
+	}
+	if !strings.Contains(err.Error(), "ours") || !strings.Contains(err.Error(), "100-300") {
+		t.Errorf("error = %q, want it to name the side and both MPs", err)
+	}
+	if len(writer.written) != 0 || len(deleter.deleted) != 0 {
+		t.Errorf("written %v, deleted %v; want nothing touched", writer.written, deleter.deleted)
+	}
+}
+
+func TestMergeDocument(t *testing.T) {
+	tests := []struct {
+		name               string
+		base, ours, theirs string
+		want               string
+		wantConflict       bool
+	}{
+		{"changed on theirs", "a\n", "a\n", "b\n", "b\n", false},
+		{"changed on ours", "a\n", "b\n", "a\n", "b\n", false},
+		{"same change on both", "a\n", "b\n", "b\n", "b\n", false},
+		{"changed differently", "a\n", "b\n", "c", "<<<<<<< ours\nb\n=======\nc\n>>>>>>> theirs\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, conflict := MergeDocument(tt.base, tt.ours, tt.theirs)
+
+			if got != tt.want || conflict != tt.wantConflict {
+				t.Errorf("MergeDocument() = %q, %v; want %q, %v", got, conflict, tt.want, tt.wantConflict)
+			}
+		})
+	}
+}
+
+func TestOutlineService_ResolveMerge(t *testing.T) {
+	sourceErr := errors.New("not a git repository")
+	lockErr := errors.New("locked")
+	theirs := withChanges(mergeBase, map[string]string{"100-300_SIDCHAPAA3_draft_new.md": "new\n"})
+	source := &fakeMergeSource{
+		base: &fakeSnapshot{files: mergeBase}, ours: &fakeSnapshot{files: mergeBase}, theirs: &fakeSnapshot{files: theirs},
+	}
+
+	tests := []struct {
+		name        string
+		source      MergeSource
+		locker      *mockLocker
+		wantErr     error
+		wantWritten int
+	}{
+		{"merges the sides", source, &mockLocker{}, nil, 1},
+		{"no source", nil, &mockLocker{}, ErrNoMergeInProgress, 0},
+		{"source failure", &fakeMergeSource{err: sourceErr}, &mockLocker{}, sourceErr, 0},
+		{"lock failure", source, &mockLocker{tryLockErr: lockErr}, lockErr, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &fakeSnapshot{files: mergeBase}
+			opts := []Option{WithDeleter(&fakeFileDeleter{}), WithContentReader(target)}
+			if tt.source != nil {
+				opts = append(opts, WithMergeSource(tt.source))
+			}
+			svc := NewOutlineService(target, &fakeFileWriter{}, tt.locker, nil, opts...)
+
+			result, err := svc.ResolveMerge(context.Background(), true)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && len(result.Written) != tt.wantWritten {
+				t.Errorf("written = %v, want %d file(s)", result.Written, tt.wantWritten)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package outline
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+// orphan is a node whose parent MP has no files.
+type orphan struct {
+	mp       string
+	sid      string
+	missing  []string // absent ancestor MPs, nearest first
+	ancestor string   // nearest existing ancestor MP, or "" for the root
+}
+
+// parentMP returns the MP one level up, or "" for a root-level MP.
+func parentMP(mp string) string {
+	if i := strings.LastIndex(mp, "-"); i >= 0 {
+		return mp[:i]
+	}
+	return ""
+}
+
+// findOrphans returns the nodes whose parent has no files, in MP order.
+func findOrphans(parsed []domain.ParsedFile) []orphan {
+	sidAt := map[string]string{}
+	for _, pf := range parsed {
+		if _, ok := sidAt[pf.MP]; !ok {
+			sidAt[pf.MP] = pf.SID
+		}
+	}
+
+	var orphans []orphan
+	for _, mp := range slices.Sorted(maps.Keys(sidAt)) {
+		parent := parentMP(mp)
+		if _, ok := sidAt[parent]; ok || parent == "" {
+			continue
+		}
+		o := orphan{mp: mp, sid: sidAt[mp]}
+		for ; parent != ""; parent = parentMP(parent) {
+			if _, ok := sidAt[parent]; ok {
+				break
+			}
+			o.missing = append(o.missing, parent)
+		}
+		o.ancestor = parent
+		orphans = append(orphans, o)
+	}
+	return orphans
+}
+
+// findOrphanedNodeFindings reports each node whose parent has no files.
+func findOrphanedNodeFindings(parsed []domain.ParsedFile) []domain.Finding {
+	var findings []domain.Finding
+	for _, o := range findOrphans(parsed) {
+		findings = append(findings, domain.Finding{
+			Type:     domain.FindingOrphanedNode,
+			Severity: domain.SeverityError,
+			Message:  fmt.Sprintf("node %s at %s has no parent %s", o.sid, o.mp, o.missing[0]),
+			SID:      o.sid,
+		})
+	}
+	return findings
+}
+
+// repairOrphansImpl gives every orphaned node a parent: by default it creates
+// a placeholder node, with a reserved SID and a draft titled from the MP, at
+// each missing ancestor MP; with reparent it moves the orphan under its
+// nearest existing ancestor instead. It returns the updated parsed files.
+func (s *OutlineService) repairOrphansImpl(ctx context.Context, parsed []domain.ParsedFile, reparent bool) ([]domain.ParsedFile, []RepairAction, error) {
+	if reparent {
+		return s.reparentOrphansImpl(ctx, parsed)
+	}
+
+	var missing []string
+	for _, o := range findOrphans(parsed) {
+		missing = append(missing, o.missing...)
+	}
+	slices.Sort(missing)
+
+	var repairs []RepairAction
+	for _, mp := range slices.Compact(missing) {
+		sid, err := s.reserver.Reserve(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		pf := domain.ParsedFile{MP: mp, SID: sid, DocType: domain.DocTypeDraft, Slug: s.slugifier.Slug(mp)}
+		pf.PathParts = strings.Split(mp, "-")
+		pf.Depth = len(pf.PathParts)
+		filename := reconstructFilename(pf)
+		if err := s.writer.WriteFile(ctx, filename, formatFrontmatter(s.fmHandler, mp)); err != nil {
+			return nil, nil, err
+		}
+		parsed = append(parsed, pf)
+		repairs = append(repairs, RepairAction{Type: domain.FindingOrphanedNode, New: filename})
+	}
+	return parsed, repairs, nil
+}
+
+// reparentOrphansImpl moves each orphan, with its subtree, to the next free
+// child slot of its nearest existing ancestor. Orphans nested inside a moved
+// subtree are handled on a later pass.
+func (s *OutlineService) reparentOrphansImpl(ctx context.Context, parsed []domain.ParsedFile) ([]domain.ParsedFile, []RepairAction, error) {
+	var repairs []RepairAction
+	for orphans := findOrphans(parsed); len(orphans) > 0; orphans = findOrphans(parsed) {
+		o := orphans[0]
+		num, err := s.config.Numbering.NextSiblingNumber(collectOccupiedChildNums(parsed, o.ancestor, ""))
+		if err != nil {
+			return nil, nil, err
+		}
+		newMP := s.config.Numbering.ChildMP(o.ancestor, num)
+		for i, pf := range parsed {
+			if pf.MP != o.mp && !isDescendantMP(pf.MP, o.mp) {
+				continue
+			}
+			oldName := reconstructFilename(pf)
+			pf.MP = newMP + pf.MP[len(o.mp):]
+			pf.PathParts = strings.Split(pf.MP, "-")
+			pf.Depth = len(pf.PathParts)
+			newName := reconstructFilename(pf)
+			if err := s.renamer.RenameFile(ctx, oldName, newName); err != nil {
+				return nil, nil, err
+			}
+			parsed[i] = pf
+			repairs = append(repairs, RepairAction{Type: domain.FindingOrphanedNode, Old: oldName, New: newName})
+		}
+	}
+	return parsed, repairs, nil
+}
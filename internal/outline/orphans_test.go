@@ -0,0 +1,151 @@
+package outline
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+// orphanTestFiles has 100-200-010 without its parent 100-200, and
+// 300-400-500-600 whose parent and grandparent are both missing.
+var orphanTestFiles = []string{
+	"100_SID001AABB_draft_root.md",
+	"100-200-010_SID002AABB_draft_orphan.md",
+	"100-200-010-100_SID003AABB_draft_child.md",
+	"300_SID004AABB_draft_other.md",
+	"300-400-500-600_SID005AABB_draft_deep.md",
+}
+
+func orphanTestService(writer *fakeFileWriter, renamer FileRenamer, reserver SIDReserver) *OutlineService {
+	reader := &fakeDirectoryReader{files: orphanTestFiles}
+	return NewOutlineService(reader, writer, &mockLocker{}, reserver, WithRenamer(renamer))
+}
+
+func TestOutlineService_Check_OrphanedNodes(t *testing.T) {
+	svc := orphanTestService(&fakeFileWriter{}, &fakeFileRenamer{}, nil)
+
+	result, err := svc.Check(context.Background(), CheckRules(string(domain.FindingOrphanedNode)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{
+		"node SID002AABB at 100-200-010 has no parent 100-200",
+		"node SID005AABB at 300-400-500-600 has no parent 300-400-500",
+	}
+	if len(result.Findings) != len(want) {
+		t.Fatalf("findings = %+v, want %d orphans", result.Findings, len(want))
+	}
+	for i, f := range result.Findings {
+		if f.Message != want[i] || f.Severity != domain.SeverityError {
+			t.Errorf("finding[%d] = %+v, want error %q", i, f, want[i])
+		}
+	}
+}
+
+func TestOutlineService_Repair_CreatesPlaceholderParents(t *testing.T) {
+	writer := &fakeFileWriter{}
+	svc := orphanTestService(writer, &fakeFileRenamer{}, &fakeSIDReserver{sid: "SIDNEWAABB"})
+
+	result, err := svc.Repair(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var created []string
+	for _, r := range result.Repairs {
+		if r.Type == domain.FindingOrphanedNode {
+			created = append(created, r.New)
+		}
+	}
+	want := []string{
+		"100-200_SIDNEWAABB_draft_100-200.md",
+		"300-400_SIDNEWAABB_draft_300-400.md",
+		"300-400-500_SIDNEWAABB_draft_300-400-500.md",
+	}
+	if len(created) != len(want) {
+		t.Fatalf("created = %v, want %v", created, want)
+	}
+	for i, name := range want {
+		if created[i] != name {
+			t.Errorf("created[%d] = %q, want %q", i, created[i], name)
+		}
+	}
+	if got := writer.written["100-200_SIDNEWAABB_draft_100-200.md"]; got != "---\ntitle: 100-200\n---\n" {
+		t.Errorf("placeholder draft = %q", got)
+	}
+}
+
+func TestOutlineService_Repair_ReparentsOrphans(t *testing.T) {
+	renamer := &fakeFileRenamer{}
+	svc := orphanTestService(&fakeFileWriter{}, renamer, nil)
+
+	_, err := svc.Repair(context.Background(), RepairReparentOrphans())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := [][2]string{
+		{"100-200-010_SID002AABB_draft_orphan.md", "100-100_SID002AABB_draft_orphan.md"},
+		{"100-200-010-100_SID003AABB_draft_child.md", "100-100-100_SID003AABB_draft_child.md"},
+		{"300-400-500-600_SID005AABB_draft_deep.md", "300-100_SID005AABB_draft_deep.md"},
+	}
+	if len(renamer.renames) != len(want) {
+		t.Fatalf("renames = %v, want %v", renamer.renames, want)
+	}
+	for i, w := range want {
+		if renamer.renames[i] != w {
+			t.Errorf("rename[%d] = %v, want %v", i, renamer.renames[i], w)
+		}
+	}
+}
+
+func TestOutlineService_Repair_OrphanErrors(t *testing.T) {
+	reserveErr := errors.New("no entropy")
+	writeErr := errors.New("disk full")
+	renameErr := errors.New("rename failed")
+
+	tests := []struct {
+		name     string
+		writer   *fakeFileWriter
+		renamer  FileRenamer
+		reserver SIDReserver
+		opts     []RepairOption
+		wantErr  error
+	}{
+		{"reserve failure", &fakeFileWriter{}, &fakeFileRenamer{}, &fakeSIDReserver{err: reserveErr}, nil, reserveErr},
+		{"write failure", &fakeFileWriter{writeErr: writeErr}, &fakeFileRenamer{}, &fakeSIDReserver{sid: "SIDNEWAABB"}, nil, writeErr},
+		{"rename failure", &fakeFileWriter{}, &fakeFileRenamer{err: renameErr}, nil, []RepairOption{RepairReparentOrphans()}, renameErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := orphanTestService(tt.writer, tt.renamer, tt.reserver)
+
+			_, err := svc.Repair(context.Background(), tt.opts...)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOutlineService_Repair_ReparentWithoutFreeSlot(t *testing.T) {
+	files := []string{"100_SID001AABB_draft_root.md", "100-200-010_SID002AABB_draft_orphan.md"}
+	for n := 1; n <= 999; n++ {
+		if n != 200 {
+			files = append(files, domain.GenerateFilename(domain.DefaultNumbering.ChildMP("100", n), "SID999AABB", "notes", ""))
+		}
+	}
+	svc := NewOutlineService(&fakeDirectoryReader{files: files}, &fakeFileWriter{}, &mockLocker{}, nil,
+		WithRenamer(&fakeFileRenamer{}))
+
+	_, err := svc.Repair(context.Background(), RepairReparentOrphans())
+
+	if err == nil {
+		t.Error("expected an error when the ancestor has no free child slot")
+	}
+}
@@ -0,0 +1,134 @@
+package outline
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+// newSIDPlaceholder stands in for a SID that repair has yet to reserve.
+const newSIDPlaceholder = "<new-sid>"
+
+// filenameRecovery is a planned rename of an invalid filename to its
+// canonical form. The parsed SID is empty when a new one must be reserved.
+type filenameRecovery struct {
+	old      string
+	parsed   domain.ParsedFile
+	conflict bool
+}
+
+// target returns the canonical filename, using the placeholder for a SID
+// that is still to be reserved.
+func (r filenameRecovery) target() string {
+	sid := r.parsed.SID
+	if sid == "" {
+		sid = newSIDPlaceholder
+	}
+	return domain.GenerateFilename(r.parsed.MP, sid, r.parsed.DocType, r.parsed.Slug)
+}
+
+// planFilenameRecoveries recovers canonical names for the invalid filenames
+// it recognises. A file that lost its SID adopts the SID of the node already
+// at its MP; otherwise all such files at one MP share a new SID. A recovery
+// whose name is taken by another file is marked as a conflict. The doc types
+// named by the config or used by valid files help tell a SID from a word.
+func (s *OutlineService) planFilenameRecoveries(invalid []string, parsed []domain.ParsedFile) []filenameRecovery {
+	taken := map[string]bool{}
+	sidAtMP := map[string]string{}
+	for _, pf := range parsed {
+		taken[reconstructFilename(pf)] = true
+		if _, ok := sidAtMP[pf.MP]; !ok {
+			sidAtMP[pf.MP] = pf.SID
+		}
+	}
+
+	known := s.config.ConfiguredDocTypes()
+	for _, pf := range parsed {
+		if !slices.Contains(known, pf.DocType) {
+			known = append(known, pf.DocType)
+		}
+	}
+
+	var plan []filenameRecovery
+	for _, name := range invalid {
+		pf, ok := domain.RecoverFilename(name, known)
+		if !ok {
+			continue
+		}
+		if pf.SID == "" {
+			pf.SID = sidAtMP[pf.MP]
+		}
+		if pf.Slug != "" && s.slugifier != nil {
+			pf.Slug = s.slugifier.Slug(pf.Slug)
+		}
+		r := filenameRecovery{old: name, parsed: pf}
+		r.conflict = taken[r.target()]
+		taken[r.target()] = true
+		plan = append(plan, r)
+	}
+	return plan
+}
+
+// invalidFilenames returns the paths of invalid_filename findings.
+func invalidFilenames(findings []domain.Finding) []string {
+	var names []string
+	for _, f := range findings {
+		if f.Type == domain.FindingInvalidFilename {
+			names = append(names, f.Path)
+		}
+	}
+	return names
+}
+
+// proposeFilenameRecoveries adds the planned canonical name to each
+// recoverable invalid_filename finding.
+func (s *OutlineService) proposeFilenameRecoveries(findings []domain.Finding, parsed []domain.ParsedFile) {
+	proposals := map[string]filenameRecovery{}
+	for _, r := range s.planFilenameRecoveries(invalidFilenames(findings), parsed) {
+		proposals[r.old] = r
+	}
+	for i, f := range findings {
+		r, ok := proposals[f.Path]
+		if f.Type != domain.FindingInvalidFilename || !ok {
+			continue
+		}
+		if r.conflict {
+			findings[i].Message += fmt.Sprintf(" (looks like %s, which already exists)", r.target())
+		} else {
+			findings[i].Message += fmt.Sprintf(" (doctor --apply renames it to %s)", r.target())
+		}
+	}
+}
+
+// recoverFilenamesImpl renames every recoverable, non-conflicting invalid
+// filename to its canonical form, reserving new SIDs where they were lost,
+// and returns the recovered files alongside the repairs.
+func (s *OutlineService) recoverFilenamesImpl(ctx context.Context, invalid []string, parsed []domain.ParsedFile) ([]domain.ParsedFile, []RepairAction, error) {
+	var recovered []domain.ParsedFile
+	var repairs []RepairAction
+	newSIDs := map[string]string{}
+	for _, r := range s.planFilenameRecoveries(invalid, parsed) {
+		if r.conflict {
+			continue
+		}
+		pf := r.parsed
+		if pf.SID == "" {
+			if pf.SID = newSIDs[pf.MP]; pf.SID == "" {
+				sid, err := s.reserver.Reserve(ctx)
+				if err != nil {
+					return nil, nil, err
+				}
+				newSIDs[pf.MP], pf.SID = sid, sid
+			}
+		}
+		newName := reconstructFilename(pf)
+		if err := s.renamer.RenameFile(ctx, r.old, newName); err != nil {
+			return nil, nil, err
+		}
+		recovered = append(recovered, pf)
+		repairs = append(repairs, RepairAction{Type: domain.FindingInvalidFilename, Old: r.old, New: newName})
+	}
+	return recovered, repairs, nil
+}
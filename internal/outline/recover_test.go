@@ -0,0 +1,186 @@
+package outline
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+// recoverTestFiles holds a valid node at 100 and near-miss filenames:
+// a notes conflict copy of 100 that collides with its notes, a notes file at
+// 100 that lost its SID, two files at 300 that lost theirs, and a stray README.
+var recoverTestFiles = []string{
+	"100_SID001AABB_draft_hello.md",
+	"100_SID001AABB_notes.md",
+	"100_SID001AABB_notes (1).md",
+	"200_SID002AABB_DRAFT_Second Part.md",
+	"300_draft_third.md",
+	"300 _ notes.md",
+	"README.md",
+}
+
+func recoverTestService(renamer FileRenamer, reserver SIDReserver) *OutlineService {
+	reader := &fakeDirectoryReader{files: recoverTestFiles}
+	return NewOutlineService(reader, &fakeFileWriter{}, &mockLocker{}, reserver,
+		WithRenamer(renamer), WithContentReader(&fakeContentReader{}), WithSlugifier(&stubSlugifier{slug: "slugged"}))
+}
+
+func TestOutlineService_Check_ProposesFilenameRecoveries(t *testing.T) {
+	svc := recoverTestService(&fakeFileRenamer{}, nil)
+
+	result, err := svc.Check(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]string{
+		"100_SID001AABB_notes (1).md":         "(looks like 100_SID001AABB_notes.md, which already exists)",
+		"200_SID002AABB_DRAFT_Second Part.md": "(doctor --apply renames it to 200_SID002AABB_draft_slugged.md)",
+		"300_draft_third.md":                  "(doctor --apply renames it to 300_<new-sid>_draft_slugged.md)",
+		"300 _ notes.md":                      "(doctor --apply renames it to 300_<new-sid>_notes.md)",
+		"README.md":                           "invalid filename: README.md",
+	}
+	for _, f := range result.Findings {
+		if f.Type != domain.FindingInvalidFilename {
+			continue
+		}
+		if !strings.HasSuffix(f.Message, want[f.Path]) {
+			t.Errorf("message for %s = %q, want suffix %q", f.Path, f.Message, want[f.Path])
+		}
+		delete(want, f.Path)
+	}
+	if len(want) != 0 {
+		t.Errorf("missing invalid_filename findings for %v", want)
+	}
+}
+
+func TestOutlineService_Repair_RecoversFilenames(t *testing.T) {
+	renamer := &fakeFileRenamer{}
+	reserver := &fakeSIDReserver{sid: "SIDNEWAABB"}
+	svc := recoverTestService(renamer, reserver)
+
+	result, err := svc.Repair(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := [][2]string{
+		{"200_SID002AABB_DRAFT_Second Part.md", "200_SID002AABB_draft_slugged.md"},
+		{"300_draft_third.md", "300_SIDNEWAABB_draft_slugged.md"},
+		{"300 _ notes.md", "300_SIDNEWAABB_notes.md"},
+	}
+	if len(renamer.renames) != len(want) {
+		t.Fatalf("renames = %v, want %v", renamer.renames, want)
+	}
+	for i, w := range want {
+		if renamer.renames[i] != w {
+			t.Errorf("rename[%d] = %v, want %v", i, renamer.renames[i], w)
+		}
+		if r := result.Repairs[i]; r.Type != domain.FindingInvalidFilename || r.Old != w[0] || r.New != w[1] {
+			t.Errorf("repair[%d] = %+v, want invalid_filename %v", i, r, w)
+		}
+	}
+}
+
+func TestOutlineService_Repair_RecoveryAdoptsSIDAtMP(t *testing.T) {
+	renamer := &fakeFileRenamer{}
+	reader := &fakeDirectoryReader{files: []string{"100_SID001AABB_draft_hello.md", "100_Notes.md"}}
+	svc := NewOutlineService(reader, &fakeFileWriter{}, &mockLocker{}, &fakeSIDReserver{err: errors.New("unused")},
+		WithRenamer(renamer))
+
+	if _, err := svc.Repair(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(renamer.renames) != 1 || renamer.renames[0][1] != "100_SID001AABB_notes.md" {
+		t.Errorf("renames = %v, want notes to adopt SID001AABB", renamer.renames)
+	}
+}
+
+func TestOutlineService_Repair_RecoveryKeepsWordsOutOfSID(t *testing.T) {
+	renamer := &fakeFileRenamer{}
+	reader := &fakeDirectoryReader{files: []string{
+		"100_SID001AABB_draft_hello.md",
+		"100_Research_notes (1).md",
+		"200_AbCdEfGhIjKl_Beats_x.md",
+		"200_AbCdEfGhIjKl_draft_two.md",
+		"300_SID003AABB_beats.md",
+	}}
+	svc := NewOutlineService(reader, &fakeFileWriter{}, &mockLocker{}, &fakeSIDReserver{err: errors.New("unused")},
+		WithRenamer(renamer))
+
+	if _, err := svc.Repair(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := [][2]string{
+		{"100_Research_notes (1).md", "100_SID001AABB_research_notes.md"},
+		{"200_AbCdEfGhIjKl_Beats_x.md", "200_AbCdEfGhIjKl_beats_x.md"},
+	}
+	if len(renamer.renames) != len(want) {
+		t.Fatalf("renames = %v, want %v", renamer.renames, want)
+	}
+	for i, w := range want {
+		if renamer.renames[i] != w {
+			t.Errorf("rename[%d] = %v, want %v", i, renamer.renames[i], w)
+		}
+	}
+}
+
+func TestOutlineService_Repair_RecoveryErrors(t *testing.T) {
+	reserveErr := errors.New("no entropy")
+	renameErr := errors.New("rename failed")
+
+	tests := []struct {
+		name     string
+		renamer  FileRenamer
+		reserver SIDReserver
+		wantErr  error
+	}{
+		{"reserve failure", &fakeFileRenamer{}, &fakeSIDReserver{err: reserveErr}, reserveErr},
+		{"rename failure", &fakeFileRenamer{err: renameErr}, &fakeSIDReserver{sid: "SIDNEWAABB"}, renameErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := recoverTestService(tt.renamer, tt.reserver)
+
+			_, err := svc.Repair(context.Background())
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOutlineService_Repair_SlugDriftDoesNotOverwrite(t *testing.T) {
+	// A conflict copy of a draft parses as valid but drifts onto the original.
+	files := []string{
+		"100_SID001AABB_draft_hello.md",
+		"100_SID001AABB_draft_hello (conflicted copy).md",
+		"100_SID001AABB_notes.md",
+	}
+	content := &fakeContentReader{contents: map[string]string{
+		"100_SID001AABB_draft_hello.md":                   "---\ntitle: hello\n---\n",
+		"100_SID001AABB_draft_hello (conflicted copy).md": "---\ntitle: hello\n---\n",
+	}}
+	renamer := &fakeFileRenamer{}
+	svc := NewOutlineService(&fakeDirectoryReader{files: files}, &fakeFileWriter{}, &mockLocker{}, nil,
+		WithRenamer(renamer), WithContentReader(content), WithSlugifier(&stubSlugifier{slug: "hello"}))
+
+	result, err := svc.Repair(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(renamer.renames) != 0 {
+		t.Errorf("renames = %v, want none", renamer.renames)
+	}
+	if len(result.Unrepaired) != 1 || !strings.Contains(result.Unrepaired[0].Message, "already exists") {
+		t.Errorf("unrepaired = %+v, want the blocked slug drift", result.Unrepaired)
+	}
+}
@@ -0,0 +1,116 @@
+package outline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+// ErrReorderMismatch is returned when a reorder does not list every child of
+// the parent exactly once.
+var ErrReorderMismatch = errors.New("reorder must list every child exactly once")
+
+// ReorderResult holds the renames that put a parent's children in a new
+// order.
+type ReorderResult struct {
+	Renames map[string]string
+}
+
+// Reorder renumbers the children of parentMP, or the root-level nodes when
+// parentMP is empty, so they follow the order of sids, acquiring an
+// advisory lock first. Every child must be listed exactly once. Children
+// take fresh numbers spaced as compact spaces them, and their subtrees are
+// renamed with them. When apply is false, the renames are planned but not
+// made.
+func (s *OutlineService) Reorder(ctx context.Context, parentMP string, sids []string, apply bool) (*ReorderResult, error) {
+	if err := s.lockForWrite(ctx); err != nil {
+		return nil, err
+	}
+	defer s.locker.Unlock()
+
+	parsed, err := s.readAndParse(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	children := map[string]string{}
+	mpSIDs := map[string]string{}
+	for _, pf := range parsed {
+		if !isDirectChild(pf, parentMP) {
+			continue
+		}
+		if other, ok := mpSIDs[pf.MP]; ok && other != pf.SID {
+			return nil, fmt.Errorf("children %s and %s share %s; run doctor to renumber them", other, pf.SID, pf.MP)
+		}
+		mpSIDs[pf.MP] = pf.SID
+		children[pf.SID] = pf.MP
+	}
+	if parentMP != "" && !slices.ContainsFunc(parsed, func(pf domain.ParsedFile) bool { return pf.MP == parentMP }) {
+		return nil, fmt.Errorf("%w: %s", ErrNodeNotFound, parentMP)
+	}
+	if err := checkReorderSIDs(children, sids); err != nil {
+		return nil, err
+	}
+
+	// The children already hold distinct numbers in one segment, so they
+	// always fit at some spacing.
+	nums, _ := s.config.Numbering.CompactNumbers(len(sids))
+	newMPs := map[string]string{}
+	for i, sid := range sids {
+		newMPs[children[sid]] = s.config.Numbering.ChildMP(parentMP, nums[i])
+	}
+
+	depth := strings.Count(parentMP, "-") + 1
+	if parentMP == "" {
+		depth = 0
+	}
+	renames := map[string]string{}
+	for _, pf := range parsed {
+		if pf.Depth <= depth {
+			continue
+		}
+		oldMP := strings.Join(pf.PathParts[:depth+1], "-")
+		newMP, ok := newMPs[oldMP]
+		if !ok || newMP == oldMP {
+			continue
+		}
+		renames[reconstructFilename(pf)] = domain.GenerateFilename(newMP+pf.MP[len(oldMP):], pf.SID, pf.DocType, pf.Slug)
+	}
+
+	if apply {
+		if err := applyRenames(ctx, s.renamer, renames); err != nil {
+			return nil, err
+		}
+	}
+	return &ReorderResult{Renames: renames}, nil
+}
+
+// checkReorderSIDs reports the first SID that is missing from, repeated in
+// or foreign to a reorder of children, which maps child SIDs to MPs.
+func checkReorderSIDs(children map[string]string, sids []string) error {
+	seen := map[string]bool{}
+	for _, sid := range sids {
+		switch {
+		case children[sid] == "":
+			return fmt.Errorf("%w: %s is not a child", ErrReorderMismatch, sid)
+		case seen[sid]:
+			return fmt.Errorf("%w: %s is listed twice", ErrReorderMismatch, sid)
+		}
+		seen[sid] = true
+	}
+	var missing []string
+	for sid := range children {
+		if !seen[sid] {
+			missing = append(missing, sid)
+		}
+	}
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		return fmt.Errorf("%w: missing %s", ErrReorderMismatch, strings.Join(missing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package outline
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"strings"
+	"testing"
+)
+
+// reorderTestFiles has three chapters under 100, the second with a scene.
+var reorderTestFiles = []string{
+	"100_SID000AABB_draft_book.md",
+	"100-100_SID001AABB_draft_one.md",
+	"100-100_SID001AABB_notes.md",
+	"100-200_SID002AABB_draft_two.md",
+	"100-200-100_SID004AABB_draft_scene.md",
+	"100-300_SID003AABB_draft_three.md",
+	"200_SID005AABB_draft_appendix.md",
+}
+
+func newReorderTestService(files []string, renamer FileRenamer) *OutlineService {
+	return NewOutlineService(&fakeDirectoryReader{files: files}, nil, &mockLocker{}, nil, WithRenamer(renamer))
+}
+
+func TestOutlineService_Reorder(t *testing.T) {
+	renamer := &fakeFileRenamer{}
+	svc := newReorderTestService(reorderTestFiles, renamer)
+
+	result, err := svc.Reorder(context.Background(), "100", []string{"SID003AABB", "SID001AABB", "SID002AABB"}, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]string{
+		"100-300_SID003AABB_draft_three.md":     "100-100_SID003AABB_draft_three.md",
+		"100-100_SID001AABB_draft_one.md":       "100-200_SID001AABB_draft_one.md",
+		"100-100_SID001AABB_notes.md":           "100-200_SID001AABB_notes.md",
+		"100-200_SID002AABB_draft_two.md":       "100-300_SID002AABB_draft_two.md",
+		"100-200-100_SID004AABB_draft_scene.md": "100-300-100_SID004AABB_draft_scene.md",
+	}
+	if !maps.Equal(result.Renames, want) {
+		t.Errorf("Renames = %v, want %v", result.Renames, want)
+	}
+	if len(renamer.renames) != len(want) {
+		t.Errorf("renames applied = %d, want %d", len(renamer.renames), len(want))
+	}
+}
+
+func TestOutlineService_Reorder_RootAndDryRun(t *testing.T) {
+	renamer := &fakeFileRenamer{}
+	svc := newReorderTestService(reorderTestFiles, renamer)
+
+	result, err := svc.Reorder(context.Background(), "", []string{"SID005AABB", "SID000AABB"}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := result.Renames["100-200-100_SID004AABB_draft_scene.md"]; got != "200-200-100_SID004AABB_draft_scene.md" {
+		t.Errorf("scene renamed to %q, want it to follow its chapter", got)
+	}
+	if got := result.Renames["200_SID005AABB_draft_appendix.md"]; got != "100_SID005AABB_draft_appendix.md" {
+		t.Errorf("appendix renamed to %q, want 100", got)
+	}
+	if len(renamer.renames) != 0 {
+		t.Errorf("dry run applied renames: %v", renamer.renames)
+	}
+}
+
+func TestOutlineService_Reorder_UnchangedOrder(t *testing.T) {
+	svc := newReorderTestService(reorderTestFiles, &fakeFileRenamer{})
+
+	result, err := svc.Reorder(context.Background(), "100", []string{"SID001AABB", "SID002AABB", "SID003AABB"}, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Renames) != 0 {
+		t.Errorf("Renames = %v, want none", result.Renames)
+	}
+}
+
+func TestOutlineService_Reorder_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   []string
+		parent  string
+		sids    []string
+		renamer FileRenamer
+		wantErr error
+		wantMsg string
+	}{
+		{"unknown parent", reorderTestFiles, "900", nil, &fakeFileRenamer{}, ErrNodeNotFound, ""},
+		{"foreign SID", reorderTestFiles, "100", []string{"SID001AABB", "SID002AABB", "SID005AABB"}, &fakeFileRenamer{}, ErrReorderMismatch, "SID005AABB is not a child"},
+		{"repeated SID", reorderTestFiles, "100", []string{"SID001AABB", "SID001AABB", "SID002AABB"}, &fakeFileRenamer{}, ErrReorderMismatch, "SID001AABB is listed twice"},
+		{"missing SIDs", reorderTestFiles, "100", []string{"SID002AABB"}, &fakeFileRenamer{}, ErrReorderMismatch, "missing SID001AABB, SID003AABB"},
+		{"rename failure", reorderTestFiles, "100", []string{"SID003AABB", "SID001AABB", "SID002AABB"}, &fakeFileRenamer{err: errors.New("disk full")}, nil, "disk full"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newReorderTestService(tt.files, tt.renamer)
+
+			_, err := svc.Reorder(context.Background(), tt.parent, tt.sids, true)
+
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantMsg != "" && !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("error = %q, want it to mention %q", err, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestOutlineService_Reorder_SharedMP(t *testing.T) {
+	files := []string{
+		"100_SID001AABB_draft_a.md",
+		"100_SID002AABB_draft_b.md",
+	}
+	svc := newReorderTestService(files, &fakeFileRenamer{})
+
+	_, err := svc.Reorder(context.Background(), "", []string{"SID002AABB", "SID001AABB"}, false)
+
+	if err == nil {
+		t.Fatal("expected error for children sharing an MP")
+	}
+}
+
+func TestOutlineService_Reorder_LockAndReadErrors(t *testing.T) {
+	locked := NewOutlineService(&fakeDirectoryReader{}, nil, &mockLocker{tryLockErr: errors.New("locked")}, nil)
+	if _, err := locked.Reorder(context.Background(), "", nil, false); err == nil {
+		t.Error("expected lock error")
+	}
+
+	unreadable := NewOutlineService(&fakeDirectoryReader{err: errors.New("read failed")}, nil, &mockLocker{}, nil)
+	if _, err := unreadable.Reorder(context.Background(), "", nil, false); err == nil {
+		t.Error("expected read error")
+	}
+}
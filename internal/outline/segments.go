@@ -0,0 +1,30 @@
+package outline
+
+import (
+	"fmt"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+// findSegmentWidthFindings reports every node whose own MP segment is not
+// width digits wide. MPs only sort into outline order when every segment
+// has the same width, so such nodes need renumbering by compact.
+func findSegmentWidthFindings(parsed []domain.ParsedFile, width int) []domain.Finding {
+	var findings []domain.Finding
+	seen := map[string]bool{}
+	for _, pf := range parsed {
+		segment := pf.PathParts[len(pf.PathParts)-1]
+		if len(segment) == width || seen[pf.MP+pf.SID] {
+			continue
+		}
+		seen[pf.MP+pf.SID] = true
+		findings = append(findings, domain.Finding{
+			Type:     domain.FindingSegmentWidth,
+			Severity: domain.SeverityError,
+			Message:  fmt.Sprintf("node %s at %s has a %d-digit segment; numbering.width is %d, run compact to renumber", pf.SID, pf.MP, len(segment), width),
+			Path:     reconstructFilename(pf),
+			SID:      pf.SID,
+		})
+	}
+	return findings
+}
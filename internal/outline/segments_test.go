@@ -0,0 +1,55 @@
+package outline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+func TestFindSegmentWidthFindings(t *testing.T) {
+	var parsed []domain.ParsedFile
+	for _, name := range []string{
+		"100_SID001AABB_draft_root.md",
+		"100_SID001AABB_notes.md",
+		"100-0100_SID002AABB_draft_wide.md",
+		"100-0100_SID002AABB_notes.md",
+		"100-0100-100_SID003AABB_draft_child.md",
+	} {
+		pf, err := domain.ParseFilename(name)
+		if err != nil {
+			t.Fatalf("ParseFilename(%q): %v", name, err)
+		}
+		parsed = append(parsed, pf)
+	}
+
+	findings := findSegmentWidthFindings(parsed, 3)
+
+	if len(findings) != 1 {
+		t.Fatalf("findings = %+v, want one per misfit node", findings)
+	}
+	f := findings[0]
+	want := "node SID002AABB at 100-0100 has a 4-digit segment; numbering.width is 3, run compact to renumber"
+	if f.Type != domain.FindingSegmentWidth || f.Severity != domain.SeverityError || f.Message != want || f.SID != "SID002AABB" {
+		t.Errorf("finding = %+v, want error %q", f, want)
+	}
+}
+
+func TestOutlineService_Check_SegmentWidth(t *testing.T) {
+	cfg := domain.DefaultConfig()
+	cfg.Numbering.Width = 4
+	reader := &fakeDirectoryReader{files: []string{
+		"0100_SID001AABB_draft_wide.md",
+		"200_SID002AABB_draft_narrow.md",
+	}}
+	svc := NewOutlineService(reader, nil, &mockLocker{}, nil, WithConfig(cfg))
+
+	result, err := svc.Check(context.Background(), CheckRules(string(domain.FindingSegmentWidth)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Findings) != 1 || result.Findings[0].SID != "SID002AABB" {
+		t.Errorf("findings = %+v, want only SID002AABB", result.Findings)
+	}
+}
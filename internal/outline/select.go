@@ -0,0 +1,161 @@
+package outline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+// ErrPromoteMany is returned when promote mode is asked to delete more than
+// one subtree at once.
+var ErrPromoteMany = errors.New("promote deletes one node at a time")
+
+// SelectNodes returns the nodes a selector expression selects, in outline
+// order, without acquiring an advisory lock.
+func (s *OutlineService) SelectNodes(ctx context.Context, expr domain.SelectorExpr) ([]domain.Node, error) {
+	parsed, err := s.readAndParse(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.selectNodes(ctx, parsed, expr)
+}
+
+// selectNodes resolves an expression against the outline built from
+// parsed. Titles are matched against the drafts' frontmatter titles,
+// falling back to the slug when a title cannot be read.
+func (s *OutlineService) selectNodes(ctx context.Context, parsed []domain.ParsedFile, expr domain.SelectorExpr) ([]domain.Node, error) {
+	o, err := s.buildOutline(ctx, parsed, expr.ByName())
+	if err != nil {
+		return nil, err
+	}
+	return o.Select(expr)
+}
+
+// DeleteNodes removes every node a selector expression selects as Delete
+// does, acquiring an advisory lock first. A selected node whose descendants
+// are all selected is removed with its subtree even without recursive mode.
+// Every deletion is planned before any is made.
+func (s *OutlineService) DeleteNodes(ctx context.Context, expr domain.SelectorExpr, mode domain.DeleteMode, apply bool) (*DeleteResult, error) {
+	if err := s.lockForWrite(ctx); err != nil {
+		return nil, err
+	}
+	defer s.locker.Unlock()
+
+	return s.deleteNodes(ctx, expr, mode, false, apply)
+}
+
+// ArchiveNodes removes every node a selector expression selects as
+// DeleteNodes does, but moves each removed subtree to the trash.
+func (s *OutlineService) ArchiveNodes(ctx context.Context, expr domain.SelectorExpr, mode domain.DeleteMode, apply bool) (*DeleteResult, error) {
+	if s.trashStore == nil {
+		return nil, ErrTrashUnavailable
+	}
+	if err := s.lockForWrite(ctx); err != nil {
+		return nil, err
+	}
+	defer s.locker.Unlock()
+
+	return s.deleteNodes(ctx, expr, mode, true, apply)
+}
+
+// deleteNodes deletes the topmost selected nodes one at a time, first
+// planning every deletion so that none is made when any would fail.
+func (s *OutlineService) deleteNodes(ctx context.Context, expr domain.SelectorExpr, mode domain.DeleteMode, archive, apply bool) (*DeleteResult, error) {
+	parsed, err := s.readAndParse(ctx)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := s.selectNodes(ctx, parsed, expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("%s: %w", expr, ErrNodeNotFound)
+	}
+
+	selected := map[string]bool{}
+	for _, n := range nodes {
+		selected[n.MP.String()] = true
+	}
+	type deletion struct {
+		sel  domain.Selector
+		mode domain.DeleteMode
+	}
+	var deletions []deletion
+	for _, n := range nodes {
+		mp := n.MP.String()
+		if hasSelectedAncestor(mp, selected) {
+			continue
+		}
+		nodeMode := mode
+		if mode == domain.DeleteModeDefault && subtreeSelected(parsed, mp, selected) {
+			nodeMode = domain.DeleteModeRecursive
+		}
+		sel, _ := domain.ParseSelector(mp)
+		deletions = append(deletions, deletion{sel, nodeMode})
+	}
+	if mode == domain.DeleteModePromote && len(deletions) > 1 {
+		return nil, fmt.Errorf("%s selects %d nodes: %w", expr, len(deletions), ErrPromoteMany)
+	}
+
+	result := &DeleteResult{}
+	for _, d := range deletions {
+		planned, err := s.deleteNode(ctx, d.sel, d.mode, archive, false)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", d.sel, err)
+		}
+		mergeDeleteResult(result, planned)
+	}
+	if !apply {
+		return result, nil
+	}
+
+	result = &DeleteResult{}
+	for _, d := range deletions {
+		done, err := s.deleteNode(ctx, d.sel, d.mode, archive, true)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", d.sel, err)
+		}
+		mergeDeleteResult(result, done)
+	}
+	return result, nil
+}
+
+// mergeDeleteResult adds the outcome of one node's deletion to result.
+func mergeDeleteResult(result, r *DeleteResult) {
+	result.FilesDeleted = append(result.FilesDeleted, r.FilesDeleted...)
+	if len(r.FilesRenamed) > 0 {
+		if result.FilesRenamed == nil {
+			result.FilesRenamed = map[string]string{}
+		}
+		maps.Copy(result.FilesRenamed, r.FilesRenamed)
+	}
+	result.SIDsPreserved = append(result.SIDsPreserved, r.SIDsPreserved...)
+	if r.TrashID != "" {
+		result.TrashIDs = append(result.TrashIDs, r.TrashID)
+		result.TrashID = result.TrashIDs[0]
+	}
+}
+
+// hasSelectedAncestor reports whether any ancestor of mp is selected.
+func hasSelectedAncestor(mp string, selected map[string]bool) bool {
+	for p := parentMP(mp); p != ""; p = parentMP(p) {
+		if selected[p] {
+			return true
+		}
+	}
+	return false
+}
+
+// subtreeSelected reports whether every descendant of mp is selected.
+func subtreeSelected(parsed []domain.ParsedFile, mp string, selected map[string]bool) bool {
+	for _, pf := range parsed {
+		if isDescendantMP(pf.MP, mp) && !selected[pf.MP] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,223 @@
+package outline
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+// selectTestFiles returns a fixture of a part with three chapters, the
+// second of which has a scene, and a second part.
+func selectTestFiles() []string {
+	return []string{
+		"100_SID001AABB_draft_part-one.md",
+		"100-100_SID002CCDD_draft_chapter-one.md",
+		"100-200_SID003EEFF_draft_chapter-two.md",
+		"100-200-100_SID004GGHH_draft_scene.md",
+		"100-300_SID005IIJJ_draft_interlude.md",
+		"200_SID006KKLL_draft_part-two.md",
+	}
+}
+
+func newSelectTestService(store *fakeTrashStore) (*OutlineService, *fakeFileDeleter) {
+	svc, deleter := newTrashTestService(selectTestFiles(), store, &mockLocker{})
+	svc.contentReader = &fakeContentReader{contents: map[string]string{
+		"100-100_SID002CCDD_draft_chapter-one.md": "---\ntitle: The Beginning\n---\n",
+	}}
+	return svc, deleter
+}
+
+func mps(nodes []domain.Node) []string {
+	var out []string
+	for _, n := range nodes {
+		out = append(out, n.MP.String())
+	}
+	return out
+}
+
+func TestOutlineService_SelectNodes(t *testing.T) {
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{"100-200/**", []string{"100-200", "100-200-100"}},
+		{"title:the *", []string{"100-100"}},
+		{"title:chapter-*", []string{"100-200"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			svc, _ := newSelectTestService(&fakeTrashStore{})
+			expr, _ := domain.ParseSelectorExpr(tt.expr)
+
+			nodes, err := svc.SelectNodes(context.Background(), expr)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := mps(nodes); !slices.Equal(got, tt.want) {
+				t.Errorf("SelectNodes(%s) = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutlineService_SelectNodes_Errors(t *testing.T) {
+	readErr := errors.New("read failed")
+	buildErr := errors.New("corrupt outline")
+	expr, _ := domain.ParseSelectorExpr("100/*")
+
+	svc, _ := newSelectTestService(&fakeTrashStore{})
+	svc.reader = &fakeDirectoryReader{err: readErr}
+	if _, err := svc.SelectNodes(context.Background(), expr); !errors.Is(err, readErr) {
+		t.Errorf("error = %v, want %v", err, readErr)
+	}
+
+	svc, _ = newSelectTestService(&fakeTrashStore{})
+	svc.builder = &fakeOutlineBuilder{err: buildErr}
+	if _, err := svc.SelectNodes(context.Background(), expr); !errors.Is(err, buildErr) {
+		t.Errorf("error = %v, want %v", err, buildErr)
+	}
+}
+
+func TestOutlineService_DeleteNodes(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		mode domain.DeleteMode
+		want []string
+	}{
+		{"whole subtree without recursive", "100-200/**", domain.DeleteModeDefault, []string{
+			"100-200_SID003EEFF_draft_chapter-two.md", "100-200-100_SID004GGHH_draft_scene.md",
+		}},
+		{"range of leaves", "100-100..100-100", domain.DeleteModeDefault, []string{"100-100_SID002CCDD_draft_chapter-one.md"}},
+		{"recursive children", "100/*", domain.DeleteModeRecursive, []string{
+			"100-100_SID002CCDD_draft_chapter-one.md",
+			"100-200_SID003EEFF_draft_chapter-two.md", "100-200-100_SID004GGHH_draft_scene.md",
+			"100-300_SID005IIJJ_draft_interlude.md",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, deleter := newSelectTestService(&fakeTrashStore{})
+			expr, _ := domain.ParseSelectorExpr(tt.expr)
+
+			result, err := svc.DeleteNodes(context.Background(), expr, tt.mode, true)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(result.FilesDeleted, tt.want) || !slices.Equal(deleter.deleted, tt.want) {
+				t.Errorf("deleted %v (reported %v), want %v", deleter.deleted, result.FilesDeleted, tt.want)
+			}
+			if len(result.TrashIDs) != 0 || result.FilesRenamed != nil {
+				t.Errorf("result = %+v, want no trash or renames", result)
+			}
+		})
+	}
+}
+
+func TestOutlineService_DeleteNodes_DryRun(t *testing.T) {
+	svc, deleter := newSelectTestService(&fakeTrashStore{})
+	expr, _ := domain.ParseSelectorExpr("type:draft")
+
+	result, err := svc.DeleteNodes(context.Background(), expr, domain.DeleteModeDefault, false)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.FilesDeleted) != 6 || len(result.SIDsPreserved) != 6 || len(deleter.deleted) != 0 {
+		t.Errorf("result = %+v, deleted %v", result, deleter.deleted)
+	}
+}
+
+func TestOutlineService_DeleteNodes_Promote(t *testing.T) {
+	svc, deleter := newSelectTestService(&fakeTrashStore{})
+	expr, _ := domain.ParseSelectorExpr("100-200..100-200")
+
+	result, err := svc.DeleteNodes(context.Background(), expr, domain.DeleteModePromote, true)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.FilesRenamed) != 1 || !slices.Equal(deleter.deleted, []string{"100-200_SID003EEFF_draft_chapter-two.md"}) {
+		t.Errorf("result = %+v, deleted %v", result, deleter.deleted)
+	}
+}
+
+func TestOutlineService_ArchiveNodes(t *testing.T) {
+	store := &fakeTrashStore{}
+	svc, deleter := newSelectTestService(store)
+	expr, _ := domain.ParseSelectorExpr("100-100..100-300")
+
+	result, err := svc.ArchiveNodes(context.Background(), expr, domain.DeleteModeRecursive, true)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(store.trashed) != 3 || len(result.TrashIDs) != 3 || result.TrashID != result.TrashIDs[0] || len(deleter.deleted) != 0 {
+		t.Errorf("result = %+v, trashed %d, deleted %v", result, len(store.trashed), deleter.deleted)
+	}
+}
+
+func TestOutlineService_DeleteNodes_Errors(t *testing.T) {
+	lockErr := errors.New("locked")
+	readErr := errors.New("read failed")
+	deleteErr := errors.New("permission denied")
+
+	tests := []struct {
+		name  string
+		expr  string
+		mode  domain.DeleteMode
+		setup func(*OutlineService, *fakeFileDeleter)
+		want  error
+	}{
+		{"locked", "100/*", domain.DeleteModeDefault, func(s *OutlineService, _ *fakeFileDeleter) { s.locker = &mockLocker{tryLockErr: lockErr} }, lockErr},
+		{"unreadable directory", "100/*", domain.DeleteModeDefault, func(s *OutlineService, _ *fakeFileDeleter) { s.reader = &fakeDirectoryReader{err: readErr} }, readErr},
+		{"missing node", "300/**", domain.DeleteModeDefault, nil, ErrNodeNotFound},
+		{"no match", "title:epilogue*", domain.DeleteModeDefault, nil, ErrNodeNotFound},
+		{"child not selected", "100..200", domain.DeleteModeDefault, nil, ErrNodeHasChildren},
+		{"promote many", "100/*", domain.DeleteModePromote, nil, ErrPromoteMany},
+		{"delete fails", "100/*", domain.DeleteModeRecursive, func(_ *OutlineService, d *fakeFileDeleter) { d.err = deleteErr }, deleteErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, deleter := newSelectTestService(&fakeTrashStore{})
+			if tt.setup != nil {
+				tt.setup(svc, deleter)
+			}
+			expr, _ := domain.ParseSelectorExpr(tt.expr)
+
+			_, err := svc.DeleteNodes(context.Background(), expr, tt.mode, true)
+
+			if !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+			if tt.want != deleteErr && len(deleter.deleted) != 0 {
+				t.Errorf("deleted %v after a failed plan", deleter.deleted)
+			}
+		})
+	}
+}
+
+func TestOutlineService_ArchiveNodes_Errors(t *testing.T) {
+	expr, _ := domain.ParseSelectorExpr("100/*")
+
+	svc, _ := newSelectTestService(&fakeTrashStore{})
+	svc.trashStore = nil
+	if _, err := svc.ArchiveNodes(context.Background(), expr, domain.DeleteModeRecursive, true); !errors.Is(err, ErrTrashUnavailable) {
+		t.Errorf("error = %v, want ErrTrashUnavailable", err)
+	}
+
+	lockErr := errors.New("locked")
+	svc, _ = newSelectTestService(&fakeTrashStore{})
+	svc.locker = &mockLocker{tryLockErr: lockErr}
+	if _, err := svc.ArchiveNodes(context.Background(), expr, domain.DeleteModeRecursive, true); !errors.Is(err, lockErr) {
+		t.Errorf("error = %v, want %v", err, lockErr)
+	}
+}
@@ -2,21 +2,27 @@
 package outline
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"io/fs"
+	"maps"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/eykd/linemark-go/internal/domain"
 )
 
 // ErrNodeNotFound is returned when a selector matches no node.
-var ErrNodeNotFound = errors.New("node not found")
+var ErrNodeNotFound = domain.ErrNodeNotFound
 
 // ErrAmbiguousSelector is returned when a selector matches more than one node.
-var ErrAmbiguousSelector = errors.New("ambiguous selector")
+var ErrAmbiguousSelector = domain.ErrAmbiguousSelector
 
 // ErrNodeHasChildren is returned when deleting a node with children in default mode.
 var ErrNodeHasChildren = errors.New("node has children; use --recursive or --promote")
@@ -30,6 +36,17 @@ var ErrCycleDetected = errors.New("cycle detected")
 // ErrTypeAlreadyExists is returned when adding a type that already exists on a node.
 var ErrTypeAlreadyExists = errors.New("type already exists")
 
+// ErrTemplateNotFound is returned when an explicitly requested template does not exist.
+var ErrTemplateNotFound = errors.New("template not found")
+
+// ErrContentUnavailable is returned by operations that need file contents
+// when the service has no ContentReader.
+var ErrContentUnavailable = errors.New("file contents are not available")
+
+// ErrConfigUnavailable is returned when saving the config without a
+// ConfigStore.
+var ErrConfigUnavailable = errors.New("config store is not available")
+
 // ErrEmptyTitle is returned when an empty or whitespace-only title is provided.
 var ErrEmptyTitle = errors.New("title must not be empty")
 
@@ -41,6 +58,8 @@ type Slugifier interface {
 // FrontmatterHandler provides frontmatter parsing and serialization operations.
 type FrontmatterHandler interface {
 	GetTitle(input string) (string, error)
+	GetFields(input string) (map[string]string, error)
+	GetList(input, key string) ([]string, error)
 	SetTitle(input, newTitle string) (string, error)
 	EncodeYAMLValue(s string) string
 	Serialize(fm, body string) string
@@ -94,6 +113,26 @@ type ReservationStore interface {
 	CreateReservation(ctx context.Context, sid string) error
 }
 
+// ConfigStore abstracts persisting the project configuration. SaveConfig
+// writes the value of one dotted key from cfg, leaving the keys the project
+// has not set to follow the built-in defaults.
+type ConfigStore interface {
+	SaveConfig(ctx context.Context, cfg domain.Config, key string) error
+}
+
+// TemplateReader abstracts reading document templates from the template
+// directory. A missing template yields an error wrapping fs.ErrNotExist.
+type TemplateReader interface {
+	ReadTemplate(ctx context.Context, name string) (string, error)
+}
+
+// BaselineStore abstracts persisting the check baseline. A missing baseline
+// loads as empty.
+type BaselineStore interface {
+	LoadBaseline(ctx context.Context) (domain.Baseline, error)
+	SaveBaseline(ctx context.Context, b domain.Baseline) error
+}
+
 // OutlineBuilder abstracts building an Outline from parsed files.
 type OutlineBuilder interface {
 	BuildOutline(files []domain.ParsedFile) (domain.Outline, []domain.Finding, error)
@@ -121,8 +160,10 @@ type ListResult struct {
 }
 
 // CheckResult holds the result of checking the outline.
+// Suppressed counts findings hidden by the baseline or lmk_ignore frontmatter.
 type CheckResult struct {
-	Findings []domain.Finding
+	Findings   []domain.Finding
+	Suppressed int
 }
 
 // RepairAction describes a single repair that was applied.
@@ -158,11 +199,19 @@ type LoadResult struct {
 	Findings []domain.Finding
 }
 
+// ConfigResult holds the result of changing a configuration value.
+type ConfigResult struct {
+	Key      string
+	OldValue string
+	NewValue string
+}
+
 // AddResult holds the result of adding a new node to the outline.
 type AddResult struct {
-	SID      string
-	MP       string
-	Filename string
+	SID        string
+	MP         string
+	Filename   string
+	Rebalanced map[string]string
 }
 
 // OutlineService coordinates outline mutations with advisory locking.
@@ -178,6 +227,17 @@ type OutlineService struct {
 	slugifier        Slugifier
 	fmHandler        FrontmatterHandler
 	reservationStore ReservationStore
+	configStore      ConfigStore
+	templateReader   TemplateReader
+	baselineStore    BaselineStore
+	mergeSource      MergeSource
+	snapshotResolver SnapshotResolver
+	snapshotStore    SnapshotStore
+	trashStore       TrashStore
+	lintRules        []LintRule
+	now              func() time.Time
+	config           domain.Config
+	configErr        error
 }
 
 // Option configures an OutlineService during construction.
@@ -207,6 +267,59 @@ func WithReservationStore(rs ReservationStore) Option {
 	return func(s *OutlineService) { s.reservationStore = rs }
 }
 
+// WithConfig sets the project configuration used by the service.
+func WithConfig(cfg domain.Config) Option { return func(s *OutlineService) { s.config = cfg } }
+
+// WithConfigError records a config load or validation failure so that Check
+// can report it. Read-only methods keep running on the current config;
+// methods that change the outline refuse to.
+func WithConfigError(err error) Option { return func(s *OutlineService) { s.configErr = err } }
+
+// WithConfigStore sets the ConfigStore on the service.
+func WithConfigStore(cs ConfigStore) Option {
+	return func(s *OutlineService) { s.configStore = cs }
+}
+
+// WithTemplateReader sets the TemplateReader used by Add and AddType.
+func WithTemplateReader(tr TemplateReader) Option {
+	return func(s *OutlineService) { s.templateReader = tr }
+}
+
+// WithBaselineStore sets the BaselineStore used by Check and WriteBaseline.
+func WithBaselineStore(bs BaselineStore) Option {
+	return func(s *OutlineService) { s.baselineStore = bs }
+}
+
+// WithMergeSource sets the MergeSource used to resolve in-progress merges.
+func WithMergeSource(m MergeSource) Option {
+	return func(s *OutlineService) { s.mergeSource = m }
+}
+
+// WithSnapshotResolver sets the SnapshotResolver used to find the outline
+// states compared by Diff.
+func WithSnapshotResolver(r SnapshotResolver) Option {
+	return func(s *OutlineService) { s.snapshotResolver = r }
+}
+
+// WithSnapshotStore sets the SnapshotStore that holds outline snapshots.
+func WithSnapshotStore(ss SnapshotStore) Option {
+	return func(s *OutlineService) { s.snapshotStore = ss }
+}
+
+// WithTrashStore sets the TrashStore that archived nodes are moved to.
+func WithTrashStore(ts TrashStore) Option {
+	return func(s *OutlineService) { s.trashStore = ts }
+}
+
+// WithLintRules registers lint rules alongside the built-in ones. They run
+// when enabled by lint.rules or requested by a CheckRules filter.
+func WithLintRules(rules ...LintRule) Option {
+	return func(s *OutlineService) { s.lintRules = append(s.lintRules, rules...) }
+}
+
+// WithClock sets the function used for the current time in template dates.
+func WithClock(now func() time.Time) Option { return func(s *OutlineService) { s.now = now } }
+
 // NewOutlineService creates an OutlineService with the given dependencies.
 func NewOutlineService(reader DirectoryReader, writer FileWriter, locker Locker, reserver SIDReserver, opts ...Option) *OutlineService {
 	svc := &OutlineService{
@@ -217,6 +330,8 @@ func NewOutlineService(reader DirectoryReader, writer FileWriter, locker Locker,
 		builder:   &defaultOutlineBuilder{},
 		slugifier: defaultSlugifier,
 		fmHandler: defaultFMHandler,
+		now:       time.Now,
+		config:    domain.DefaultConfig(),
 	}
 	for _, o := range opts {
 		o(svc)
@@ -224,13 +339,91 @@ func NewOutlineService(reader DirectoryReader, writer FileWriter, locker Locker,
 	return svc
 }
 
+// Config returns the project configuration in effect for the service.
+func (s *OutlineService) Config() domain.Config {
+	return s.config
+}
+
+// lockForWrite acquires the advisory lock for a method that changes the
+// outline. It first refuses when the config failed to load, since the
+// defaults in its place may not match the project's numbering or doc types.
+func (s *OutlineService) lockForWrite(ctx context.Context) error {
+	if s.configErr != nil {
+		return fmt.Errorf("fix %s first: %w", domain.ConfigPath, s.configErr)
+	}
+	return s.locker.TryLock(ctx)
+}
+
+// SetConfig changes a single configuration value, acquiring an advisory lock first.
+// The updated config is validated before anything is written. When apply is
+// false, the change is planned but not saved.
+func (s *OutlineService) SetConfig(ctx context.Context, key, value string, apply bool) (*ConfigResult, error) {
+	if s.configErr != nil {
+		return nil, fmt.Errorf("fix %s before setting values: %w", domain.ConfigPath, s.configErr)
+	}
+	if err := s.locker.TryLock(ctx); err != nil {
+		return nil, err
+	}
+	defer s.locker.Unlock()
+
+	cfg := s.config
+	oldValue, err := cfg.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Set(key, value); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	newValue, _ := cfg.Get(key)
+
+	result := &ConfigResult{Key: key, OldValue: oldValue, NewValue: newValue}
+
+	if !apply {
+		return result, nil
+	}
+	if s.configStore == nil {
+		return nil, ErrConfigUnavailable
+	}
+
+	if err := s.configStore.SaveConfig(ctx, cfg, key); err != nil {
+		return nil, err
+	}
+	s.config = cfg
+
+	return result, nil
+}
+
+// AddTypeOption configures the AddType method.
+type AddTypeOption func(*addTypeConfig)
+
+type addTypeConfig struct {
+	template string
+}
+
+// AddTypeTemplate uses the named template instead of the configured lookup.
+func AddTypeTemplate(name string) AddTypeOption { return func(c *addTypeConfig) { c.template = name } }
+
 // AddType adds a document type to a node, acquiring an advisory lock first.
-func (s *OutlineService) AddType(ctx context.Context, docType, selector string) (*ModifyResult, error) {
+// The new file is filled from a template when one applies.
+func (s *OutlineService) AddType(ctx context.Context, docType, selector string, opts ...AddTypeOption) (*ModifyResult, error) {
 	if err := domain.ValidateDocType(docType); err != nil {
 		return nil, err
 	}
 
-	if err := s.locker.TryLock(ctx); err != nil {
+	var cfg addTypeConfig
+	for _, o := range opts {
+		o(&cfg)
+	}
+	if cfg.template != "" {
+		if err := domain.ValidateTemplateName(cfg.template); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.lockForWrite(ctx); err != nil {
 		return nil, err
 	}
 	defer s.locker.Unlock()
@@ -239,17 +432,17 @@ func (s *OutlineService) AddType(ctx context.Context, docType, selector string)
 		return &ModifyResult{}, nil
 	}
 
-	return s.addTypeImpl(ctx, docType, selector)
+	return s.addTypeImpl(ctx, docType, selector, cfg.template)
 }
 
 // addTypeImpl performs the I/O operations for AddType.
-func (s *OutlineService) addTypeImpl(ctx context.Context, docType, selector string) (*ModifyResult, error) {
+func (s *OutlineService) addTypeImpl(ctx context.Context, docType, selector, template string) (*ModifyResult, error) {
 	parsed, err := s.readAndParse(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	nodeMP, nodeSID, err := findNodeBySelector(parsed, selector)
+	nodeMP, nodeSID, err := s.findNodeBySelector(ctx, parsed, selector)
 	if err != nil {
 		return nil, err
 	}
@@ -260,8 +453,19 @@ func (s *OutlineService) addTypeImpl(ctx context.Context, docType, selector stri
 		}
 	}
 
+	mp, err := domain.NewMaterializedPath(nodeMP)
+	if err != nil {
+		return nil, err
+	}
+	content, err := s.renderTemplateImpl(ctx, template, docType, mp.Depth(), func() domain.TemplateVars {
+		return s.templateVarsImpl(ctx, parsed, nodeMP, nodeSID, s.nodeTitleImpl(ctx, parsed, nodeMP))
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	filename := domain.GenerateFilename(nodeMP, nodeSID, docType, "")
-	if err := s.writer.WriteFile(ctx, filename, ""); err != nil {
+	if err := s.writer.WriteFile(ctx, filename, content); err != nil {
 		return nil, err
 	}
 
@@ -278,7 +482,7 @@ func (s *OutlineService) RemoveType(ctx context.Context, docType, selector strin
 		return nil, err
 	}
 
-	if err := s.locker.TryLock(ctx); err != nil {
+	if err := s.lockForWrite(ctx); err != nil {
 		return nil, err
 	}
 	defer s.locker.Unlock()
@@ -297,7 +501,7 @@ func (s *OutlineService) removeTypeImpl(ctx context.Context, docType, selector s
 		return nil, err
 	}
 
-	nodeMP, nodeSID, err := findNodeBySelector(parsed, selector)
+	nodeMP, nodeSID, err := s.findNodeBySelector(ctx, parsed, selector)
 	if err != nil {
 		return nil, err
 	}
@@ -330,7 +534,7 @@ func (s *OutlineService) listTypesImpl(ctx context.Context, selector string) (*L
 		return nil, err
 	}
 
-	nodeMP, nodeSID, err := findNodeBySelector(parsed, selector)
+	nodeMP, nodeSID, err := s.findNodeBySelector(ctx, parsed, selector)
 	if err != nil {
 		return nil, err
 	}
@@ -350,8 +554,58 @@ func (s *OutlineService) listTypesImpl(ctx context.Context, selector string) (*L
 	}, nil
 }
 
-// Check validates the outline without acquiring an advisory lock.
-func (s *OutlineService) Check(ctx context.Context) (*CheckResult, error) {
+// CheckOption configures the Check method's rule filtering.
+type CheckOption func(*checkConfig)
+
+type checkConfig struct {
+	rules          []string
+	skip           []string
+	ignoreBaseline bool
+}
+
+// CheckRules limits findings to the given rule IDs: lint rule IDs or
+// finding types. Named lint rules run even when lint.rules does not enable them.
+func CheckRules(ids ...string) CheckOption {
+	return func(c *checkConfig) { c.rules = append(c.rules, ids...) }
+}
+
+// CheckSkipRules drops findings for the given rule IDs and skips those lint rules.
+func CheckSkipRules(ids ...string) CheckOption {
+	return func(c *checkConfig) { c.skip = append(c.skip, ids...) }
+}
+
+// CheckIgnoreBaseline reports findings even when the baseline lists them.
+func CheckIgnoreBaseline() CheckOption {
+	return func(c *checkConfig) { c.ignoreBaseline = true }
+}
+
+// Check validates the outline without acquiring an advisory lock. Findings
+// suppressed by lmk_ignore frontmatter or listed in the baseline are counted
+// but not reported.
+func (s *OutlineService) Check(ctx context.Context, opts ...CheckOption) (*CheckResult, error) {
+	var cfg checkConfig
+	for _, o := range opts {
+		o(&cfg)
+	}
+	result, err := s.check(ctx, cfg)
+	if err != nil || cfg.ignoreBaseline || s.baselineStore == nil {
+		return result, err
+	}
+	baseline, err := s.baselineStore.LoadBaseline(ctx)
+	if err != nil {
+		return nil, err
+	}
+	findings, covered := baseline.Filter(result.Findings)
+	return &CheckResult{Findings: findings, Suppressed: result.Suppressed + covered}, nil
+}
+
+// check runs every check and applies rule filters and inline suppressions.
+func (s *OutlineService) check(ctx context.Context, cfg checkConfig) (*CheckResult, error) {
+	rules := s.allLintRules()
+	if err := validateRuleFilter(rules, append(cfg.rules, cfg.skip...)); err != nil {
+		return nil, err
+	}
+
 	var parsed []domain.ParsedFile
 	var findings []domain.Finding
 	if s.reader != nil {
@@ -360,6 +614,7 @@ func (s *OutlineService) Check(ctx context.Context) (*CheckResult, error) {
 		if err != nil {
 			return nil, err
 		}
+		s.proposeFilenameRecoveries(findings, parsed)
 	}
 
 	outline, buildFindings, err := s.builder.BuildOutline(parsed)
@@ -368,22 +623,63 @@ func (s *OutlineService) Check(ctx context.Context) (*CheckResult, error) {
 	}
 
 	findings = append(findings, buildFindings...)
-	findings = append(findings, findMissingDocTypeFindings(outline.Nodes)...)
+	findings = append(findings, findDuplicateMPFindings(parsed)...)
+	findings = append(findings, findSegmentWidthFindings(parsed, s.config.Numbering.SegmentWidth())...)
+	findings = append(findings, findOrphanedNodeFindings(parsed)...)
+	findings = append(findings, findConfigFindings(s.configErr)...)
+	findings = append(findings, findMissingDocTypeFindings(outline.Nodes, s.config)...)
 	findings = append(findings, s.findSlugDriftFindingsImpl(ctx, outline.Nodes)...)
 	findings = append(findings, s.findMalformedFrontmatterFindingsImpl(ctx, parsed)...)
+	findings = append(findings, s.findSchemaFindingsImpl(ctx, parsed)...)
 
 	missingRes, err := s.findMissingReservationFindings(ctx, parsed)
 	if err != nil {
 		return nil, err
 	}
 	findings = append(findings, missingRes...)
+	findings = append(findings, findUnknownLintRuleFindings(rules, s.config.Lint.Rules)...)
+	findings = append(findings, s.findLintFindingsImpl(ctx, parsed, enabledLintRules(rules, s.config.Lint.Rules, cfg))...)
+
+	findings, suppressed := s.suppressInlineImpl(ctx, filterFindings(findings, cfg))
+	return &CheckResult{Findings: findings, Suppressed: suppressed}, nil
+}
+
+// RepairOption configures the Repair method.
+type RepairOption func(*repairConfig)
+
+type repairConfig struct {
+	fixDuplicates   bool
+	keep            []string
+	reparentOrphans bool
+}
 
-	return &CheckResult{Findings: findings}, nil
+// RepairFixDuplicates opts in to repairing duplicate SIDs and shared MPs:
+// each extra node sharing a SID gets a freshly reserved one, and each extra
+// node sharing an MP is renumbered with its subtree.
+func RepairFixDuplicates() RepairOption {
+	return func(c *repairConfig) { c.fixDuplicates = true }
+}
+
+// RepairKeep chooses, by MP, which node of a duplicate SID group keeps the
+// SID. Without it the node whose files are listed first keeps it.
+func RepairKeep(mps ...string) RepairOption {
+	return func(c *repairConfig) { c.keep = append(c.keep, mps...) }
+}
+
+// RepairReparentOrphans repairs orphaned nodes by moving them under their
+// nearest existing ancestor instead of creating placeholder parents.
+func RepairReparentOrphans() RepairOption {
+	return func(c *repairConfig) { c.reparentOrphans = true }
 }
 
 // Repair repairs the outline, acquiring an advisory lock first.
-func (s *OutlineService) Repair(ctx context.Context) (*RepairResult, error) {
-	if err := s.locker.TryLock(ctx); err != nil {
+func (s *OutlineService) Repair(ctx context.Context, opts ...RepairOption) (*RepairResult, error) {
+	var cfg repairConfig
+	for _, o := range opts {
+		o(&cfg)
+	}
+
+	if err := s.lockForWrite(ctx); err != nil {
 		return nil, err
 	}
 	defer s.locker.Unlock()
@@ -392,30 +688,66 @@ func (s *OutlineService) Repair(ctx context.Context) (*RepairResult, error) {
 		return &RepairResult{}, nil
 	}
 
-	return s.repairImpl(ctx)
+	return s.repairImpl(ctx, cfg)
 }
 
 // repairImpl performs the I/O operations for Repair.
-func (s *OutlineService) repairImpl(ctx context.Context) (*RepairResult, error) {
-	parsed, err := s.readAndParse(ctx)
+func (s *OutlineService) repairImpl(ctx context.Context, cfg repairConfig) (*RepairResult, error) {
+	parsed, parseFindings, err := s.readAndParseWithFindings(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	outline, buildFindings, err := s.builder.BuildOutline(parsed)
+	result := &RepairResult{}
+
+	// Recover near-miss filenames first so the recovered files take part in
+	// the remaining repairs.
+	recovered, repairs, err := s.recoverFilenamesImpl(ctx, invalidFilenames(parseFindings), parsed)
 	if err != nil {
 		return nil, err
 	}
+	parsed = append(parsed, recovered...)
+	result.Repairs = append(result.Repairs, repairs...)
 
-	result := &RepairResult{}
+	if cfg.fixDuplicates {
+		repairs, err := s.fixDuplicateSIDsImpl(ctx, parsed, cfg.keep)
+		if err != nil {
+			return nil, err
+		}
+		result.Repairs = append(result.Repairs, repairs...)
+	}
+
+	if cfg.fixDuplicates {
+		if repairs, err = s.fixDuplicateMPsImpl(ctx, parsed); err != nil {
+			return nil, err
+		}
+		result.Repairs = append(result.Repairs, repairs...)
+	} else {
+		result.Unrepaired = append(result.Unrepaired, findDuplicateMPFindings(parsed)...)
+	}
+
+	parsed, repairs, err = s.repairOrphansImpl(ctx, parsed, cfg.reparentOrphans)
+	if err != nil {
+		return nil, err
+	}
+	result.Repairs = append(result.Repairs, repairs...)
+
+	outline, buildFindings, err := s.builder.BuildOutline(parsed)
+	if err != nil {
+		return nil, err
+	}
 
 	// Collect unrepaired findings (e.g., duplicate SIDs)
 	result.Unrepaired = append(result.Unrepaired, buildFindings...)
 
-	// Repair missing doc types
+	// Repair missing doc types. A draft carries the node's title, so a missing
+	// draft cannot be recreated and is left for the user.
 	for _, node := range outline.Nodes {
-		if !nodeHasDocType(node, domain.DocTypeNotes) {
-			filename := domain.GenerateFilename(node.MP.String(), node.SID, domain.DocTypeNotes, "")
+		for _, docType := range s.config.RequiredDocTypesAt(node.MP.Depth()) {
+			if docType == domain.DocTypeDraft || nodeHasDocType(node, docType) {
+				continue
+			}
+			filename := domain.GenerateFilename(node.MP.String(), node.SID, docType, "")
 			if err := s.writer.WriteFile(ctx, filename, ""); err != nil {
 				return nil, err
 			}
@@ -441,12 +773,27 @@ func (s *OutlineService) repairImpl(ctx context.Context) (*RepairResult, error)
 		})
 	}
 
-	// Repair slug drift
+	// Repair slug drift, unless the new name is taken: a conflict copy of a
+	// draft drifts onto the original and must not overwrite it.
+	taken := map[string]bool{}
+	for _, pf := range parsed {
+		taken[reconstructFilename(pf)] = true
+	}
 	for _, d := range s.detectSlugDriftsImpl(ctx, outline.Nodes) {
 		newFilename := domain.GenerateFilename(d.parsed.MP, d.parsed.SID, d.parsed.DocType, d.expectedSlug)
+		if taken[newFilename] {
+			result.Unrepaired = append(result.Unrepaired, domain.Finding{
+				Type:     domain.FindingSlugDrift,
+				Severity: domain.SeverityWarning,
+				Message:  fmt.Sprintf("slug drift: %s (expected %s, but %s already exists)", d.filename, d.expectedSlug, newFilename),
+				Path:     d.filename,
+			})
+			continue
+		}
 		if err := s.renamer.RenameFile(ctx, d.filename, newFilename); err != nil {
 			return nil, err
 		}
+		taken[newFilename] = true
 		result.Repairs = append(result.Repairs, RepairAction{
 			Type: domain.FindingSlugDrift,
 			Old:  d.filename,
@@ -478,73 +825,59 @@ func (s *OutlineService) Load(ctx context.Context) (*LoadResult, error) {
 }
 
 // ResolveSelector loads the outline and returns the node matching the given selector.
+// Title, slug and relative selectors are resolved as resolveTarget does, and
+// the node returned for a title or slug selector carries its frontmatter title.
 func (s *OutlineService) ResolveSelector(ctx context.Context, sel domain.Selector) (domain.Node, error) {
-	result, err := s.Load(ctx)
+	parsed, err := s.readAndParse(ctx)
 	if err != nil {
 		return domain.Node{}, err
 	}
-
-	if sel.Kind() == domain.SelectorMP {
-		return findNodeByMP(result.Outline.Nodes, sel.Value())
-	}
-	return findNodeBySID(result.Outline.Nodes, sel.Value())
-}
-
-// findNodeByMP returns the first node whose MP matches the given value.
-func findNodeByMP(nodes []domain.Node, mp string) (domain.Node, error) {
-	for _, n := range nodes {
-		if n.MP.String() == mp {
-			return n, nil
-		}
+	o, err := s.buildOutline(ctx, parsed, sel.ByName())
+	if err != nil {
+		return domain.Node{}, err
 	}
-	return domain.Node{}, ErrNodeNotFound
-}
 
-// findNodeBySID returns the unique node with the given SID, or an error if
-// zero or multiple nodes match.
-func findNodeBySID(nodes []domain.Node, sid string) (domain.Node, error) {
-	var matches []domain.Node
-	for _, n := range nodes {
-		if n.SID == sid {
-			matches = append(matches, n)
-		}
-	}
-	switch len(matches) {
-	case 0:
-		return domain.Node{}, ErrNodeNotFound
-	case 1:
-		return matches[0], nil
-	default:
-		return domain.Node{}, ErrAmbiguousSelector
-	}
+	return o.Find(sel)
 }
 
-// MoveResult holds the result of a move operation.
+// MoveResult holds the result of a move operation. Rebalanced holds the
+// renames of neighbouring siblings renumbered to open a gap.
 type MoveResult struct {
-	Renames map[string]string
+	Renames    map[string]string
+	Rebalanced map[string]string
 }
 
 // DeleteResult holds the result of a delete operation at the service level.
+// TrashIDs lists the trash entries made by DeleteNodes and ArchiveNodes, one
+// per removed subtree; TrashID is the first.
 type DeleteResult struct {
 	FilesDeleted  []string
 	FilesRenamed  map[string]string
 	SIDsPreserved []string
+	TrashID       string
+	TrashIDs      []string
 }
 
 // Delete removes a node from the outline, acquiring an advisory lock first.
 func (s *OutlineService) Delete(ctx context.Context, sel domain.Selector, mode domain.DeleteMode, apply bool) (*DeleteResult, error) {
-	if err := s.locker.TryLock(ctx); err != nil {
+	if err := s.lockForWrite(ctx); err != nil {
 		return nil, err
 	}
 	defer s.locker.Unlock()
 
+	return s.deleteNode(ctx, sel, mode, false, apply)
+}
+
+// deleteNode removes a node from the outline, moving its files to the
+// trash instead of deleting them when archive is true.
+func (s *OutlineService) deleteNode(ctx context.Context, sel domain.Selector, mode domain.DeleteMode, archive, apply bool) (*DeleteResult, error) {
 	parsed, err := s.readAndParse(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	// Resolve the target node
-	targetMP, targetSID, err := resolveTarget(parsed, sel)
+	targetMP, targetSID, err := s.resolveTarget(ctx, parsed, sel)
 	if err != nil {
 		return nil, err
 	}
@@ -562,12 +895,17 @@ func (s *OutlineService) Delete(ctx context.Context, sel domain.Selector, mode d
 
 	hasChildren := len(descendantFiles) > 0
 
+	var trash *TrashEntry
+	if archive {
+		trash = newTrashEntry(parsed, targetMP, targetSID)
+	}
+
 	switch mode {
 	case domain.DeleteModeDefault:
 		if hasChildren {
 			return nil, ErrNodeHasChildren
 		}
-		return s.deleteFiles(ctx, targetFiles, nil, []string{targetSID}, apply)
+		return s.deleteFiles(ctx, targetFiles, nil, []string{targetSID}, trash, apply)
 	case domain.DeleteModeRecursive:
 		allFiles := append([]string{}, targetFiles...)
 		sidSet := map[string]bool{targetSID: true}
@@ -579,15 +917,32 @@ func (s *OutlineService) Delete(ctx context.Context, sel domain.Selector, mode d
 		for sid := range sidSet {
 			sids = append(sids, sid)
 		}
-		return s.deleteFiles(ctx, allFiles, nil, sids, apply)
+		return s.deleteFiles(ctx, allFiles, nil, sids, trash, apply)
 	default: // DeleteModePromote
-		return s.promoteChildren(ctx, parsed, targetMP, targetSID, targetFiles, descendantFiles, apply)
+		return s.promoteChildren(ctx, parsed, targetMP, targetSID, targetFiles, descendantFiles, trash, apply)
 	}
 }
 
+// MoveOption configures the Move method.
+type MoveOption func(*moveConfig)
+
+type moveConfig struct {
+	noRebalance bool
+}
+
+// MoveRebalance controls whether Move renumbers neighbouring siblings when
+// no gap exists at the target position. It is enabled by default; when
+// disabled, Move fails with domain.ErrNoSlotAvailable instead.
+func MoveRebalance(enabled bool) MoveOption { return func(c *moveConfig) { c.noRebalance = !enabled } }
+
 // Move relocates a node and its descendants under a new parent, acquiring an advisory lock first.
-func (s *OutlineService) Move(ctx context.Context, source, target domain.Selector, before, after string, apply bool) (*MoveResult, error) {
-	if err := s.locker.TryLock(ctx); err != nil {
+func (s *OutlineService) Move(ctx context.Context, source, target domain.Selector, before, after string, apply bool, opts ...MoveOption) (*MoveResult, error) {
+	var cfg moveConfig
+	for _, o := range opts {
+		o(&cfg)
+	}
+
+	if err := s.lockForWrite(ctx); err != nil {
 		return nil, err
 	}
 	defer s.locker.Unlock()
@@ -597,40 +952,30 @@ func (s *OutlineService) Move(ctx context.Context, source, target domain.Selecto
 		return nil, err
 	}
 
-	sourceMP, _, err := resolveTarget(parsed, source)
+	sourceMP, _, err := s.resolveTarget(ctx, parsed, source)
 	if err != nil {
 		return nil, err
 	}
-	targetMP, _, err := resolveTarget(parsed, target)
+	targetMP, _, err := s.resolveTarget(ctx, parsed, target)
 	if err != nil {
 		return nil, err
 	}
 
+	return s.moveNode(ctx, parsed, sourceMP, targetMP, before, after, apply, cfg)
+}
+
+// moveNode renames the node at sourceMP and its descendants to sit under
+// targetMP, or at the root level when targetMP is empty.
+func (s *OutlineService) moveNode(ctx context.Context, parsed []domain.ParsedFile, sourceMP, targetMP, before, after string, apply bool, cfg moveConfig) (*MoveResult, error) {
 	if targetMP == sourceMP || isDescendantMP(targetMP, sourceMP) {
 		return nil, fmt.Errorf("cannot move %s to descendant %s: %w", sourceMP, targetMP, ErrCycleDetected)
 	}
 
-	// Collect existing children at the target level (excluding the source being moved)
-	occupied := collectOccupiedChildNums(parsed, targetMP, sourceMP)
-
-	var nextNum int
-	var numErr error
-	switch {
-	case before != "":
-		parts := strings.Split(before, "-")
-		beforeNum, _ := strconv.Atoi(parts[len(parts)-1])
-		nextNum, numErr = domain.SiblingNumberBefore(occupied, beforeNum)
-	case after != "":
-		parts := strings.Split(after, "-")
-		afterNum, _ := strconv.Atoi(parts[len(parts)-1])
-		nextNum, numErr = domain.SiblingNumberAfter(occupied, afterNum)
-	default:
-		nextNum, numErr = domain.NextSiblingNumber(occupied)
-	}
-	if numErr != nil {
-		return nil, numErr
+	nextNum, rebalanced, err := s.siblingSlot(parsed, targetMP, sourceMP, before, after, !cfg.noRebalance)
+	if err != nil {
+		return nil, err
 	}
-	newSourceMP := buildChildMP(targetMP, nextNum)
+	newSourceMP := s.config.Numbering.ChildMP(targetMP, nextNum)
 
 	renames := map[string]string{}
 	for _, pf := range parsed {
@@ -642,13 +987,15 @@ func (s *OutlineService) Move(ctx context.Context, source, target domain.Selecto
 		}
 	}
 
-	result := &MoveResult{Renames: renames}
+	result := &MoveResult{Renames: renames, Rebalanced: rebalanced}
 
 	if !apply {
 		return result, nil
 	}
 
-	if err := applyRenames(ctx, s.renamer, renames); err != nil {
+	all := maps.Clone(renames)
+	maps.Copy(all, rebalanced)
+	if err := applyRenames(ctx, s.renamer, all); err != nil {
 		return nil, err
 	}
 
@@ -657,7 +1004,7 @@ func (s *OutlineService) Move(ctx context.Context, source, target domain.Selecto
 
 // Compact renumbers nodes at consistent spacing, acquiring an advisory lock first.
 func (s *OutlineService) Compact(ctx context.Context, selector string, apply bool) (*CompactResult, error) {
-	if err := s.locker.TryLock(ctx); err != nil {
+	if err := s.lockForWrite(ctx); err != nil {
 		return nil, err
 	}
 	defer s.locker.Unlock()
@@ -671,7 +1018,15 @@ func (s *OutlineService) compactImpl(ctx context.Context, selector string, apply
 	if err != nil {
 		return nil, err
 	}
-	renames := s.compactChildrenImpl(parsed, selector, selector)
+	if sel, err := domain.ParseSelector(selector); err == nil && !sel.Direct() {
+		if selector, _, err = s.resolveTarget(ctx, parsed, sel); err != nil {
+			return nil, err
+		}
+	}
+	renames, err := s.compactChildrenImpl(parsed, selector, selector)
+	if err != nil {
+		return nil, err
+	}
 
 	result := &CompactResult{Renames: renames}
 
@@ -686,11 +1041,12 @@ func (s *OutlineService) compactImpl(ctx context.Context, selector string, apply
 	return result, nil
 }
 
-// compactChildrenImpl computes renames to compact children of oldParentMP at consistent spacing.
+// compactChildrenImpl computes renames to compact children of oldParentMP at consistent spacing,
+// widening or narrowing segments to the configured width.
 // oldParentMP is the current on-disk parent path (used to find children in parsed files).
 // newParentMP is the destination parent path (used to compute new filenames).
 // At the top level both are the same; they diverge when an ancestor was also renumbered.
-func (s *OutlineService) compactChildrenImpl(parsed []domain.ParsedFile, oldParentMP, newParentMP string) map[string]string {
+func (s *OutlineService) compactChildrenImpl(parsed []domain.ParsedFile, oldParentMP, newParentMP string) (map[string]string, error) {
 	renames := map[string]string{}
 
 	// Find unique direct child MPs using the old (on-disk) parent path
@@ -702,21 +1058,22 @@ func (s *OutlineService) compactChildrenImpl(parsed []domain.ParsedFile, oldPare
 			childMPs = append(childMPs, pf.MP)
 		}
 	}
-	sort.Strings(childMPs)
+	// Order numerically, since segments may differ in width.
+	slices.SortFunc(childMPs, func(a, b string) int { return cmp.Compare(lastSegmentNum(a), lastSegmentNum(b)) })
 
 	if len(childMPs) == 0 {
-		return renames
+		return renames, nil
 	}
 
 	// Compute new numbers
-	newNums, err := domain.CompactNumbers(len(childMPs))
+	newNums, err := s.config.Numbering.CompactNumbers(len(childMPs))
 	if err != nil {
-		return renames
+		return nil, fmt.Errorf("compacting children of %q: %w", oldParentMP, err)
 	}
 
 	// Build rename map for each child (direct files only)
 	for i, oldChildMP := range childMPs {
-		newChildMP := buildChildMP(newParentMP, newNums[i])
+		newChildMP := s.config.Numbering.ChildMP(newParentMP, newNums[i])
 
 		for _, pf := range parsed {
 			if pf.MP == oldChildMP {
@@ -729,18 +1086,19 @@ func (s *OutlineService) compactChildrenImpl(parsed []domain.ParsedFile, oldPare
 		}
 
 		// Recursively compact descendants, passing both old and new child paths
-		childRenames := s.compactChildrenImpl(parsed, oldChildMP, newChildMP)
-		for old, newName := range childRenames {
-			renames[old] = newName
+		childRenames, err := s.compactChildrenImpl(parsed, oldChildMP, newChildMP)
+		if err != nil {
+			return nil, err
 		}
+		maps.Copy(renames, childRenames)
 	}
 
-	return renames
+	return renames, nil
 }
 
 // Rename changes the title and slug of a node, acquiring an advisory lock first.
 func (s *OutlineService) Rename(ctx context.Context, selector, newTitle string, apply bool) (*RenameResult, error) {
-	if err := s.locker.TryLock(ctx); err != nil {
+	if err := s.lockForWrite(ctx); err != nil {
 		return nil, err
 	}
 	defer s.locker.Unlock()
@@ -755,7 +1113,7 @@ func (s *OutlineService) renameImpl(ctx context.Context, selector, newTitle stri
 		return nil, err
 	}
 
-	nodeMP, nodeSID, err := findNodeBySelector(parsed, selector)
+	nodeMP, nodeSID, err := s.findNodeBySelector(ctx, parsed, selector)
 	if err != nil {
 		return nil, err
 	}
@@ -815,8 +1173,12 @@ func (s *OutlineService) renameImpl(ctx context.Context, selector, newTitle stri
 }
 
 // findNodeBySelector finds the MP and SID of the first node matching the given selector string.
-// The selector may be either a materialized path (e.g. "100") or a stable ID (e.g. "SID001AABB").
-func findNodeBySelector(parsed []domain.ParsedFile, selector string) (string, string, error) {
+// The selector may be either a materialized path (e.g. "100") or a stable ID (e.g. "SID001AABB"),
+// or a title, slug or relative selector resolved as resolveTarget does.
+func (s *OutlineService) findNodeBySelector(ctx context.Context, parsed []domain.ParsedFile, selector string) (string, string, error) {
+	if sel, err := domain.ParseSelector(selector); err == nil && !sel.Direct() {
+		return s.resolveTarget(ctx, parsed, sel)
+	}
 	for _, pf := range parsed {
 		if pf.MP == selector || pf.SID == selector {
 			return pf.MP, pf.SID, nil
@@ -835,23 +1197,41 @@ func nodeHasDocType(node domain.Node, docType string) bool {
 	return false
 }
 
-// findMissingDocTypeFindings checks each node for missing required document types.
-func findMissingDocTypeFindings(nodes []domain.Node) []domain.Finding {
+// findMissingDocTypeFindings checks each node for the document types its depth requires.
+func findMissingDocTypeFindings(nodes []domain.Node, cfg domain.Config) []domain.Finding {
 	var findings []domain.Finding
 	for _, node := range nodes {
-		if !nodeHasDocType(node, domain.DocTypeDraft) {
-			findings = append(findings, domain.Finding{
-				Type:     domain.FindingMissingDocType,
-				Severity: domain.SeverityError,
-				Message:  fmt.Sprintf("node %s missing draft", node.SID),
-			})
+		for _, docType := range cfg.RequiredDocTypesAt(node.MP.Depth()) {
+			if !nodeHasDocType(node, docType) {
+				findings = append(findings, domain.Finding{
+					Type:     domain.FindingMissingDocType,
+					Severity: domain.SeverityError,
+					Message:  fmt.Sprintf("node %s missing %s", node.SID, docType),
+					SID:      node.SID,
+					DocType:  docType,
+				})
+			}
 		}
-		if !nodeHasDocType(node, domain.DocTypeNotes) {
-			findings = append(findings, domain.Finding{
-				Type:     domain.FindingMissingDocType,
-				Severity: domain.SeverityError,
-				Message:  fmt.Sprintf("node %s missing notes", node.SID),
-			})
+	}
+	return findings
+}
+
+// findConfigFindings converts a config load error into one finding per problem.
+func findConfigFindings(configErr error) []domain.Finding {
+	if configErr == nil {
+		return nil
+	}
+	errs := []error{configErr}
+	if joined, ok := configErr.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+	findings := make([]domain.Finding, len(errs))
+	for i, err := range errs {
+		findings[i] = domain.Finding{
+			Type:     domain.FindingInvalidConfig,
+			Severity: domain.SeverityError,
+			Message:  err.Error(),
+			Path:     domain.ConfigPath,
 		}
 	}
 	return findings
@@ -862,6 +1242,8 @@ type slugDrift struct {
 	filename     string
 	expectedSlug string
 	parsed       domain.ParsedFile
+	titleLine    int
+	titleText    string
 }
 
 // detectSlugDriftsImpl reads draft documents and returns any whose filename slug
@@ -890,10 +1272,13 @@ func (s *OutlineService) detectSlugDriftsImpl(ctx context.Context, nodes []domai
 				continue
 			}
 			if pf.Slug != expectedSlug {
+				line, text := frontmatterKeyLine(content, "title")
 				drifts = append(drifts, slugDrift{
 					filename:     doc.Filename,
 					expectedSlug: expectedSlug,
 					parsed:       pf,
+					titleLine:    line,
+					titleText:    text,
 				})
 			}
 		}
@@ -906,16 +1291,33 @@ func (s *OutlineService) findSlugDriftFindingsImpl(ctx context.Context, nodes []
 	drifts := s.detectSlugDriftsImpl(ctx, nodes)
 	var findings []domain.Finding
 	for _, d := range drifts {
-		findings = append(findings, domain.Finding{
+		f := domain.Finding{
 			Type:     domain.FindingSlugDrift,
 			Severity: domain.SeverityWarning,
 			Message:  fmt.Sprintf("slug drift: %s (expected %s)", d.filename, d.expectedSlug),
 			Path:     d.filename,
-		})
+		}
+		if d.titleLine > 0 {
+			f.Line, f.Column = d.titleLine, 1
+			f.EndLine, f.EndColumn = d.titleLine, utf8.RuneCountInString(d.titleText)+1
+		}
+		findings = append(findings, f)
 	}
 	return findings
 }
 
+// frontmatterKeyLine returns the 1-based line number and text of the
+// top-level frontmatter line that sets key, or 0 when there is none.
+func frontmatterKeyLine(content, key string) (int, string) {
+	lines := strings.Split(content[:bodyOffset(content)], "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, key+":") {
+			return i + 1, line
+		}
+	}
+	return 0, ""
+}
+
 // findMalformedFrontmatterFindingsImpl checks draft documents for malformed YAML frontmatter.
 func (s *OutlineService) findMalformedFrontmatterFindingsImpl(ctx context.Context, parsed []domain.ParsedFile) []domain.Finding {
 	if s.contentReader == nil {
@@ -932,17 +1334,70 @@ func (s *OutlineService) findMalformedFrontmatterFindingsImpl(ctx context.Contex
 			continue
 		}
 		if _, err := s.fmHandler.GetTitle(content); err != nil {
-			findings = append(findings, domain.Finding{
+			f := domain.Finding{
 				Type:     domain.FindingMalformedFrontmatter,
 				Severity: domain.SeverityError,
 				Message:  fmt.Sprintf("malformed frontmatter: %s", filename),
 				Path:     filename,
-			})
+			}
+			var posErr *domain.PositionError
+			if errors.As(err, &posErr) {
+				f.Message = fmt.Sprintf("malformed frontmatter: %s: %v", filename, posErr.Err)
+				f.Line, f.Column = posErr.Line, posErr.Column
+			}
+			findings = append(findings, f)
 		}
 	}
 	return findings
 }
 
+// findSchemaFindingsImpl checks frontmatter fields against the configured
+// schemas. Files whose frontmatter cannot be parsed are skipped; they are
+// reported as malformed instead.
+func (s *OutlineService) findSchemaFindingsImpl(ctx context.Context, parsed []domain.ParsedFile) []domain.Finding {
+	if s.contentReader == nil {
+		return nil
+	}
+	var findings []domain.Finding
+	for _, pf := range parsed {
+		schema := s.config.SchemaFor(strings.Count(pf.MP, "-")+1, pf.DocType)
+		if len(schema) == 0 {
+			continue
+		}
+		filename := reconstructFilename(pf)
+		content, err := s.contentReader.ReadFile(ctx, filename)
+		if err != nil {
+			continue
+		}
+		fields, err := s.fmHandler.GetFields(content)
+		if err != nil {
+			continue
+		}
+		for _, v := range schema.Violations(fields) {
+			findings = append(findings, schemaFinding(pf.SID, filename, v))
+		}
+	}
+	return findings
+}
+
+// schemaFinding converts a field violation into a check finding.
+func schemaFinding(sid, filename string, v domain.FieldViolation) domain.Finding {
+	if v.Missing {
+		return domain.Finding{
+			Type:     domain.FindingMissingField,
+			Severity: domain.SeverityError,
+			Message:  fmt.Sprintf("node %s missing frontmatter field %q", sid, v.Field),
+			Path:     filename,
+		}
+	}
+	return domain.Finding{
+		Type:     domain.FindingInvalidField,
+		Severity: domain.SeverityError,
+		Message:  fmt.Sprintf("node %s field %q has value %q, want one of: %s", sid, v.Field, v.Value, strings.Join(v.Allowed, ", ")),
+		Path:     filename,
+	}
+}
+
 // uniqueSIDsFromParsed returns the unique SIDs from a slice of parsed files in order of first appearance.
 func uniqueSIDsFromParsed(parsed []domain.ParsedFile) []string {
 	seen := map[string]bool{}
@@ -973,6 +1428,7 @@ func (s *OutlineService) findMissingReservationFindings(ctx context.Context, par
 				Severity: domain.SeverityWarning,
 				Message:  fmt.Sprintf("missing reservation marker for SID %s", sid),
 				Path:     sid,
+				SID:      sid,
 			})
 		}
 	}
@@ -1021,7 +1477,20 @@ func reconstructFilename(pf domain.ParsedFile) string {
 }
 
 // resolveTarget finds the MP and SID of the node matching the selector.
-func resolveTarget(parsed []domain.ParsedFile, sel domain.Selector) (string, string, error) {
+// Title, slug and relative selectors are resolved by domain.Outline.Find,
+// with titles read from the drafts' frontmatter.
+func (s *OutlineService) resolveTarget(ctx context.Context, parsed []domain.ParsedFile, sel domain.Selector) (string, string, error) {
+	if !sel.Direct() {
+		o, err := s.buildOutline(ctx, parsed, sel.ByName())
+		if err != nil {
+			return "", "", err
+		}
+		node, err := o.Find(sel)
+		if err != nil {
+			return "", "", err
+		}
+		return node.MP.String(), node.SID, nil
+	}
 	isSID := sel.Kind() == domain.SelectorSID
 	for _, pf := range parsed {
 		if isSID && pf.SID == sel.Value() {
@@ -1035,7 +1504,7 @@ func resolveTarget(parsed []domain.ParsedFile, sel domain.Selector) (string, str
 }
 
 // promoteChildren handles the promote delete mode.
-func (s *OutlineService) promoteChildren(ctx context.Context, parsed []domain.ParsedFile, targetMP, targetSID string, targetFiles []string, descendantFiles []domain.ParsedFile, apply bool) (*DeleteResult, error) {
+func (s *OutlineService) promoteChildren(ctx context.Context, parsed []domain.ParsedFile, targetMP, targetSID string, targetFiles []string, descendantFiles []domain.ParsedFile, trash *TrashEntry, apply bool) (*DeleteResult, error) {
 	// Find the parent MP of the target (empty string for root-level nodes).
 	parentMP := ""
 	if i := strings.LastIndex(targetMP, "-"); i >= 0 {
@@ -1059,7 +1528,7 @@ func (s *OutlineService) promoteChildren(ctx context.Context, parsed []domain.Pa
 
 	// Check for sufficient gaps to place all children
 	need := len(childMPs)
-	available := countAvailableGaps(siblingNums)
+	available := countAvailableGaps(siblingNums, s.config.Numbering.MaxSibling())
 	if available < need {
 		return nil, fmt.Errorf("%w: need %d slots, have %d", ErrInsufficientGaps, need, available)
 	}
@@ -1068,7 +1537,7 @@ func (s *OutlineService) promoteChildren(ctx context.Context, parsed []domain.Pa
 	occupied := append([]int{}, siblingNums...)
 	newNumbers := make([]int, need)
 	for i := range childMPs {
-		num, _ := domain.NextSiblingNumber(occupied)
+		num, _ := s.config.Numbering.NextSiblingNumber(occupied)
 		newNumbers[i] = num
 		occupied = append(occupied, num)
 	}
@@ -1076,7 +1545,7 @@ func (s *OutlineService) promoteChildren(ctx context.Context, parsed []domain.Pa
 	// Build rename map
 	renames := map[string]string{}
 	for i, childMP := range childMPs {
-		newMP := buildChildMP(parentMP, newNumbers[i])
+		newMP := s.config.Numbering.ChildMP(parentMP, newNumbers[i])
 		for _, pf := range descendantFiles {
 			if pf.MP == childMP {
 				oldName := reconstructFilename(pf)
@@ -1086,13 +1555,12 @@ func (s *OutlineService) promoteChildren(ctx context.Context, parsed []domain.Pa
 		}
 	}
 
-	return s.deleteFiles(ctx, targetFiles, renames, []string{targetSID}, apply)
+	return s.deleteFiles(ctx, targetFiles, renames, []string{targetSID}, trash, apply)
 }
 
 // countAvailableGaps returns the number of available sibling positions
-// in the range [1, 999], excluding the occupied positions.
-func countAvailableGaps(occupied []int) int {
-	const maxPositions = 999
+// in the range [1, maxPositions], excluding the occupied positions.
+func countAvailableGaps(occupied []int, maxPositions int) int {
 	unique := map[int]bool{}
 	for _, n := range occupied {
 		unique[n] = true
@@ -1101,7 +1569,8 @@ func countAvailableGaps(occupied []int) int {
 }
 
 // deleteFiles performs the actual file deletions and renames, or just plans them.
-func (s *OutlineService) deleteFiles(ctx context.Context, toDelete []string, toRename map[string]string, sids []string, apply bool) (*DeleteResult, error) {
+// With a trash entry, the files are moved to the trash instead of deleted.
+func (s *OutlineService) deleteFiles(ctx context.Context, toDelete []string, toRename map[string]string, sids []string, trash *TrashEntry, apply bool) (*DeleteResult, error) {
 	result := &DeleteResult{
 		FilesDeleted:  toDelete,
 		FilesRenamed:  toRename,
@@ -1119,6 +1588,16 @@ func (s *OutlineService) deleteFiles(ctx context.Context, toDelete []string, toR
 		}
 	}
 
+	if trash != nil {
+		trash.Files = toDelete
+		entry, err := s.trashStore.Trash(ctx, *trash)
+		if err != nil {
+			return nil, err
+		}
+		result.TrashID = entry.ID
+		return result, nil
+	}
+
 	// Perform deletes, tracking completed deletions for diagnostics.
 	var deleted []string
 	for _, f := range toDelete {
@@ -1147,6 +1626,15 @@ func applyRenames(ctx context.Context, renamer FileRenamer, renames map[string]s
 	return nil
 }
 
+// renamePairs lists a rename map as old/new pairs, for rollbackRenames.
+func renamePairs(renames map[string]string) [][2]string {
+	pairs := make([][2]string, 0, len(renames))
+	for oldName, newName := range renames {
+		pairs = append(pairs, [2]string{oldName, newName})
+	}
+	return pairs
+}
+
 // rollbackRenames reverses already-completed renames on a best-effort basis.
 func rollbackRenames(ctx context.Context, renamer FileRenamer, completed [][2]string) error {
 	var errs []error
@@ -1162,9 +1650,11 @@ func rollbackRenames(ctx context.Context, renamer FileRenamer, completed [][2]st
 type AddOption func(*addConfig)
 
 type addConfig struct {
-	before string
-	after  string
-	dryRun bool
+	before      string
+	after       string
+	dryRun      bool
+	template    string
+	noRebalance bool
 }
 
 // AddBefore positions the new node before the sibling with the given MP.
@@ -1173,10 +1663,19 @@ func AddBefore(mp string) AddOption { return func(c *addConfig) { c.before = mp
 // AddAfter positions the new node after the sibling with the given MP.
 func AddAfter(mp string) AddOption { return func(c *addConfig) { c.after = mp } }
 
+// AddTemplate fills the new draft from the named template instead of the
+// configured lookup.
+func AddTemplate(name string) AddOption { return func(c *addConfig) { c.template = name } }
+
 // AddApply controls whether Add writes files to disk.
 // When apply is false, the node position and filenames are planned but no I/O is performed.
 func AddApply(apply bool) AddOption { return func(c *addConfig) { c.dryRun = !apply } }
 
+// AddRebalance controls whether Add renumbers neighbouring siblings when no
+// gap exists at the requested position. It is enabled by default; when
+// disabled, Add fails with domain.ErrNoSlotAvailable instead.
+func AddRebalance(enabled bool) AddOption { return func(c *addConfig) { c.noRebalance = !enabled } }
+
 // lastSegmentNum extracts the last numeric segment from an MP string.
 func lastSegmentNum(mp string) int {
 	parts := strings.Split(mp, "-")
@@ -1189,7 +1688,18 @@ func (s *OutlineService) Add(ctx context.Context, title, parentMP string, opts .
 	if strings.TrimSpace(title) == "" {
 		return nil, ErrEmptyTitle
 	}
-	if err := s.locker.TryLock(ctx); err != nil {
+
+	var cfg addConfig
+	for _, o := range opts {
+		o(&cfg)
+	}
+	if cfg.template != "" {
+		if err := domain.ValidateTemplateName(cfg.template); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.lockForWrite(ctx); err != nil {
 		return nil, err
 	}
 	defer s.locker.Unlock()
@@ -1210,67 +1720,185 @@ func (s *OutlineService) Add(ctx context.Context, title, parentMP string, opts .
 		}
 	}
 
-	var cfg addConfig
-	for _, o := range opts {
-		o(&cfg)
-	}
-
-	occupied := collectChildNumbers(files, parentMP)
-
-	var nextNum int
-	switch {
-	case cfg.before != "":
-		beforeNum := lastSegmentNum(cfg.before)
-		nextNum, err = domain.SiblingNumberBefore(occupied, beforeNum)
-	case cfg.after != "":
-		afterNum := lastSegmentNum(cfg.after)
-		nextNum, err = domain.SiblingNumberAfter(occupied, afterNum)
-	default:
-		nextNum, err = domain.NextSiblingNumber(occupied)
-	}
+	parsed, _ := parseFilesWithFindings(files)
+	nextNum, rebalanced, err := s.siblingSlot(parsed, parentMP, "", cfg.before, cfg.after, !cfg.noRebalance)
 	if err != nil {
 		return nil, err
 	}
 
-	mp := buildChildMP(parentMP, nextNum)
+	mp := s.config.Numbering.ChildMP(parentMP, nextNum)
 
 	slugStr := s.slugifier.Slug(title)
 	filename := domain.GenerateFilename(mp, sid, domain.DocTypeDraft, slugStr)
 
+	depth := strings.Count(mp, "-") + 1
+	var vars *domain.TemplateVars
+	varsFn := func() domain.TemplateVars {
+		if vars == nil {
+			v := s.templateVarsImpl(ctx, parsed, mp, sid, title)
+			vars = &v
+		}
+		return *vars
+	}
+
+	// Templates are resolved even on a dry run so a bad --template is reported.
+	draft, err := s.renderTemplateImpl(ctx, cfg.template, domain.DocTypeDraft, depth, varsFn)
+	if err != nil {
+		return nil, err
+	}
+	content := formatFrontmatter(s.fmHandler, title)
+	if draft != "" {
+		// The title always comes from the command line, so set it safely
+		// rather than trusting the template's frontmatter.
+		if content, err = s.fmHandler.SetTitle(draft, title); err != nil {
+			return nil, fmt.Errorf("applying draft template: %w", err)
+		}
+	}
+
 	if !cfg.dryRun {
-		content := formatFrontmatter(s.fmHandler, title)
+		if err := applyRenames(ctx, s.renamer, rebalanced); err != nil {
+			return nil, err
+		}
 		if err := s.writer.WriteFile(ctx, filename, content); err != nil {
+			// Put the rebalanced siblings back rather than leave them
+			// renumbered around a node that was never created.
+			if rbErr := rollbackRenames(ctx, s.renamer, renamePairs(rebalanced)); rbErr != nil {
+				return nil, fmt.Errorf("%w; rollback failed: %v", err, rbErr)
+			}
 			return nil, err
 		}
 
-		notesFilename := domain.GenerateFilename(mp, sid, domain.DocTypeNotes, "")
-		if err := s.writer.WriteFile(ctx, notesFilename, ""); err != nil {
-			return nil, err
+		// The draft is always written above; create the remaining default types.
+		for _, docType := range s.config.DefaultDocTypesAt(depth) {
+			if docType == domain.DocTypeDraft {
+				continue
+			}
+			docContent, err := s.renderTemplateImpl(ctx, "", docType, depth, varsFn)
+			if err != nil {
+				return nil, err
+			}
+			docFilename := domain.GenerateFilename(mp, sid, docType, "")
+			if err := s.writer.WriteFile(ctx, docFilename, docContent); err != nil {
+				return nil, err
+			}
 		}
 	}
 
 	return &AddResult{
-		SID:      sid,
-		MP:       mp,
-		Filename: filename,
+		SID:        sid,
+		MP:         mp,
+		Filename:   filename,
+		Rebalanced: rebalanced,
 	}, nil
 }
 
+// renderTemplateImpl returns the expanded template for a doc type at a depth,
+// or "" when no template applies. An explicit template name replaces the
+// configured lookup and must exist. vars is only called once a template is found.
+// Values expanded inside the template's frontmatter are encoded as YAML
+// scalars, so a title such as "Act 1: Dawn" keeps the frontmatter valid.
+func (s *OutlineService) renderTemplateImpl(ctx context.Context, name, docType string, depth int, vars func() domain.TemplateVars) (string, error) {
+	candidates := s.config.TemplateCandidates(docType, depth)
+	if name != "" {
+		candidates = []string{domain.TemplateFile(name)}
+	}
+	if s.templateReader == nil {
+		if name != "" {
+			return "", fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+		}
+		return "", nil
+	}
+
+	for _, candidate := range candidates {
+		tmpl, err := s.templateReader.ReadTemplate(ctx, candidate)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		v := vars()
+		offset := bodyOffset(tmpl)
+		return domain.ExpandTemplate(tmpl[:offset], s.yamlTemplateVars(v)) + domain.ExpandTemplate(tmpl[offset:], v), nil
+	}
+
+	if name != "" {
+		return "", fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+	return "", nil
+}
+
+// yamlTemplateVars returns vars with every value encoded as a YAML scalar.
+func (s *OutlineService) yamlTemplateVars(vars domain.TemplateVars) domain.TemplateVars {
+	encode := s.fmHandler.EncodeYAMLValue
+	return domain.TemplateVars{
+		Title:       encode(vars.Title),
+		SID:         encode(vars.SID),
+		MP:          encode(vars.MP),
+		ParentTitle: encode(vars.ParentTitle),
+		Date:        encode(vars.Date),
+	}
+}
+
+// templateVarsImpl gathers placeholder values for a node, reading the parent's
+// title from its draft.
+func (s *OutlineService) templateVarsImpl(ctx context.Context, parsed []domain.ParsedFile, mp, sid, title string) domain.TemplateVars {
+	vars := domain.TemplateVars{
+		Title: title,
+		SID:   sid,
+		MP:    mp,
+		Date:  s.now().Format(time.DateOnly),
+	}
+	if i := strings.LastIndex(mp, "-"); i >= 0 {
+		vars.ParentTitle = s.nodeTitleImpl(ctx, parsed, mp[:i])
+	}
+	return vars
+}
+
+// buildOutline builds the outline from parsed. With byName set, each node's
+// title is read from its draft's frontmatter, falling back to the slug, so
+// that title selectors match what the writer sees.
+func (s *OutlineService) buildOutline(ctx context.Context, parsed []domain.ParsedFile, byName bool) (domain.Outline, error) {
+	o, _, err := s.builder.BuildOutline(parsed)
+	if err != nil {
+		return domain.Outline{}, err
+	}
+	if byName {
+		for i, n := range o.Nodes {
+			if title := s.nodeTitleImpl(ctx, parsed, n.MP.String()); title != "" {
+				o.Nodes[i].Title = title
+			}
+		}
+	}
+	return o, nil
+}
+
+// nodeTitleImpl reads the title from a node's draft frontmatter. It returns
+// "" when the draft is missing or unreadable.
+func (s *OutlineService) nodeTitleImpl(ctx context.Context, parsed []domain.ParsedFile, mp string) string {
+	if s.contentReader == nil {
+		return ""
+	}
+	for _, pf := range parsed {
+		if pf.MP != mp || pf.DocType != domain.DocTypeDraft {
+			continue
+		}
+		content, err := s.contentReader.ReadFile(ctx, reconstructFilename(pf))
+		if err != nil {
+			return ""
+		}
+		title, _ := s.fmHandler.GetTitle(content)
+		return title
+	}
+	return ""
+}
+
 // formatFrontmatter creates YAML frontmatter with a title field.
 // The title is encoded as a safe YAML scalar to prevent injection.
 func formatFrontmatter(fmh FrontmatterHandler, title string) string {
 	return fmh.Serialize("title: "+fmh.EncodeYAMLValue(title)+"\n", "")
 }
 
-// buildChildMP constructs an MP path by appending a numbered segment under parentMP.
-func buildChildMP(parentMP string, num int) string {
-	segment := fmt.Sprintf("%03d", num)
-	if parentMP == "" {
-		return segment
-	}
-	return parentMP + "-" + segment
-}
-
 // isDescendantMP reports whether childMP is a descendant of ancestorMP.
 func isDescendantMP(childMP, ancestorMP string) bool {
 	return strings.HasPrefix(childMP, ancestorMP+"-")
@@ -1298,19 +1926,66 @@ func collectOccupiedChildNums(parsed []domain.ParsedFile, parentMP, excludeMP st
 	return nums
 }
 
-// collectChildNumbers returns the occupied sibling numbers under parentMP.
-func collectChildNumbers(files []string, parentMP string) []int {
-	var occupied []int
-	for _, f := range files {
-		pf, parseErr := domain.ParseFilename(f)
-		if parseErr != nil {
+// siblingSlot returns the number for a new child of parentMP placed before
+// or after the sibling with the given MP, or after the last sibling when
+// neither is set. Children at excludeMP, such as a node being moved, are
+// ignored. When no gap exists and rebalance is set, neighbouring siblings
+// are renumbered to open one, and the renames for them and their subtrees
+// are returned.
+func (s *OutlineService) siblingSlot(parsed []domain.ParsedFile, parentMP, excludeMP, before, after string, rebalance bool) (int, map[string]string, error) {
+	numbering := s.config.Numbering
+	occupied := collectOccupiedChildNums(parsed, parentMP, excludeMP)
+
+	var num int
+	var err error
+	switch {
+	case before != "":
+		num, err = numbering.SiblingNumberBefore(occupied, lastSegmentNum(before))
+	case after != "":
+		num, err = numbering.SiblingNumberAfter(occupied, lastSegmentNum(after))
+	default:
+		num, err = numbering.NextSiblingNumber(occupied)
+	}
+	if !rebalance || !errors.Is(err, domain.ErrNoSlotAvailable) {
+		return num, nil, err
+	}
+
+	sorted := slices.Compact(slices.Sorted(slices.Values(occupied)))
+	index := len(sorted)
+	switch {
+	case before != "":
+		index, _ = slices.BinarySearch(sorted, lastSegmentNum(before))
+	case after != "":
+		i, found := slices.BinarySearch(sorted, lastSegmentNum(after))
+		if found {
+			i++
+		}
+		index = i
+	}
+	// Rebalance only fails when every slot is taken, which the lookup above
+	// reports as ErrMaxSiblingsReached instead.
+	num, renumbered, _ := numbering.Rebalance(sorted, index)
+
+	// depth indexes the child segment within each path.
+	depth := 0
+	if parentMP != "" {
+		depth = strings.Count(parentMP, "-") + 1
+	}
+	renames := map[string]string{}
+	for _, pf := range parsed {
+		if pf.Depth <= depth || (parentMP != "" && !isDescendantMP(pf.MP, parentMP)) {
 			continue
 		}
-		if isDirectChild(pf, parentMP) {
-			// PathParts are guaranteed numeric by ParseFilename regex (\d{3}).
-			n, _ := strconv.Atoi(pf.PathParts[len(pf.PathParts)-1])
-			occupied = append(occupied, n)
+		if pf.MP == excludeMP || isDescendantMP(pf.MP, excludeMP) {
+			continue
+		}
+		newNum, ok := renumbered[lastSegmentNum(pf.PathParts[depth])]
+		if !ok {
+			continue
 		}
+		childMP := strings.Join(pf.PathParts[:depth+1], "-")
+		newMP := numbering.ChildMP(parentMP, newNum) + pf.MP[len(childMP):]
+		renames[reconstructFilename(pf)] = domain.GenerateFilename(newMP, pf.SID, pf.DocType, pf.Slug)
 	}
-	return occupied
+	return num, renames, nil
 }
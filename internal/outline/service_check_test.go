@@ -311,3 +311,89 @@ func TestOutlineService_Check_FindingSeverities(t *testing.T) {
 		})
 	}
 }
+
+func TestOutlineService_Check_FindingPositions(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		content  string
+		want     domain.Finding
+	}{
+		{
+			name:     "slug drift points at title line",
+			filename: "100_SID001AABB_draft_wrong-slug.md",
+			content:  "---\nstatus: draft\ntitle: Réal Title\n---\n",
+			want: domain.Finding{
+				Type: domain.FindingSlugDrift, Severity: domain.SeverityWarning,
+				Message: "slug drift: 100_SID001AABB_draft_wrong-slug.md (expected real-title)",
+				Path:    "100_SID001AABB_draft_wrong-slug.md",
+				Line:    3, Column: 1, EndLine: 3, EndColumn: 18,
+			},
+		},
+		{
+			name:     "slug drift with flow mapping has no position",
+			filename: "100_SID001AABB_draft_wrong-slug.md",
+			content:  "---\n{title: Real Title}\n---\n",
+			want: domain.Finding{
+				Type: domain.FindingSlugDrift, Severity: domain.SeverityWarning,
+				Message: "slug drift: 100_SID001AABB_draft_wrong-slug.md (expected real-title)",
+				Path:    "100_SID001AABB_draft_wrong-slug.md",
+			},
+		},
+		{
+			name:     "yaml syntax error line",
+			filename: "100_SID001AABB_draft_act.md",
+			content:  "---\ntitle: Act\n  status: draft\n---\n",
+			want: domain.Finding{
+				Type: domain.FindingMalformedFrontmatter, Severity: domain.SeverityError,
+				Message: "malformed frontmatter: 100_SID001AABB_draft_act.md: mapping values are not allowed in this context",
+				Path:    "100_SID001AABB_draft_act.md",
+				Line:    3,
+			},
+		},
+		{
+			name:     "non-string title position",
+			filename: "100_SID001AABB_draft_act.md",
+			content:  "---\ntitle: [a, b]\n---\n",
+			want: domain.Finding{
+				Type: domain.FindingMalformedFrontmatter, Severity: domain.SeverityError,
+				Message: "malformed frontmatter: 100_SID001AABB_draft_act.md: title is not a string",
+				Path:    "100_SID001AABB_draft_act.md",
+				Line:    2, Column: 8,
+			},
+		},
+		{
+			name:     "unpositioned yaml error",
+			filename: "100_SID001AABB_draft_act.md",
+			content:  "---\ntitle: \x01\n---\n",
+			want: domain.Finding{
+				Type: domain.FindingMalformedFrontmatter, Severity: domain.SeverityError,
+				Message: "malformed frontmatter: 100_SID001AABB_draft_act.md",
+				Path:    "100_SID001AABB_draft_act.md",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := &fakeDirectoryReader{files: []string{tt.filename, "100_SID001AABB_notes.md"}}
+			svc := NewOutlineService(reader, nil, &mockLocker{}, nil,
+				WithContentReader(&fakeContentReader{contents: map[string]string{tt.filename: tt.content}}))
+
+			result, err := svc.Check(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for _, f := range result.Findings {
+				if f.Type == tt.want.Type {
+					if f != tt.want {
+						t.Errorf("finding = %+v, want %+v", f, tt.want)
+					}
+					return
+				}
+			}
+			t.Errorf("findings = %+v, want %+v", result.Findings, tt.want)
+		})
+	}
+}
@@ -0,0 +1,447 @@
+package outline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/eykd/linemark-go/internal/domain"
+	"github.com/eykd/linemark-go/internal/lock"
+)
+
+// fakeConfigStore is a test double for the ConfigStore interface.
+type fakeConfigStore struct {
+	saved   []domain.Config
+	keys    []string
+	saveErr error
+}
+
+func (f *fakeConfigStore) SaveConfig(_ context.Context, cfg domain.Config, key string) error {
+	if f.saveErr != nil {
+		return f.saveErr
+	}
+	f.saved = append(f.saved, cfg)
+	f.keys = append(f.keys, key)
+	return nil
+}
+
+func TestNewOutlineService_DefaultConfig(t *testing.T) {
+	svc := NewOutlineService(nil, nil, &mockLocker{}, nil)
+
+	if got := svc.Config().SIDLength; got != domain.DefaultSIDLength {
+		t.Errorf("SIDLength = %d, want default %d", got, domain.DefaultSIDLength)
+	}
+}
+
+func TestWithConfig_SetsConfig(t *testing.T) {
+	cfg := domain.DefaultConfig()
+	cfg.SIDLength = 8
+	svc := NewOutlineService(nil, nil, &mockLocker{}, nil, WithConfig(cfg))
+
+	if got := svc.Config().SIDLength; got != 8 {
+		t.Errorf("SIDLength = %d, want 8", got)
+	}
+}
+
+func TestWithConfigStore_SetsConfigStore(t *testing.T) {
+	cs := &fakeConfigStore{}
+	svc := NewOutlineService(nil, nil, &mockLocker{}, nil, WithConfigStore(cs))
+
+	if svc.configStore != cs {
+		t.Error("WithConfigStore did not set configStore field")
+	}
+}
+
+func TestOutlineService_Check_ReportsConfigErrors(t *testing.T) {
+	cfgErr := errors.Join(
+		fmt.Errorf("%w: sid.length: too short", domain.ErrInvalidConfig),
+		fmt.Errorf("%w: numbering.tiers: must not be empty", domain.ErrInvalidConfig),
+	)
+	svc := NewOutlineService(&fakeDirectoryReader{}, nil, &mockLocker{}, nil, WithConfigError(cfgErr))
+
+	result, err := svc.Check(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Findings) != 2 {
+		t.Fatalf("findings = %d, want 2: %v", len(result.Findings), result.Findings)
+	}
+	for _, f := range result.Findings {
+		if f.Type != domain.FindingInvalidConfig {
+			t.Errorf("Type = %q, want %q", f.Type, domain.FindingInvalidConfig)
+		}
+		if f.Severity != domain.SeverityError {
+			t.Errorf("Severity = %q, want error", f.Severity)
+		}
+		if f.Path != domain.ConfigPath {
+			t.Errorf("Path = %q, want %q", f.Path, domain.ConfigPath)
+		}
+	}
+}
+
+func TestOutlineService_Check_SingleConfigError(t *testing.T) {
+	svc := NewOutlineService(&fakeDirectoryReader{}, nil, &mockLocker{}, nil,
+		WithConfigError(fmt.Errorf("%w: yaml: bad", domain.ErrInvalidConfig)))
+
+	result, err := svc.Check(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Findings) != 1 || result.Findings[0].Type != domain.FindingInvalidConfig {
+		t.Errorf("findings = %v, want one invalid_config", result.Findings)
+	}
+}
+
+func TestOutlineService_Check_UsesConfiguredRequiredDocTypes(t *testing.T) {
+	cfg := domain.DefaultConfig()
+	cfg.RequiredDocTypes = []string{"draft", "research", "sources"}
+	reader := &fakeDirectoryReader{files: []string{
+		"100_SID001AABB_draft_hello.md",
+		"100_SID001AABB_research.md",
+	}}
+	svc := NewOutlineService(reader, nil, &mockLocker{}, nil, WithConfig(cfg))
+
+	result, err := svc.Check(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Findings) != 1 {
+		t.Fatalf("findings = %v, want one missing sources", result.Findings)
+	}
+	want := "node SID001AABB missing sources"
+	if result.Findings[0].Message != want {
+		t.Errorf("Message = %q, want %q", result.Findings[0].Message, want)
+	}
+}
+
+func TestOutlineService_Add_UsesConfiguredNumbering(t *testing.T) {
+	cfg := domain.DefaultConfig()
+	cfg.Numbering = domain.Numbering{Tiers: []int{50, 5, 1}}
+	reader := &fakeDirectoryReader{files: []string{"050_SID001AABB_draft_first.md"}}
+	writer := &fakeFileWriter{}
+	svc := NewOutlineService(reader, writer, &mockLocker{}, &fakeSIDReserver{sid: "NEWID12345AB"}, WithConfig(cfg))
+
+	result, err := svc.Add(context.Background(), "Second", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.MP != "100" {
+		t.Errorf("MP = %q, want %q", result.MP, "100")
+	}
+}
+
+func TestOutlineService_Compact_UsesConfiguredNumbering(t *testing.T) {
+	cfg := domain.DefaultConfig()
+	cfg.Numbering = domain.Numbering{Tiers: []int{50, 1}}
+	reader := &fakeDirectoryReader{files: []string{
+		"100_SID001AABB_draft_a.md",
+		"200_SID002AABB_draft_b.md",
+	}}
+	svc := NewOutlineService(reader, nil, &mockLocker{}, nil, WithConfig(cfg))
+
+	result, err := svc.Compact(context.Background(), "", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]string{
+		"100_SID001AABB_draft_a.md": "050_SID001AABB_draft_a.md",
+		"200_SID002AABB_draft_b.md": "100_SID002AABB_draft_b.md",
+	}
+	if len(result.Renames) != len(want) {
+		t.Fatalf("renames = %v, want %v", result.Renames, want)
+	}
+	for old, newName := range want {
+		if result.Renames[old] != newName {
+			t.Errorf("rename %s = %q, want %q", old, result.Renames[old], newName)
+		}
+	}
+}
+
+func TestOutlineService_Compact_AdaptsSpacingToChildCount(t *testing.T) {
+	var files []string
+	for i := 1; i <= 12; i++ {
+		files = append(files, fmt.Sprintf("%03d_SID%03dAABB_draft_n.md", i, i))
+	}
+	svc := NewOutlineService(&fakeDirectoryReader{files: files}, nil, &mockLocker{}, nil)
+
+	result, err := svc.Compact(context.Background(), "", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := result.Renames["012_SID012AABB_draft_n.md"]; got != "600_SID012AABB_draft_n.md" {
+		t.Errorf("last child renamed to %q, want 50-spaced 600", got)
+	}
+}
+
+func TestOutlineService_Compact_TooManyChildren(t *testing.T) {
+	files := []string{"001_SID000AABB_draft_n.md"}
+	for i := 1; i <= 999; i++ {
+		files = append(files, fmt.Sprintf("%03d-%03d_SID%04dAB_draft_n.md", 1, i, i))
+	}
+	files = append(files, "001-0999_SIDWIDEAAB_draft_n.md")
+	svc := NewOutlineService(&fakeDirectoryReader{files: files}, nil, &mockLocker{}, nil)
+
+	_, err := svc.Compact(context.Background(), "", false)
+
+	if !errors.Is(err, domain.ErrMaxSiblingsReached) {
+		t.Errorf("error = %v, want ErrMaxSiblingsReached", err)
+	}
+}
+
+func TestOutlineService_Compact_WidensSegments(t *testing.T) {
+	cfg := domain.DefaultConfig()
+	cfg.Numbering.Width = 4
+	reader := &fakeDirectoryReader{files: []string{
+		"100_SID001AABB_draft_a.md",
+		"100-200_SID002AABB_draft_b.md",
+	}}
+	svc := NewOutlineService(reader, nil, &mockLocker{}, nil, WithConfig(cfg))
+
+	result, err := svc.Compact(context.Background(), "", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]string{
+		"100_SID001AABB_draft_a.md":     "0100_SID001AABB_draft_a.md",
+		"100-200_SID002AABB_draft_b.md": "0100-0100_SID002AABB_draft_b.md",
+	}
+	if len(result.Renames) != len(want) {
+		t.Fatalf("renames = %v, want %v", result.Renames, want)
+	}
+	for old, newName := range want {
+		if result.Renames[old] != newName {
+			t.Errorf("rename %s = %q, want %q", old, result.Renames[old], newName)
+		}
+	}
+}
+
+func TestOutlineService_Add_UsesConfiguredWidth(t *testing.T) {
+	cfg := domain.DefaultConfig()
+	cfg.Numbering.Width = 4
+	reader := &fakeDirectoryReader{files: []string{"0100_SID001AABB_draft_first.md"}}
+	svc := NewOutlineService(reader, &fakeFileWriter{}, &mockLocker{}, &fakeSIDReserver{sid: "NEWID12345AB"}, WithConfig(cfg))
+
+	result, err := svc.Add(context.Background(), "Child", "0100")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.MP != "0100-0100" {
+		t.Errorf("MP = %q, want %q", result.MP, "0100-0100")
+	}
+}
+
+func TestOutlineService_SetConfig(t *testing.T) {
+	store := &fakeConfigStore{}
+	locker := &mockLocker{}
+	svc := NewOutlineService(nil, nil, locker, nil, WithConfigStore(store))
+
+	result, err := svc.SetConfig(context.Background(), "doc_types.required", "draft, research", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.OldValue != "draft,notes" || result.NewValue != "draft,research" {
+		t.Errorf("result = %+v, want draft,notes -> draft,research", result)
+	}
+	if len(store.saved) != 1 || store.keys[0] != "doc_types.required" {
+		t.Fatalf("saved = %d with keys %v, want doc_types.required once", len(store.saved), store.keys)
+	}
+	if got, _ := svc.Config().Get("doc_types.required"); got != "draft,research" {
+		t.Errorf("service config not updated: %q", got)
+	}
+	if !locker.tryLockCalled || !locker.unlockCalled {
+		t.Error("SetConfig should acquire and release the lock")
+	}
+}
+
+func TestOutlineService_SetConfig_DryRunDoesNotSave(t *testing.T) {
+	store := &fakeConfigStore{}
+	svc := NewOutlineService(nil, nil, &mockLocker{}, nil, WithConfigStore(store))
+
+	result, err := svc.SetConfig(context.Background(), "sid.length", "10", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.NewValue != "10" {
+		t.Errorf("NewValue = %q, want 10", result.NewValue)
+	}
+	if len(store.saved) != 0 {
+		t.Error("dry run should not save")
+	}
+	if svc.Config().SIDLength != domain.DefaultSIDLength {
+		t.Error("dry run should not change the service config")
+	}
+}
+
+func TestOutlineService_SetConfig_Errors(t *testing.T) {
+	saveErr := fmt.Errorf("disk full")
+	tests := []struct {
+		name      string
+		key       string
+		value     string
+		configErr error
+		lockErr   error
+		saveErr   error
+		wantErrIs error
+	}{
+		{"unknown key", "bogus", "1", nil, nil, nil, domain.ErrUnknownConfigKey},
+		{"unparseable value", "sid.length", "x", nil, nil, nil, domain.ErrInvalidConfig},
+		{"fails validation", "sid.length", "3", nil, nil, nil, domain.ErrInvalidConfig},
+		{"existing config broken", "sid.length", "10", domain.ErrInvalidConfig, nil, nil, domain.ErrInvalidConfig},
+		{"already locked", "sid.length", "10", nil, lock.ErrAlreadyLocked, nil, lock.ErrAlreadyLocked},
+		{"save fails", "sid.length", "10", nil, nil, saveErr, saveErr},
+		{"no config store", "sid.length", "10", nil, nil, nil, ErrConfigUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := []Option{WithConfigError(tt.configErr)}
+			if tt.wantErrIs != ErrConfigUnavailable {
+				opts = append(opts, WithConfigStore(&fakeConfigStore{saveErr: tt.saveErr}))
+			}
+			svc := NewOutlineService(nil, nil, &mockLocker{tryLockErr: tt.lockErr}, nil, opts...)
+
+			_, err := svc.SetConfig(context.Background(), tt.key, tt.value, true)
+
+			if !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("error = %v, want %v", err, tt.wantErrIs)
+			}
+			if svc.Config().SIDLength != domain.DefaultSIDLength {
+				t.Error("config should be unchanged after an error")
+			}
+		})
+	}
+}
+
+func TestOutlineService_BrokenConfigRefusesChanges(t *testing.T) {
+	cfgErr := fmt.Errorf("%w: numbering.width: must be between 3 and 6", domain.ErrInvalidConfig)
+	reader := &fakeDirectoryReader{files: []string{"0100_SID001AABB_draft_first.md"}}
+	writer := &fakeFileWriter{}
+	locker := &mockLocker{}
+	svc := NewOutlineService(reader, writer, locker, &fakeSIDReserver{sid: "NEWID12345AB"},
+		WithConfigError(cfgErr), WithRenamer(&fakeFileRenamer{}), WithDeleter(&fakeFileDeleter{}))
+	ctx := context.Background()
+	first, _ := domain.ParseSelector("SID001AABB")
+
+	changes := map[string]func() error{
+		"add": func() error { _, err := svc.Add(ctx, "Second", ""); return err },
+		"move": func() error {
+			_, err := svc.Move(ctx, first, domain.Selector{}, "", "", false)
+			return err
+		},
+		"compact": func() error { _, err := svc.Compact(ctx, "", false); return err },
+		"rename":  func() error { _, err := svc.Rename(ctx, "SID001AABB", "New", false); return err },
+		"delete": func() error {
+			_, err := svc.Delete(ctx, first, domain.DeleteModeDefault, false)
+			return err
+		},
+	}
+	for name, change := range changes {
+		if err := change(); !errors.Is(err, domain.ErrInvalidConfig) {
+			t.Errorf("%s: error = %v, want the config error", name, err)
+		}
+	}
+	if len(writer.written) != 0 || locker.tryLockCalled {
+		t.Error("changes should be refused before locking or writing")
+	}
+
+	if _, err := svc.Check(ctx); err != nil {
+		t.Errorf("check should still run: %v", err)
+	}
+}
+
+func TestOutlineService_Check_RequiresDocTypesPerDepth(t *testing.T) {
+	cfg := domain.DefaultConfig()
+	cfg.DepthDocTypes = map[int]domain.DocTypeRule{2: {Required: []string{"beats"}}}
+	reader := &fakeDirectoryReader{files: []string{
+		"100_SID001AABB_draft_chapter.md",
+		"100_SID001AABB_notes.md",
+		"100-100_SID002AABB_draft_scene.md",
+		"100-100_SID002AABB_notes.md",
+	}}
+	svc := NewOutlineService(reader, nil, &mockLocker{}, nil, WithConfig(cfg))
+
+	result, err := svc.Check(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Findings) != 1 {
+		t.Fatalf("findings = %v, want only the scene's missing beats", result.Findings)
+	}
+	if want := "node SID002AABB missing beats"; result.Findings[0].Message != want {
+		t.Errorf("Message = %q, want %q", result.Findings[0].Message, want)
+	}
+}
+
+func TestOutlineService_Repair_BackfillsConfiguredDocTypes(t *testing.T) {
+	cfg := domain.DefaultConfig()
+	cfg.DepthDocTypes = map[int]domain.DocTypeRule{2: {Required: []string{"beats"}}}
+	reader := &fakeDirectoryReader{files: []string{
+		"100_SID001AABB_notes.md",
+		"100-100_SID002AABB_draft_scene.md",
+		"100-100_SID002AABB_notes.md",
+	}}
+	writer := &fakeFileWriter{}
+	svc := NewOutlineService(reader, writer, &mockLocker{}, nil, WithConfig(cfg))
+
+	if _, err := svc.Repair(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := writer.written["100-100_SID002AABB_beats.md"]; !ok {
+		t.Errorf("expected beats file for the scene, got writes: %v", writer.written)
+	}
+	if _, ok := writer.written["100_SID001AABB_draft.md"]; ok {
+		t.Error("repair should not fabricate a missing draft")
+	}
+	if _, ok := writer.written["100_SID001AABB_beats.md"]; ok {
+		t.Error("beats is only required at depth 2")
+	}
+}
+
+func TestOutlineService_Add_CreatesConfiguredDefaultDocTypes(t *testing.T) {
+	cfg := domain.DefaultConfig()
+	cfg.DefaultDocTypes = []string{"draft"}
+	cfg.DepthDocTypes = map[int]domain.DocTypeRule{2: {Defaults: []string{"beats"}}}
+	reader := &fakeDirectoryReader{files: []string{"100_SID001AABB_draft_chapter.md"}}
+
+	tests := []struct {
+		name      string
+		parentMP  string
+		wantFiles []string
+	}{
+		{"top level gets draft only", "", []string{"200_NEWID12345AB_draft_new.md"}},
+		{"child gets beats", "100", []string{"100-100_NEWID12345AB_draft_new.md", "100-100_NEWID12345AB_beats.md"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &fakeFileWriter{}
+			svc := NewOutlineService(reader, writer, &mockLocker{}, &fakeSIDReserver{sid: "NEWID12345AB"}, WithConfig(cfg))
+
+			if _, err := svc.Add(context.Background(), "New", tt.parentMP); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(writer.written) != len(tt.wantFiles) {
+				t.Errorf("written = %v, want %v", writerKeys(writer), tt.wantFiles)
+			}
+			for _, name := range tt.wantFiles {
+				if _, ok := writer.written[name]; !ok {
+					t.Errorf("expected %s to be written, got %v", name, writerKeys(writer))
+				}
+			}
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"strings"
 	"testing"
 
 	"github.com/eykd/linemark-go/internal/domain"
@@ -466,7 +468,7 @@ func TestOutlineService_Add_SiblingSlotsExhausted(t *testing.T) {
 	reserver := &fakeSIDReserver{sid: "NEWID12345AB"}
 	svc := NewOutlineService(reader, writer, locker, reserver)
 
-	_, err := svc.Add(context.Background(), "One More", "")
+	_, err := svc.Add(context.Background(), "One More", "", AddRebalance(false))
 
 	if err == nil {
 		t.Fatal("expected error when all sibling slots exhausted, got nil")
@@ -482,7 +484,138 @@ func TestOutlineService_Add_SiblingSlotsExhausted(t *testing.T) {
 	}
 }
 
-// --- Test gap: invalid filenames skipped in collectChildNumbers ---
+func TestOutlineService_Add_RebalancesNeighbours(t *testing.T) {
+	reader := &fakeDirectoryReader{files: []string{
+		"100_ABCD1234EF_draft_first.md",
+		"101_BCDE2345FG_draft_second.md",
+		"101_BCDE2345FG_notes.md",
+	}}
+	writer := &fakeFileWriter{written: make(map[string]string)}
+	renamer := &fakeFileRenamer{}
+	svc := NewOutlineService(reader, writer, &mockLocker{}, &fakeSIDReserver{sid: "NEWID12345AB"}, WithRenamer(renamer))
+
+	result, err := svc.Add(context.Background(), "Between", "", AddBefore("101"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.MP != "400" {
+		t.Errorf("MP = %q, want 400", result.MP)
+	}
+	wantRebalanced := map[string]string{
+		"101_BCDE2345FG_draft_second.md": "700_BCDE2345FG_draft_second.md",
+		"101_BCDE2345FG_notes.md":        "700_BCDE2345FG_notes.md",
+	}
+	if !maps.Equal(result.Rebalanced, wantRebalanced) {
+		t.Errorf("Rebalanced = %v, want %v", result.Rebalanced, wantRebalanced)
+	}
+	if len(renamer.renames) != 2 {
+		t.Errorf("renames applied = %v, want 2", renamer.renames)
+	}
+	if _, ok := writer.written["400_NEWID12345AB_draft_between.md"]; !ok {
+		t.Errorf("written = %v, want the new draft at 400", writer.written)
+	}
+}
+
+func TestOutlineService_Add_RebalancesAfter(t *testing.T) {
+	reader := &fakeDirectoryReader{files: []string{
+		"100_ABCD1234EF_draft_first.md",
+		"101_BCDE2345FG_draft_second.md",
+		"102_CDEF3456GH_draft_third.md",
+	}}
+	svc := NewOutlineService(reader, &fakeFileWriter{written: make(map[string]string)}, &mockLocker{}, &fakeSIDReserver{sid: "NEWID12345AB"}, WithRenamer(&fakeFileRenamer{}))
+
+	result, err := svc.Add(context.Background(), "Between", "", AddAfter("100"), AddApply(false))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]string{"100_ABCD1234EF_draft_first.md": "033_ABCD1234EF_draft_first.md"}
+	if result.MP != "067" || !maps.Equal(result.Rebalanced, want) {
+		t.Errorf("result = %+v, want new node at 067 after 100 -> 033", result)
+	}
+}
+
+func TestOutlineService_Add_RebalanceDryRun(t *testing.T) {
+	reader := &fakeDirectoryReader{files: []string{"999_ABCD1234EF_draft_last-slot.md"}}
+	writer := &fakeFileWriter{written: make(map[string]string)}
+	renamer := &fakeFileRenamer{}
+	svc := NewOutlineService(reader, writer, &mockLocker{}, &fakeSIDReserver{sid: "NEWID12345AB"}, WithRenamer(renamer))
+
+	result, err := svc.Add(context.Background(), "One More", "", AddApply(false))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.MP != "666" || result.Rebalanced["999_ABCD1234EF_draft_last-slot.md"] != "333_ABCD1234EF_draft_last-slot.md" {
+		t.Errorf("result = %+v, want new node at 666 after 999 -> 333", result)
+	}
+	if len(renamer.renames) != 0 || len(writer.written) != 0 {
+		t.Error("dry run should neither rename nor write")
+	}
+}
+
+func TestOutlineService_Add_RebalanceRenameError(t *testing.T) {
+	reader := &fakeDirectoryReader{files: []string{"999_ABCD1234EF_draft_last-slot.md"}}
+	writer := &fakeFileWriter{written: make(map[string]string)}
+	renamer := &fakeFileRenamer{err: errors.New("disk full")}
+	svc := NewOutlineService(reader, writer, &mockLocker{}, &fakeSIDReserver{sid: "NEWID12345AB"}, WithRenamer(renamer))
+
+	_, err := svc.Add(context.Background(), "One More", "")
+
+	if err == nil {
+		t.Fatal("expected rename error")
+	}
+	if len(writer.written) != 0 {
+		t.Error("no file should be written when rebalancing fails")
+	}
+}
+
+func TestOutlineService_Add_RebalanceWriteErrorRollsBack(t *testing.T) {
+	reader := &fakeDirectoryReader{files: []string{
+		"100_ABCD1234EF_draft_first.md",
+		"101_BCDE2345FG_draft_second.md",
+		"101_BCDE2345FG_notes.md",
+	}}
+	writeErr := errors.New("disk full")
+	writer := &fakeFileWriter{writeErr: writeErr}
+	renamer := &fakeFileRenamer{}
+	svc := NewOutlineService(reader, writer, &mockLocker{}, &fakeSIDReserver{sid: "NEWID12345AB"}, WithRenamer(renamer))
+
+	_, err := svc.Add(context.Background(), "Between", "", AddBefore("101"))
+
+	if !errors.Is(err, writeErr) {
+		t.Fatalf("error = %v, want %v", err, writeErr)
+	}
+	if len(renamer.renames) != 4 {
+		t.Fatalf("renames = %v, want 2 rebalance renames and 2 reversals", renamer.renames)
+	}
+	moved := map[string]int{}
+	for _, r := range renamer.renames {
+		moved[r[0]]++
+		moved[r[1]]--
+	}
+	for name, n := range moved {
+		if n != 0 {
+			t.Errorf("%s not restored: renames = %v", name, renamer.renames)
+		}
+	}
+}
+
+func TestOutlineService_Add_RebalanceWriteErrorRollbackFails(t *testing.T) {
+	reader := &fakeDirectoryReader{files: []string{"999_ABCD1234EF_draft_last-slot.md"}}
+	writeErr := errors.New("disk full")
+	renamer := &fakeFileRenamer{failOnFile: "333_ABCD1234EF_draft_last-slot.md", err: errors.New("read-only")}
+	svc := NewOutlineService(reader, &fakeFileWriter{writeErr: writeErr}, &mockLocker{}, &fakeSIDReserver{sid: "NEWID12345AB"}, WithRenamer(renamer))
+
+	_, err := svc.Add(context.Background(), "One More", "")
+
+	if !errors.Is(err, writeErr) || !strings.Contains(err.Error(), "rollback failed") {
+		t.Errorf("error = %v, want write error with rollback failure", err)
+	}
+}
+
+// --- Test gap: invalid filenames skipped when numbering siblings ---
 
 func TestOutlineService_Add_InvalidFilenamesSkipped(t *testing.T) {
 	// Mix of valid and invalid filenames — invalid ones should be ignored
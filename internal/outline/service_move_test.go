@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"strings"
 	"testing"
 
@@ -358,7 +359,8 @@ func TestOutlineService_Move_ReadDirError(t *testing.T) {
 
 func TestOutlineService_Move_NoSlotAvailable(t *testing.T) {
 	// Two adjacent siblings at 200-001 and 200-002 leave no gap between them.
-	// Moving with before="200-002" should fail with no slot available.
+	// Moving with before="200-002" and rebalancing disabled should fail with
+	// no slot available.
 	files := []string{
 		"200_SID002CCDD_draft_target.md",
 		"200-001_SID003EEFF_draft_child-one.md",
@@ -373,16 +375,81 @@ func TestOutlineService_Move_NoSlotAvailable(t *testing.T) {
 
 	sourceSel, _ := domain.ParseSelector("300")
 	targetSel, _ := domain.ParseSelector("200")
-	_, err := svc.Move(context.Background(), sourceSel, targetSel, "200-002", "", true)
+	_, err := svc.Move(context.Background(), sourceSel, targetSel, "200-002", "", true, MoveRebalance(false))
 
 	if err == nil {
 		t.Fatal("expected error when no slot available between adjacent siblings")
 	}
+	if len(renamer.renames) != 0 {
+		t.Errorf("renames = %v, want none", renamer.renames)
+	}
 	if !errors.Is(err, domain.ErrNoSlotAvailable) {
 		t.Errorf("error = %v, want ErrNoSlotAvailable", err)
 	}
 }
 
+func TestOutlineService_Move_RebalancesNeighbours(t *testing.T) {
+	files := []string{
+		"200_SID002CCDD_draft_target.md",
+		"200-001_SID003EEFF_draft_child-one.md",
+		"200-002_SID005IIJJ_draft_child-two.md",
+		"200-002-100_SID006KKLL_draft_grandchild.md",
+		"300_SID004GGHH_draft_source.md",
+	}
+	renamer := &fakeFileRenamer{}
+	svc := NewOutlineService(&fakeDirectoryReader{files: files}, nil, &mockLocker{}, nil, WithRenamer(renamer))
+
+	sourceSel, _ := domain.ParseSelector("300")
+	targetSel, _ := domain.ParseSelector("200")
+	result, err := svc.Move(context.Background(), sourceSel, targetSel, "200-002", "", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := result.Renames["300_SID004GGHH_draft_source.md"]; got != "200-334_SID004GGHH_draft_source.md" {
+		t.Errorf("source renamed to %q, want 200-334", got)
+	}
+	wantRebalanced := map[string]string{
+		"200-002_SID005IIJJ_draft_child-two.md":      "200-667_SID005IIJJ_draft_child-two.md",
+		"200-002-100_SID006KKLL_draft_grandchild.md": "200-667-100_SID006KKLL_draft_grandchild.md",
+	}
+	if !maps.Equal(result.Rebalanced, wantRebalanced) {
+		t.Errorf("Rebalanced = %v, want %v", result.Rebalanced, wantRebalanced)
+	}
+	if len(renamer.renames) != 3 {
+		t.Errorf("renames applied = %v, want source and both rebalanced files", renamer.renames)
+	}
+}
+
+func TestOutlineService_Move_RebalanceSkipsSourceSubtree(t *testing.T) {
+	// Moving 100-001-500 before 100-001 renumbers its own parent 100-001;
+	// the source's files must only be renamed by the move itself.
+	files := []string{
+		"100_SID001AABB_draft_top.md",
+		"100-001_SID002AABB_draft_a.md",
+		"100-001-500_SID003AABB_draft_source.md",
+		"100-002_SID004AABB_draft_b.md",
+		"100-003_SID005AABB_draft_c.md",
+	}
+	svc := NewOutlineService(&fakeDirectoryReader{files: files}, nil, &mockLocker{}, nil, WithRenamer(&fakeFileRenamer{}))
+
+	sourceSel, _ := domain.ParseSelector("100-001-500")
+	targetSel, _ := domain.ParseSelector("100")
+	result, err := svc.Move(context.Background(), sourceSel, targetSel, "100-001", "", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for old := range result.Rebalanced {
+		if _, moved := result.Renames[old]; moved {
+			t.Errorf("%s renamed by both the move and rebalancing", old)
+		}
+	}
+	if len(result.Rebalanced) != 3 {
+		t.Errorf("Rebalanced = %v, want the three existing children", result.Rebalanced)
+	}
+}
+
 func TestOutlineService_Move_PreservesSIDs(t *testing.T) {
 	renamer := &fakeFileRenamer{}
 	svc := newMoveTestService(moveTestFiles(), renamer)
@@ -89,7 +89,11 @@ func (s *stubSlugifier) Slug(_ string) string { return s.slug }
 // stubFrontmatterHandler is a test double for FrontmatterHandler.
 type stubFrontmatterHandler struct{}
 
-func (s *stubFrontmatterHandler) GetTitle(input string) (string, error)           { return "", nil }
+func (s *stubFrontmatterHandler) GetTitle(input string) (string, error) { return "", nil }
+func (s *stubFrontmatterHandler) GetFields(input string) (map[string]string, error) {
+	return nil, nil
+}
+func (s *stubFrontmatterHandler) GetList(input, key string) ([]string, error)     { return nil, nil }
 func (s *stubFrontmatterHandler) SetTitle(input, newTitle string) (string, error) { return "", nil }
 func (s *stubFrontmatterHandler) EncodeYAMLValue(str string) string               { return str }
 func (s *stubFrontmatterHandler) Serialize(fm, body string) string                { return fm }
@@ -46,15 +46,15 @@ func TestOutlineService_Delete_Promote_OnlySibling_Succeeds(t *testing.T) {
 
 func TestCountAvailableGaps_EmptyOccupied(t *testing.T) {
 	// When no positions are occupied, all 999 positions are available.
-	got := countAvailableGaps(nil)
+	got := countAvailableGaps(nil, 999)
 	if got < 1 {
-		t.Errorf("countAvailableGaps(nil) = %d, want >= 1 (all positions available)", got)
+		t.Errorf("countAvailableGaps(nil, 999) = %d, want >= 1 (all positions available)", got)
 	}
 }
 
 func TestCountAvailableGaps_SingleOccupied(t *testing.T) {
 	// When only position 500 is occupied, positions 1-499 and 501-999 are available.
-	got := countAvailableGaps([]int{500})
+	got := countAvailableGaps([]int{500}, 999)
 	want := 998 // 999 total - 1 occupied
 	if got != want {
 		t.Errorf("countAvailableGaps([500]) = %d, want %d", got, want)
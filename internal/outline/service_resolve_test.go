@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"testing"
 
 	"github.com/eykd/linemark-go/internal/domain"
@@ -166,3 +167,129 @@ func TestOutlineService_ResolveSelector_LoadErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestOutlineService_ResolveSelector_ByName(t *testing.T) {
+	tests := []struct {
+		selector  string
+		wantMP    string
+		wantTitle string
+	}{
+		{"title:beginning", "100-100", "The Beginning"},
+		{"title:Chapter Two", "100-200", "chapter-two"},
+		{"slug:chapter-one", "100-100", "The Beginning"},
+		{"slug:intrlde", "100-300", "interlude"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			svc, _ := newSelectTestService(&fakeTrashStore{})
+			sel, _ := domain.ParseSelector(tt.selector)
+
+			node, err := svc.ResolveSelector(context.Background(), sel)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if node.MP.String() != tt.wantMP || node.Title != tt.wantTitle {
+				t.Errorf("node = %s %q, want %s %q", node.MP, node.Title, tt.wantMP, tt.wantTitle)
+			}
+		})
+	}
+}
+
+func TestOutlineService_ResolveSelector_AmbiguousTitle(t *testing.T) {
+	svc, _ := newSelectTestService(&fakeTrashStore{})
+	sel, _ := domain.ParseSelector("title:part")
+
+	_, err := svc.ResolveSelector(context.Background(), sel)
+
+	var ambiguous *domain.AmbiguousSelectorError
+	if !errors.As(err, &ambiguous) || !errors.Is(err, ErrAmbiguousSelector) {
+		t.Fatalf("error = %v, want *domain.AmbiguousSelectorError", err)
+	}
+	want := []domain.Candidate{
+		{MP: "100", SID: "SID001AABB", Title: "part-one"},
+		{MP: "200", SID: "SID006KKLL", Title: "part-two"},
+	}
+	if !slices.Equal(ambiguous.Candidates, want) {
+		t.Errorf("candidates = %v, want %v", ambiguous.Candidates, want)
+	}
+}
+
+func TestOutlineService_ByNameSelectorsInCommands(t *testing.T) {
+	ctx := context.Background()
+
+	svc, deleter := newSelectTestService(&fakeTrashStore{})
+	sel, _ := domain.ParseSelector("title:the beginning")
+	if _, err := svc.Delete(ctx, sel, domain.DeleteModeDefault, true); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if !slices.Equal(deleter.deleted, []string{"100-100_SID002CCDD_draft_chapter-one.md"}) {
+		t.Errorf("deleted %v, want chapter one", deleter.deleted)
+	}
+
+	svc, _ = newSelectTestService(&fakeTrashStore{})
+	types, err := svc.ListTypes(ctx, "slug:chapter-two")
+	if err != nil || types.NodeMP != "100-200" {
+		t.Errorf("ListTypes = %+v, %v; want 100-200", types, err)
+	}
+
+	if _, err := svc.Compact(ctx, "slug:part-one", false); err != nil {
+		t.Errorf("Compact: %v", err)
+	}
+	if _, err := svc.Compact(ctx, "slug:epilogue", false); !errors.Is(err, ErrNodeNotFound) {
+		t.Errorf("Compact error = %v, want ErrNodeNotFound", err)
+	}
+}
+
+func TestOutlineService_ResolveTarget_ByNameErrors(t *testing.T) {
+	sel, _ := domain.ParseSelector("title:scene")
+	buildErr := errors.New("corrupt outline")
+
+	svc, _ := newSelectTestService(&fakeTrashStore{})
+	svc.builder = &fakeOutlineBuilder{err: buildErr}
+	if _, err := svc.Delete(context.Background(), sel, domain.DeleteModeDefault, true); !errors.Is(err, buildErr) {
+		t.Errorf("error = %v, want %v", err, buildErr)
+	}
+	if _, err := svc.ResolveSelector(context.Background(), sel); !errors.Is(err, buildErr) {
+		t.Errorf("error = %v, want %v", err, buildErr)
+	}
+
+	svc, _ = newSelectTestService(&fakeTrashStore{})
+	sel, _ = domain.ParseSelector("title:epilogue")
+	if _, err := svc.Delete(context.Background(), sel, domain.DeleteModeDefault, true); !errors.Is(err, ErrNodeNotFound) {
+		t.Errorf("error = %v, want ErrNodeNotFound", err)
+	}
+}
+
+func TestOutlineService_RelativeSelectors(t *testing.T) {
+	ctx := context.Background()
+
+	svc, deleter := newSelectTestService(&fakeTrashStore{})
+	sel, _ := domain.ParseSelector("SID002CCDD+2")
+	if _, err := svc.Delete(ctx, sel, domain.DeleteModeDefault, true); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if !slices.Equal(deleter.deleted, []string{"100-300_SID005IIJJ_draft_interlude.md"}) {
+		t.Errorf("deleted %v, want the interlude", deleter.deleted)
+	}
+
+	svc, _ = newSelectTestService(&fakeTrashStore{})
+	types, err := svc.ListTypes(ctx, "last:SID004GGHH^")
+	if err != nil || types.NodeMP != "100-200-100" {
+		t.Errorf("ListTypes = %+v, %v; want 100-200-100", types, err)
+	}
+
+	sel, _ = domain.ParseSelector("100/2")
+	node, err := svc.ResolveSelector(ctx, sel)
+	if err != nil || node.SID != "SID003EEFF" {
+		t.Errorf("ResolveSelector = %+v, %v; want SID003EEFF", node, err)
+	}
+
+	if _, err := svc.Compact(ctx, "SID004GGHH^", false); err != nil {
+		t.Errorf("Compact: %v", err)
+	}
+	if _, err := svc.Compact(ctx, "SID006KKLL^", false); !errors.Is(err, domain.ErrNoRelative) {
+		t.Errorf("Compact error = %v, want ErrNoRelative", err)
+	}
+}
@@ -0,0 +1,177 @@
+package outline
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+func schemaTestConfig() domain.Config {
+	cfg := domain.DefaultConfig()
+	cfg.DepthSchemas = map[int]domain.Schema{
+		2: {
+			"pov":    {Required: true},
+			"status": {Required: true, Allowed: []string{"draft", "revised", "final"}},
+		},
+	}
+	cfg.DocTypeSchemas = map[string]domain.Schema{
+		"notes": {"tags": {Required: true}},
+	}
+	return cfg
+}
+
+func TestOutlineService_Check_FrontmatterSchemas(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents map[string]string
+		want     []domain.Finding
+	}{
+		{
+			name: "valid frontmatter",
+			contents: map[string]string{
+				"100-100_SID002AABB_draft_storm.md": "---\ntitle: Storm\npov: Ann\nstatus: draft\n---\n",
+				"100-100_SID002AABB_notes.md":       "---\ntags: weather\n---\n",
+			},
+		},
+		{
+			name: "missing and invalid fields",
+			contents: map[string]string{
+				"100-100_SID002AABB_draft_storm.md": "---\ntitle: Storm\nstatus: wip\n---\n",
+				"100-100_SID002AABB_notes.md":       "",
+			},
+			want: []domain.Finding{
+				{
+					Type:     domain.FindingMissingField,
+					Severity: domain.SeverityError,
+					Message:  `node SID002AABB missing frontmatter field "pov"`,
+					Path:     "100-100_SID002AABB_draft_storm.md",
+				},
+				{
+					Type:     domain.FindingInvalidField,
+					Severity: domain.SeverityError,
+					Message:  `node SID002AABB field "status" has value "wip", want one of: draft, revised, final`,
+					Path:     "100-100_SID002AABB_draft_storm.md",
+				},
+				{
+					Type:     domain.FindingMissingField,
+					Severity: domain.SeverityError,
+					Message:  `node SID002AABB missing frontmatter field "tags"`,
+					Path:     "100-100_SID002AABB_notes.md",
+				},
+			},
+		},
+		{
+			name: "comment-only frontmatter",
+			contents: map[string]string{
+				"100-100_SID002AABB_draft_storm.md": "---\ntitle: Storm\npov: Ann\nstatus: draft\n---\n",
+				"100-100_SID002AABB_notes.md":       "---\n# todo\n---\n",
+			},
+			want: []domain.Finding{
+				{
+					Type:     domain.FindingMissingField,
+					Severity: domain.SeverityError,
+					Message:  `node SID002AABB missing frontmatter field "tags"`,
+					Path:     "100-100_SID002AABB_notes.md",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contents := map[string]string{
+				"100_SID001AABB_draft_act.md": "---\ntitle: Act\n---\n",
+				"100_SID001AABB_notes.md":     "---\ntags: act\n---\n",
+			}
+			for k, v := range tt.contents {
+				contents[k] = v
+			}
+			reader := &fakeDirectoryReader{files: []string{
+				"100_SID001AABB_draft_act.md",
+				"100_SID001AABB_notes.md",
+				"100-100_SID002AABB_draft_storm.md",
+				"100-100_SID002AABB_notes.md",
+			}}
+			svc := NewOutlineService(reader, nil, &mockLocker{}, nil,
+				WithConfig(schemaTestConfig()),
+				WithContentReader(&fakeContentReader{contents: contents}))
+
+			result, err := svc.Check(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var got []domain.Finding
+			for _, f := range result.Findings {
+				if f.Type == domain.FindingMissingField || f.Type == domain.FindingInvalidField {
+					got = append(got, f)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("schema findings = %+v, want %+v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("finding[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestOutlineService_Check_SchemaSkipsUnreadableAndMalformedFiles(t *testing.T) {
+	reader := &fakeDirectoryReader{files: []string{
+		"100_SID001AABB_draft_act.md",
+		"100_SID001AABB_notes.md",
+		"100-100_SID002AABB_draft_storm.md",
+		"100-100_SID002AABB_notes.md",
+	}}
+
+	tests := []struct {
+		name    string
+		content ContentReader
+	}{
+		{"unreadable", &fakeContentReader{err: errors.New("io error")}},
+		{"malformed", &fakeContentReader{contents: map[string]string{
+			"100_SID001AABB_notes.md":           "---\ntags: act\n---\n",
+			"100-100_SID002AABB_draft_storm.md": "---\npov: [unclosed\n---\n",
+			"100-100_SID002AABB_notes.md":       "---\ntags: [unclosed\n---\n",
+		}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewOutlineService(reader, nil, &mockLocker{}, nil,
+				WithConfig(schemaTestConfig()), WithContentReader(tt.content))
+
+			result, err := svc.Check(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for _, f := range result.Findings {
+				if f.Type == domain.FindingMissingField || f.Type == domain.FindingInvalidField {
+					t.Errorf("unexpected schema finding: %+v", f)
+				}
+			}
+		})
+	}
+}
+
+func TestOutlineService_Check_SchemaWithoutContentReader(t *testing.T) {
+	reader := &fakeDirectoryReader{files: []string{"100-100_SID002AABB_draft_storm.md"}}
+	svc := NewOutlineService(reader, nil, &mockLocker{}, nil, WithConfig(schemaTestConfig()))
+
+	result, err := svc.Check(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, f := range result.Findings {
+		if f.Type == domain.FindingMissingField {
+			t.Errorf("schema findings need file contents, got %+v", f)
+		}
+	}
+}
@@ -0,0 +1,329 @@
+package outline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+	"time"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+// fakeTemplateReader is a test double for the TemplateReader interface.
+type fakeTemplateReader struct {
+	templates map[string]string
+	err       error
+	read      []string
+}
+
+func (f *fakeTemplateReader) ReadTemplate(_ context.Context, name string) (string, error) {
+	f.read = append(f.read, name)
+	if f.err != nil {
+		return "", f.err
+	}
+	tmpl, ok := f.templates[name]
+	if !ok {
+		return "", fmt.Errorf("open %s: %w", name, fs.ErrNotExist)
+	}
+	return tmpl, nil
+}
+
+var fixedClock = func() time.Time { return time.Date(2026, 3, 14, 9, 0, 0, 0, time.UTC) }
+
+func TestOutlineService_Add_FillsDraftFromTemplate(t *testing.T) {
+	reader := &fakeDirectoryReader{files: []string{"100_SID001AABB_draft_act-one.md"}}
+	writer := &fakeFileWriter{}
+	templates := &fakeTemplateReader{templates: map[string]string{
+		"draft.md": "---\nstatus: todo\n---\n# {{title}}\n\nIn {{parent_title}} ({{mp}}, {{sid}}) on {{date}}\n",
+	}}
+	content := &fakeContentReader{contents: map[string]string{
+		"100_SID001AABB_draft_act-one.md": "---\ntitle: Act One\n---\n",
+	}}
+	svc := NewOutlineService(reader, writer, &mockLocker{}, &fakeSIDReserver{sid: "NEWID12345AB"},
+		WithTemplateReader(templates), WithContentReader(content), WithClock(fixedClock))
+
+	result, err := svc.Add(context.Background(), "The Storm", "100")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "---\nstatus: todo\ntitle: The Storm\n---\n# The Storm\n\nIn Act One (100-100, NEWID12345AB) on 2026-03-14\n"
+	if got := writer.written[result.Filename]; got != want {
+		t.Errorf("draft content =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestOutlineService_Add_EncodesFrontmatterValues(t *testing.T) {
+	reader := &fakeDirectoryReader{files: []string{"100_SID001AABB_draft_act-one.md"}}
+	writer := &fakeFileWriter{}
+	templates := &fakeTemplateReader{templates: map[string]string{
+		"draft.md": "---\ntitle: {{title}}\npart: {{parent_title}}\n---\n# {{title}}\n",
+	}}
+	content := &fakeContentReader{contents: map[string]string{
+		"100_SID001AABB_draft_act-one.md": "---\ntitle: \"Part: One\"\n---\n",
+	}}
+	svc := NewOutlineService(reader, writer, &mockLocker{}, &fakeSIDReserver{sid: "NEWID12345AB"},
+		WithTemplateReader(templates), WithContentReader(content))
+
+	result, err := svc.Add(context.Background(), "Act 1: Dawn", "100")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "---\ntitle: \"Act 1: Dawn\"\npart: \"Part: One\"\n---\n# Act 1: Dawn\n"
+	if got := writer.written[result.Filename]; got != want {
+		t.Errorf("draft content =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestOutlineService_Add_TemplateLookupOrder(t *testing.T) {
+	cfg := domain.DefaultConfig()
+	cfg.DepthNames = []string{"part", "chapter"}
+
+	tests := []struct {
+		name      string
+		templates map[string]string
+		opts      []AddOption
+		wantDraft string
+	}{
+		{
+			name:      "no template keeps title-only draft",
+			templates: map[string]string{},
+			wantDraft: "---\ntitle: New\n---\n",
+		},
+		{
+			name:      "depth template wins over generic",
+			templates: map[string]string{"chapter/draft.md": "chapter body\n", "draft.md": "generic body\n"},
+			wantDraft: "---\ntitle: New\n---\nchapter body\n",
+		},
+		{
+			name:      "generic template used when no depth template",
+			templates: map[string]string{"draft.md": "generic body\n"},
+			wantDraft: "---\ntitle: New\n---\ngeneric body\n",
+		},
+		{
+			name:      "explicit template overrides lookup",
+			templates: map[string]string{"chapter/draft.md": "chapter body\n", "interlude.md": "interlude body\n"},
+			opts:      []AddOption{AddTemplate("interlude")},
+			wantDraft: "---\ntitle: New\n---\ninterlude body\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := &fakeDirectoryReader{files: []string{"100_SID001AABB_draft_part.md"}}
+			writer := &fakeFileWriter{}
+			svc := NewOutlineService(reader, writer, &mockLocker{}, &fakeSIDReserver{sid: "NEWID12345AB"},
+				WithConfig(cfg), WithTemplateReader(&fakeTemplateReader{templates: tt.templates}))
+
+			result, err := svc.Add(context.Background(), "New", "100", tt.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := writer.written[result.Filename]; got != tt.wantDraft {
+				t.Errorf("draft = %q, want %q", got, tt.wantDraft)
+			}
+		})
+	}
+}
+
+func TestOutlineService_Add_FillsOtherDefaultTypesFromTemplates(t *testing.T) {
+	reader := &fakeDirectoryReader{}
+	writer := &fakeFileWriter{}
+	templates := &fakeTemplateReader{templates: map[string]string{"notes.md": "Notes for {{title}}\n"}}
+	svc := NewOutlineService(reader, writer, &mockLocker{}, &fakeSIDReserver{sid: "NEWID12345AB"},
+		WithTemplateReader(templates))
+
+	if _, err := svc.Add(context.Background(), "Prologue", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := writer.written["100_NEWID12345AB_notes.md"]; got != "Notes for Prologue\n" {
+		t.Errorf("notes = %q, want expanded template", got)
+	}
+}
+
+func TestOutlineService_Add_DryRunStillResolvesTemplate(t *testing.T) {
+	writer := &fakeFileWriter{}
+	svc := NewOutlineService(&fakeDirectoryReader{}, writer, &mockLocker{}, &fakeSIDReserver{sid: "NEWID12345AB"},
+		WithTemplateReader(&fakeTemplateReader{}))
+
+	_, err := svc.Add(context.Background(), "New", "", AddTemplate("missing"), AddApply(false))
+
+	if !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("error = %v, want ErrTemplateNotFound", err)
+	}
+	if len(writer.written) != 0 {
+		t.Errorf("dry run wrote files: %v", writer.written)
+	}
+}
+
+func TestOutlineService_Add_TemplateErrors(t *testing.T) {
+	readErr := errors.New("permission denied")
+
+	tests := []struct {
+		name      string
+		templates *fakeTemplateReader
+		opts      []AddOption
+		wantErrIs error
+	}{
+		{"invalid template name", &fakeTemplateReader{}, []AddOption{AddTemplate("../etc/passwd")}, domain.ErrInvalidTemplateName},
+		{"missing explicit template", &fakeTemplateReader{}, []AddOption{AddTemplate("nope")}, ErrTemplateNotFound},
+		{"explicit template without reader", nil, []AddOption{AddTemplate("nope")}, ErrTemplateNotFound},
+		{"read failure", &fakeTemplateReader{err: readErr}, nil, readErr},
+		{"malformed template frontmatter", &fakeTemplateReader{templates: map[string]string{"draft.md": "---\nunclosed\n"}}, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &fakeFileWriter{}
+			opts := []Option{}
+			if tt.templates != nil {
+				opts = append(opts, WithTemplateReader(tt.templates))
+			}
+			svc := NewOutlineService(&fakeDirectoryReader{}, writer, &mockLocker{}, &fakeSIDReserver{sid: "NEWID12345AB"}, opts...)
+
+			_, err := svc.Add(context.Background(), "New", "", tt.opts...)
+
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("error = %v, want %v", err, tt.wantErrIs)
+			}
+			if len(writer.written) != 0 {
+				t.Errorf("no files should be written on error, got %v", writer.written)
+			}
+		})
+	}
+}
+
+func TestOutlineService_Add_NotesTemplateReadFailure(t *testing.T) {
+	readErr := errors.New("permission denied")
+	templates := &failingAfterDraftReader{err: readErr}
+	svc := NewOutlineService(&fakeDirectoryReader{}, &fakeFileWriter{}, &mockLocker{}, &fakeSIDReserver{sid: "NEWID12345AB"},
+		WithTemplateReader(templates))
+
+	_, err := svc.Add(context.Background(), "New", "")
+
+	if !errors.Is(err, readErr) {
+		t.Errorf("error = %v, want %v", err, readErr)
+	}
+}
+
+// failingAfterDraftReader reports no draft templates and fails for every other doc type.
+type failingAfterDraftReader struct {
+	err error
+}
+
+func (f *failingAfterDraftReader) ReadTemplate(_ context.Context, name string) (string, error) {
+	if name == "draft.md" || name == "1/draft.md" {
+		return "", fs.ErrNotExist
+	}
+	return "", f.err
+}
+
+func TestOutlineService_AddType_FillsFromTemplate(t *testing.T) {
+	reader := &fakeDirectoryReader{files: []string{
+		"100_SID001AABB_draft_act-one.md",
+		"100-100_SID002AABB_draft_storm.md",
+	}}
+	content := &fakeContentReader{contents: map[string]string{
+		"100_SID001AABB_draft_act-one.md":   "---\ntitle: Act One\n---\n",
+		"100-100_SID002AABB_draft_storm.md": "---\ntitle: The Storm\n---\n",
+	}}
+
+	tests := []struct {
+		name      string
+		templates map[string]string
+		opts      []AddTypeOption
+		want      string
+	}{
+		{
+			name:      "configured template",
+			templates: map[string]string{"beats.md": "Beats for {{title}} in {{parent_title}}\n"},
+			want:      "Beats for The Storm in Act One\n",
+		},
+		{
+			name:      "explicit template",
+			templates: map[string]string{"beats.md": "generic\n", "beats-short.md": "short {{sid}}\n"},
+			opts:      []AddTypeOption{AddTypeTemplate("beats-short")},
+			want:      "short SID002AABB\n",
+		},
+		{
+			name:      "no template writes empty file",
+			templates: map[string]string{},
+			want:      "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &fakeFileWriter{}
+			svc := NewOutlineService(reader, writer, &mockLocker{}, nil,
+				WithContentReader(content), WithTemplateReader(&fakeTemplateReader{templates: tt.templates}))
+
+			result, err := svc.AddType(context.Background(), "beats", "100-100", tt.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got, ok := writer.written[result.Filename]
+			if !ok {
+				t.Fatalf("expected %s to be written", result.Filename)
+			}
+			if got != tt.want {
+				t.Errorf("content = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutlineService_AddType_TemplateErrors(t *testing.T) {
+	reader := &fakeDirectoryReader{files: []string{"100_SID001AABB_draft_act-one.md"}}
+
+	tests := []struct {
+		name      string
+		opts      []AddTypeOption
+		wantErrIs error
+	}{
+		{"invalid template name", []AddTypeOption{AddTypeTemplate("Beats!")}, domain.ErrInvalidTemplateName},
+		{"missing explicit template", []AddTypeOption{AddTypeTemplate("beats-long")}, ErrTemplateNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &fakeFileWriter{}
+			svc := NewOutlineService(reader, writer, &mockLocker{}, nil, WithTemplateReader(&fakeTemplateReader{}))
+
+			_, err := svc.AddType(context.Background(), "beats", "100", tt.opts...)
+
+			if !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("error = %v, want %v", err, tt.wantErrIs)
+			}
+			if len(writer.written) != 0 {
+				t.Errorf("no files should be written on error, got %v", writer.written)
+			}
+		})
+	}
+}
+
+func TestOutlineService_NodeTitle_UnreadableDraftYieldsEmptyTitle(t *testing.T) {
+	reader := &fakeDirectoryReader{files: []string{"100_SID001AABB_draft_act-one.md"}}
+	writer := &fakeFileWriter{}
+	svc := NewOutlineService(reader, writer, &mockLocker{}, nil,
+		WithContentReader(&fakeContentReader{err: errors.New("unreadable")}),
+		WithTemplateReader(&fakeTemplateReader{templates: map[string]string{"beats.md": "[{{title}}]"}}))
+
+	result, err := svc.AddType(context.Background(), "beats", "100")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := writer.written[result.Filename]; got != "[]" {
+		t.Errorf("content = %q, want empty title placeholder", got)
+	}
+}
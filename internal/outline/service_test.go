@@ -34,7 +34,11 @@ func TestOutlineService_MutatingCommands_Locking(t *testing.T) {
 		name string
 		call func(*OutlineService) mutatingFn
 	}{
-		{"AddType", func(s *OutlineService) mutatingFn { return s.AddType }},
+		{"AddType", func(s *OutlineService) mutatingFn {
+			return func(ctx context.Context, docType, selector string) (*ModifyResult, error) {
+				return s.AddType(ctx, docType, selector)
+			}
+		}},
 		{"RemoveType", func(s *OutlineService) mutatingFn { return s.RemoveType }},
 	}
 
@@ -0,0 +1,223 @@
+package outline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+// ErrNoSuchSnapshot is returned when a snapshot ID is not in the store.
+var ErrNoSuchSnapshot = errors.New("no such snapshot")
+
+// ErrSnapshotsUnavailable is returned when snapshots are used without a
+// SnapshotStore.
+var ErrSnapshotsUnavailable = errors.New("snapshots are not available")
+
+// SnapshotInfo describes a stored snapshot.
+type SnapshotInfo struct {
+	ID      string
+	Created time.Time
+	Message string
+	Files   int
+}
+
+// SnapshotStore abstracts storing copies of the outline's content files
+// outside version control. Stored snapshots open as read-only Snapshots.
+type SnapshotStore interface {
+	SaveSnapshot(ctx context.Context, message string, files map[string]string) (SnapshotInfo, error)
+	ListSnapshots(ctx context.Context) ([]SnapshotInfo, error)
+	OpenSnapshot(ctx context.Context, id string) (Snapshot, error)
+}
+
+// RestoreResult lists the files a restore writes and deletes.
+type RestoreResult struct {
+	Written []string
+	Deleted []string
+}
+
+// readFilesImpl reads every validly named content file of a snapshot, by
+// filename.
+func readFilesImpl(ctx context.Context, snap Snapshot) (map[string]string, error) {
+	names, err := snap.ReadDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+	parsed, _ := parseFilesWithFindings(names)
+	files := map[string]string{}
+	for _, pf := range parsed {
+		name := reconstructFilename(pf)
+		content, err := snap.ReadFile(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		files[name] = content
+	}
+	return files, nil
+}
+
+// readProjectFilesImpl reads every validly named content file in the
+// project, by filename.
+func (s *OutlineService) readProjectFilesImpl(ctx context.Context) (map[string]string, error) {
+	return readFilesImpl(ctx, projectSnapshot{s.reader, s.contentReader})
+}
+
+// CreateSnapshot stores a copy of every content file in the project,
+// acquiring an advisory lock first so no command is midway through
+// changing them. When apply is false, nothing is stored and the returned
+// info has no ID.
+func (s *OutlineService) CreateSnapshot(ctx context.Context, message string, apply bool) (*SnapshotInfo, error) {
+	if s.snapshotStore == nil {
+		return nil, ErrSnapshotsUnavailable
+	}
+	if err := s.locker.TryLock(ctx); err != nil {
+		return nil, err
+	}
+	defer s.locker.Unlock()
+
+	files, err := s.readProjectFilesImpl(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !apply {
+		return &SnapshotInfo{Message: message, Files: len(files)}, nil
+	}
+	info, err := s.snapshotStore.SaveSnapshot(ctx, message, files)
+	if err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// ListSnapshots returns the stored snapshots, oldest first.
+func (s *OutlineService) ListSnapshots(ctx context.Context) ([]SnapshotInfo, error) {
+	if s.snapshotStore == nil {
+		return nil, ErrSnapshotsUnavailable
+	}
+	return s.snapshotStore.ListSnapshots(ctx)
+}
+
+// openSnapshot opens a stored snapshot, where "" names the project
+// directory itself.
+func (s *OutlineService) openSnapshot(ctx context.Context, id string) (Snapshot, error) {
+	if id == "" {
+		return projectSnapshot{s.reader, s.contentReader}, nil
+	}
+	if s.snapshotStore == nil {
+		return nil, ErrSnapshotsUnavailable
+	}
+	return s.snapshotStore.OpenSnapshot(ctx, id)
+}
+
+// DiffSnapshots compares two stored snapshots node by node, as Diff does.
+// An empty ID names the project directory.
+func (s *OutlineService) DiffSnapshots(ctx context.Context, from, to string) (*DiffResult, error) {
+	var snaps [2]Snapshot
+	for i, id := range []string{from, to} {
+		snap, err := s.openSnapshot(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		snaps[i] = snap
+	}
+	return s.diffImpl(ctx, snaps[0], snaps[1])
+}
+
+// RestoreSnapshot returns the project to a stored snapshot, acquiring an
+// advisory lock first: the snapshot's files are written as stored and
+// content files it lacks are deleted. With a SID, only that node's documents
+// are restored, at its current MP, or at its snapshot MP when it has since
+// been deleted. When apply is false, the changes are planned but not made.
+func (s *OutlineService) RestoreSnapshot(ctx context.Context, id, sid string, apply bool) (*RestoreResult, error) {
+	if err := s.lockForWrite(ctx); err != nil {
+		return nil, err
+	}
+	defer s.locker.Unlock()
+
+	snap, err := s.openSnapshot(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var files map[string]string
+	var inScope func(domain.ParsedFile) bool
+	if sid == "" {
+		if files, err = readFilesImpl(ctx, snap); err != nil {
+			return nil, err
+		}
+	} else {
+		if files, err = s.restoreNodeFilesImpl(ctx, snap, id, sid); err != nil {
+			return nil, err
+		}
+		inScope = func(pf domain.ParsedFile) bool { return pf.SID == sid }
+	}
+	written, deleted, err := s.syncFilesImpl(ctx, files, inScope, apply)
+	if err != nil {
+		return nil, err
+	}
+	return &RestoreResult{Written: written, Deleted: deleted}, nil
+}
+
+// restoreNodeFilesImpl returns the files that restore one node's documents
+// from a snapshot, by filename. A SID the snapshot holds at more than one MP
+// is ambiguous.
+func (s *OutlineService) restoreNodeFilesImpl(ctx context.Context, snap Snapshot, id, sid string) (map[string]string, error) {
+	side, err := readSnapshotImpl(ctx, snap)
+	if err != nil {
+		return nil, err
+	}
+	n := side[sid]
+	if n == nil {
+		return nil, fmt.Errorf("%w: %s in snapshot %s", ErrNodeNotFound, sid, id)
+	}
+	if len(n.others) > 0 {
+		return nil, fmt.Errorf("%w: %s is at %s and %s in snapshot %s", ErrAmbiguousSelector, sid, n.mp, strings.Join(n.others, ", "), id)
+	}
+	mp, err := s.restoreMPImpl(ctx, sid, n.mp)
+	if err != nil {
+		return nil, err
+	}
+	files := map[string]string{}
+	for docType, doc := range n.docs {
+		files[domain.GenerateFilename(mp, sid, docType, doc.slug)] = doc.content
+	}
+	return files, nil
+}
+
+// restoreMPImpl returns the MP to restore a single node at: its current MP
+// if it still exists, otherwise its snapshot MP, moved to a free number
+// after it when another node now holds that MP.
+func (s *OutlineService) restoreMPImpl(ctx context.Context, sid, snapshotMP string) (string, error) {
+	names, err := s.reader.ReadDir(ctx)
+	if err != nil {
+		return "", err
+	}
+	parsed, _ := parseFilesWithFindings(names)
+
+	taken, parentExists := false, false
+	parent := parentMP(snapshotMP)
+	for _, pf := range parsed {
+		switch {
+		case pf.SID == sid:
+			return pf.MP, nil
+		case pf.MP == snapshotMP:
+			taken = true
+		case pf.MP == parent:
+			parentExists = true
+		}
+	}
+	if parent != "" && !parentExists {
+		return "", fmt.Errorf("%w: parent %s of %s; restore it first", ErrNodeNotFound, parent, sid)
+	}
+	if !taken {
+		return snapshotMP, nil
+	}
+	num, err := s.config.Numbering.SiblingNumberAfter(collectOccupiedChildNums(parsed, parent, ""), lastSegmentNum(snapshotMP))
+	if err != nil {
+		return "", err
+	}
+	return s.config.Numbering.ChildMP(parent, num), nil
+}
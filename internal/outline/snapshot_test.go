@@ -0,0 +1,337 @@
+package outline
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+// fakeSnapshotStore is an in-memory SnapshotStore.
+type fakeSnapshotStore struct {
+	saved     map[string]string
+	message   string
+	infos     []SnapshotInfo
+	snapshots map[string]Snapshot
+	err       error
+}
+
+func (f *fakeSnapshotStore) SaveSnapshot(_ context.Context, message string, files map[string]string) (SnapshotInfo, error) {
+	f.saved, f.message = files, message
+	return SnapshotInfo{ID: "20260301T120000Z", Message: message, Files: len(files)}, f.err
+}
+
+func (f *fakeSnapshotStore) ListSnapshots(_ context.Context) ([]SnapshotInfo, error) {
+	return f.infos, f.err
+}
+
+func (f *fakeSnapshotStore) OpenSnapshot(_ context.Context, id string) (Snapshot, error) {
+	snap, ok := f.snapshots[id]
+	if !ok {
+		return nil, ErrNoSuchSnapshot
+	}
+	return snap, f.err
+}
+
+// snapshotBase is a part with two chapters, as stored in snapshot "s1".
+var snapshotBase = map[string]string{
+	"100_SIDPARTAA1_draft_part.md":    "part\n",
+	"100-100_SIDCHAPAA1_draft_one.md": "one\n",
+	"100-100_SIDCHAPAA1_notes.md":     "notes\n",
+	"100-200_SIDCHAPAA2_draft_two.md": "two\n",
+}
+
+// newSnapshotService returns a service over project with snapshot "s1"
+// holding snapshotBase, and the writer and deleter it uses.
+func newSnapshotService(project map[string]string, locker *mockLocker) (*OutlineService, *fakeFileWriter, *fakeFileDeleter) {
+	target := &fakeSnapshot{files: project}
+	writer := &fakeFileWriter{}
+	deleter := &fakeFileDeleter{}
+	store := &fakeSnapshotStore{snapshots: map[string]Snapshot{"s1": &fakeSnapshot{files: snapshotBase}}}
+	svc := NewOutlineService(target, writer, locker, nil,
+		WithDeleter(deleter), WithContentReader(target), WithSnapshotStore(store))
+	return svc, writer, deleter
+}
+
+// restored returns project after applying the writes and deletes.
+func restored(project map[string]string, writer *fakeFileWriter, deleter *fakeFileDeleter) map[string]string {
+	out := maps.Clone(project)
+	for _, name := range deleter.deleted {
+		delete(out, name)
+	}
+	maps.Copy(out, writer.written)
+	return out
+}
+
+func TestOutlineService_CreateSnapshot(t *testing.T) {
+	project := withChanges(snapshotBase, map[string]string{"README.md": "not content\n"})
+	target := &fakeSnapshot{files: project}
+	store := &fakeSnapshotStore{}
+	locker := &mockLocker{}
+	svc := NewOutlineService(target, nil, locker, nil, WithContentReader(target), WithSnapshotStore(store))
+
+	info, err := svc.CreateSnapshot(context.Background(), "before cuts", true)
+
+	if err != nil {
+		t.Fatalf("CreateSnapshot() error = %v", err)
+	}
+	if !maps.Equal(store.saved, snapshotBase) || store.message != "before cuts" {
+		t.Errorf("saved %v with %q, want the content files", store.saved, store.message)
+	}
+	if info.ID != "20260301T120000Z" || info.Files != 4 {
+		t.Errorf("info = %+v", info)
+	}
+	if !locker.tryLockCalled || !locker.unlockCalled {
+		t.Error("expected the advisory lock to be taken and released")
+	}
+}
+
+func TestOutlineService_CreateSnapshot_DryRun(t *testing.T) {
+	target := &fakeSnapshot{files: snapshotBase}
+	store := &fakeSnapshotStore{}
+	svc := NewOutlineService(target, nil, &mockLocker{}, nil, WithContentReader(target), WithSnapshotStore(store))
+
+	info, err := svc.CreateSnapshot(context.Background(), "", false)
+
+	if err != nil || info.ID != "" || info.Files != 4 {
+		t.Errorf("CreateSnapshot() = %+v, %v", info, err)
+	}
+	if store.saved != nil {
+		t.Error("dry run should not save")
+	}
+}
+
+func TestOutlineService_CreateSnapshot_Errors(t *testing.T) {
+	storeErr := errors.New("disk full")
+	lockErr := errors.New("locked")
+	readErr := errors.New("unreadable")
+
+	tests := []struct {
+		name    string
+		target  *fakeSnapshot
+		store   SnapshotStore
+		locker  *mockLocker
+		wantErr error
+	}{
+		{"no store", &fakeSnapshot{}, nil, &mockLocker{}, ErrSnapshotsUnavailable},
+		{"lock", &fakeSnapshot{}, &fakeSnapshotStore{}, &mockLocker{tryLockErr: lockErr}, lockErr},
+		{"listing", &fakeSnapshot{dirErr: readErr}, &fakeSnapshotStore{}, &mockLocker{}, readErr},
+		{"contents", &fakeSnapshot{files: snapshotBase, readErr: readErr}, &fakeSnapshotStore{}, &mockLocker{}, readErr},
+		{"store", &fakeSnapshot{}, &fakeSnapshotStore{err: storeErr}, &mockLocker{}, storeErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := []Option{WithContentReader(tt.target)}
+			if tt.store != nil {
+				opts = append(opts, WithSnapshotStore(tt.store))
+			}
+			svc := NewOutlineService(tt.target, nil, tt.locker, nil, opts...)
+
+			_, err := svc.CreateSnapshot(context.Background(), "", true)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOutlineService_ListSnapshots(t *testing.T) {
+	store := &fakeSnapshotStore{infos: []SnapshotInfo{{ID: "a"}, {ID: "b"}}}
+	svc := NewOutlineService(nil, nil, nil, nil, WithSnapshotStore(store))
+
+	infos, err := svc.ListSnapshots(context.Background())
+
+	if err != nil || len(infos) != 2 {
+		t.Errorf("ListSnapshots() = %v, %v", infos, err)
+	}
+	if _, err := NewOutlineService(nil, nil, nil, nil).ListSnapshots(context.Background()); !errors.Is(err, ErrSnapshotsUnavailable) {
+		t.Errorf("without a store: error = %v, want ErrSnapshotsUnavailable", err)
+	}
+}
+
+func TestOutlineService_DiffSnapshots(t *testing.T) {
+	project := withChanges(snapshotBase, map[string]string{"100-300_SIDCHAPAA3_draft_three.md": "three\n"})
+	svc, _, _ := newSnapshotService(project, &mockLocker{})
+
+	result, err := svc.DiffSnapshots(context.Background(), "s1", "")
+
+	if err != nil {
+		t.Fatalf("DiffSnapshots() error = %v", err)
+	}
+	if len(result.Nodes) != 1 || result.Nodes[0].SID != "SIDCHAPAA3" || result.Nodes[0].Status != DiffAdded {
+		t.Errorf("nodes = %+v, want the added chapter", result.Nodes)
+	}
+
+	if _, err := svc.DiffSnapshots(context.Background(), "missing", ""); !errors.Is(err, ErrNoSuchSnapshot) {
+		t.Errorf("unknown snapshot: error = %v", err)
+	}
+	if _, err := NewOutlineService(nil, nil, nil, nil).DiffSnapshots(context.Background(), "s1", ""); !errors.Is(err, ErrSnapshotsUnavailable) {
+		t.Errorf("without a store: error = %v", err)
+	}
+}
+
+func TestOutlineService_RestoreSnapshot(t *testing.T) {
+	// Since the snapshot: chapter one moved under two and was edited,
+	// chapter two lost its draft's text, and chapter three was added.
+	project := map[string]string{
+		"100_SIDPARTAA1_draft_part.md":        "part\n",
+		"100-200_SIDCHAPAA2_draft_two.md":     "",
+		"100-200-100_SIDCHAPAA1_draft_one.md": "one, revised\n",
+		"100-300_SIDCHAPAA3_draft_three.md":   "three\n",
+		"README.md":                           "kept\n",
+	}
+
+	tests := []struct {
+		name string
+		sid  string
+		want map[string]string
+	}{
+		{
+			name: "whole outline",
+			want: withChanges(snapshotBase, map[string]string{"README.md": "kept\n"}),
+		},
+		{
+			name: "one node restored where it now is",
+			sid:  "SIDCHAPAA1",
+			want: withChanges(project, map[string]string{
+				"100-200-100_SIDCHAPAA1_draft_one.md": "one\n",
+				"100-200-100_SIDCHAPAA1_notes.md":     "notes\n",
+			}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locker := &mockLocker{}
+			svc, writer, deleter := newSnapshotService(project, locker)
+
+			_, err := svc.RestoreSnapshot(context.Background(), "s1", tt.sid, true)
+
+			if err != nil {
+				t.Fatalf("RestoreSnapshot() error = %v", err)
+			}
+			if got := restored(project, writer, deleter); !maps.Equal(got, tt.want) {
+				t.Errorf("restored:\n got %v\nwant %v", got, tt.want)
+			}
+			if !locker.tryLockCalled || !locker.unlockCalled {
+				t.Error("expected the advisory lock to be taken and released")
+			}
+		})
+	}
+}
+
+func TestOutlineService_RestoreSnapshot_DeletedNode(t *testing.T) {
+	tests := []struct {
+		name    string
+		project map[string]string
+		want    string
+	}{
+		{
+			name:    "at its snapshot MP",
+			project: withChanges(snapshotBase, map[string]string{"100-200_SIDCHAPAA2_draft_two.md": ""}),
+			want:    "100-200_SIDCHAPAA2_draft_two.md",
+		},
+		{
+			name: "after its snapshot MP when that is taken",
+			project: withChanges(snapshotBase, map[string]string{
+				"100-200_SIDCHAPAA2_draft_two.md": "",
+				"100-200_SIDCHAPAA3_draft_new.md": "new\n",
+			}),
+			want: "100-300_SIDCHAPAA2_draft_two.md",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, writer, _ := newSnapshotService(tt.project, &mockLocker{})
+
+			result, err := svc.RestoreSnapshot(context.Background(), "s1", "SIDCHAPAA2", false)
+
+			if err != nil {
+				t.Fatalf("RestoreSnapshot() error = %v", err)
+			}
+			if !slices.Equal(result.Written, []string{tt.want}) || result.Deleted != nil {
+				t.Errorf("result = %+v, want only %s written", result, tt.want)
+			}
+			if len(writer.written) != 0 {
+				t.Error("dry run should not write")
+			}
+		})
+	}
+}
+
+func TestOutlineService_RestoreSnapshot_Errors(t *testing.T) {
+	lockErr := errors.New("locked")
+	readErr := errors.New("unreadable")
+	crowded := map[string]string{"100_SIDPARTAA1_draft_part.md": "part\n"}
+	for n := 1; n <= 999; n++ {
+		mp := domain.DefaultNumbering.ChildMP("100", n)
+		crowded[domain.GenerateFilename(mp, "SIDBASE"+mp[4:], "draft", "")] = "x"
+	}
+
+	tests := []struct {
+		name    string
+		project *fakeSnapshot
+		locker  *mockLocker
+		id, sid string
+		wantErr error
+	}{
+		{"lock", &fakeSnapshot{}, &mockLocker{tryLockErr: lockErr}, "s1", "", lockErr},
+		{"unknown snapshot", &fakeSnapshot{}, &mockLocker{}, "missing", "", ErrNoSuchSnapshot},
+		{"unreadable snapshot", &fakeSnapshot{}, &mockLocker{}, "broken", "", readErr},
+		{"unreadable project", &fakeSnapshot{dirErr: readErr}, &mockLocker{}, "s1", "", readErr},
+		{"unreadable project for one node", &fakeSnapshot{dirErr: readErr}, &mockLocker{}, "s1", "SIDCHAPAA2", readErr},
+		{"node not in snapshot", &fakeSnapshot{}, &mockLocker{}, "s1", "SIDNOPENOPE", ErrNodeNotFound},
+		{"parent since deleted", &fakeSnapshot{}, &mockLocker{}, "s1", "SIDCHAPAA2", ErrNodeNotFound},
+		{"no free number", &fakeSnapshot{files: crowded}, &mockLocker{}, "s1", "SIDCHAPAA2", domain.ErrMaxSiblingsReached},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeSnapshotStore{snapshots: map[string]Snapshot{
+				"s1":     &fakeSnapshot{files: snapshotBase},
+				"broken": &fakeSnapshot{dirErr: readErr},
+			}}
+			svc := NewOutlineService(tt.project, &fakeFileWriter{}, tt.locker, nil,
+				WithDeleter(&fakeFileDeleter{}), WithContentReader(tt.project), WithSnapshotStore(store))
+
+			_, err := svc.RestoreSnapshot(context.Background(), tt.id, tt.sid, true)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOutlineService_RestoreSnapshot_DuplicateSIDs(t *testing.T) {
+	// A bad merge left one SID at two MPs; the project is unchanged since.
+	dup := map[string]string{
+		"100_AAAAAAAA1111_draft_a.md": "a\n",
+		"200_AAAAAAAA1111_draft_b.md": "b\n",
+		"200_AAAAAAAA1111_notes.md":   "notes\n",
+	}
+	store := &fakeSnapshotStore{snapshots: map[string]Snapshot{"s1": &fakeSnapshot{files: dup}}}
+	project := &fakeSnapshot{files: maps.Clone(dup)}
+	writer, deleter := &fakeFileWriter{}, &fakeFileDeleter{}
+	svc := NewOutlineService(project, writer, &mockLocker{}, nil,
+		WithDeleter(deleter), WithContentReader(project), WithSnapshotStore(store))
+
+	result, err := svc.RestoreSnapshot(context.Background(), "s1", "", true)
+
+	if err != nil {
+		t.Fatalf("RestoreSnapshot() error = %v", err)
+	}
+	if len(result.Written) != 0 || len(result.Deleted) != 0 || len(writer.written) != 0 || len(deleter.deleted) != 0 {
+		t.Errorf("result = %+v, want an unchanged snapshot to restore nothing", result)
+	}
+
+	if _, err := svc.RestoreSnapshot(context.Background(), "s1", "AAAAAAAA1111", true); !errors.Is(err, ErrAmbiguousSelector) {
+		t.Errorf("restoring the duplicated SID: error = %v, want %v", err, ErrAmbiguousSelector)
+	}
+}
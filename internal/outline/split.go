@@ -0,0 +1,211 @@
+package outline
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+// ErrBadSplitPoint is returned when a split point does not fall inside the
+// body of the node's draft.
+var ErrBadSplitPoint = errors.New("split point is not in the draft body")
+
+// SplitPoint says where a draft is split: before a line of the file,
+// counted from 1, or before the first heading whose text matches Heading,
+// ignoring case.
+type SplitPoint struct {
+	Line    int
+	Heading string
+}
+
+// SplitResult holds the result of a split operation. The new node, at MP
+// with SID and Title, holds the Lines lines of the draft from FirstLine on;
+// Draft is the original draft, kept with the lines before it.
+type SplitResult struct {
+	MP         string
+	SID        string
+	Title      string
+	Draft      string
+	Created    []string
+	Lines      int
+	FirstLine  string
+	Rebalanced map[string]string
+}
+
+// Split moves the tail of a node's draft, from the split point on, into a
+// new sibling placed right after it, acquiring an advisory lock first. The
+// new node takes title, or when that is empty, the heading split at or the
+// node's title followed by "(part 2)". It gets a freshly reserved SID and
+// the default document types for its depth. When apply is false, the SID
+// is reserved but nothing is written.
+func (s *OutlineService) Split(ctx context.Context, sel domain.Selector, at SplitPoint, title string, apply bool) (*SplitResult, error) {
+	if s.contentReader == nil {
+		return nil, ErrContentUnavailable
+	}
+	if err := s.lockForWrite(ctx); err != nil {
+		return nil, err
+	}
+	defer s.locker.Unlock()
+
+	parsed, err := s.readAndParse(ctx)
+	if err != nil {
+		return nil, err
+	}
+	nodeMP, _, err := s.resolveTarget(ctx, parsed, sel)
+	if err != nil {
+		return nil, err
+	}
+
+	draft := findDraft(parsed, nodeMP)
+	if draft == nil {
+		return nil, fmt.Errorf("%s has no draft to split: %w", nodeMP, ErrBadSplitPoint)
+	}
+	draftName := reconstructFilename(*draft)
+	content, err := s.contentReader.ReadFile(ctx, draftName)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", draftName, err)
+	}
+	head, tail, heading, err := splitDraft(content, at)
+	if err != nil {
+		return nil, err
+	}
+	if title = strings.TrimSpace(title); title == "" {
+		nodeTitle, _ := s.fmHandler.GetTitle(content)
+		title = cmp.Or(heading, cmp.Or(nodeTitle, draft.Slug)+" (part 2)")
+	}
+
+	parent := parentMP(nodeMP)
+	num, rebalanced, err := s.siblingSlot(parsed, parent, "", adjacentSibling(parsed, nodeMP, true), "", true)
+	if err != nil {
+		return nil, err
+	}
+	sid, err := s.reserver.Reserve(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mp := s.config.Numbering.ChildMP(parent, num)
+	newDraft := domain.GenerateFilename(mp, sid, domain.DocTypeDraft, s.slugifier.Slug(title))
+	tailLines := strings.Split(strings.TrimRight(tail, "\n"), "\n")
+	result := &SplitResult{
+		MP:         mp,
+		SID:        sid,
+		Title:      title,
+		Draft:      cmp.Or(rebalanced[draftName], draftName),
+		Created:    []string{newDraft},
+		Lines:      len(tailLines),
+		FirstLine:  tailLines[0],
+		Rebalanced: rebalanced,
+	}
+	depth := strings.Count(mp, "-") + 1
+	for _, docType := range s.config.DefaultDocTypesAt(depth) {
+		if docType != domain.DocTypeDraft {
+			result.Created = append(result.Created, domain.GenerateFilename(mp, sid, docType, ""))
+		}
+	}
+
+	if !apply {
+		return result, nil
+	}
+	newContent := s.fmHandler.Serialize("title: "+s.fmHandler.EncodeYAMLValue(title)+"\n", tail)
+	if err := s.applySplitImpl(ctx, parsed, result, head, newContent); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// applySplitImpl renumbers neighbours, writes the new node's files and
+// then cuts the tail from the original draft, so a failure part way never
+// loses text.
+func (s *OutlineService) applySplitImpl(ctx context.Context, parsed []domain.ParsedFile, result *SplitResult, head, newContent string) error {
+	if s.reservationStore != nil {
+		if err := s.reservationStore.CreateReservation(ctx, result.SID); err != nil {
+			return err
+		}
+	}
+	if err := applyRenames(ctx, s.renamer, result.Rebalanced); err != nil {
+		return err
+	}
+	if err := s.writer.WriteFile(ctx, result.Created[0], newContent); err != nil {
+		if rbErr := rollbackRenames(ctx, s.renamer, renamePairs(result.Rebalanced)); rbErr != nil {
+			return fmt.Errorf("%w; rollback failed: %v", err, rbErr)
+		}
+		return err
+	}
+
+	depth := strings.Count(result.MP, "-") + 1
+	vars := func() domain.TemplateVars {
+		return s.templateVarsImpl(ctx, parsed, result.MP, result.SID, result.Title)
+	}
+	for _, docType := range s.config.DefaultDocTypesAt(depth) {
+		if docType == domain.DocTypeDraft {
+			continue
+		}
+		content, err := s.renderTemplateImpl(ctx, "", docType, depth, vars)
+		if err != nil {
+			return err
+		}
+		if err := s.writer.WriteFile(ctx, domain.GenerateFilename(result.MP, result.SID, docType, ""), content); err != nil {
+			return err
+		}
+	}
+	return s.writer.WriteFile(ctx, result.Draft, head)
+}
+
+// findDraft returns the draft file of the node at mp, or nil.
+func findDraft(parsed []domain.ParsedFile, mp string) *domain.ParsedFile {
+	for i, pf := range parsed {
+		if pf.MP == mp && pf.DocType == domain.DocTypeDraft {
+			return &parsed[i]
+		}
+	}
+	return nil
+}
+
+// splitDraft cuts a draft at a split point into the head, which keeps the
+// frontmatter and ends with a single newline, and the tail. When splitting
+// at a heading, its text is returned too. The tail must hold more than
+// blank lines.
+func splitDraft(content string, at SplitPoint) (head, tail, heading string, err error) {
+	offset := bodyOffset(content)
+	frontLines := strings.Count(content[:offset], "\n")
+	lines := strings.SplitAfter(content[offset:], "\n")
+
+	index := -1
+	if at.Heading != "" {
+		for i, line := range lines {
+			if text, ok := headingText(line); ok && strings.EqualFold(text, strings.TrimSpace(at.Heading)) {
+				index, heading = i, text
+				break
+			}
+		}
+		if index < 0 {
+			return "", "", "", fmt.Errorf("no heading %q: %w", at.Heading, ErrBadSplitPoint)
+		}
+	} else {
+		index = at.Line - frontLines - 1
+	}
+	if index < 0 || index >= len(lines) || strings.TrimSpace(strings.Join(lines[index:], "")) == "" {
+		return "", "", "", fmt.Errorf("line %d: %w", at.Line, ErrBadSplitPoint)
+	}
+
+	head = content[:offset] + strings.TrimRight(strings.Join(lines[:index], ""), "\n")
+	if !strings.HasSuffix(head, "\n") {
+		head += "\n"
+	}
+	return head, strings.Join(lines[index:], ""), heading, nil
+}
+
+// headingText returns the text of an ATX Markdown heading line.
+func headingText(line string) (string, bool) {
+	line = strings.TrimRight(line, "\r\n")
+	level := len(line) - len(strings.TrimLeft(line, "#"))
+	if level == 0 || level > 6 || (len(line) > level && line[level] != ' ' && line[level] != '\t') {
+		return "", false
+	}
+	return strings.TrimSpace(strings.TrimRight(strings.TrimSpace(line[level:]), "#")), true
+}
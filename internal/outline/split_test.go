@@ -0,0 +1,294 @@
+package outline
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+const splitTestDraft = "---\ntitle: Part One\n---\nIntro.\n\n## The Storm\nRain.\n"
+
+// splitTestFiles returns a fixture of two root nodes, the first with a
+// draft holding a heading.
+func splitTestFiles() []string {
+	return []string{
+		"100_SID001AABB_draft_part-one.md",
+		"100_SID001AABB_notes.md",
+		"200_SID004GGHH_draft_part-two.md",
+	}
+}
+
+func newSplitTestService(files []string, contents map[string]string) (*OutlineService, *copyTestDeps) {
+	svc, deps := newCopyTestService(files)
+	deps.content.contents = contents
+	return svc, deps
+}
+
+func TestOutlineService_Split(t *testing.T) {
+	tests := []struct {
+		name      string
+		selector  string
+		at        SplitPoint
+		title     string
+		want      *SplitResult
+		wantHead  string
+		wantDraft string
+	}{
+		{
+			name:     "at a heading",
+			selector: "100",
+			at:       SplitPoint{Heading: "the storm"},
+			want: &SplitResult{
+				MP: "110", SID: "SIDN01AABB", Title: "The Storm", Draft: "100_SID001AABB_draft_part-one.md",
+				Created:   []string{"110_SIDN01AABB_draft_the-storm.md", "110_SIDN01AABB_notes.md"},
+				Lines:     2,
+				FirstLine: "## The Storm",
+			},
+			wantHead:  "---\ntitle: Part One\n---\nIntro.\n",
+			wantDraft: "---\ntitle: The Storm\n---\n## The Storm\nRain.\n",
+		},
+		{
+			name:     "at a line",
+			selector: "SID001AABB",
+			at:       SplitPoint{Line: 7},
+			want: &SplitResult{
+				MP: "110", SID: "SIDN01AABB", Title: "Part One (part 2)", Draft: "100_SID001AABB_draft_part-one.md",
+				Created:   []string{"110_SIDN01AABB_draft_part-one-part-2.md", "110_SIDN01AABB_notes.md"},
+				Lines:     1,
+				FirstLine: "Rain.",
+			},
+			wantHead:  "---\ntitle: Part One\n---\nIntro.\n\n## The Storm\n",
+			wantDraft: "---\ntitle: Part One (part 2)\n---\nRain.\n",
+		},
+		{
+			name:     "with a title after the last sibling",
+			selector: "200",
+			at:       SplitPoint{Line: 2},
+			title:    " Coda ",
+			want: &SplitResult{
+				MP: "300", SID: "SIDN01AABB", Title: "Coda", Draft: "200_SID004GGHH_draft_part-two.md",
+				Created:   []string{"300_SIDN01AABB_draft_coda.md", "300_SIDN01AABB_notes.md"},
+				Lines:     1,
+				FirstLine: "Two.",
+			},
+			wantHead:  "One.\n",
+			wantDraft: "---\ntitle: Coda\n---\nTwo.\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, deps := newSplitTestService(splitTestFiles(), map[string]string{
+				"100_SID001AABB_draft_part-one.md": splitTestDraft,
+				"200_SID004GGHH_draft_part-two.md": "One.\nTwo.\n",
+			})
+			sel, _ := domain.ParseSelector(tt.selector)
+
+			result, err := svc.Split(context.Background(), sel, tt.at, tt.title, true)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.MP != tt.want.MP || result.SID != tt.want.SID || result.Title != tt.want.Title || result.Draft != tt.want.Draft ||
+				!slices.Equal(result.Created, tt.want.Created) || result.Lines != tt.want.Lines || result.FirstLine != tt.want.FirstLine {
+				t.Errorf("result = %+v, want %+v", result, tt.want)
+			}
+			if got := deps.writer.written[tt.want.Draft]; got != tt.wantHead {
+				t.Errorf("original draft = %q, want %q", got, tt.wantHead)
+			}
+			if got := deps.writer.written[tt.want.Created[0]]; got != tt.wantDraft {
+				t.Errorf("new draft = %q, want %q", got, tt.wantDraft)
+			}
+			if _, ok := deps.writer.written[tt.want.Created[1]]; !ok {
+				t.Errorf("notes not created: %v", deps.writer.written)
+			}
+			if !slices.Equal(deps.reservations.created, []string{"SIDN01AABB"}) {
+				t.Errorf("reservations = %v", deps.reservations.created)
+			}
+		})
+	}
+}
+
+func TestOutlineService_Split_RebalancesNeighbours(t *testing.T) {
+	files := []string{"100_SID001AABB_draft_one.md", "101_SID002CCDD_draft_two.md"}
+	svc, deps := newSplitTestService(files, map[string]string{"100_SID001AABB_draft_one.md": "a\nb\n"})
+	sel, _ := domain.ParseSelector("100")
+
+	result, err := svc.Split(context.Background(), sel, SplitPoint{Line: 2}, "", true)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Rebalanced) == 0 || len(deps.renamer.renames) != len(result.Rebalanced) {
+		t.Fatalf("rebalanced = %v, renamed %v", result.Rebalanced, deps.renamer.renames)
+	}
+	if deps.writer.written[result.Draft] != "a\n" {
+		t.Errorf("written = %v, want the head at %s", deps.writer.written, result.Draft)
+	}
+	if lastSegmentNum(result.MP) <= lastSegmentNum(renamedMP(result.Rebalanced, "100_SID001AABB_draft_one.md")) {
+		t.Errorf("new node %s does not follow the original", result.MP)
+	}
+}
+
+func TestOutlineService_Split_WriteErrorRollsBackRebalance(t *testing.T) {
+	files := []string{"100_SID001AABB_draft_one.md", "101_SID002CCDD_draft_two.md"}
+	svc, deps := newSplitTestService(files, map[string]string{"100_SID001AABB_draft_one.md": "a\nb\n"})
+	deps.writer.writeErr = errors.New("disk full")
+	sel, _ := domain.ParseSelector("100")
+
+	_, err := svc.Split(context.Background(), sel, SplitPoint{Line: 2}, "", true)
+
+	if !errors.Is(err, deps.writer.writeErr) {
+		t.Fatalf("error = %v, want %v", err, deps.writer.writeErr)
+	}
+	moved := map[string]int{}
+	for _, r := range deps.renamer.renames {
+		moved[r[0]]++
+		moved[r[1]]--
+	}
+	for name, n := range moved {
+		if n != 0 {
+			t.Errorf("%s not restored: renames = %v", name, deps.renamer.renames)
+		}
+	}
+	if len(deps.renamer.renames) == 0 {
+		t.Error("expected the neighbours to be rebalanced and restored")
+	}
+}
+
+// renamedMP returns the MP of a file after any rename in renames.
+func renamedMP(renames map[string]string, name string) string {
+	if newName, ok := renames[name]; ok {
+		name = newName
+	}
+	pf, _ := domain.ParseFilename(name)
+	return pf.MP
+}
+
+func TestOutlineService_Split_DryRun(t *testing.T) {
+	svc, deps := newSplitTestService(splitTestFiles(), map[string]string{"100_SID001AABB_draft_part-one.md": splitTestDraft})
+	sel, _ := domain.ParseSelector("100")
+
+	result, err := svc.Split(context.Background(), sel, SplitPoint{Heading: "The Storm"}, "", false)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.MP != "110" || len(deps.writer.written) != 0 || len(deps.reservations.created) != 0 {
+		t.Errorf("result = %+v, written %v, reserved %v", result, deps.writer.written, deps.reservations.created)
+	}
+}
+
+func TestOutlineService_Split_Errors(t *testing.T) {
+	lockErr := errors.New("locked")
+	readErr := errors.New("read failed")
+	contentErr := errors.New("unreadable")
+	reserveErr := errors.New("no more SIDs")
+	createErr := errors.New("no reservations")
+	writeErr := errors.New("disk full")
+	renameErr := errors.New("rename failed")
+	crowded := []string{"100_SID001AABB_draft_part.md"}
+	for n := 1; n <= 999; n++ {
+		mp := domain.DefaultNumbering.ChildMP("100", n)
+		crowded = append(crowded, domain.GenerateFilename(mp, "SIDBASE"+mp[4:], "draft", ""))
+	}
+
+	tests := []struct {
+		name     string
+		selector string
+		at       SplitPoint
+		setup    func(*OutlineService, *copyTestDeps)
+		want     error
+	}{
+		{"no content reader", "100", SplitPoint{Line: 4}, func(s *OutlineService, _ *copyTestDeps) { s.contentReader = nil }, ErrContentUnavailable},
+		{"locked", "100", SplitPoint{Line: 4}, func(s *OutlineService, _ *copyTestDeps) { s.locker = &mockLocker{tryLockErr: lockErr} }, lockErr},
+		{"unreadable directory", "100", SplitPoint{Line: 4}, func(s *OutlineService, _ *copyTestDeps) { s.reader = &fakeDirectoryReader{err: readErr} }, readErr},
+		{"missing node", "300", SplitPoint{Line: 4}, nil, ErrNodeNotFound},
+		{"no draft", "100", SplitPoint{Line: 4}, func(s *OutlineService, _ *copyTestDeps) {
+			s.reader = &fakeDirectoryReader{files: []string{"100_SID001AABB_notes.md"}}
+		}, ErrBadSplitPoint},
+		{"unreadable draft", "100", SplitPoint{Line: 4}, func(_ *OutlineService, d *copyTestDeps) { d.content.err = contentErr }, contentErr},
+		{"line in the frontmatter", "100", SplitPoint{Line: 3}, nil, ErrBadSplitPoint},
+		{"line past the end", "100", SplitPoint{Line: 9}, nil, ErrBadSplitPoint},
+		{"blank tail", "200", SplitPoint{Line: 2}, nil, ErrBadSplitPoint},
+		{"missing heading", "100", SplitPoint{Heading: "The Calm"}, nil, ErrBadSplitPoint},
+		{"no free number", "100-500", SplitPoint{Line: 1}, func(s *OutlineService, d *copyTestDeps) {
+			s.reader = &fakeDirectoryReader{files: crowded}
+			d.content.contents["100-500_SIDBASE500_draft.md"] = "a\n"
+		}, domain.ErrMaxSiblingsReached},
+		{"reserve fails", "100", SplitPoint{Line: 4}, func(_ *OutlineService, d *copyTestDeps) { d.reserver.err = reserveErr }, reserveErr},
+		{"reservation fails", "100", SplitPoint{Line: 4}, func(_ *OutlineService, d *copyTestDeps) { d.reservations.createErr = createErr }, createErr},
+		{"write fails", "100", SplitPoint{Line: 4}, func(_ *OutlineService, d *copyTestDeps) { d.writer.writeErr = writeErr }, writeErr},
+		{"rebalance fails", "100", SplitPoint{Line: 4}, func(s *OutlineService, d *copyTestDeps) {
+			s.reader = &fakeDirectoryReader{files: append(splitTestFiles(), "101_SID002CCDD_draft_tight.md")}
+			d.renamer.err = renameErr
+		}, renameErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, deps := newSplitTestService(splitTestFiles(), map[string]string{
+				"100_SID001AABB_draft_part-one.md": splitTestDraft,
+				"200_SID004GGHH_draft_part-two.md": "Text.\n\n\n",
+			})
+			if tt.setup != nil {
+				tt.setup(svc, deps)
+			}
+			sel, _ := domain.ParseSelector(tt.selector)
+
+			_, err := svc.Split(context.Background(), sel, tt.at, "", true)
+
+			if !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutlineService_Split_TemplateError(t *testing.T) {
+	svc, _ := newSplitTestService(splitTestFiles(), map[string]string{"100_SID001AABB_draft_part-one.md": splitTestDraft})
+	tmplErr := errors.New("templates unreadable")
+	svc.templateReader = &fakeTemplateReader{err: tmplErr}
+	sel, _ := domain.ParseSelector("100")
+
+	_, err := svc.Split(context.Background(), sel, SplitPoint{Line: 4}, "", true)
+
+	if !errors.Is(err, tmplErr) {
+		t.Errorf("error = %v, want %v", err, tmplErr)
+	}
+}
+
+func TestHeadingText(t *testing.T) {
+	tests := map[string]struct {
+		text string
+		ok   bool
+	}{
+		"# One\n":       {"One", true},
+		"### Two ###\n": {"Two", true},
+		"##\tTab\r\n":   {"Tab", true},
+		"#hashtag\n":    {"", false},
+		"####### x\n":   {"", false},
+		"plain\n":       {"", false},
+	}
+
+	for line, want := range tests {
+		text, ok := headingText(line)
+		if text != want.text || ok != want.ok {
+			t.Errorf("headingText(%q) = %q, %v; want %q, %v", line, text, ok, want.text, want.ok)
+		}
+	}
+}
+
+func TestSplitDraft_KeepsContentWithoutFrontmatter(t *testing.T) {
+	head, tail, _, err := splitDraft("a\n\n\nb\n", SplitPoint{Line: 4})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if head != "a\n" || tail != "b\n" {
+		t.Errorf("head, tail = %q, %q; want %q, %q", head, tail, "a\n", "b\n")
+	}
+}
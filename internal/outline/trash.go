@@ -0,0 +1,202 @@
+package outline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+// ErrTrashUnavailable is returned when the trash is used without a
+// TrashStore.
+var ErrTrashUnavailable = errors.New("trash is not available")
+
+// ErrNoSuchTrash is returned when a trash ID is not in the store.
+var ErrNoSuchTrash = errors.New("no such trash entry")
+
+// ErrNodeExists is returned when restoring a node whose SID is already in
+// the outline.
+var ErrNodeExists = errors.New("node already exists")
+
+// ErrTrashConflict is returned when moving a file to or from the trash
+// would overwrite a file already there.
+var ErrTrashConflict = errors.New("file already exists")
+
+// TrashEntry describes an archived node: where it stood in the outline
+// and the files moved to the trash, named as they were in the project.
+type TrashEntry struct {
+	ID        string
+	Deleted   time.Time
+	SID       string
+	MP        string
+	ParentSID string
+	Slug      string
+	Files     []string
+}
+
+// TrashStore abstracts keeping archived files outside the outline.
+// Trash moves an entry's files out of the project and returns the entry
+// with its ID; Untrash moves them back under new names and forgets it.
+type TrashStore interface {
+	Trash(ctx context.Context, entry TrashEntry) (TrashEntry, error)
+	ListTrash(ctx context.Context) ([]TrashEntry, error)
+	Untrash(ctx context.Context, id string, names map[string]string) error
+	EmptyTrash(ctx context.Context) error
+}
+
+// TrashRestoreResult holds the outcome of restoring a node from the trash.
+type TrashRestoreResult struct {
+	ID      string
+	SID     string
+	MP      string
+	Renames map[string]string
+}
+
+// newTrashEntry records the position of the node at targetMP: its MP, its
+// parent's SID and its draft's slug.
+func newTrashEntry(parsed []domain.ParsedFile, targetMP, targetSID string) *TrashEntry {
+	entry := &TrashEntry{SID: targetSID, MP: targetMP}
+	parent := parentMP(targetMP)
+	for _, pf := range parsed {
+		switch {
+		case parent != "" && pf.MP == parent:
+			entry.ParentSID = pf.SID
+		case pf.MP == targetMP && pf.DocType == domain.DocTypeDraft:
+			entry.Slug = pf.Slug
+		}
+	}
+	return entry
+}
+
+// Archive removes a node from the outline as Delete does, but moves its
+// files to the trash so it can be restored later.
+func (s *OutlineService) Archive(ctx context.Context, sel domain.Selector, mode domain.DeleteMode, apply bool) (*DeleteResult, error) {
+	if s.trashStore == nil {
+		return nil, ErrTrashUnavailable
+	}
+	if err := s.lockForWrite(ctx); err != nil {
+		return nil, err
+	}
+	defer s.locker.Unlock()
+
+	return s.deleteNode(ctx, sel, mode, true, apply)
+}
+
+// ListTrash returns the archived nodes, oldest first.
+func (s *OutlineService) ListTrash(ctx context.Context) ([]TrashEntry, error) {
+	if s.trashStore == nil {
+		return nil, ErrTrashUnavailable
+	}
+	return s.trashStore.ListTrash(ctx)
+}
+
+// RestoreTrash returns an archived node to the outline, acquiring an
+// advisory lock first. The node goes back under its original parent, found
+// by SID wherever it now is, at its original number, or at a free number
+// after it when that is taken. When apply is false, the restore is planned
+// but not made.
+func (s *OutlineService) RestoreTrash(ctx context.Context, id string, apply bool) (*TrashRestoreResult, error) {
+	if s.trashStore == nil {
+		return nil, ErrTrashUnavailable
+	}
+	if err := s.lockForWrite(ctx); err != nil {
+		return nil, err
+	}
+	defer s.locker.Unlock()
+
+	entries, err := s.trashStore.ListTrash(ctx)
+	if err != nil {
+		return nil, err
+	}
+	i := slices.IndexFunc(entries, func(e TrashEntry) bool { return e.ID == id })
+	if i < 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoSuchTrash, id)
+	}
+	entry := entries[i]
+
+	parsed, err := s.readAndParse(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mp, err := s.restoreTrashMP(parsed, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	renames := map[string]string{}
+	for _, name := range entry.Files {
+		pf, err := domain.ParseFilename(name)
+		if err != nil {
+			return nil, fmt.Errorf("trash entry %s: %w", id, err)
+		}
+		renames[name] = domain.GenerateFilename(mp+strings.TrimPrefix(pf.MP, entry.MP), pf.SID, pf.DocType, pf.Slug)
+	}
+	if apply {
+		if err := s.trashStore.Untrash(ctx, id, renames); err != nil {
+			return nil, err
+		}
+	}
+	return &TrashRestoreResult{ID: id, SID: entry.SID, MP: mp, Renames: renames}, nil
+}
+
+// restoreTrashMP returns the MP to restore an archived node at.
+func (s *OutlineService) restoreTrashMP(parsed []domain.ParsedFile, entry TrashEntry) (string, error) {
+	trashed := map[string]bool{}
+	for _, name := range entry.Files {
+		if pf, err := domain.ParseFilename(name); err == nil {
+			trashed[pf.SID] = true
+		}
+	}
+
+	parent := ""
+	parentFound := entry.ParentSID == ""
+	for _, pf := range parsed {
+		if trashed[pf.SID] {
+			return "", fmt.Errorf("%w: %s", ErrNodeExists, pf.SID)
+		}
+		if pf.SID == entry.ParentSID && !parentFound {
+			parent, parentFound = pf.MP, true
+		}
+	}
+	if !parentFound {
+		return "", fmt.Errorf("%w: parent %s of %s; restore it first", ErrNodeNotFound, entry.ParentSID, entry.SID)
+	}
+
+	num := lastSegmentNum(entry.MP)
+	occupied := collectOccupiedChildNums(parsed, parent, "")
+	if slices.Contains(occupied, num) {
+		var err error
+		if num, err = s.config.Numbering.SiblingNumberAfter(occupied, num); err != nil {
+			return "", err
+		}
+	}
+	return s.config.Numbering.ChildMP(parent, num), nil
+}
+
+// EmptyTrash permanently deletes every archived node, acquiring an advisory
+// lock first, and returns the entries deleted. When apply is false, nothing
+// is deleted.
+func (s *OutlineService) EmptyTrash(ctx context.Context, apply bool) ([]TrashEntry, error) {
+	if s.trashStore == nil {
+		return nil, ErrTrashUnavailable
+	}
+	if err := s.lockForWrite(ctx); err != nil {
+		return nil, err
+	}
+	defer s.locker.Unlock()
+
+	entries, err := s.trashStore.ListTrash(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if apply {
+		if err := s.trashStore.EmptyTrash(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
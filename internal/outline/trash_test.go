@@ -0,0 +1,325 @@
+package outline
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/eykd/linemark-go/internal/domain"
+)
+
+// fakeTrashStore is an in-memory TrashStore.
+type fakeTrashStore struct {
+	entries  []TrashEntry
+	trashed  []TrashEntry
+	untrash  map[string]string
+	emptied  bool
+	err      error
+	trashErr error
+}
+
+func (f *fakeTrashStore) Trash(_ context.Context, entry TrashEntry) (TrashEntry, error) {
+	entry.ID = "20260301T120000Z"
+	f.trashed = append(f.trashed, entry)
+	return entry, f.trashErr
+}
+
+func (f *fakeTrashStore) ListTrash(_ context.Context) ([]TrashEntry, error) {
+	return f.entries, f.err
+}
+
+func (f *fakeTrashStore) Untrash(_ context.Context, _ string, names map[string]string) error {
+	f.untrash = names
+	return f.trashErr
+}
+
+func (f *fakeTrashStore) EmptyTrash(_ context.Context) error {
+	f.emptied = true
+	return f.trashErr
+}
+
+// newTrashTestService returns a service over files with the given trash.
+func newTrashTestService(files []string, store *fakeTrashStore, locker *mockLocker) (*OutlineService, *fakeFileDeleter) {
+	deleter := &fakeFileDeleter{}
+	svc := NewOutlineService(&fakeDirectoryReader{files: files}, nil, locker, nil,
+		WithDeleter(deleter), WithRenamer(&fakeFileRenamer{}), WithTrashStore(store))
+	return svc, deleter
+}
+
+var trashFiles = []string{
+	"100_SID001AABB_draft_part-one.md",
+	"100-100_SID002CCDD_draft_chapter.md",
+	"100-100_SID002CCDD_notes.md",
+	"100-100-100_SID003EEFF_draft_scene.md",
+	"200_SID004GGHH_draft_part-two.md",
+}
+
+func TestOutlineService_Archive(t *testing.T) {
+	tests := []struct {
+		name      string
+		selector  string
+		mode      domain.DeleteMode
+		want      TrashEntry
+		wantFiles int
+	}{
+		{
+			name:     "leaf",
+			selector: "100-100-100",
+			mode:     domain.DeleteModeDefault,
+			want:     TrashEntry{SID: "SID003EEFF", MP: "100-100-100", ParentSID: "SID002CCDD", Slug: "scene"},
+		},
+		{
+			name:     "subtree",
+			selector: "100-100",
+			mode:     domain.DeleteModeRecursive,
+			want:     TrashEntry{SID: "SID002CCDD", MP: "100-100", ParentSID: "SID001AABB", Slug: "chapter"},
+		},
+		{
+			name:     "root node with children promoted",
+			selector: "100",
+			mode:     domain.DeleteModePromote,
+			want:     TrashEntry{SID: "SID001AABB", MP: "100", Slug: "part-one"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeTrashStore{}
+			svc, deleter := newTrashTestService(trashFiles, store, &mockLocker{})
+			sel, _ := domain.ParseSelector(tt.selector)
+
+			result, err := svc.Archive(context.Background(), sel, tt.mode, true)
+
+			if err != nil {
+				t.Fatalf("Archive() error = %v", err)
+			}
+			if len(store.trashed) != 1 {
+				t.Fatalf("trashed %d entries, want 1", len(store.trashed))
+			}
+			got := store.trashed[0]
+			if got.SID != tt.want.SID || got.MP != tt.want.MP || got.ParentSID != tt.want.ParentSID || got.Slug != tt.want.Slug {
+				t.Errorf("entry = %+v, want %+v", got, tt.want)
+			}
+			if !slices.Equal(got.Files, result.FilesDeleted) || result.TrashID != "20260301T120000Z" {
+				t.Errorf("entry files %v, result %+v", got.Files, result)
+			}
+			if len(deleter.deleted) != 0 {
+				t.Errorf("deleted %v; archived files should not be deleted", deleter.deleted)
+			}
+		})
+	}
+}
+
+func TestOutlineService_Archive_DryRun(t *testing.T) {
+	store := &fakeTrashStore{}
+	svc, _ := newTrashTestService(trashFiles, store, &mockLocker{})
+	sel, _ := domain.ParseSelector("200")
+
+	result, err := svc.Archive(context.Background(), sel, domain.DeleteModeDefault, false)
+
+	if err != nil || len(result.FilesDeleted) != 1 || result.TrashID != "" || store.trashed != nil {
+		t.Errorf("Archive() = %+v, %v; trashed %v", result, err, store.trashed)
+	}
+}
+
+func TestOutlineService_Archive_Errors(t *testing.T) {
+	lockErr := errors.New("locked")
+	storeErr := errors.New("disk full")
+	sel, _ := domain.ParseSelector("200")
+
+	svc := NewOutlineService(&fakeDirectoryReader{files: trashFiles}, nil, &mockLocker{}, nil)
+	if _, err := svc.Archive(context.Background(), sel, domain.DeleteModeDefault, true); !errors.Is(err, ErrTrashUnavailable) {
+		t.Errorf("without a store: error = %v", err)
+	}
+	svc, _ = newTrashTestService(trashFiles, &fakeTrashStore{}, &mockLocker{tryLockErr: lockErr})
+	if _, err := svc.Archive(context.Background(), sel, domain.DeleteModeDefault, true); !errors.Is(err, lockErr) {
+		t.Errorf("locked: error = %v", err)
+	}
+	svc, _ = newTrashTestService(trashFiles, &fakeTrashStore{trashErr: storeErr}, &mockLocker{})
+	if _, err := svc.Archive(context.Background(), sel, domain.DeleteModeDefault, true); !errors.Is(err, storeErr) {
+		t.Errorf("store failure: error = %v", err)
+	}
+}
+
+func TestOutlineService_ListTrash(t *testing.T) {
+	store := &fakeTrashStore{entries: []TrashEntry{{ID: "a"}}}
+	svc, _ := newTrashTestService(nil, store, &mockLocker{})
+
+	if entries, err := svc.ListTrash(context.Background()); err != nil || len(entries) != 1 {
+		t.Errorf("ListTrash() = %v, %v", entries, err)
+	}
+	if _, err := NewOutlineService(nil, nil, nil, nil).ListTrash(context.Background()); !errors.Is(err, ErrTrashUnavailable) {
+		t.Errorf("without a store: error = %v", err)
+	}
+}
+
+func TestOutlineService_RestoreTrash(t *testing.T) {
+	scene := TrashEntry{
+		ID: "s", SID: "SID005IIJJ", MP: "100-100-200", ParentSID: "SID002CCDD",
+		Files: []string{"100-100-200_SID005IIJJ_draft_scene.md"},
+	}
+	chapter := TrashEntry{
+		ID: "c", SID: "SID005IIJJ", MP: "100-200", ParentSID: "SID001AABB",
+		Files: []string{"100-200_SID005IIJJ_draft_two.md", "100-200-100_SID006KKLL_draft_beat.md"},
+	}
+
+	tests := []struct {
+		name   string
+		files  []string
+		entry  TrashEntry
+		wantMP string
+		want   map[string]string
+	}{
+		{
+			name:   "at its original MP",
+			files:  trashFiles,
+			entry:  scene,
+			wantMP: "100-100-200",
+			want:   map[string]string{"100-100-200_SID005IIJJ_draft_scene.md": "100-100-200_SID005IIJJ_draft_scene.md"},
+		},
+		{
+			name:   "under its parent after the parent moved",
+			files:  []string{"300_SID002CCDD_draft_chapter.md"},
+			entry:  scene,
+			wantMP: "300-200",
+			want:   map[string]string{"100-100-200_SID005IIJJ_draft_scene.md": "300-200_SID005IIJJ_draft_scene.md"},
+		},
+		{
+			name:   "after its original MP when taken, with descendants",
+			files:  append(slices.Clone(trashFiles), "100-200_SID007MMNN_draft_new.md"),
+			entry:  chapter,
+			wantMP: "100-300",
+			want: map[string]string{
+				"100-200_SID005IIJJ_draft_two.md":      "100-300_SID005IIJJ_draft_two.md",
+				"100-200-100_SID006KKLL_draft_beat.md": "100-300-100_SID006KKLL_draft_beat.md",
+			},
+		},
+		{
+			name:   "at the root",
+			files:  trashFiles,
+			entry:  TrashEntry{ID: "r", SID: "SID005IIJJ", MP: "100", Files: []string{"100_SID005IIJJ_draft_x.md"}},
+			wantMP: "300",
+			want:   map[string]string{"100_SID005IIJJ_draft_x.md": "300_SID005IIJJ_draft_x.md"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeTrashStore{entries: []TrashEntry{tt.entry}}
+			locker := &mockLocker{}
+			svc, _ := newTrashTestService(tt.files, store, locker)
+
+			result, err := svc.RestoreTrash(context.Background(), tt.entry.ID, true)
+
+			if err != nil {
+				t.Fatalf("RestoreTrash() error = %v", err)
+			}
+			if result.MP != tt.wantMP || result.SID != "SID005IIJJ" || !maps.Equal(result.Renames, tt.want) {
+				t.Errorf("result = %+v, want MP %s and renames %v", result, tt.wantMP, tt.want)
+			}
+			if !maps.Equal(store.untrash, tt.want) {
+				t.Errorf("untrashed %v", store.untrash)
+			}
+			if !locker.tryLockCalled || !locker.unlockCalled {
+				t.Error("expected the advisory lock to be taken and released")
+			}
+		})
+	}
+}
+
+func TestOutlineService_RestoreTrash_DryRun(t *testing.T) {
+	store := &fakeTrashStore{entries: []TrashEntry{{ID: "x", SID: "SID005IIJJ", MP: "300", Files: []string{"300_SID005IIJJ_draft_x.md"}}}}
+	svc, _ := newTrashTestService(trashFiles, store, &mockLocker{})
+
+	result, err := svc.RestoreTrash(context.Background(), "x", false)
+
+	if err != nil || result.MP != "300" || store.untrash != nil {
+		t.Errorf("RestoreTrash() = %+v, %v; untrashed %v", result, err, store.untrash)
+	}
+}
+
+func TestOutlineService_RestoreTrash_Errors(t *testing.T) {
+	lockErr := errors.New("locked")
+	storeErr := errors.New("disk full")
+	crowded := []string{"100_SID001AABB_draft_part.md"}
+	for n := 1; n <= 999; n++ {
+		mp := domain.DefaultNumbering.ChildMP("100", n)
+		crowded = append(crowded, domain.GenerateFilename(mp, "SIDBASE"+mp[4:], "draft", ""))
+	}
+	entry := TrashEntry{ID: "x", SID: "SID005IIJJ", MP: "100-500", ParentSID: "SID001AABB", Files: []string{"100-500_SID005IIJJ_draft_x.md"}}
+
+	tests := []struct {
+		name    string
+		svc     *OutlineService
+		id      string
+		wantErr error
+	}{
+		{"no store", NewOutlineService(&fakeDirectoryReader{}, nil, &mockLocker{}, nil), "x", ErrTrashUnavailable},
+		{"lock", trashService(trashFiles, &fakeTrashStore{}, lockErr), "x", lockErr},
+		{"listing", trashService(trashFiles, &fakeTrashStore{err: storeErr}, nil), "x", storeErr},
+		{"unknown ID", trashService(trashFiles, &fakeTrashStore{entries: []TrashEntry{entry}}, nil), "y", ErrNoSuchTrash},
+		{"unreadable project", NewOutlineService(&fakeDirectoryReader{err: storeErr}, nil, &mockLocker{}, nil,
+			WithTrashStore(&fakeTrashStore{entries: []TrashEntry{entry}})), "x", storeErr},
+		{"SID back in the outline", trashService(append(slices.Clone(trashFiles), "400_SID005IIJJ_draft_x.md"),
+			&fakeTrashStore{entries: []TrashEntry{entry}}, nil), "x", ErrNodeExists},
+		{"parent gone", trashService([]string{"200_SID004GGHH_draft_b.md"}, &fakeTrashStore{entries: []TrashEntry{entry}}, nil), "x", ErrNodeNotFound},
+		{"no free number", trashService(crowded, &fakeTrashStore{entries: []TrashEntry{entry}}, nil), "x", domain.ErrMaxSiblingsReached},
+		{"bad filename", trashService(trashFiles, &fakeTrashStore{entries: []TrashEntry{{ID: "x", SID: "SID005IIJJ", MP: "300", Files: []string{"notes.txt"}}}}, nil), "x", nil},
+		{"store failure", trashService(trashFiles, &fakeTrashStore{entries: []TrashEntry{entry}, trashErr: storeErr}, nil), "x", storeErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.svc.RestoreTrash(context.Background(), tt.id, true)
+
+			if err == nil || (tt.wantErr != nil && !errors.Is(err, tt.wantErr)) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// trashService returns a service over files with the given trash whose
+// lock fails with lockErr.
+func trashService(files []string, store *fakeTrashStore, lockErr error) *OutlineService {
+	svc, _ := newTrashTestService(files, store, &mockLocker{tryLockErr: lockErr})
+	return svc
+}
+
+func TestOutlineService_EmptyTrash(t *testing.T) {
+	store := &fakeTrashStore{entries: []TrashEntry{{ID: "a"}, {ID: "b"}}}
+	svc, _ := newTrashTestService(nil, store, &mockLocker{})
+
+	if entries, err := svc.EmptyTrash(context.Background(), false); err != nil || len(entries) != 2 || store.emptied {
+		t.Errorf("dry run: EmptyTrash() = %v, %v; emptied %v", entries, err, store.emptied)
+	}
+	if entries, err := svc.EmptyTrash(context.Background(), true); err != nil || len(entries) != 2 || !store.emptied {
+		t.Errorf("EmptyTrash() = %v, %v; emptied %v", entries, err, store.emptied)
+	}
+}
+
+func TestOutlineService_EmptyTrash_Errors(t *testing.T) {
+	lockErr := errors.New("locked")
+	storeErr := errors.New("disk full")
+
+	tests := []struct {
+		name    string
+		svc     *OutlineService
+		wantErr error
+	}{
+		{"no store", NewOutlineService(nil, nil, &mockLocker{}, nil), ErrTrashUnavailable},
+		{"lock", trashService(nil, &fakeTrashStore{}, lockErr), lockErr},
+		{"listing", trashService(nil, &fakeTrashStore{err: storeErr}, nil), storeErr},
+		{"store failure", trashService(nil, &fakeTrashStore{trashErr: storeErr}, nil), storeErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.svc.EmptyTrash(context.Background(), true); !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
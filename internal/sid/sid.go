@@ -17,10 +17,16 @@ const (
 // It uses rejection sampling to ensure unbiased distribution: bytes >= 248
 // are discarded and re-read.
 func Generate(r io.Reader) (string, error) {
+	return GenerateLength(r, sidLength)
+}
+
+// GenerateLength produces a base62 SID of the given length using the same
+// rejection sampling as Generate.
+func GenerateLength(r io.Reader, length int) (string, error) {
 	var buf [1]byte
-	result := make([]byte, 0, sidLength)
+	result := make([]byte, 0, length)
 
-	for len(result) < sidLength {
+	for len(result) < length {
 		_, err := r.Read(buf[:])
 		if err != nil {
 			return "", fmt.Errorf("reading random byte: %w", err)
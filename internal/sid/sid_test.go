@@ -183,3 +183,15 @@ func TestGenerate_ReaderExhausted(t *testing.T) {
 		t.Fatal("expected error when reader exhausted, got nil")
 	}
 }
+
+func TestGenerateLength(t *testing.T) {
+	for _, n := range []int{8, 10, 12} {
+		got, err := sid.GenerateLength(rand.Reader, n)
+		if err != nil {
+			t.Fatalf("GenerateLength(%d) unexpected error: %v", n, err)
+		}
+		if len(got) != n {
+			t.Errorf("GenerateLength(%d) length = %d", n, len(got))
+		}
+	}
+}